@@ -0,0 +1,168 @@
+package catalog
+
+import (
+	"embed"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+//go:embed configs
+var starterConfigs embed.FS
+
+// StarterConfig returns the contents of a starter config file in configs/
+func StarterConfig(file string) ([]byte, error) {
+	return starterConfigs.ReadFile("configs/" + file)
+}
+
+// Item is something decor can detect and install. The core languages have dedicated installers;
+// every other item is installed by the first of its strategies that fits the platform.
+type Item struct {
+	Name        string            // display name, also used to select the item on the command line
+	Category    string            // heading the item is listed under
+	Description string            // one line shown next to the item
+	Version     []string          // command printing the installed version, e.g. {"jupyter", "--version"}
+	Expect      string            // text a line of Version's output must contain, for commands that list what's installed
+	Verify      []string          // command that must succeed after installing, when Version isn't enough
+	ReportPath  bool              // note where Version's program was installed, for tools editors need to find
+	SmokeTest   []string          // slower end-to-end check run after installing, reported in the summary without failing the install
+	Requires    []string          // items that must be installed first
+	Group       string            // items in the same group are alternatives; the user's settings pick one
+	Conflicts   map[string]string // items that clash with this one, and why; declaring it on either side is enough
+	FollowUps   []string          // items offered once this one is installed, e.g. tooling for a language
+	Configure   [][]string        // commands run after installing to set the item up
+	Touches     []string          // shell startup files the installer script or Configure edits, confirmed before installing
+	Starter     map[string]string // starter config files written when the setting is on: destination (~ allowed) to file in configs/
+	Services    map[string]string // service per GOOS (systemd unit, launchd label or Windows service), enabled after installing
+	HealthCheck []string          // command that succeeds once the service accepts connections, e.g. {"pg_isready"}
+	UserGroup   string            // group the user joins after installing on Linux, so the tool works without sudo
+	Login       []string          // interactive login offered once the item is installed, e.g. {"gh", "auth", "login"}
+	LoginStatus []string          // command that succeeds once the user is logged in
+	Hardware    string            // only offered when this GPU vendor is present, e.g. "nvidia"
+	OS          string            // only offered on this GOOS, e.g. "darwin"
+	Manual      string            // how to install by hand, for items decor can't install itself
+	UsesJDK     bool              // a Java build tool; after installing, check it runs on decor's JDK and JAVA_HOME agrees
+	Repo        string            // GitHub repository as owner/name, whose release notes are shown before updating
+	Notes       string            // release notes page, for items that don't publish GitHub releases
+	Components  []Component       // optional parts picked from a checklist before installing
+	Completion  []string          // command printing the program's completion script for {shell}, offered as a component
+
+	// Install strategies
+	Brew      []string          // Homebrew formulae
+	BrewCask  bool              // Brew lists casks rather than formulae
+	Apt       []string          // Debian/Ubuntu packages
+	Winget    string            // winget package ID of a GUI tool, installed on the Windows side from WSL when the setting is on
+	Pipx      []string          // pipx package followed by extra pipx install flags
+	Npm       []string          // npm packages installed globally; the item should require Node.js
+	Cargo     []string          // crate followed by extra cargo install flags; the item should require Rust
+	Scripts   map[string]string // installer script URL per "GOOS/GOARCH", or "*" for any platform
+	Args      []string          // arguments passed to the installer script
+	Shell     string            // interpreter for the installer script, sh if empty
+	Env       []string          // KEY=value pairs for the installer script; values may start with ~
+	Debs      map[string]string // .deb package URL per "GOOS/GOARCH", for software missing from the apt repositories
+	Binaries  map[string]string // single-file program URL per "GOOS/GOARCH", saved to ~/.local/bin as Version's command
+	Release   string            // glob picking Repo's latest release asset holding Version's command, with {os} and {arch}; "*" picks by name
+	Archive   Archive           // tarball or zip unpacked into a directory of its own
+	GoInstall string            // package go install builds, e.g. golang.org/x/tools/gopls@latest; the item should require Go
+	Command   []string          // command that installs or updates the item, run as the user, e.g. {"rustup", "target", "add", ...}
+}
+
+// Component is an optional part of an item, like Python's tkinter, picked from a checklist before the
+// item is installed. It comes in packages for the package manager in use, a command, or files the
+// item's install includes and leaves out when it isn't picked.
+type Component struct {
+	Name        string   // what the [components] table lists, e.g. "tkinter"
+	Description string   // one line shown next to it
+	Default     bool     // picked until the user says otherwise
+	Brew        []string // Homebrew formulae
+	Apt         []string // Debian/Ubuntu packages
+	Command     []string // command run as the user once the item is installed, e.g. {"rustup", "component", "add", "rust-src"}
+	Omit        []string // paths in the item's install that are the component, deleted when it isn't picked
+}
+
+// Archive is software shipped as a tarball or zip and unpacked into a directory of its own. URL and
+// Checksum may use {version}, {os} and {arch}; {arch} is the GOARCH, or 32-bit ARM's "armv6" or "armv7",
+// unless Arches names it differently.
+type Archive struct {
+	URL      string            // download URL
+	Checksum string            // URL of the SHA-256 published for the download; empty skips the check
+	Type     string            // "tar.gz", "tar.xz" or "zip"; guessed from URL if empty
+	Strip    int               // leading directories dropped from every path in the archive, like tar --strip-components
+	Dir      string            // where it's unpacked: relative to the install prefix, or starting with ~
+	Links    []string          // programs under Dir linked into ~/.local/bin, e.g. "bin/tool"
+	Arches   map[string]string // what URL calls an architecture when it isn't {arch}'s usual name, e.g. "armv7": "armv6l"
+}
+
+// Preset is a named bundle of items installed together
+type Preset struct {
+	Name        string
+	Description string
+	Items       []string
+}
+
+// custom counts the items at the end of Items that were defined in the user's settings
+var custom int
+
+// SetCustom replaces the items defined in the user's settings, which are listed after decor's own.
+// Items named like one of decor's are left out, and returned as an error.
+func SetCustom(items []Item) error {
+	n := len(Items) - custom
+	builtin := Items[:n:n]
+	var clashes []string
+	added := 0
+	for _, item := range items {
+		if slices.ContainsFunc(builtin, func(i Item) bool { return strings.EqualFold(i.Name, item.Name) }) {
+			clashes = append(clashes, item.Name)
+			continue
+		}
+		builtin = append(builtin, item)
+		added++
+	}
+	Items, custom = builtin, added
+	if len(clashes) > 0 {
+		return fmt.Errorf("decor already has %s, so the custom tools by those names are ignored", strings.Join(clashes, ", "))
+	}
+	return nil
+}
+
+// Find looks up an item by name, ignoring case
+func Find(name string) (Item, bool) {
+	for _, item := range Items {
+		if strings.EqualFold(item.Name, name) {
+			return item, true
+		}
+	}
+	return Item{}, false
+}
+
+// FindPreset looks up a preset by name, ignoring case
+func FindPreset(name string) (Preset, bool) {
+	for _, preset := range Presets {
+		if strings.EqualFold(preset.Name, name) {
+			return preset, true
+		}
+	}
+	return Preset{}, false
+}
+
+// Names returns the names of every item in display order
+func Names() []string {
+	names := make([]string, 0, len(Items))
+	for _, item := range Items {
+		names = append(names, item.Name)
+	}
+	return names
+}
+
+// Categories returns the item categories in display order
+func Categories() []string {
+	var categories []string
+	seen := make(map[string]bool)
+	for _, item := range Items {
+		if !seen[item.Category] {
+			seen[item.Category] = true
+			categories = append(categories, item.Category)
+		}
+	}
+	return categories
+}
@@ -0,0 +1,10 @@
+-- Starter WezTerm config from decor
+local wezterm = require 'wezterm'
+local config = wezterm.config_builder()
+
+config.font_size = 13.0
+config.scrollback_lines = 50000
+config.audible_bell = 'Disabled'
+config.window_padding = { left = 6, right = 6, top = 6, bottom = 6 }
+
+return config
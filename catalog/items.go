@@ -0,0 +1,854 @@
+package catalog
+
+// Items lists everything decor can install, grouped by category in display order
+var Items = []Item{
+	// Languages have dedicated installers in the installer package,
+	// and offer their language servers once installed
+	{Name: "Go", Category: "Languages", Description: "Go toolchain from go.dev", Notes: "https://go.dev/doc/devel/release", FollowUps: []string{"gopls", "golangci-lint"}},
+	{
+		Name:        "Python",
+		Category:    "Languages",
+		Description: "Python 3 interpreter",
+		Notes:       "https://docs.python.org/3/whatsnew/changelog.html",
+		FollowUps:   []string{"pipx", "uv", "poetry", "virtualenvwrapper", "pyright", "ruff"},
+		Components: []Component{
+			{Name: "venv", Description: "python3 -m venv, for virtual environments", Default: true, Apt: []string{"python3-venv"}},
+			{Name: "headers", Description: "C headers for building extension modules", Apt: []string{"python3-dev"}},
+			{Name: "tkinter", Description: "Tk GUI bindings", Brew: []string{"python-tk"}, Apt: []string{"python3-tk"}},
+		},
+	},
+	{
+		Name:        "Rust",
+		Category:    "Languages",
+		Description: "Rust via rustup",
+		Repo:        "rust-lang/rust",
+		FollowUps:   []string{"rust-analyzer"},
+		Completion:  []string{"rustup", "completions", "{shell}"},
+		Components: []Component{
+			{Name: "rust-src", Description: "standard library source, for IDEs and building std", Command: []string{"rustup", "component", "add", "rust-src"}},
+			{Name: "llvm-tools", Description: "LLVM tools for coverage and binary inspection", Command: []string{"rustup", "component", "add", "llvm-tools"}},
+		},
+	},
+	{
+		Name:        "C++",
+		Category:    "Languages",
+		Description: "C and C++ compilers",
+		FollowUps:   []string{"CMake", "Ninja", "ccache", "vcpkg", "clangd", "clang-format"},
+	},
+	{
+		Name:        "Java",
+		Category:    "Languages",
+		Description: "A JDK from Temurin, Zulu, Corretto or GraalVM (see settings)",
+		FollowUps:   []string{"Maven", "Gradle", "jdtls"},
+		Components: []Component{
+			{Name: "sources", Description: "the class library's source, lib/src.zip, for IDEs", Default: true, Omit: []string{"lib/src.zip"}},
+		},
+	},
+
+	// Package Managers other items install through; Homebrew has a dedicated installer too
+	{
+		Name:        "Homebrew",
+		Category:    "Package Managers",
+		Description: "The macOS package manager most tools here install through",
+		Repo:        "Homebrew/brew",
+		Version:     []string{"brew", "--version"},
+		OS:          "darwin",
+	},
+
+	// Language Servers report where they were installed, so editors can be pointed at them
+	{
+		Name:        "gopls",
+		Category:    "Language Servers",
+		Description: "Go language server",
+		Version:     []string{"gopls", "version"},
+		ReportPath:  true,
+		Requires:    []string{"Go"},
+		Command:     []string{"go", "install", "golang.org/x/tools/gopls@latest"},
+	},
+	{
+		Name:        "pyright",
+		Category:    "Language Servers",
+		Description: "Python type checker and language server",
+		Repo:        "microsoft/pyright",
+		Version:     []string{"pyright", "--version"},
+		ReportPath:  true,
+		Requires:    []string{"pipx"},
+		Brew:        []string{"pyright"},
+		Pipx:        []string{"pyright"},
+	},
+	{
+		Name:        "ruff",
+		Category:    "Language Servers",
+		Description: "Python linter and formatter, with a language server in ruff server",
+		Repo:        "astral-sh/ruff",
+		Version:     []string{"ruff", "--version"},
+		ReportPath:  true,
+		Requires:    []string{"pipx"},
+		Brew:        []string{"ruff"},
+		Pipx:        []string{"ruff"},
+	},
+	{
+		Name:        "rust-analyzer",
+		Category:    "Language Servers",
+		Description: "Rust language server, as a rustup component",
+		Repo:        "rust-lang/rust-analyzer",
+		Version:     []string{"rust-analyzer", "--version"},
+		ReportPath:  true,
+		Requires:    []string{"Rust"},
+		Command:     []string{"rustup", "component", "add", "rust-analyzer"},
+	},
+	{
+		Name:        "clangd",
+		Category:    "Language Servers",
+		Description: "C and C++ language server from LLVM",
+		Version:     []string{"clangd", "--version"},
+		ReportPath:  true,
+		Brew:        []string{"llvm"},
+		Apt:         []string{"clangd"},
+	},
+	{
+		Name:        "jdtls",
+		Category:    "Language Servers",
+		Description: "Eclipse's Java language server",
+		Requires:    []string{"Java"},
+		Brew:        []string{"jdtls"},
+		Manual:      "download a milestone from download.eclipse.org/jdtls/milestones and put its bin directory on PATH",
+	},
+
+	// Linting
+	{
+		Name:        "pre-commit",
+		Category:    "Linting",
+		Description: "Runs linters and formatters as Git hooks (decor precommit writes a starter config)",
+		Version:     []string{"pre-commit", "--version"},
+		Requires:    []string{"pipx"},
+		Brew:        []string{"pre-commit"},
+		Pipx:        []string{"pre-commit"},
+	},
+	{
+		Name:        "golangci-lint",
+		Category:    "Linting",
+		Description: "Runs Go linters in parallel",
+		Repo:        "golangci/golangci-lint",
+		Version:     []string{"golangci-lint", "--version"},
+		ReportPath:  true,
+		Brew:        []string{"golangci-lint"},
+		Scripts:     map[string]string{"*": "https://raw.githubusercontent.com/golangci/golangci-lint/HEAD/install.sh"},
+		Args:        []string{"-b", "~/.local/bin"},
+	},
+	{
+		Name:        "clang-format",
+		Category:    "Linting",
+		Description: "Formats C, C++ and Objective-C",
+		Version:     []string{"clang-format", "--version"},
+		Brew:        []string{"clang-format"},
+		Apt:         []string{"clang-format"},
+	},
+	{
+		Name:        "ktlint",
+		Category:    "Linting",
+		Description: "Kotlin linter and formatter (needs Java)",
+		Version:     []string{"ktlint", "--version"},
+		Requires:    []string{"Java"},
+		Brew:        []string{"ktlint"},
+		Binaries: map[string]string{
+			"linux/amd64": "https://github.com/pinterest/ktlint/releases/download/1.3.1/ktlint",
+			"linux/arm64": "https://github.com/pinterest/ktlint/releases/download/1.3.1/ktlint",
+		},
+	},
+
+	// Terminals
+	{
+		Name:        "tmux",
+		Category:    "Terminals",
+		Description: "Terminal multiplexer",
+		Version:     []string{"tmux", "-V"},
+		Starter:     map[string]string{"~/.tmux.conf": "tmux.conf"},
+		Brew:        []string{"tmux"},
+		Apt:         []string{"tmux"},
+	},
+	{
+		Name:        "zellij",
+		Category:    "Terminals",
+		Description: "Terminal workspace with discoverable keybindings",
+		Repo:        "zellij-org/zellij",
+		Version:     []string{"zellij", "--version"},
+		Starter:     map[string]string{"~/.config/zellij/config.kdl": "zellij.kdl"},
+		Brew:        []string{"zellij"},
+		Command:     []string{"cargo", "install", "--locked", "zellij"},
+	},
+	{
+		Name:        "Alacritty",
+		Category:    "Terminals",
+		Description: "GPU-accelerated terminal emulator",
+		Repo:        "alacritty/alacritty",
+		Version:     []string{"alacritty", "--version"},
+		Starter:     map[string]string{"~/.config/alacritty/alacritty.toml": "alacritty.toml"},
+		Brew:        []string{"alacritty"},
+		BrewCask:    true,
+		Winget:      "Alacritty.Alacritty",
+		Apt:         []string{"alacritty"},
+	},
+	{
+		Name:        "kitty",
+		Category:    "Terminals",
+		Description: "GPU-based terminal emulator with images and tabs",
+		Repo:        "kovidgoyal/kitty",
+		Version:     []string{"kitty", "--version"},
+		Starter:     map[string]string{"~/.config/kitty/kitty.conf": "kitty.conf"},
+		Brew:        []string{"kitty"},
+		BrewCask:    true,
+		Apt:         []string{"kitty"},
+	},
+	{
+		Name:        "WezTerm",
+		Category:    "Terminals",
+		Description: "Terminal emulator and multiplexer configured in Lua",
+		Repo:        "wezterm/wezterm",
+		Version:     []string{"wezterm", "--version"},
+		Starter:     map[string]string{"~/.wezterm.lua": "wezterm.lua"},
+		Brew:        []string{"wezterm"},
+		BrewCask:    true,
+		Winget:      "wez.wezterm",
+		Debs: map[string]string{
+			"linux/amd64": "https://github.com/wez/wezterm/releases/download/20240203-110809-5046fc22/wezterm-20240203-110809-5046fc22.Ubuntu22.04.deb",
+		},
+	},
+	{
+		Name:        "Ghostty",
+		Category:    "Terminals",
+		Description: "Fast, native terminal emulator",
+		Version:     []string{"ghostty", "--version"},
+		Starter:     map[string]string{"~/.config/ghostty/config": "ghostty"},
+		Brew:        []string{"ghostty"},
+		BrewCask:    true,
+		Manual:      "install the package for your distribution listed at ghostty.org/docs/install/binary",
+	},
+
+	// CLI Tools come from the system package manager. fd and bat are detected through it too, since
+	// Debian renames their commands to fdfind and batcat.
+	{
+		Name:        "ripgrep",
+		Category:    "CLI Tools",
+		Description: "rg, a fast recursive grep that respects .gitignore",
+		Repo:        "BurntSushi/ripgrep",
+		Version:     []string{"rg", "--version"},
+		Brew:        []string{"ripgrep"},
+		Apt:         []string{"ripgrep"},
+	},
+	{
+		Name:        "fd",
+		Category:    "CLI Tools",
+		Description: "Simple, fast alternative to find (fdfind on Debian and Ubuntu)",
+		Repo:        "sharkdp/fd",
+		Brew:        []string{"fd"},
+		Apt:         []string{"fd-find"},
+	},
+	{
+		Name:        "fzf",
+		Category:    "CLI Tools",
+		Description: "Fuzzy finder for files, history and anything piped in",
+		Repo:        "junegunn/fzf",
+		Version:     []string{"fzf", "--version"},
+		Brew:        []string{"fzf"},
+		Apt:         []string{"fzf"},
+	},
+	{
+		Name:        "bat",
+		Category:    "CLI Tools",
+		Description: "cat with syntax highlighting and Git integration (batcat on Debian and Ubuntu)",
+		Repo:        "sharkdp/bat",
+		Brew:        []string{"bat"},
+		Apt:         []string{"bat"},
+	},
+	{
+		Name:        "eza",
+		Category:    "CLI Tools",
+		Description: "Modern ls with colors, icons and Git status",
+		Repo:        "eza-community/eza",
+		Version:     []string{"eza", "--version"},
+		Brew:        []string{"eza"},
+		Apt:         []string{"eza"},
+	},
+	{
+		Name:        "jq",
+		Category:    "CLI Tools",
+		Description: "Command-line JSON processor",
+		Repo:        "jqlang/jq",
+		Version:     []string{"jq", "--version"},
+		Brew:        []string{"jq"},
+		Apt:         []string{"jq"},
+	},
+	{
+		Name:        "yq",
+		Category:    "CLI Tools",
+		Description: "jq for YAML, TOML and XML (mikefarah/yq)",
+		Repo:        "mikefarah/yq",
+		Version:     []string{"yq", "--version"},
+		Brew:        []string{"yq"},
+		Binaries: map[string]string{
+			"linux/amd64": "https://github.com/mikefarah/yq/releases/download/v4.44.3/yq_linux_amd64",
+			"linux/arm64": "https://github.com/mikefarah/yq/releases/download/v4.44.3/yq_linux_arm64",
+		},
+	},
+	{
+		Name:        "delta",
+		Category:    "CLI Tools",
+		Description: "Syntax-highlighting pager for git diff",
+		Repo:        "dandavison/delta",
+		Version:     []string{"delta", "--version"},
+		Brew:        []string{"git-delta"},
+		Apt:         []string{"git-delta"},
+	},
+	{
+		Name:        "htop",
+		Category:    "CLI Tools",
+		Description: "Interactive process viewer",
+		Version:     []string{"htop", "--version"},
+		Brew:        []string{"htop"},
+		Apt:         []string{"htop"},
+	},
+	{
+		Name:        "btop",
+		Category:    "CLI Tools",
+		Description: "Resource monitor with graphs for CPU, memory, disks and network",
+		Repo:        "aristocratos/btop",
+		Version:     []string{"btop", "--version"},
+		Brew:        []string{"btop"},
+		Apt:         []string{"btop"},
+	},
+	{
+		Name:        "direnv",
+		Category:    "CLI Tools",
+		Description: "Loads a directory's .envrc on entering it, so a project's tool versions switch on by themselves",
+		Repo:        "direnv/direnv",
+		Version:     []string{"direnv", "version"},
+		Brew:        []string{"direnv"},
+		Apt:         []string{"direnv"},
+	},
+	{
+		Name:        "mise",
+		Category:    "CLI Tools",
+		Description: "Per-project tool versions, which decor uses to install a project's .decor toolchain",
+		Repo:        "jdx/mise",
+		Version:     []string{"mise", "--version"},
+		Brew:        []string{"mise"},
+		Scripts:     map[string]string{"*": "https://mise.run"},
+	},
+
+	// Git hosting
+	{
+		Name:        "GitHub CLI",
+		Category:    "Git Hosting",
+		Description: "gh, with an optional browser login so private repos clone right away",
+		Repo:        "cli/cli",
+		Version:     []string{"gh", "--version"},
+		Login:       []string{"sh", "-c", "gh auth login --web --hostname github.com --git-protocol https && gh auth setup-git"},
+		LoginStatus: []string{"gh", "auth", "status", "--hostname", "github.com"},
+		Completion:  []string{"gh", "completion", "-s", "{shell}"},
+		Brew:        []string{"gh"},
+		Apt:         []string{"gh"},
+	},
+
+	// Java build tools check they run on decor's JDK once installed
+	{
+		Name:        "Maven",
+		Category:    "Java Build Tools",
+		Description: "Apache Maven build tool",
+		Version:     []string{"mvn", "-v"},
+		Requires:    []string{"Java"},
+		UsesJDK:     true,
+		Brew:        []string{"maven"},
+		Apt:         []string{"maven"},
+	},
+	{
+		Name:        "Gradle",
+		Category:    "Java Build Tools",
+		Description: "Gradle build tool, from Homebrew or SDKMAN!",
+		Repo:        "gradle/gradle",
+		Version:     []string{"gradle", "-v"},
+		Requires:    []string{"Java", "SDKMAN"},
+		UsesJDK:     true,
+		Brew:        []string{"gradle"},
+		Command:     []string{"bash", "-c", "source ~/.sdkman/bin/sdkman-init.sh && sdk install gradle"},
+	},
+	{
+		Name:        "SDKMAN",
+		Category:    "Java Build Tools",
+		Description: "Manages parallel versions of JVM tools like Gradle",
+		Version:     []string{"bash", "-c", "source ~/.sdkman/bin/sdkman-init.sh && sdk version"},
+		Scripts:     map[string]string{"*": "https://get.sdkman.io"},
+		Shell:       "bash",
+		Touches:     []string{"~/.bashrc", "~/.zshrc"},
+	},
+
+	// JavaScript Tooling
+	{
+		Name:        "Node.js",
+		Category:    "JavaScript Tooling",
+		Description: "JavaScript runtime, with npm for installing packages",
+		Repo:        "nodejs/node",
+		Version:     []string{"node", "--version"},
+		Brew:        []string{"node"},
+		Apt:         []string{"nodejs", "npm"},
+		Components: []Component{
+			{Name: "corepack", Description: "yarn and pnpm shims at the versions projects pin", Brew: []string{"corepack"}, Command: []string{"corepack", "enable", "--install-directory", "~/.local/bin"}},
+		},
+	},
+
+	// Python Tooling
+	{
+		Name:        "pipx",
+		Category:    "Python Tooling",
+		Description: "Installs Python applications in isolated environments",
+		Repo:        "pypa/pipx",
+		Version:     []string{"pipx", "--version"},
+		Requires:    []string{"Python"},
+		Configure:   [][]string{{"pipx", "ensurepath"}},
+		Touches:     []string{"~/.bashrc", "~/.zshrc"},
+		Brew:        []string{"pipx"},
+		Apt:         []string{"pipx"},
+	},
+	{
+		Name:        "uv",
+		Category:    "Python Tooling",
+		Description: "Fast Python package and project manager",
+		Repo:        "astral-sh/uv",
+		Version:     []string{"uv", "--version"},
+		Group:       "python-manager",
+		Brew:        []string{"uv"},
+		Scripts:     map[string]string{"*": "https://astral.sh/uv/install.sh"},
+		Touches:     []string{"~/.profile", "~/.bashrc", "~/.zshrc"},
+	},
+	{
+		Name:        "poetry",
+		Category:    "Python Tooling",
+		Description: "Dependency management and packaging, with virtualenvs kept in each project",
+		Repo:        "python-poetry/poetry",
+		Version:     []string{"poetry", "--version"},
+		Requires:    []string{"pipx"},
+		Configure:   [][]string{{"poetry", "config", "virtualenvs.in-project", "true"}},
+		Pipx:        []string{"poetry"},
+	},
+	{
+		Name:        "virtualenvwrapper",
+		Category:    "Python Tooling",
+		Description: "mkvirtualenv and workon shell commands",
+		Requires:    []string{"Python"},
+		Apt:         []string{"virtualenvwrapper"},
+	},
+
+	// GPU toolkits are only offered on machines with a matching card
+	{
+		Name:        "CUDA Toolkit",
+		Category:    "GPU",
+		Description: "nvcc and the CUDA libraries, checked against your NVIDIA driver first",
+		Version:     []string{"nvcc", "--version"},
+		Hardware:    "nvidia",
+		Apt:         []string{"nvidia-cuda-toolkit"},
+	},
+	{
+		Name:        "cuDNN",
+		Category:    "GPU",
+		Description: "NVIDIA's deep learning primitives",
+		Requires:    []string{"CUDA Toolkit"},
+		Hardware:    "nvidia",
+		Apt:         []string{"nvidia-cudnn"},
+	},
+	{
+		Name:        "ROCm",
+		Category:    "GPU",
+		Description: "AMD's GPU compute stack with the HIP compiler",
+		Version:     []string{"hipcc", "--version"},
+		Hardware:    "amd",
+		Apt:         []string{"rocm"},
+	},
+
+	// C/C++ build tools; vcpkg and Conan are alternatives picked by the C++ packages setting
+	{
+		Name:        "CMake",
+		Category:    "C/C++ Build Tools",
+		Description: "Cross-platform build system generator",
+		Repo:        "Kitware/CMake",
+		Version:     []string{"cmake", "--version"},
+		Brew:        []string{"cmake"},
+		Apt:         []string{"cmake"},
+	},
+	{
+		Name:        "Ninja",
+		Category:    "C/C++ Build Tools",
+		Description: "Small, fast build system CMake can generate for",
+		Repo:        "ninja-build/ninja",
+		Version:     []string{"ninja", "--version"},
+		Brew:        []string{"ninja"},
+		Apt:         []string{"ninja-build"},
+	},
+	{
+		Name:        "ccache",
+		Category:    "C/C++ Build Tools",
+		Description: "Compiler cache that makes rebuilds faster",
+		Repo:        "ccache/ccache",
+		Version:     []string{"ccache", "--version"},
+		Brew:        []string{"ccache"},
+		Apt:         []string{"ccache"},
+	},
+	{
+		Name:        "vcpkg",
+		Category:    "C/C++ Build Tools",
+		Description: "Microsoft's C++ package manager, cloned to ~/vcpkg",
+		Version:     []string{"vcpkg", "version"},
+		Group:       "cpp-packages",
+		Requires:    []string{"C++"},
+		Command:     []string{"git", "clone", "https://github.com/microsoft/vcpkg.git", "~/vcpkg"},
+		Configure:   [][]string{{"~/vcpkg/bootstrap-vcpkg.sh", "-disableMetrics"}},
+	},
+	{
+		Name:        "Conan",
+		Category:    "C/C++ Build Tools",
+		Description: "Decentralized C and C++ package manager",
+		Repo:        "conan-io/conan",
+		Version:     []string{"conan", "--version"},
+		Group:       "cpp-packages",
+		Requires:    []string{"pipx"},
+		Configure:   [][]string{{"conan", "profile", "detect", "--exist-ok"}},
+		Brew:        []string{"conan"},
+		Pipx:        []string{"conan"},
+	},
+
+	// Cross Compile
+	{
+		Name:        "mingw-w64",
+		Category:    "Cross Compile",
+		Description: "GCC targeting 32 and 64-bit Windows",
+		Version:     []string{"x86_64-w64-mingw32-gcc", "--version"},
+		Brew:        []string{"mingw-w64"},
+		Apt:         []string{"mingw-w64"},
+	},
+	{
+		Name:        "musl-cross",
+		Category:    "Cross Compile",
+		Description: "GCC targeting static Linux binaries with musl libc",
+		Brew:        []string{"filosottile/musl-cross/musl-cross"},
+		Apt:         []string{"musl-tools"},
+	},
+	{
+		Name:        "osxcross prerequisites",
+		Category:    "Cross Compile",
+		Description: "Clang, CMake and the libraries osxcross builds against (Linux hosts; bring your own macOS SDK)",
+		Apt:         []string{"clang", "cmake", "patch", "cpio", "xz-utils", "libxml2-dev", "libssl-dev", "lzma-dev", "libbz2-dev", "zlib1g-dev"},
+	},
+	{
+		Name:        "Android NDK",
+		Category:    "Cross Compile",
+		Description: "Clang toolchains targeting Android",
+		Brew:        []string{"android-ndk"},
+		BrewCask:    true,
+		Apt:         []string{"google-android-ndk-installer"},
+	},
+
+	// WebAssembly
+	{
+		Name:        "wasm32 target",
+		Category:    "WebAssembly",
+		Description: "Rust's wasm32-unknown-unknown target, added with rustup",
+		Version:     []string{"rustup", "target", "list", "--installed"},
+		Expect:      "wasm32-unknown-unknown",
+		Requires:    []string{"Rust"},
+		Command:     []string{"rustup", "target", "add", "wasm32-unknown-unknown"},
+	},
+	{
+		Name:        "wasm-pack",
+		Category:    "WebAssembly",
+		Description: "Builds Rust crates into npm-ready WebAssembly packages",
+		Repo:        "rustwasm/wasm-pack",
+		Version:     []string{"wasm-pack", "--version"},
+		Requires:    []string{"Rust"},
+		Brew:        []string{"wasm-pack"},
+		Scripts:     map[string]string{"*": "https://rustwasm.github.io/wasm-pack/installer/init.sh"},
+	},
+	{
+		Name:        "wasmtime",
+		Category:    "WebAssembly",
+		Description: "Standalone WebAssembly and WASI runtime from the Bytecode Alliance",
+		Repo:        "bytecodealliance/wasmtime",
+		Version:     []string{"wasmtime", "--version"},
+		Brew:        []string{"wasmtime"},
+		Scripts:     map[string]string{"*": "https://wasmtime.dev/install.sh"},
+		Touches:     []string{"~/.profile", "~/.bashrc", "~/.zshrc"},
+	},
+	{
+		Name:        "wasmer",
+		Category:    "WebAssembly",
+		Description: "WebAssembly runtime with its own package registry",
+		Version:     []string{"wasmer", "--version"},
+		Brew:        []string{"wasmer"},
+		Scripts:     map[string]string{"*": "https://get.wasmer.io"},
+		Touches:     []string{"~/.bashrc", "~/.zshrc"},
+	},
+	{
+		Name:        "TinyGo",
+		Category:    "WebAssembly",
+		Description: "Go compiler for WebAssembly and microcontrollers",
+		Repo:        "tinygo-org/tinygo",
+		Version:     []string{"tinygo", "version"},
+		Requires:    []string{"Go"},
+		Brew:        []string{"tinygo-org/tools/tinygo"},
+		Debs: map[string]string{
+			"linux/amd64": "https://github.com/tinygo-org/tinygo/releases/download/v0.34.0/tinygo_0.34.0_amd64.deb",
+			"linux/arm64": "https://github.com/tinygo-org/tinygo/releases/download/v0.34.0/tinygo_0.34.0_arm64.deb",
+		},
+	},
+	{
+		Name:        "Emscripten",
+		Category:    "WebAssembly",
+		Description: "Compiles C and C++ to WebAssembly",
+		Version:     []string{"emcc", "--version"},
+		Brew:        []string{"emscripten"},
+		Apt:         []string{"emscripten"},
+	},
+
+	// Mobile
+	{
+		Name:        "Android command-line tools",
+		Category:    "Mobile",
+		Description: "sdkmanager and friends, without Android Studio",
+		Version:     []string{"sdkmanager", "--version"},
+		Requires:    []string{"Java"},
+		Brew:        []string{"android-commandlinetools"},
+		BrewCask:    true,
+		Apt:         []string{"sdkmanager"},
+	},
+	{
+		Name:        "Android SDK",
+		Category:    "Mobile",
+		Description: "Platform tools, the latest platform and build tools, with the SDK licenses accepted",
+		Version:     []string{"sdkmanager", "--list_installed"},
+		Expect:      "platform-tools",
+		Requires:    []string{"Android command-line tools"},
+		Command:     []string{"sh", "-c", "yes | sdkmanager --licenses > /dev/null; sdkmanager platform-tools 'platforms;android-35' 'build-tools;35.0.0'"},
+	},
+	{
+		Name:        "Xcode",
+		Category:    "Mobile",
+		Description: "Apple's IDE and iOS SDKs, detected with xcodebuild",
+		Version:     []string{"xcodebuild", "-version"},
+		OS:          "darwin",
+		Manual:      "install Xcode from the App Store, then run sudo xcodebuild -license accept",
+	},
+	{
+		Name:        "CocoaPods",
+		Category:    "Mobile",
+		Description: "Dependency manager for iOS projects",
+		Version:     []string{"pod", "--version"},
+		OS:          "darwin",
+		Brew:        []string{"cocoapods"},
+	},
+	{
+		Name:        "Fastlane",
+		Category:    "Mobile",
+		Description: "Automates building, signing and releasing mobile apps",
+		Version:     []string{"fastlane", "--version"},
+		Brew:        []string{"fastlane"},
+	},
+
+	// Kubernetes
+	{
+		Name:        "kubectl",
+		Category:    "Kubernetes",
+		Description: "Kubernetes command-line client",
+		Version:     []string{"kubectl", "version", "--client"},
+		Completion:  []string{"kubectl", "completion", "{shell}"},
+		Brew:        []string{"kubernetes-cli"},
+		Binaries: map[string]string{
+			"linux/amd64": "https://dl.k8s.io/release/v1.31.1/bin/linux/amd64/kubectl",
+			"linux/arm64": "https://dl.k8s.io/release/v1.31.1/bin/linux/arm64/kubectl",
+		},
+	},
+	{
+		Name:        "kind",
+		Category:    "Kubernetes",
+		Description: "Local clusters with nodes running as containers (needs Docker or Podman)",
+		Repo:        "kubernetes-sigs/kind",
+		Version:     []string{"kind", "version"},
+		Completion:  []string{"kind", "completion", "{shell}"},
+		Group:       "local-cluster",
+		SmokeTest:   []string{"sh", "-c", "kind create cluster --name decor-smoke-test --wait 2m; status=$?; kind delete cluster --name decor-smoke-test; exit $status"},
+		Brew:        []string{"kind"},
+		Binaries: map[string]string{
+			"linux/amd64": "https://kind.sigs.k8s.io/dl/v0.24.0/kind-linux-amd64",
+			"linux/arm64": "https://kind.sigs.k8s.io/dl/v0.24.0/kind-linux-arm64",
+		},
+	},
+	{
+		Name:        "minikube",
+		Category:    "Kubernetes",
+		Description: "Local clusters in a VM or container",
+		Repo:        "kubernetes/minikube",
+		Version:     []string{"minikube", "version", "--short"},
+		Completion:  []string{"minikube", "completion", "{shell}"},
+		Group:       "local-cluster",
+		SmokeTest:   []string{"sh", "-c", "minikube start -p decor-smoke-test --wait=all; status=$?; minikube delete -p decor-smoke-test; exit $status"},
+		Brew:        []string{"minikube"},
+		Binaries: map[string]string{
+			"linux/amd64": "https://storage.googleapis.com/minikube/releases/latest/minikube-linux-amd64",
+			"linux/arm64": "https://storage.googleapis.com/minikube/releases/latest/minikube-linux-arm64",
+		},
+	},
+	{
+		Name:        "helm",
+		Category:    "Kubernetes",
+		Description: "Kubernetes package manager",
+		Repo:        "helm/helm",
+		Version:     []string{"helm", "version", "--short"},
+		Completion:  []string{"helm", "completion", "{shell}"},
+		Brew:        []string{"helm"},
+		Scripts:     map[string]string{"*": "https://raw.githubusercontent.com/helm/helm/main/scripts/get-helm-3"},
+		Args:        []string{"--no-sudo"},
+		Env:         []string{"HELM_INSTALL_DIR=~/.local/bin"},
+	},
+	{
+		Name:        "k9s",
+		Category:    "Kubernetes",
+		Description: "Terminal UI for Kubernetes clusters",
+		Repo:        "derailed/k9s",
+		Version:     []string{"k9s", "version", "--short"},
+		Brew:        []string{"k9s"},
+		Debs: map[string]string{
+			"linux/amd64": "https://github.com/derailed/k9s/releases/download/v0.32.5/k9s_linux_amd64.deb",
+			"linux/arm64": "https://github.com/derailed/k9s/releases/download/v0.32.5/k9s_linux_arm64.deb",
+		},
+	},
+	{
+		Name:        "kubectx",
+		Category:    "Kubernetes",
+		Description: "kubectx and kubens for switching contexts and namespaces",
+		Version:     []string{"kubectx", "--help"},
+		Brew:        []string{"kubectx"},
+		Apt:         []string{"kubectx"},
+	},
+
+	// Containers
+	{
+		Name:        "Docker",
+		Category:    "Containers",
+		Description: "Docker Engine on Linux, Docker Desktop on macOS",
+		Version:     []string{"docker", "--version"},
+		Completion:  []string{"docker", "completion", "{shell}"},
+		Services:    map[string]string{"linux": "docker"},
+		UserGroup:   "docker",
+		Brew:        []string{"docker"},
+		BrewCask:    true,
+		Winget:      "Docker.DockerDesktop",
+		Apt:         []string{"docker.io"},
+	},
+	{
+		Name:        "Podman",
+		Category:    "Containers",
+		Description: "Daemonless, rootless containers with a Docker-compatible CLI",
+		Version:     []string{"podman", "--version"},
+		Services:    map[string]string{"linux": "podman.socket"},
+		Brew:        []string{"podman"},
+		Apt:         []string{"podman"},
+		Conflicts: map[string]string{
+			"Docker": "both provide the docker command and socket, and on macOS Docker Desktop and a Podman machine each run their own VM",
+		},
+	},
+
+	// Databases run as services and are reported running or stopped on the status screen
+	{
+		Name:        "PostgreSQL",
+		Category:    "Databases",
+		Description: "Relational database server and psql",
+		Version:     []string{"psql", "--version"},
+		Services:    map[string]string{"linux": "postgresql", "darwin": "homebrew.mxcl.postgresql@17"},
+		HealthCheck: []string{"pg_isready"},
+		Brew:        []string{"postgresql@17"},
+		Apt:         []string{"postgresql"},
+	},
+	{
+		Name:        "Redis",
+		Category:    "Databases",
+		Description: "In-memory key-value store",
+		Version:     []string{"redis-server", "--version"},
+		Services:    map[string]string{"linux": "redis-server", "darwin": "homebrew.mxcl.redis"},
+		HealthCheck: []string{"redis-cli", "ping"},
+		Brew:        []string{"redis"},
+		Apt:         []string{"redis-server"},
+	},
+
+	// Data Science
+	{
+		Name:        "Miniforge",
+		Category:    "Data Science",
+		Description: "conda with the conda-forge channel",
+		Version:     []string{"conda", "--version"},
+		Group:       "python-manager",
+		Brew:        []string{"miniforge"},
+		BrewCask:    true,
+		Scripts: map[string]string{
+			"linux/amd64":  "https://github.com/conda-forge/miniforge/releases/latest/download/Miniforge3-Linux-x86_64.sh",
+			"linux/arm64":  "https://github.com/conda-forge/miniforge/releases/latest/download/Miniforge3-Linux-aarch64.sh",
+			"darwin/amd64": "https://github.com/conda-forge/miniforge/releases/latest/download/Miniforge3-MacOSX-x86_64.sh",
+			"darwin/arm64": "https://github.com/conda-forge/miniforge/releases/latest/download/Miniforge3-MacOSX-arm64.sh",
+		},
+		Args: []string{"-b", "-u", "-p", "~/miniforge3"},
+		Conflicts: map[string]string{
+			"uv": "both manage Python versions and environments, and conda's activated environment overrides the Python uv picks",
+		},
+	},
+	{
+		Name:        "Jupyter",
+		Category:    "Data Science",
+		Description: "JupyterLab and notebooks",
+		Version:     []string{"jupyter", "--version"},
+		Verify:      []string{"jupyter", "--version"},
+		Requires:    []string{"pipx"},
+		Pipx:        []string{"jupyter", "--include-deps"},
+	},
+	{
+		Name:        "Scientific libraries",
+		Category:    "Data Science",
+		Description: "BLAS, LAPACK, HDF5 and a Fortran compiler for building numpy, scipy and friends",
+		Brew:        []string{"openblas", "lapack", "hdf5", "gcc"},
+		Apt:         []string{"libopenblas-dev", "liblapack-dev", "libhdf5-dev", "gfortran"},
+	},
+}
+
+// Presets are bundles offered at the top of the selection screen
+var Presets = []Preset{
+	{
+		Name:        "Data Science",
+		Description: "Python, pipx, a Python manager (uv or conda, see settings), Jupyter and scientific libraries",
+		Items:       []string{"Python", "pipx", "uv", "Jupyter", "Scientific libraries"},
+	},
+	{
+		Name:        "WebAssembly",
+		Description: "Rust with the wasm32 target, wasm-pack, wasmtime, TinyGo and Emscripten",
+		Items:       []string{"Rust", "wasm32 target", "wasm-pack", "wasmtime", "Go", "TinyGo", "Emscripten"},
+	},
+	{
+		Name:        "Android",
+		Description: "Java, the Android command-line tools, SDK and NDK",
+		Items:       []string{"Java", "Android command-line tools", "Android SDK", "Android NDK"},
+	},
+	{
+		Name:        "iOS",
+		Description: "Xcode, CocoaPods and Fastlane",
+		Items:       []string{"Xcode", "CocoaPods", "Fastlane"},
+	},
+	{
+		Name:        "CLI tools",
+		Description: "ripgrep, fd, fzf, bat, eza, jq, yq, delta and btop",
+		Items:       []string{"ripgrep", "fd", "fzf", "bat", "eza", "jq", "yq", "delta", "btop"},
+	},
+	{
+		Name:        "Kubernetes",
+		Description: "kubectl, a local cluster (kind or minikube, see settings) started once as a test, helm, k9s and kubectx",
+		Items:       []string{"kubectl", "kind", "helm", "k9s", "kubectx"},
+	},
+	{
+		Name:        "Linting",
+		Description: "pre-commit with golangci-lint, ruff and clang-format",
+		Items:       []string{"pre-commit", "golangci-lint", "ruff", "clang-format"},
+	},
+}
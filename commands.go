@@ -0,0 +1,846 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"decor/catalog"
+	"decor/config"
+	"decor/doctor"
+	"decor/download"
+	"decor/envfile"
+	"decor/ghactions"
+	"decor/installed"
+	"decor/installer"
+	"decor/manifest"
+	"decor/models"
+	"decor/project"
+	"decor/runner"
+	"decor/sbom"
+	"decor/snapshot"
+	"decor/symbols"
+	"decor/verify"
+)
+
+// configure applies the settings to the installers and returns them, warning when they can't be read
+// or a custom tool can't be used
+func configure() config.Config {
+	cfg, _, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not read settings, using defaults: %v\n", err)
+	}
+	if err := installer.Configure(cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+	}
+	return cfg
+}
+
+// apply installs, updates or removes languages according to choices with the engine the TUI uses, and
+// prints each result. Unless confirmed, what the plan deletes or edits outside decor's own files is
+// confirmed first.
+func apply(languages []string, choices map[string]string, confirmed bool) error {
+	if touches := installer.Touches(installer.Plan(languages, choices)); len(touches) > 0 && !confirmed {
+		if err := confirmTouches(os.Stdin, os.Stdout, languages, touches); err != nil {
+			return err
+		}
+	}
+
+	privileged := installer.Privileged()
+	if privileged.NeedsElevation() && installer.NeedsRoot(languages, choices) {
+		auth := privileged.AuthCommand()
+		auth.Stdin, auth.Stdout, auth.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := runner.CheckAuth(auth.Run()); err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	trackers := installer.NewTrackers(languages, choices)
+	results := installer.Run(ctx, languages, choices, trackers)
+	failed, ran := 0, 0
+	for _, lang := range languages {
+		if choices[lang] == "skip" {
+			continue
+		}
+		ran++
+		fmt.Printf("  %s: %s\n", lang, results[lang])
+		if strings.HasPrefix(results[lang], "error") {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d failed", failed, ran)
+	}
+	return nil
+}
+
+// runList prints the items decor can install on this machine by category, marking the ones it
+// installed, and then the presets
+func runList(args []string) error {
+	configure()
+	managed := make(map[string]bool)
+	records, _ := installed.List()
+	for _, record := range records {
+		managed[record.Name] = true
+	}
+	offered := make(map[string]bool)
+	for _, name := range installer.Offered() {
+		offered[name] = true
+	}
+
+	for _, category := range catalog.Categories() {
+		var lines []string
+		for _, item := range catalog.Items {
+			if item.Category != category || !offered[item.Name] {
+				continue
+			}
+			marker := " "
+			if managed[item.Name] {
+				marker = symbols.OK.String()
+			}
+			lines = append(lines, fmt.Sprintf("  %s %-28s %s", marker, item.Name, item.Description))
+		}
+		if len(lines) > 0 {
+			fmt.Printf("%s\n%s\n\n", category, strings.Join(lines, "\n"))
+		}
+	}
+	fmt.Println("Presets")
+	for _, preset := range installer.OfferedPresets() {
+		fmt.Printf("    %-28s %s\n", preset.Name, strings.Join(installer.PresetItems(preset), ", "))
+	}
+	fmt.Printf("\n%s marks what decor installed\n", symbols.OK)
+	return nil
+}
+
+// statusLine describes an item's installed version and whether it's the latest
+func statusLine(s *installer.InstallationStatus) string {
+	switch {
+	case s.TimedOut:
+		return fmt.Sprintf("%s %s: timed out (%s)", symbols.TimedOut, s.Language, s.Error)
+	case !s.Installed:
+		return fmt.Sprintf("%s %s: not installed", symbols.Failed, s.Language)
+	}
+	managed := ""
+	if s.Managed {
+		managed = ", installed by decor"
+	}
+	if s.LatestVersion == "" || s.Version == s.LatestVersion {
+		return fmt.Sprintf("%s %s: %s%s", symbols.OK, s.Language, s.Version, managed)
+	}
+	return fmt.Sprintf("%s %s: %s %s %s%s", symbols.Update, s.Language, s.Version, symbols.Arrow, s.LatestVersion, managed)
+}
+
+// statusColumns names the columns of decor status, for --sort
+var statusColumns = []string{"item", "installed", "latest", "source", "managed", "arch"}
+
+// runStatus prints a table of every item decor can install, or the items given: what version is
+// installed, the latest, what installed it and whether decor did
+func runStatus(args []string) error {
+	column := slices.Index(statusColumns, *statusSort)
+	if column < 0 {
+		usageError(fmt.Sprintf("status can sort by %s, not %s", strings.Join(statusColumns, ", "), *statusSort))
+	}
+	configure()
+	languages := installer.Offered()
+	if len(args) > 0 {
+		var err error
+		if languages, err = resolveLanguages(args); err != nil {
+			return err
+		}
+	}
+
+	status := installer.Check(languages)
+	statuses := make([]*installer.InstallationStatus, len(languages))
+	for i, lang := range languages {
+		statuses[i] = status[lang]
+	}
+	t := models.StatusTable(statuses)
+	t.SortBy, t.Reverse = column, *statusReverse
+	t.Sort()
+	fmt.Print(t)
+	return nil
+}
+
+// runInstall installs the items and presets given and what they need, leaving what's installed alone
+func runInstall(args []string) error {
+	if len(args) == 0 {
+		usageError("install needs items or presets, e.g. decor install go ripgrep")
+	}
+	configure()
+	languages, err := resolveLanguages(args)
+	if err != nil {
+		return err
+	}
+	languages, _ = installer.WithDependencies(languages)
+
+	choices := make(map[string]string)
+	status := installer.Check(languages)
+	pending := 0
+	for _, lang := range languages {
+		choices[lang] = "install"
+		if s := status[lang]; s.Installed || s.TimedOut {
+			choices[lang] = "skip"
+			fmt.Printf("  %s\n", statusLine(s))
+			continue
+		}
+		pending++
+	}
+	if pending == 0 {
+		fmt.Println("Nothing to install")
+		return nil
+	}
+	return apply(languages, choices, *yes || *dryRun)
+}
+
+// runUpdate updates the items given, or with --all everything decor installed that's outdated
+func runUpdate(args []string) error {
+	if len(args) == 0 && !*updateAll {
+		usageError("update needs items, or --all for everything decor installed")
+	}
+	configure()
+
+	var languages []string
+	choices := make(map[string]string)
+	if *updateAll {
+		outdated, err := installer.Outdated()
+		if err != nil {
+			return err
+		}
+		for _, status := range outdated {
+			languages = append(languages, status.Language)
+		}
+	}
+	var named []string
+	if len(args) > 0 {
+		var err error
+		if named, err = resolveLanguages(args); err != nil {
+			return err
+		}
+	}
+	status := installer.Check(named)
+	for _, lang := range named {
+		if slices.Contains(languages, lang) {
+			continue
+		}
+		if !status[lang].Installed {
+			fmt.Printf("  %s\n", statusLine(status[lang]))
+			continue
+		}
+		languages = append(languages, lang)
+	}
+	if len(languages) == 0 {
+		fmt.Println("Nothing to update")
+		return nil
+	}
+	for _, lang := range languages {
+		choices[lang] = "update"
+	}
+	return apply(languages, choices, *yes || *dryRun)
+}
+
+// runRemove uninstalls the items given, which decor must have installed
+func runRemove(args []string) error {
+	if len(args) == 0 {
+		usageError("remove needs items, e.g. decor remove ripgrep")
+	}
+	configure()
+	languages, err := resolveLanguages(args)
+	if err != nil {
+		return err
+	}
+	choices := make(map[string]string)
+	for _, lang := range languages {
+		if _, ok := installed.Get(lang); !ok {
+			return fmt.Errorf("decor didn't install %s, so it leaves removing it to you", lang)
+		}
+		choices[lang] = "remove"
+	}
+	return apply(languages, choices, *yes || *dryRun)
+}
+
+// runRollback switches items back to the versions in use before their last install or update, which
+// decor keeps beside them
+func runRollback(args []string) error {
+	if len(args) == 0 {
+		usageError("rollback needs items, e.g. decor rollback go")
+	}
+	configure()
+	languages, err := resolveLanguages(args)
+	if err != nil {
+		return err
+	}
+	choices := make(map[string]string)
+	for _, lang := range languages {
+		choices[lang] = "rollback"
+	}
+	privileged := installer.Privileged()
+	if privileged.NeedsElevation() && installer.NeedsRoot(languages, choices) {
+		auth := privileged.AuthCommand()
+		auth.Stdin, auth.Stdout, auth.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := runner.CheckAuth(auth.Run()); err != nil {
+			return err
+		}
+	}
+
+	failed := 0
+	for _, lang := range languages {
+		from, to, err := installer.Rollback(context.Background(), lang)
+		if err != nil {
+			fmt.Printf("  %s: error: %v\n", lang, err)
+			failed++
+			continue
+		}
+		fmt.Printf("  %s: rolled back from %s to %s\n", lang, from, to)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d failed", failed, len(languages))
+	}
+	return nil
+}
+
+// runGC deletes the old versions kept beside what decor installed from archives, all but the --keep (or
+// keep_versions) most recent of each, printing the space reclaimed. What's deleted is confirmed first
+// unless --yes is given.
+func runGC(args []string) error {
+	cfg := configure()
+	keep := cfg.KeepVersions
+	if *gcKeep > 0 {
+		keep = *gcKeep
+	}
+	old, err := installer.OldVersions(keep)
+	if err != nil {
+		return err
+	}
+	if len(old) == 0 {
+		fmt.Println("No old versions to delete.")
+		return nil
+	}
+	var total int64
+	var languages []string
+	choices := make(map[string]string)
+	for _, v := range old {
+		fmt.Printf("  %s %s: %s, %s\n", v.Item, v.Version, v.Dir, doctor.FormatBytes(uint64(v.Size)))
+		total += v.Size
+		if choices[v.Item] == "" {
+			languages = append(languages, v.Item)
+			choices[v.Item] = "gc"
+		}
+	}
+	if !*yes && !*dryRun {
+		fmt.Printf("Type yes to delete these, %s: ", doctor.FormatBytes(uint64(total)))
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if strings.TrimSpace(answer) != "yes" {
+			return fmt.Errorf("not confirmed, nothing was deleted; pass --yes to confirm up front")
+		}
+	}
+	privileged := installer.Privileged()
+	if privileged.NeedsElevation() && installer.NeedsRoot(languages, choices) {
+		auth := privileged.AuthCommand()
+		auth.Stdin, auth.Stdout, auth.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := runner.CheckAuth(auth.Run()); err != nil {
+			return err
+		}
+	}
+
+	freed, err := installer.Prune(context.Background(), old)
+	fmt.Printf("Reclaimed %s\n", doctor.FormatBytes(uint64(freed)))
+	return err
+}
+
+// runProject checks the toolchain the .decor file of the project decor runs in asks for, and offers to
+// install what's missing into the project with mise or asdf, installing mise first if neither is there
+func runProject(args []string) error {
+	configure()
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	path := project.Find(dir)
+	if path == "" {
+		return fmt.Errorf("there's no %s file here or in the directories above", project.File)
+	}
+	entries, err := manifest.Load(path)
+	if err != nil {
+		return err
+	}
+
+	manager := project.Manager()
+	if manager == "" {
+		fmt.Printf("%s installs the project's tools with mise or asdf, and neither is installed.\n", path)
+		if !*yes {
+			fmt.Print("Install mise? [y/N] ")
+			answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			if !strings.EqualFold(strings.TrimSpace(answer), "y") {
+				return nil
+			}
+		}
+		if err := apply([]string{"mise"}, map[string]string{"mise": "install"}, *yes); err != nil {
+			return err
+		}
+		if manager = project.Manager(); manager == "" && !*dryRun {
+			return fmt.Errorf("mise is installed but not on PATH yet; open a new shell and run decor project again")
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fmt.Printf("Checking %s with %s:\n", path, manager)
+	var missing []project.Status
+	for _, s := range project.Check(ctx, installer.Privileged(), manager, entries) {
+		if s.Installed != "" {
+			fmt.Printf("  %s %s %s\n", symbols.OK, s.Name, s.Installed)
+			continue
+		}
+		missing = append(missing, s)
+		want := s.Version
+		if want == "" {
+			want = "any version"
+		}
+		fmt.Printf("  %s %s: missing, %s required\n", symbols.Failed, s.Name, want)
+	}
+	if len(missing) == 0 {
+		fmt.Printf("%s Every tool the project needs is installed\n", symbols.OK)
+	} else {
+		if !*yes {
+			fmt.Printf("Install them into %s with %s? [y/N] ", filepath.Dir(path), manager)
+			answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			if !strings.EqualFold(strings.TrimSpace(answer), "y") {
+				return errReported
+			}
+		}
+		failed := 0
+		for _, s := range missing {
+			version, err := project.Install(ctx, installer.Privileged(), manager, filepath.Dir(path), s)
+			if err != nil {
+				fmt.Printf("  %s: error: %v\n", s.Name, err)
+				failed++
+				continue
+			}
+			fmt.Printf("  %s: installed %s\n", s.Name, version)
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d of %d failed", failed, len(missing))
+		}
+	}
+
+	switch {
+	case *projectEnvrc:
+		return useDirenv(ctx, filepath.Dir(path), manager)
+	case !project.EnvrcActivates(filepath.Dir(path), manager):
+		fmt.Printf("Run decor project --envrc to have direnv switch these versions on whenever you enter %s\n", filepath.Dir(path))
+	}
+	return nil
+}
+
+// useDirenv writes the project's .envrc so direnv loads manager's versions on entering dir, installing
+// direnv first if it's missing
+func useDirenv(ctx context.Context, dir, manager string) error {
+	if !project.HasDirenv() {
+		if !*yes {
+			fmt.Print("direnv isn't installed. Install it? [y/N] ")
+			answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			if !strings.EqualFold(strings.TrimSpace(answer), "y") {
+				return nil
+			}
+		}
+		if err := apply([]string{"direnv"}, map[string]string{"direnv": "install"}, *yes); err != nil {
+			return err
+		}
+		if !project.HasDirenv() && !*dryRun {
+			return fmt.Errorf("direnv is installed but not on PATH yet; open a new shell and run decor project --envrc again")
+		}
+	}
+	if err := project.WriteEnvrc(ctx, installer.Privileged(), dir, manager); err != nil {
+		return err
+	}
+	// decor's environment script loads direnv's hook, so shells that source it pick the .envrc up
+	fmt.Printf("%s %s switches the project's versions on when you enter it\n", symbols.OK, filepath.Join(dir, project.Envrc))
+	return nil
+}
+
+// runDoctor prints the environment checks, failing if any check failed
+func runDoctor(args []string) error {
+	results := doctor.Run()
+	fmt.Print(doctor.Format("Decor Doctor", results))
+	if doctor.HasFailures(results) {
+		return errReported
+	}
+	return nil
+}
+
+// runVerify compiles and runs a tiny program with each installed language, failing if any didn't work
+func runVerify(args []string) error {
+	results := verify.Run(context.Background(), installer.Privileged())
+	if len(results) == 0 {
+		fmt.Println("No languages found to verify")
+		return nil
+	}
+	fmt.Print(doctor.Format("Decor Verify", results))
+	if doctor.HasFailures(results) {
+		return errReported
+	}
+	return nil
+}
+
+// runClean removes what's left in the download cache
+func runClean(args []string) error {
+	freed, dir, err := download.Purge()
+	if err != nil {
+		return fmt.Errorf("could not clean %s: %w", dir, err)
+	}
+	fmt.Printf("Removed %.1f MB from %s\n", float64(freed)/(1<<20), dir)
+	return nil
+}
+
+// runEnv brings decor's environment scripts up to date and prints the line that loads the one for the
+// shell given, or $SHELL's, so eval "$(decor env)" loads it too
+func runEnv(args []string) error {
+	shell := envfile.Shell(os.Getenv("SHELL"), runtime.GOOS)
+	if len(args) > 0 {
+		shell = args[0]
+	}
+	if !slices.Contains(envfile.Shells, shell) {
+		usageError("env takes a shell: %s", strings.Join(envfile.Shells, ", "))
+	}
+	if err := envfile.Refresh(); err != nil {
+		return fmt.Errorf("could not write the environment scripts: %w", err)
+	}
+	line, err := envfile.SourceLine(shell)
+	if err != nil {
+		return err
+	}
+	fmt.Println(line)
+	return nil
+}
+
+// runSnapshot writes what's installed here as JSON, to the file given or stdout, for decor diff to
+// compare another machine with
+func runSnapshot(args []string) error {
+	configure()
+	data, err := json.MarshalIndent(snapshot.Take(installer.Offered()), "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if len(args) == 0 {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(args[0], data, 0o644)
+}
+
+// runSBOM writes what decor installed as an SBOM, to the file given or stdout
+func runSBOM(args []string) error {
+	if !slices.Contains(sbom.Formats, *sbomFormat) {
+		usageError("sbom writes %s, not %s", strings.Join(sbom.Formats, " or "), *sbomFormat)
+	}
+	records, err := installed.List()
+	if err != nil {
+		return fmt.Errorf("could not read what decor installed: %w", err)
+	}
+	host, _ := os.Hostname()
+	data, err := sbom.Generate(*sbomFormat, records, sbom.Machine{Host: host, Distro: sbom.Distro()}, time.Now())
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(args[0], data, 0o644)
+}
+
+// runPins prints [[pins]] tables for the files decor downloaded to install what it installed, or the
+// items named, for pinning them in config.toml
+func runPins(args []string) error {
+	records, err := installed.List()
+	if err != nil {
+		return fmt.Errorf("could not read what decor installed: %w", err)
+	}
+	var pins []config.Pin
+	for _, r := range records {
+		if len(args) > 0 && !slices.ContainsFunc(args, func(name string) bool { return strings.EqualFold(name, r.Name) }) {
+			continue
+		}
+		for _, source := range r.Sources {
+			pins = append(pins, config.Pin{Item: r.Name, Version: r.Version, URL: source.URL, SHA256: source.SHA256})
+		}
+	}
+	if len(pins) == 0 {
+		return fmt.Errorf("decor downloaded nothing it recorded for these items; only files decor downloads itself can be pinned, and only since it recorded them")
+	}
+	fmt.Print("# The files decor downloaded for what it installed. Add these to config.toml to install exactly them,\n# from a mirror if the URLs are changed to one.\n" + config.FormatPins(pins))
+	return nil
+}
+
+// runDiff compares the tools here with a snapshot file, a manifest, or another machine reached over SSH,
+// failing if they differ
+func runDiff(args []string) error {
+	if len(args) != 1 {
+		usageError("diff needs a snapshot, a manifest or a host, e.g. decor diff build-server")
+	}
+	configure()
+	target := args[0]
+	var there snapshot.Snapshot
+	var err error
+	if _, statErr := os.Stat(target); statErr == nil {
+		there, err = snapshot.Load(target)
+	} else {
+		there, err = snapshot.Remote(context.Background(), installer.Privileged(), strings.TrimPrefix(target, "ssh://"))
+	}
+	if err != nil {
+		return err
+	}
+
+	// A manifest only needs the tools it lists checked
+	languages := installer.Offered()
+	if there.Partial {
+		languages = listed(there)
+	}
+	here := snapshot.Take(languages)
+	diffs := snapshot.Diff(here, there)
+
+	if *jsonOutput {
+		if err := json.NewEncoder(os.Stdout).Encode(diffs); err != nil {
+			return err
+		}
+	} else if len(diffs) == 0 {
+		fmt.Printf("%s This machine has the same tools as %s\n", symbols.OK, there.Host)
+	} else {
+		fmt.Printf("Comparing this machine with %s:\n", there.Host)
+		for _, d := range diffs {
+			fmt.Printf("  %s\n", differenceLine(d))
+		}
+	}
+	if len(diffs) > 0 {
+		return errReported
+	}
+	return nil
+}
+
+// differenceLine describes how a tool differs here from there
+func differenceLine(d snapshot.Difference) string {
+	switch d.Kind {
+	case snapshot.Missing:
+		if d.There == "" {
+			return fmt.Sprintf("%s %s: missing here", symbols.Failed, d.Name)
+		}
+		return fmt.Sprintf("%s %s: missing here, %s there", symbols.Failed, d.Name, d.There)
+	case snapshot.Extra:
+		return fmt.Sprintf("%s %s: %s here, not there", symbols.Info, d.Name, d.Here)
+	case snapshot.Older:
+		return fmt.Sprintf("%s %s: %s here, %s there", symbols.Update, d.Name, d.Here, d.There)
+	}
+	return fmt.Sprintf("%s %s: %s here, older %s there", symbols.Warning, d.Name, d.Here, d.There)
+}
+
+// listed returns the items of the catalog a snapshot or manifest names
+func listed(s snapshot.Snapshot) []string {
+	var languages []string
+	for _, item := range s.Items {
+		if found, ok := catalog.Find(item.Name); ok {
+			languages = append(languages, found.Name)
+		}
+	}
+	return languages
+}
+
+// complianceReport is what decor check --json prints
+type complianceReport struct {
+	Manifest   string                `json:"manifest"`
+	Compliant  bool                  `json:"compliant"`
+	Violations []snapshot.Difference `json:"violations"`
+}
+
+// runCheck checks this machine against the manifest given, or the team's from the required_manifest
+// setting, without installing anything, failing with a report if a tool is missing or at the wrong
+// version
+func runCheck(args []string) error {
+	cfg := configure()
+	path := config.ExpandHome(cfg.Manifest)
+	if len(args) > 0 {
+		path = args[0]
+	}
+	if path == "" {
+		usageError("check needs a manifest, or the required_manifest setting")
+	}
+	required, err := snapshot.Load(path)
+	if err != nil {
+		return err
+	}
+	// Only what's required counts, even if the file is a full snapshot
+	required.Partial = true
+
+	here := snapshot.Take(listed(required))
+	violations := snapshot.Diff(here, required)
+	if *jsonOutput {
+		report := complianceReport{Manifest: path, Compliant: len(violations) == 0, Violations: violations}
+		if report.Violations == nil {
+			report.Violations = []snapshot.Difference{}
+		}
+		if err := json.NewEncoder(os.Stdout).Encode(report); err != nil {
+			return err
+		}
+	} else {
+		fmt.Printf("Checking this machine against %s:\n", path)
+		for _, want := range required.Items {
+			i := slices.IndexFunc(violations, func(d snapshot.Difference) bool { return strings.EqualFold(d.Name, want.Name) })
+			if i >= 0 {
+				fmt.Printf("  %s\n", violationLine(violations[i]))
+				continue
+			}
+			for _, have := range here.Items {
+				if strings.EqualFold(have.Name, want.Name) {
+					fmt.Printf("  %s %s %s\n", symbols.OK, have.Name, have.Version)
+				}
+			}
+		}
+		if len(violations) == 0 {
+			fmt.Printf("%s Every required tool is installed\n", symbols.OK)
+		} else {
+			fmt.Printf("%d of %d requirements not met\n", len(violations), len(required.Items))
+		}
+	}
+	if len(violations) > 0 {
+		return errReported
+	}
+	return nil
+}
+
+// violationLine describes a requirement the machine doesn't meet
+func violationLine(d snapshot.Difference) string {
+	if d.Kind == snapshot.Missing {
+		if _, ok := catalog.Find(d.Name); !ok {
+			return fmt.Sprintf("%s %s: not a tool decor knows, so it can't be checked", symbols.Failed, d.Name)
+		}
+		return fmt.Sprintf("%s %s: missing", symbols.Failed, d.Name)
+	}
+	return fmt.Sprintf("%s %s: %s installed, %s required", symbols.Failed, d.Name, d.Here, d.There)
+}
+
+// ciReport is what decor ci-check prints: the runner, and each tool the manifest asks for with what's
+// installed of it
+type ciReport struct {
+	Manifest  string    `json:"manifest"`
+	Host      string    `json:"host"`
+	Platform  string    `json:"platform"` // GOOS/GOARCH
+	Checked   time.Time `json:"checked"`
+	Compliant bool      `json:"compliant"`
+	Tools     []ciTool  `json:"tools"`
+}
+
+// ciTool is one requirement in a ci-check report
+type ciTool struct {
+	Name      string `json:"name"`
+	Required  string `json:"required,omitempty"`  // empty for any version
+	Installed string `json:"installed,omitempty"` // empty when it's missing
+	// Problem is empty when the requirement's met, and otherwise missing, older, newer, unknown for a
+	// version that couldn't be read in full, or unsupported for a tool decor can't check
+	Problem string `json:"problem,omitempty"`
+}
+
+// toolchainManifest returns the manifest command was given, or else the project's .decor file, or else
+// the one the required_manifest setting names, exiting with the usage if there's none
+func toolchainManifest(command string, cfg config.Config, args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+	if dir, err := os.Getwd(); err == nil {
+		if path := project.Find(dir); path != "" {
+			return path
+		}
+	}
+	if cfg.Manifest == "" {
+		usageError("%s needs a manifest, a %s file in the project, or the required_manifest setting", command, project.File)
+	}
+	return config.ExpandHome(cfg.Manifest)
+}
+
+// runCICheck checks a CI runner against the manifest given, the project's .decor file or the
+// required_manifest setting, strictly: each tool must be at the version asked for, read in full. It
+// always prints a JSON report to stdout, says what's wrong on stderr, and fails unless the runner
+// complies, so it can be a pipeline's first step.
+func runCICheck(args []string) error {
+	path := toolchainManifest("ci-check", configure(), args)
+	required, err := snapshot.Load(path)
+	if err != nil {
+		return err
+	}
+	required.Partial = true
+
+	here := snapshot.Take(listed(required))
+	violations := snapshot.Exact(here, required)
+	report := ciReport{Manifest: path, Host: here.Host, Platform: runtime.GOOS + "/" + runtime.GOARCH, Checked: here.Taken, Compliant: len(violations) == 0, Tools: []ciTool{}}
+	for _, want := range required.Items {
+		tool := ciTool{Name: want.Name, Required: want.Version}
+		for _, have := range here.Items {
+			if strings.EqualFold(have.Name, want.Name) {
+				tool.Installed = have.Version
+			}
+		}
+		if i := slices.IndexFunc(violations, func(d snapshot.Difference) bool { return strings.EqualFold(d.Name, want.Name) }); i >= 0 {
+			tool.Problem = violations[i].Kind
+			if _, ok := catalog.Find(want.Name); !ok {
+				tool.Problem = "unsupported"
+			}
+			fmt.Fprintf(os.Stderr, "%s\n", ciLine(tool))
+		}
+		report.Tools = append(report.Tools, tool)
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(report); err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		fmt.Fprintf(os.Stderr, "%d of %d requirements in %s not met\n", len(violations), len(required.Items), path)
+		return errReported
+	}
+	return nil
+}
+
+// ciLine describes a requirement the runner doesn't meet
+func ciLine(t ciTool) string {
+	switch t.Problem {
+	case "unsupported":
+		return fmt.Sprintf("%s %s: not a tool decor knows, so it can't be checked", symbols.Failed, t.Name)
+	case snapshot.Missing:
+		return fmt.Sprintf("%s %s: missing", symbols.Failed, t.Name)
+	case snapshot.Unknown:
+		if t.Installed == "" {
+			return fmt.Sprintf("%s %s: installed, but its version couldn't be read; %s required", symbols.Failed, t.Name, t.Required)
+		}
+		return fmt.Sprintf("%s %s: reports %s, which doesn't say whether it's %s", symbols.Failed, t.Name, t.Installed, t.Required)
+	}
+	return fmt.Sprintf("%s %s: %s installed, %s required", symbols.Failed, t.Name, t.Installed, t.Required)
+}
+
+// runExportGHA prints GitHub Actions steps setting up the toolchain in a manifest, the project's .decor
+// file or the required_manifest setting with the setup-* actions, so CI uses the versions decor does
+func runExportGHA(args []string) error {
+	cfg := configure()
+	path := toolchainManifest("export-gha", cfg, args)
+	required, err := snapshot.Load(path)
+	if err != nil {
+		return err
+	}
+	var entries []manifest.Entry
+	for _, item := range required.Items {
+		entries = append(entries, manifest.Entry{Name: item.Name, Version: item.Version})
+	}
+	steps, err := ghactions.Steps(filepath.Base(path), entries, cfg.JavaVendor)
+	if err != nil {
+		return err
+	}
+	fmt.Print(steps)
+	return nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+
+	"decor/catalog"
+	"decor/completion"
+	"decor/envfile"
+	"decor/sbom"
+	"decor/scaffold"
+)
+
+// commandLine describes decor's flags, subcommands and items for completions and the man page
+func commandLine() completion.CLI {
+	items := catalog.Names()
+	for _, preset := range catalog.Presets {
+		items = append(items, preset.Name)
+	}
+	var subcommands []completion.Command
+	for _, c := range commands() {
+		sub := completion.Command{Name: c.name, Usage: c.summary, Args: commandArgs(c.name, items)}
+		if c.flags != nil {
+			sub.Flags = completion.Flags(c.flags)
+		}
+		for i, f := range sub.Flags {
+			sub.Flags[i].Values = flagValues[f.Name]
+		}
+		subcommands = append(subcommands, sub)
+	}
+	return completion.CLI{
+		Name:    "decor",
+		Summary: "set up a development environment from the terminal",
+		Description: "With no arguments, decor opens a terminal UI to pick languages and tools, checks what's installed, " +
+			"and installs or updates what you choose after showing the plan.\n\n" +
+			"install, update, remove and status do the same from the command line, and with --json and the names of items or " +
+			"presets decor installs without the UI, writing newline-delimited JSON events to stdout for scripts and CI.",
+		Synopsis: synopsis(),
+		Flags:    completion.Flags(flag.CommandLine),
+		Commands: subcommands,
+		Items:    items,
+		Env: []completion.Var{
+			{Name: "DECOR_LANG", Usage: "the language decor's screens use when the language setting is auto, before LC_ALL, LC_MESSAGES and LANG"},
+			{Name: "DECOR_ELEVATOR", Usage: "sudo or doas, for running commands as root when the sudo_policy setting is auto"},
+			{Name: "DECOR_POLICY", Usage: "the organization's policy file, over the system-wide one like /etc/decor/policy.toml"},
+			{Name: "NO_COLOR", Usage: "turns off color, like --no-color"},
+			{Name: "TERM", Usage: "dumb turns on plain ASCII output, like --ascii"},
+			{Name: "GITHUB_TOKEN, GITLAB_TOKEN", Usage: "tokens decor ssh -upload uses to add your key"},
+			{Name: "GITLAB_URL", Usage: "the GitLab instance for decor ssh -upload gitlab, gitlab.com if unset"},
+		},
+	}
+}
+
+// flagValues lists what the subcommands' flags can be set to, for completing them
+var flagValues = map[string][]string{
+	"upload": {"github", "gitlab"},
+	"sort":   statusColumns,
+	"format": sbom.Formats,
+}
+
+// commandArgs lists the words completed as the arguments of the subcommand called name
+func commandArgs(name string, items []string) []string {
+	switch name {
+	case "status", "install", "update", "remove", "rollback", "pins":
+		return items
+	case "new":
+		return scaffold.Languages()
+	case "goversions":
+		return []string{"list", "install", "use"}
+	case "completion":
+		return slices.Concat(completion.Shells, []string{"man"})
+	case "env":
+		return envfile.Shells
+	}
+	return nil
+}
+
+// runCompletion prints the completion script for the shell in args, or the man page
+func runCompletion(args []string) error {
+	if len(args) == 0 {
+		usageError("completion needs a shell: %s, or man for the man page", strings.Join(completion.Shells, ", "))
+	}
+	configure()
+	out, err := completion.Generate(commandLine(), args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Print(out)
+	return nil
+}
@@ -0,0 +1,86 @@
+package completion
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// Flag is a command-line flag, named without dashes
+type Flag struct {
+	Name   string
+	Usage  string
+	Values []string // what the flag's value can be, completed after it; empty for boolean flags
+}
+
+// Command is a subcommand, like doctor or goversions
+type Command struct {
+	Name  string
+	Usage string   // one line on what the command does
+	Args  []string // words completed as the command's arguments, e.g. {"list", "install", "use"}
+	Flags []Flag
+}
+
+// Var is an environment variable the program reads
+type Var struct {
+	Name  string
+	Usage string
+}
+
+// CLI describes a program's command line, for generating shell completions and a man page
+type CLI struct {
+	Name        string
+	Summary     string   // what the program is, for the man page's NAME section
+	Description string   // a paragraph or two on how it's used
+	Synopsis    []string // usage lines, each starting with the program's name
+	Flags       []Flag   // flags of the program itself, allowed anywhere among its items
+	Commands    []Command
+	Items       []string // what the program takes when no command is given, e.g. languages to install
+	Env         []Var
+}
+
+// Shells lists the shells completions can be generated for
+var Shells = []string{"bash", "zsh", "fish"}
+
+// Generate returns the completion script for shell, or the man page for "man"
+func Generate(cli CLI, shell string) (string, error) {
+	switch shell {
+	case "bash":
+		return Bash(cli), nil
+	case "zsh":
+		return Zsh(cli), nil
+	case "fish":
+		return Fish(cli), nil
+	case "man":
+		return Man(cli), nil
+	}
+	return "", fmt.Errorf("unknown shell %q, expected one of %s or man", shell, strings.Join(Shells, ", "))
+}
+
+// Flags describes the flags defined on flags, in the order they sort in
+func Flags(flags *flag.FlagSet) []Flag {
+	var described []Flag
+	flags.VisitAll(func(f *flag.Flag) {
+		described = append(described, Flag{Name: f.Name, Usage: f.Usage})
+	})
+	return described
+}
+
+// dashed writes a flag the way it's typed: single-letter flags with one dash, longer ones with two
+func dashed(name string) string {
+	if len(name) == 1 {
+		return "-" + name
+	}
+	return "--" + name
+}
+
+// valued lists the flags among flags that take a value
+func valued(flags []Flag) []Flag {
+	var withValues []Flag
+	for _, f := range flags {
+		if len(f.Values) > 0 {
+			withValues = append(withValues, f)
+		}
+	}
+	return withValues
+}
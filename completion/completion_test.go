@@ -0,0 +1,58 @@
+package completion
+
+import (
+	"strings"
+	"testing"
+)
+
+var testCLI = CLI{
+	Name:     "tool",
+	Summary:  "does things",
+	Synopsis: []string{"tool [--json] <item>..."},
+	Flags:    []Flag{{Name: "json", Usage: "print JSON"}},
+	Commands: []Command{
+		{Name: "ssh", Usage: "set up SSH", Flags: []Flag{{Name: "upload", Usage: "upload the key", Values: []string{"github", "gitlab"}}}},
+		{Name: "new", Usage: "create a project", Args: []string{"go", "rust"}},
+	},
+	Items: []string{"Go", "GitHub CLI", "C++"},
+	Env:   []Var{{Name: "NO_COLOR", Usage: "turns off color"}},
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		shell string
+		want  []string
+	}{
+		{"bash", []string{"complete -F _tool tool", "-upload|--upload) _tool_reply 'github\ngitlab'", "GitHub CLI\nC++'"}},
+		{"zsh", []string{"#compdef tool", "'--json:print JSON'", "compadd -- 'go' 'rust'", "'GitHub CLI'"}},
+		{"fish", []string{"complete -c tool -n '__fish_seen_subcommand_from ssh' -l upload -x -a '\\'github\\' \\'gitlab\\''", "-a '\\'GitHub CLI\\''"}},
+		{"man", []string{".TH TOOL 1", `\fBtool\fR [\-\-json] <item>...`, `\fB\-\-upload\fR \fIgithub|gitlab\fR`, ".SH ENVIRONMENT\n.TP\n.B NO_COLOR"}},
+	}
+	for _, tt := range tests {
+		out, err := Generate(testCLI, tt.shell)
+		if err != nil {
+			t.Fatalf("Generate(%q): %v", tt.shell, err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(out, want) {
+				t.Errorf("the %s output is missing %q:\n%s", tt.shell, want, out)
+			}
+		}
+	}
+	if _, err := Generate(testCLI, "powershell"); err == nil {
+		t.Error("generated completions for powershell")
+	}
+}
+
+func TestRoff(t *testing.T) {
+	for in, want := range map[string]string{
+		"--dry-run":     `\-\-dry\-run`,
+		".hidden":       `\&.hidden`,
+		`C:\path`:       `C:\epath`,
+		"'quoted' text": `\&'quoted' text`,
+	} {
+		if got := roff(in); got != want {
+			t.Errorf("roff(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
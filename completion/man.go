@@ -0,0 +1,79 @@
+package completion
+
+import (
+	"fmt"
+	"strings"
+)
+
+// roff escapes s for a man page: backslashes and dashes, and a leading dot or quote that would start a
+// request
+func roff(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
+
+// manFlags writes a tagged paragraph per flag
+func manFlags(b *strings.Builder, flags []Flag) {
+	for _, f := range flags {
+		fmt.Fprintf(b, ".TP\n\\fB%s\\fR", roff(dashed(f.Name)))
+		if len(f.Values) > 0 {
+			fmt.Fprintf(b, " \\fI%s\\fR", roff(strings.Join(f.Values, "|")))
+		}
+		fmt.Fprintf(b, "\n%s\n", roff(f.Usage))
+	}
+}
+
+// Man returns a man page in roff, for man -l or saving as decor.1 in a man directory
+func Man(cli CLI) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, ".TH %s 1 \"\" \"%s\" \"User Commands\"\n", strings.ToUpper(cli.Name), cli.Name)
+	fmt.Fprintf(&b, ".SH NAME\n%s \\- %s\n", cli.Name, roff(cli.Summary))
+
+	b.WriteString(".SH SYNOPSIS\n.nf\n")
+	for _, line := range cli.Synopsis {
+		name, rest, _ := strings.Cut(strings.TrimSpace(line), " ")
+		fmt.Fprintf(&b, "\\fB%s\\fR %s\n", name, roff(rest))
+	}
+	b.WriteString(".fi\n")
+
+	b.WriteString(".SH DESCRIPTION\n")
+	for i, paragraph := range strings.Split(cli.Description, "\n\n") {
+		if i > 0 {
+			b.WriteString(".PP\n")
+		}
+		b.WriteString(roff(paragraph) + "\n")
+	}
+
+	b.WriteString(".SH OPTIONS\n")
+	manFlags(&b, cli.Flags)
+
+	b.WriteString(".SH COMMANDS\n")
+	for _, c := range cli.Commands {
+		fmt.Fprintf(&b, ".TP\n.B %s\n%s\n", c.Name, roff(c.Usage))
+		if len(c.Args) > 0 {
+			fmt.Fprintf(&b, "Takes %s.\n", roff(strings.Join(c.Args, ", ")))
+		}
+		if len(c.Flags) > 0 {
+			b.WriteString(".RS\n")
+			manFlags(&b, c.Flags)
+			b.WriteString(".RE\n")
+		}
+	}
+
+	if len(cli.Items) > 0 {
+		b.WriteString(".SH ITEMS\n")
+		b.WriteString(roff(strings.Join(cli.Items, ", ")) + "\n")
+	}
+
+	if len(cli.Env) > 0 {
+		b.WriteString(".SH ENVIRONMENT\n")
+		for _, v := range cli.Env {
+			fmt.Fprintf(&b, ".TP\n.B %s\n%s\n", v.Name, roff(v.Usage))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,206 @@
+package completion
+
+import (
+	"fmt"
+	"strings"
+)
+
+// bashWords joins words for compgen -W, one per line so names with spaces stay whole
+func bashWords(words []string) string {
+	return shellQuote(strings.Join(words, "\n"))
+}
+
+// shellQuote single-quotes s for sh-like shells
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// flagWords lists flags as they're typed
+func flagWords(flags []Flag) []string {
+	words := make([]string, len(flags))
+	for i, f := range flags {
+		words[i] = dashed(f.Name)
+	}
+	return words
+}
+
+// Bash returns a bash completion script, loaded with source <(decor completion bash)
+func Bash(cli CLI) string {
+	fn := "_" + cli.Name
+	var b strings.Builder
+	fmt.Fprintf(&b, "# bash completion for %s, generated by `%s completion bash`\n\n", cli.Name, cli.Name)
+	fmt.Fprintf(&b, "%s_reply() {\n", fn)
+	b.WriteString("    local IFS=$'\\n'\n")
+	b.WriteString("    COMPREPLY=($(compgen -W \"$1\" -- \"$2\"))\n")
+	b.WriteString("    ((${#COMPREPLY[@]})) && COMPREPLY=($(printf '%q\\n' \"${COMPREPLY[@]}\"))\n")
+	b.WriteString("}\n\n")
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("    local cur=\"${COMP_WORDS[COMP_CWORD]}\" prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+	b.WriteString("    local command=\"\" i\n")
+	b.WriteString("    for ((i = 1; i < COMP_CWORD; i++)); do\n")
+	b.WriteString("        case \"${COMP_WORDS[i]}\" in\n")
+	b.WriteString("            -*) ;;\n")
+	b.WriteString("            *) command=\"${COMP_WORDS[i]}\"; break ;;\n")
+	b.WriteString("        esac\n")
+	b.WriteString("    done\n\n")
+
+	b.WriteString("    case \"$command\" in\n")
+	for _, c := range cli.Commands {
+		fmt.Fprintf(&b, "        %s)\n", c.Name)
+		if flags := valued(c.Flags); len(flags) > 0 {
+			b.WriteString("            case \"$prev\" in\n")
+			for _, f := range flags {
+				fmt.Fprintf(&b, "                -%s|--%s) %s_reply %s \"$cur\"; return ;;\n", f.Name, f.Name, fn, bashWords(f.Values))
+			}
+			b.WriteString("            esac\n")
+		}
+		fmt.Fprintf(&b, "            %s_reply %s \"$cur\"\n", fn, bashWords(append(flagWords(c.Flags), c.Args...)))
+		b.WriteString("            return ;;\n")
+	}
+	b.WriteString("    esac\n\n")
+
+	var commands []string
+	for _, c := range cli.Commands {
+		commands = append(commands, c.Name)
+	}
+	// Commands only come first; after an item come more items
+	b.WriteString("    if [[ -z \"$command\" ]]; then\n")
+	fmt.Fprintf(&b, "        %s_reply %s \"$cur\"\n", fn, bashWords(append(append(flagWords(cli.Flags), commands...), cli.Items...)))
+	b.WriteString("    else\n")
+	fmt.Fprintf(&b, "        %s_reply %s \"$cur\"\n", fn, bashWords(append(flagWords(cli.Flags), cli.Items...)))
+	b.WriteString("    fi\n")
+	b.WriteString("}\n\n")
+	fmt.Fprintf(&b, "complete -F %s %s\n", fn, cli.Name)
+	return b.String()
+}
+
+// zshDescribed formats name:usage pairs for _describe, escaping the colons in names
+func zshDescribed(name, usage string) string {
+	return shellQuote(strings.ReplaceAll(name, ":", `\:`) + ":" + usage)
+}
+
+// zshFlags writes the flags array for _describe
+func zshFlags(b *strings.Builder, indent string, flags []Flag) {
+	fmt.Fprintf(b, "%slocal -a flags=(\n", indent)
+	for _, f := range flags {
+		fmt.Fprintf(b, "%s    %s\n", indent, zshDescribed(dashed(f.Name), f.Usage))
+	}
+	fmt.Fprintf(b, "%s)\n", indent)
+}
+
+// zshWords quotes each of words for zsh
+func zshWords(words []string) string {
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		quoted[i] = shellQuote(w)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// Zsh returns a zsh completion script, saved as _decor somewhere on $fpath
+func Zsh(cli CLI) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "#compdef %s\n# zsh completion for %s, generated by `%s completion zsh`\n\n", cli.Name, cli.Name, cli.Name)
+	fmt.Fprintf(&b, "_%s() {\n", cli.Name)
+	b.WriteString("    local command i\n")
+	b.WriteString("    for ((i = 2; i < CURRENT; i++)); do\n")
+	b.WriteString("        if [[ $words[i] != -* ]]; then\n")
+	b.WriteString("            command=$words[i]\n")
+	b.WriteString("            break\n")
+	b.WriteString("        fi\n")
+	b.WriteString("    done\n\n")
+
+	b.WriteString("    case $command in\n")
+	for _, c := range cli.Commands {
+		fmt.Fprintf(&b, "        %s)\n", c.Name)
+		for _, f := range valued(c.Flags) {
+			fmt.Fprintf(&b, "            if [[ $words[CURRENT-1] == (-%s|--%s) ]]; then\n", f.Name, f.Name)
+			fmt.Fprintf(&b, "                compadd -- %s\n", zshWords(f.Values))
+			b.WriteString("                return\n")
+			b.WriteString("            fi\n")
+		}
+		if len(c.Flags) > 0 {
+			zshFlags(&b, "            ", c.Flags)
+			b.WriteString("            [[ $PREFIX == -* ]] && _describe -t flags flag flags\n")
+		}
+		if len(c.Args) > 0 {
+			fmt.Fprintf(&b, "            compadd -- %s\n", zshWords(c.Args))
+		}
+		b.WriteString("            return ;;\n")
+	}
+	b.WriteString("    esac\n\n")
+
+	zshFlags(&b, "    ", cli.Flags)
+	fmt.Fprintf(&b, "    local -a items=(%s)\n", zshWords(cli.Items))
+	b.WriteString("    if [[ $PREFIX == -* ]]; then\n")
+	b.WriteString("        _describe -t flags flag flags\n")
+	b.WriteString("        return\n")
+	b.WriteString("    fi\n")
+	b.WriteString("    if [[ -z $command ]]; then\n")
+	b.WriteString("        local -a commands=(\n")
+	for _, c := range cli.Commands {
+		fmt.Fprintf(&b, "            %s\n", zshDescribed(c.Name, c.Usage))
+	}
+	b.WriteString("        )\n")
+	b.WriteString("        _describe -t commands command commands\n")
+	b.WriteString("    fi\n")
+	b.WriteString("    compadd -a items\n")
+	b.WriteString("}\n\n")
+	fmt.Fprintf(&b, "_%s \"$@\"\n", cli.Name)
+	return b.String()
+}
+
+// fishQuote single-quotes s for fish
+func fishQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
+
+// fishFlag writes complete's options for a flag
+func fishFlag(f Flag) string {
+	opts := "-l " + f.Name
+	if len(f.Name) == 1 {
+		opts = "-s " + f.Name
+	}
+	if len(f.Values) > 0 {
+		// Quoted twice: once for complete, once for the list it splits
+		quoted := make([]string, len(f.Values))
+		for i, v := range f.Values {
+			quoted[i] = fishQuote(v)
+		}
+		opts += " -x -a " + fishQuote(strings.Join(quoted, " "))
+	}
+	return opts + " -d " + fishQuote(f.Usage)
+}
+
+// Fish returns a fish completion script, saved as ~/.config/fish/completions/decor.fish
+func Fish(cli CLI) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# fish completion for %s, generated by `%s completion fish`\n\n", cli.Name, cli.Name)
+	var commands []string
+	for _, c := range cli.Commands {
+		commands = append(commands, c.Name)
+	}
+	noCommand := fmt.Sprintf("not __fish_seen_subcommand_from %s", strings.Join(commands, " "))
+
+	fmt.Fprintf(&b, "complete -c %s -f\n", cli.Name)
+	for _, f := range cli.Flags {
+		fmt.Fprintf(&b, "complete -c %s -n %s %s\n", cli.Name, fishQuote(noCommand), fishFlag(f))
+	}
+	for _, c := range cli.Commands {
+		fmt.Fprintf(&b, "complete -c %s -n %s -a %s -d %s\n", cli.Name, fishQuote("__fish_use_subcommand"), c.Name, fishQuote(c.Usage))
+	}
+	for _, item := range cli.Items {
+		fmt.Fprintf(&b, "complete -c %s -n %s -a %s\n", cli.Name, fishQuote(noCommand), fishQuote(fishQuote(item)))
+	}
+	for _, c := range cli.Commands {
+		seen := fishQuote("__fish_seen_subcommand_from " + c.Name)
+		for _, f := range c.Flags {
+			fmt.Fprintf(&b, "complete -c %s -n %s %s\n", cli.Name, seen, fishFlag(f))
+		}
+		for _, arg := range c.Args {
+			fmt.Fprintf(&b, "complete -c %s -n %s -a %s\n", cli.Name, seen, fishQuote(fishQuote(arg)))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,501 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"decor/extract"
+	"decor/paths"
+)
+
+// Config holds the user's preferences, persisted to config.toml in the config directory
+type Config struct {
+	PackageManager string        // "auto", "brew" or "apt"
+	InstallPrefix  string        // where tarball-based toolchains are extracted
+	SudoPolicy     string        // "auto", "sudo", "doas" or "none"; auto honors DECOR_ELEVATOR, then whichever is installed
+	Theme          string        // "default", "mono" for no color, or "ascii" for no color or emoji
+	Language       string        // "auto" to follow LANG, or a locale decor has messages for like "en" or "es"
+	Mouse          bool          // click to pick items and scroll with the wheel; decor then runs full screen
+	Telemetry      bool          // opt-in only, off by default
+	LocalMetrics   bool          // count installs, failures and durations in a local file for decor stats; opt-in, never sent anywhere
+	Channel        string        // "stable" or "lts"
+	UpdateCheck    string        // how often decor checks the tools it installed for updates on start: "daily", "weekly" or "never"
+	PythonManager  string        // "uv" or "conda", used by presets that need one
+	LocalCluster   string        // "kind" or "minikube", used by the Kubernetes preset
+	CppCompiler    string        // "gcc" or "clang", optionally versioned like "gcc-13"; what cc and c++ point at on Linux
+	CppPackages    string        // "vcpkg" or "conan", offered after installing C++
+	JavaVendor     string        // JDK distribution: "temurin", "zulu", "corretto" or "graalvm"
+	JavaVersion    string        // JDK feature release, e.g. "21", or "latest"
+	StarterConfigs bool          // write starter configs for tools like tmux, never over existing files
+	WSLWinget      bool          // in WSL, install GUI tools like terminals on the Windows side with winget
+	ProjectDir     string        // where hello-world projects are scaffolded after an install
+	DownloadLimit  int64         // MB the plan may download before it warns; 0 for no limit
+	DownloadConns  int           // ranged requests large downloads are split into at once; 1 downloads with one
+	KeepVersions   int           // versions of each archive install decor gc keeps, the one in use included
+	DetectTimeout  time.Duration // how long a version check may run before it's killed
+	InstallTimeout time.Duration // how long a single language's install may run before it's killed
+	HTTPTimeout    time.Duration // how long each attempt at a version lookup, checksum or other small request may take
+	DownloadIdle   time.Duration // how long a download may receive nothing before it's retried; 0 waits on
+	MetadataMaxAge time.Duration // how long a cached version lookup is used before it's checked with the server again
+	Manifest       string        // the team's required tools, a manifest file decor check compares the machine with
+
+	// KeyStyle and Keys come from the [keys] table: a preset ("vim", "emacs" or "arrows") and the
+	// actions it remaps, e.g. up = ["up", "ctrl+k"]
+	KeyStyle string
+	Keys     map[string][]string
+
+	// Components come from the [components] table: the optional components picked for each item, by
+	// its lowercased name, e.g. python = ["venv", "tkinter"]. Items missing from it get their defaults.
+	Components map[string][]string
+
+	// Tools come from [[tools]] tables, for installing what decor doesn't know about
+	Tools []Tool
+
+	// Pins come from [[pins]] tables: the exact files items' installs download and their checksums,
+	// for installs that can be audited and reproduced
+	Pins []Pin
+}
+
+// Tool is a custom tool from a [[tools]] table, listed and installed like decor's own items
+type Tool struct {
+	Name        string
+	Description string
+	Category    string   // heading it's listed under, Custom if empty
+	Version     []string // command printing the installed version, {name, "--version"} if empty; its program is what's installed
+	Repo        string   // GitHub repository publishing the tool's binaries as release assets, as owner/name
+	Asset       string   // glob picking the release asset, with {os} and {arch} for the platform; empty picks by name
+	GoInstall   string   // package go install builds, e.g. example.com/cmd/tool@v1.2.0; @latest if no version
+	Pipx        string   // Python package installed with pipx
+	Npm         string   // npm package installed globally
+	Cargo       string   // crate installed with cargo install
+	Archive     string   // tarball or zip URL, with {os} and {arch} for the platform
+	Checksum    string   // URL of the archive's published SHA-256
+	ArchiveType string   // "tar.gz", "tar.xz", "tar.zst" or "zip"; guessed from Archive if empty
+	Strip       int      // leading directories dropped from the archive's paths
+	Dir         string   // where the archive is unpacked, ~/.local/opt/<name> if empty
+	Links       []string // programs in Dir linked into ~/.local/bin, Version's program if empty
+}
+
+// Pin is an artifact from a [[pins]] table. It stands in for any download of the item with the same
+// file name, so its URL can be an internal mirror for machines without internet access.
+type Pin struct {
+	Item    string // the item it's for, by name
+	Version string // the item's version once installed, checked after the install; empty for any
+	URL     string // where the file is downloaded from
+	SHA256  string // the file's SHA-256, which the download must match
+}
+
+// Default returns the preferences used before the user changes anything
+func Default() Config {
+	return Config{
+		PackageManager: "auto",
+		InstallPrefix:  "/usr/local",
+		SudoPolicy:     "auto",
+		Theme:          "default",
+		Language:       "auto",
+		Telemetry:      false,
+		Channel:        "stable",
+		UpdateCheck:    "weekly",
+		PythonManager:  "uv",
+		LocalCluster:   "kind",
+		CppCompiler:    "gcc",
+		CppPackages:    "vcpkg",
+		JavaVendor:     "temurin",
+		JavaVersion:    "21",
+		ProjectDir:     "~/projects",
+		DownloadConns:  1,
+		KeepVersions:   2,
+		DetectTimeout:  10 * time.Second,
+		InstallTimeout: 30 * time.Minute,
+		HTTPTimeout:    30 * time.Second,
+		DownloadIdle:   time.Minute,
+		MetadataMaxAge: 6 * time.Hour,
+		KeyStyle:       "vim",
+	}
+}
+
+// Path returns the location of the config file
+func Path() (string, error) {
+	dir, err := paths.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.toml"), nil
+}
+
+// legacyPath returns ~/.decor/config.toml, where older versions saved the config file
+func legacyPath() (string, error) {
+	dir, err := paths.LegacyDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.toml"), nil
+}
+
+// Load reads the config file, returning the defaults and false if it doesn't exist yet
+func Load() (Config, bool, error) {
+	cfg := Default()
+
+	path, err := Path()
+	if err != nil {
+		return cfg, false, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		data, err = migrateLegacy(path)
+	}
+	if os.IsNotExist(err) {
+		return cfg, false, nil
+	}
+	if err != nil {
+		return cfg, false, err
+	}
+
+	doc, err := parseTOML(string(data))
+	if err != nil {
+		return cfg, true, fmt.Errorf("%s: %w", path, err)
+	}
+
+	cfg.PackageManager = doc.getString("package_manager", cfg.PackageManager)
+	cfg.InstallPrefix = doc.getString("install_prefix", cfg.InstallPrefix)
+	cfg.SudoPolicy = doc.getString("sudo_policy", cfg.SudoPolicy)
+	cfg.Theme = doc.getString("theme", cfg.Theme)
+	cfg.Language = doc.getString("language", cfg.Language)
+	cfg.Mouse = doc.getBool("mouse", cfg.Mouse)
+	cfg.Telemetry = doc.getBool("telemetry", cfg.Telemetry)
+	cfg.LocalMetrics = doc.getBool("local_metrics", cfg.LocalMetrics)
+	cfg.Channel = doc.getString("channel", cfg.Channel)
+	cfg.UpdateCheck = doc.getString("update_check", cfg.UpdateCheck)
+	cfg.PythonManager = doc.getString("python_manager", cfg.PythonManager)
+	cfg.LocalCluster = doc.getString("local_cluster", cfg.LocalCluster)
+	cfg.CppCompiler = doc.getString("cpp_compiler", cfg.CppCompiler)
+	cfg.CppPackages = doc.getString("cpp_package_manager", cfg.CppPackages)
+	cfg.JavaVendor = doc.getString("java_vendor", cfg.JavaVendor)
+	cfg.JavaVersion = doc.getString("java_version", cfg.JavaVersion)
+	cfg.StarterConfigs = doc.getBool("starter_configs", cfg.StarterConfigs)
+	cfg.WSLWinget = doc.getBool("wsl_winget", cfg.WSLWinget)
+	cfg.ProjectDir = doc.getString("project_dir", cfg.ProjectDir)
+	cfg.Manifest = doc.getString("required_manifest", cfg.Manifest)
+	if cfg.DownloadLimit, err = doc.getInt("download_limit_mb", cfg.DownloadLimit); err != nil {
+		return cfg, true, fmt.Errorf("%s: %w", path, err)
+	}
+	conns, err := doc.getInt("download_connections", int64(cfg.DownloadConns))
+	if err != nil {
+		return cfg, true, fmt.Errorf("%s: %w", path, err)
+	}
+	cfg.DownloadConns = max(1, int(conns))
+	keep, err := doc.getInt("keep_versions", int64(cfg.KeepVersions))
+	if err != nil {
+		return cfg, true, fmt.Errorf("%s: %w", path, err)
+	}
+	cfg.KeepVersions = max(1, int(keep))
+	if cfg.DetectTimeout, err = doc.getDuration("detect_timeout", cfg.DetectTimeout); err != nil {
+		return cfg, true, fmt.Errorf("%s: %w", path, err)
+	}
+	if cfg.InstallTimeout, err = doc.getDuration("install_timeout", cfg.InstallTimeout); err != nil {
+		return cfg, true, fmt.Errorf("%s: %w", path, err)
+	}
+	if cfg.HTTPTimeout, err = doc.getDuration("http_timeout", cfg.HTTPTimeout); err != nil {
+		return cfg, true, fmt.Errorf("%s: %w", path, err)
+	}
+	// Unlike the other timeouts, 0 turns this one off
+	if idle, _ := doc["download_idle_timeout"].(string); idle == "0" || idle == "0s" {
+		cfg.DownloadIdle = 0
+	} else if cfg.DownloadIdle, err = doc.getDuration("download_idle_timeout", cfg.DownloadIdle); err != nil {
+		return cfg, true, fmt.Errorf("%s: %w", path, err)
+	}
+	if cfg.MetadataMaxAge, err = doc.getDuration("metadata_max_age", cfg.MetadataMaxAge); err != nil {
+		return cfg, true, fmt.Errorf("%s: %w", path, err)
+	}
+	if keys, ok := doc["keys"].(table); ok {
+		cfg.KeyStyle = keys.getString("style", cfg.KeyStyle)
+		for action := range keys {
+			if action == "style" {
+				continue
+			}
+			bound, err := keys.getStrings(action)
+			if err != nil {
+				return cfg, true, fmt.Errorf("%s: keys.%w", path, err)
+			}
+			if cfg.Keys == nil {
+				cfg.Keys = make(map[string][]string)
+			}
+			cfg.Keys[action] = bound
+		}
+	}
+	if components, ok := doc["components"].(table); ok {
+		cfg.Components = make(map[string][]string)
+		for item := range components {
+			picked, err := components.getStrings(item)
+			if err != nil {
+				return cfg, true, fmt.Errorf("%s: components.%w", path, err)
+			}
+			cfg.Components[item] = picked
+		}
+	}
+	if cfg.Tools, err = parseTools(doc); err != nil {
+		return cfg, true, fmt.Errorf("%s: %w", path, err)
+	}
+	if cfg.Pins, err = parsePins(doc); err != nil {
+		return cfg, true, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, true, nil
+}
+
+// parseTools reads the [[tools]] tables
+func parseTools(doc table) ([]Tool, error) {
+	entries, _ := doc["tools"].([]table)
+	tools := make([]Tool, 0, len(entries))
+	for i, entry := range entries {
+		tool := Tool{
+			Name:        entry.getString("name", ""),
+			Description: entry.getString("description", ""),
+			Category:    entry.getString("category", ""),
+			Repo:        entry.getString("repo", ""),
+			Asset:       entry.getString("asset", ""),
+			GoInstall:   entry.getString("go_install", ""),
+			Pipx:        entry.getString("pipx", ""),
+			Npm:         entry.getString("npm", ""),
+			Cargo:       entry.getString("cargo", ""),
+			Archive:     entry.getString("archive", ""),
+			Checksum:    entry.getString("checksum", ""),
+			ArchiveType: entry.getString("archive_type", ""),
+			Dir:         entry.getString("dir", ""),
+		}
+		for _, list := range []struct {
+			key    string
+			values *[]string
+		}{{"version", &tool.Version}, {"links", &tool.Links}} {
+			if _, ok := entry[list.key]; ok {
+				var err error
+				if *list.values, err = entry.getStrings(list.key); err != nil {
+					return nil, fmt.Errorf("tools[%d].%w", i, err)
+				}
+			}
+		}
+		strip, err := entry.getInt("strip", 0)
+		if err != nil {
+			return nil, fmt.Errorf("tools[%d].%w", i, err)
+		}
+		tool.Strip = int(strip)
+		switch {
+		case tool.Name == "":
+			return nil, fmt.Errorf("tools[%d] has no name", i)
+		case tool.Repo == "" && tool.GoInstall == "" && tool.Pipx == "" && tool.Npm == "" && tool.Cargo == "" && tool.Archive == "":
+			return nil, fmt.Errorf("tools[%d] (%s) says nothing about how to install it; set repo, go_install, pipx, npm, cargo or archive", i, tool.Name)
+		case tool.ArchiveType != "" && !slices.Contains(extract.Types, tool.ArchiveType):
+			return nil, fmt.Errorf("tools[%d].archive_type: expected one of %s, got %q", i, strings.Join(extract.Types, ", "), tool.ArchiveType)
+		}
+		tools = append(tools, tool)
+	}
+	if len(tools) == 0 {
+		return nil, nil
+	}
+	return tools, nil
+}
+
+// sha256Pattern matches a hex SHA-256
+var sha256Pattern = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
+// parsePins reads the [[pins]] tables
+func parsePins(doc table) ([]Pin, error) {
+	entries, _ := doc["pins"].([]table)
+	var pins []Pin
+	for i, entry := range entries {
+		pin := Pin{
+			Item:    entry.getString("item", ""),
+			Version: entry.getString("version", ""),
+			URL:     entry.getString("url", ""),
+			SHA256:  strings.ToLower(entry.getString("sha256", "")),
+		}
+		switch {
+		case pin.Item == "":
+			return nil, fmt.Errorf("pins[%d] has no item", i)
+		case pin.URL == "":
+			return nil, fmt.Errorf("pins[%d] (%s) has no url", i, pin.Item)
+		case !sha256Pattern.MatchString(pin.SHA256):
+			return nil, fmt.Errorf("pins[%d].sha256: expected 64 hex digits, got %q", i, pin.SHA256)
+		}
+		pins = append(pins, pin)
+	}
+	return pins, nil
+}
+
+// FormatPins writes pins as [[pins]] tables
+func FormatPins(pins []Pin) string {
+	var b strings.Builder
+	for _, pin := range pins {
+		b.WriteString("\n[[pins]]\n")
+		fmt.Fprintf(&b, "item = %s\n", quote(pin.Item))
+		if pin.Version != "" {
+			fmt.Fprintf(&b, "version = %s\n", quote(pin.Version))
+		}
+		fmt.Fprintf(&b, "url = %s\n", quote(pin.URL))
+		fmt.Fprintf(&b, "sha256 = %s\n", quote(pin.SHA256))
+	}
+	return b.String()
+}
+
+// migrateLegacy moves a config file saved by an older version to path and returns its contents. The
+// legacy directory is removed too once it's empty.
+func migrateLegacy(path string) ([]byte, error) {
+	legacy, err := legacyPath()
+	if err != nil {
+		return nil, os.ErrNotExist
+	}
+	data, err := os.ReadFile(legacy)
+	if err != nil {
+		return nil, err
+	}
+
+	// A failed move leaves the legacy file in place to be read again next time
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return data, nil
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return data, nil
+	}
+	if os.Remove(legacy) == nil {
+		os.Remove(filepath.Dir(legacy))
+	}
+	return data, nil
+}
+
+// Save writes the config file, creating the config directory if needed
+func Save(cfg Config) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString("# decor configuration, edit here or press s in decor\n\n")
+	fmt.Fprintf(&b, "package_manager = %s\n", quote(cfg.PackageManager))
+	fmt.Fprintf(&b, "install_prefix = %s\n", quote(cfg.InstallPrefix))
+	fmt.Fprintf(&b, "sudo_policy = %s\n", quote(cfg.SudoPolicy))
+	fmt.Fprintf(&b, "theme = %s\n", quote(cfg.Theme))
+	fmt.Fprintf(&b, "language = %s\n", quote(cfg.Language))
+	fmt.Fprintf(&b, "mouse = %t\n", cfg.Mouse)
+	fmt.Fprintf(&b, "telemetry = %t\n", cfg.Telemetry)
+	fmt.Fprintf(&b, "local_metrics = %t\n", cfg.LocalMetrics)
+	fmt.Fprintf(&b, "channel = %s\n", quote(cfg.Channel))
+	fmt.Fprintf(&b, "update_check = %s\n", quote(cfg.UpdateCheck))
+	fmt.Fprintf(&b, "python_manager = %s\n", quote(cfg.PythonManager))
+	fmt.Fprintf(&b, "local_cluster = %s\n", quote(cfg.LocalCluster))
+	fmt.Fprintf(&b, "cpp_compiler = %s\n", quote(cfg.CppCompiler))
+	fmt.Fprintf(&b, "cpp_package_manager = %s\n", quote(cfg.CppPackages))
+	fmt.Fprintf(&b, "java_vendor = %s\n", quote(cfg.JavaVendor))
+	fmt.Fprintf(&b, "java_version = %s\n", quote(cfg.JavaVersion))
+	fmt.Fprintf(&b, "starter_configs = %t\n", cfg.StarterConfigs)
+	fmt.Fprintf(&b, "wsl_winget = %t\n", cfg.WSLWinget)
+	fmt.Fprintf(&b, "project_dir = %s\n", quote(cfg.ProjectDir))
+	fmt.Fprintf(&b, "download_limit_mb = %d\n", cfg.DownloadLimit)
+	fmt.Fprintf(&b, "download_connections = %d\n", cfg.DownloadConns)
+	fmt.Fprintf(&b, "keep_versions = %d\n", cfg.KeepVersions)
+	fmt.Fprintf(&b, "detect_timeout = %s\n", quote(cfg.DetectTimeout.String()))
+	fmt.Fprintf(&b, "install_timeout = %s\n", quote(cfg.InstallTimeout.String()))
+	fmt.Fprintf(&b, "http_timeout = %s\n", quote(cfg.HTTPTimeout.String()))
+	fmt.Fprintf(&b, "download_idle_timeout = %s\n", quote(cfg.DownloadIdle.String()))
+	fmt.Fprintf(&b, "metadata_max_age = %s\n", quote(cfg.MetadataMaxAge.String()))
+	fmt.Fprintf(&b, "required_manifest = %s\n", quote(cfg.Manifest))
+
+	b.WriteString("\n[keys]\n")
+	fmt.Fprintf(&b, "style = %s\n", quote(cfg.KeyStyle))
+	actions := make([]string, 0, len(cfg.Keys))
+	for action := range cfg.Keys {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	for _, action := range actions {
+		quoted := make([]string, len(cfg.Keys[action]))
+		for i, k := range cfg.Keys[action] {
+			quoted[i] = quote(k)
+		}
+		fmt.Fprintf(&b, "%s = [%s]\n", action, strings.Join(quoted, ", "))
+	}
+
+	if len(cfg.Components) > 0 {
+		b.WriteString("\n[components]\n")
+		items := make([]string, 0, len(cfg.Components))
+		for item := range cfg.Components {
+			items = append(items, item)
+		}
+		sort.Strings(items)
+		for _, item := range items {
+			quoted := make([]string, len(cfg.Components[item]))
+			for i, name := range cfg.Components[item] {
+				quoted[i] = quote(name)
+			}
+			// Item names like node.js need quoting to be one key
+			fmt.Fprintf(&b, "%s = [%s]\n", quote(item), strings.Join(quoted, ", "))
+		}
+	}
+
+	for _, tool := range cfg.Tools {
+		b.WriteString("\n[[tools]]\n")
+		fmt.Fprintf(&b, "name = %s\n", quote(tool.Name))
+		for _, field := range [][2]string{
+			{"description", tool.Description},
+			{"category", tool.Category},
+			{"repo", tool.Repo},
+			{"asset", tool.Asset},
+			{"go_install", tool.GoInstall},
+			{"pipx", tool.Pipx},
+			{"npm", tool.Npm},
+			{"cargo", tool.Cargo},
+			{"archive", tool.Archive},
+			{"checksum", tool.Checksum},
+			{"archive_type", tool.ArchiveType},
+			{"dir", tool.Dir},
+		} {
+			if field[1] != "" {
+				fmt.Fprintf(&b, "%s = %s\n", field[0], quote(field[1]))
+			}
+		}
+		if tool.Strip > 0 {
+			fmt.Fprintf(&b, "strip = %d\n", tool.Strip)
+		}
+		for _, list := range []struct {
+			key    string
+			values []string
+		}{{"version", tool.Version}, {"links", tool.Links}} {
+			if len(list.values) == 0 {
+				continue
+			}
+			quoted := make([]string, len(list.values))
+			for i, value := range list.values {
+				quoted[i] = quote(value)
+			}
+			fmt.Fprintf(&b, "%s = [%s]\n", list.key, strings.Join(quoted, ", "))
+		}
+	}
+	b.WriteString(FormatPins(cfg.Pins))
+
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
+
+// Prefix returns the install prefix with a leading ~ expanded
+func (c Config) Prefix() string {
+	return ExpandHome(c.InstallPrefix)
+}
+
+// ExpandHome replaces a leading ~ with the user's home directory
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
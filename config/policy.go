@@ -0,0 +1,194 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Policy is what an organization allows decor to install, from a policy file its admins ship to
+// developer machines. The zero Policy allows everything.
+type Policy struct {
+	Path             string            // where it was read from, "" when there's no policy file
+	Enforcement      string            // "refuse" to stop what breaks the policy, or "warn" to only say so
+	RequireChecksums bool              // refuse downloads with no published checksum to verify them against
+	ForbiddenSources []string          // install methods like "script" or "npm", or download hosts like "example.com"
+	Versions         map[string]string // allowed versions by lowercased item name, e.g. go = ">=1.22, <1.24"
+}
+
+// Enforcements are the values enforcement takes
+var Enforcements = []string{"refuse", "warn"}
+
+// PolicyPath returns where the policy file is read from: DECOR_POLICY when set, otherwise a
+// system-wide file an admin can write and a user can't
+func PolicyPath() string {
+	if path := os.Getenv("DECOR_POLICY"); path != "" {
+		return path
+	}
+	return systemPolicyPath(runtime.GOOS)
+}
+
+// systemPolicyPath returns the system-wide policy file on goos
+func systemPolicyPath(goos string) string {
+	switch goos {
+	case "darwin":
+		return "/Library/Application Support/decor/policy.toml"
+	case "windows":
+		data := os.Getenv("ProgramData")
+		if data == "" {
+			data = `C:\ProgramData`
+		}
+		return data + `\decor\policy.toml`
+	}
+	return "/etc/decor/policy.toml"
+}
+
+// LoadPolicy reads the policy file, returning the zero Policy when there's none
+func LoadPolicy() (Policy, error) {
+	path := PolicyPath()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Policy{}, nil
+	}
+	if err != nil {
+		return Policy{}, err
+	}
+	policy, err := parsePolicy(string(data))
+	if err != nil {
+		return Policy{}, fmt.Errorf("%s: %w", path, err)
+	}
+	policy.Path = filepath.Clean(path)
+	return policy, nil
+}
+
+// parsePolicy reads a policy file's contents
+func parsePolicy(data string) (Policy, error) {
+	doc, err := parseTOML(data)
+	if err != nil {
+		return Policy{}, err
+	}
+	policy := Policy{
+		Enforcement:      doc.getString("enforcement", "refuse"),
+		RequireChecksums: doc.getBool("require_checksums", false),
+	}
+	if !slices.Contains(Enforcements, policy.Enforcement) {
+		return Policy{}, fmt.Errorf("enforcement: expected %s, got %q", strings.Join(Enforcements, " or "), policy.Enforcement)
+	}
+	if _, ok := doc["forbidden_sources"]; ok {
+		if policy.ForbiddenSources, err = doc.getStrings("forbidden_sources"); err != nil {
+			return Policy{}, err
+		}
+	}
+	if versions, ok := doc["versions"].(table); ok {
+		policy.Versions = make(map[string]string)
+		for item, value := range versions {
+			allowed, ok := value.(string)
+			if !ok {
+				return Policy{}, fmt.Errorf("versions.%s: expected a range like \">=1.22, <1.24\", got %v", item, value)
+			}
+			if _, err := VersionAllowed(allowed, "0"); err != nil {
+				return Policy{}, fmt.Errorf("versions.%s: %w", item, err)
+			}
+			policy.Versions[strings.ToLower(item)] = allowed
+		}
+	}
+	return policy, nil
+}
+
+// Refuses reports whether breaking the policy stops what's being done, rather than only warning
+func (p Policy) Refuses() bool {
+	return p.Enforcement != "warn"
+}
+
+// ForbidsMethod reports whether the policy forbids installing with method, like "script"
+func (p Policy) ForbidsMethod(method string) bool {
+	return method != "" && slices.Contains(p.ForbiddenSources, method)
+}
+
+// ForbidsURL reports whether the policy forbids downloading from rawURL's host or a subdomain of it
+func (p Policy) ForbidsURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, source := range p.ForbiddenSources {
+		source = strings.ToLower(source)
+		if host == source || strings.HasSuffix(host, "."+source) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowedVersions returns the range of versions the policy allows for item, or "" for any
+func (p Policy) AllowedVersions(item string) string {
+	return p.Versions[strings.ToLower(item)]
+}
+
+// versionNumber matches the first dotted version in a tool's output
+var versionNumber = regexp.MustCompile(`\d+(\.\d+)*`)
+
+// VersionNumber picks the version out of what a version command printed, e.g. 1.25.5 from "go version
+// go1.25.5 linux/amd64", for VersionAllowed, returning "" if there's none
+func VersionNumber(output string) string {
+	return versionNumber.FindString(output)
+}
+
+// VersionAllowed reports whether version is in allowed, comma-separated conditions that must all
+// hold, like ">=1.22, <1.24". A condition is a version after >=, >, <=, < or =, or a bare version,
+// which is the same as =. Versions compare as far as the shorter goes, as manifests' do, so 1.23.4
+// is =1.23 and <1.24, but not >1.23.
+func VersionAllowed(allowed, version string) (bool, error) {
+	version = strings.TrimLeft(version, "vV")
+	ok := true
+	for _, condition := range strings.Split(allowed, ",") {
+		condition = strings.TrimSpace(condition)
+		op := condition[:len(condition)-len(strings.TrimLeft(condition, "<>="))]
+		want := strings.TrimLeft(strings.TrimSpace(strings.TrimLeft(condition, "<>=")), "vV")
+		if want == "" || want[0] < '0' || want[0] > '9' {
+			return false, fmt.Errorf("expected a range like \">=1.22, <1.24\", got %q", allowed)
+		}
+		c := compareVersions(version, want)
+		switch op {
+		case ">=":
+			ok = ok && c >= 0
+		case ">":
+			ok = ok && c > 0
+		case "<=":
+			ok = ok && c <= 0
+		case "<":
+			ok = ok && c < 0
+		case "=", "==", "":
+			ok = ok && c == 0
+		default:
+			return false, fmt.Errorf("%q: expected >=, >, <=, < or = before %s", condition, want)
+		}
+	}
+	return ok, nil
+}
+
+// compareVersions compares dotted versions as far as the shorter goes, returning -1, 0 or 1
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		x, errX := strconv.Atoi(as[i])
+		y, errY := strconv.Atoi(bs[i])
+		if errX != nil || errY != nil {
+			return strings.Compare(as[i], bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
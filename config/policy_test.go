@@ -0,0 +1,109 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestVersionAllowed(t *testing.T) {
+	tests := []struct {
+		allowed, version string
+		want             bool
+	}{
+		{">=1.22, <1.24", "1.22.0", true},
+		{">=1.22, <1.24", "1.23.9", true},
+		{">=1.22, <1.24", "1.24.1", false},
+		{">=1.22, <1.24", "1.21.13", false},
+		{"1.22", "1.22.3", true},
+		{"=1.22", "1.23.0", false},
+		{">1.23", "1.23.4", false},
+		{">1.23", "1.24", true},
+		{"<=21", "21.0.2", true},
+		{">=3.12", "v3.13.1", true},
+	}
+	for _, tt := range tests {
+		got, err := VersionAllowed(tt.allowed, tt.version)
+		if err != nil {
+			t.Fatalf("VersionAllowed(%q, %q): %v", tt.allowed, tt.version, err)
+		}
+		if got != tt.want {
+			t.Errorf("VersionAllowed(%q, %q) = %t, want %t", tt.allowed, tt.version, got, tt.want)
+		}
+	}
+	for _, bad := range []string{"", ">=", "~>1.2", ">=1.22,"} {
+		if _, err := VersionAllowed(bad, "1.22"); err == nil {
+			t.Errorf("VersionAllowed(%q) accepted a malformed range", bad)
+		}
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.toml")
+	t.Setenv("DECOR_POLICY", path)
+
+	policy, err := LoadPolicy()
+	if err != nil || policy.Path != "" {
+		t.Fatalf("LoadPolicy() with no file = %+v, %v, want the zero policy", policy, err)
+	}
+
+	data := `enforcement = "warn"
+require_checksums = true
+forbidden_sources = ["script", "example.com"]
+
+[versions]
+Go = ">=1.22, <1.24"
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	policy, err = LoadPolicy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Policy{
+		Path:             path,
+		Enforcement:      "warn",
+		RequireChecksums: true,
+		ForbiddenSources: []string{"script", "example.com"},
+		Versions:         map[string]string{"go": ">=1.22, <1.24"},
+	}
+	if !reflect.DeepEqual(policy, want) {
+		t.Errorf("LoadPolicy() = %+v, want %+v", policy, want)
+	}
+	if policy.Refuses() || !policy.ForbidsMethod("script") || policy.ForbidsMethod("apt") {
+		t.Errorf("%+v enforces the wrong things", policy)
+	}
+	if !policy.ForbidsURL("https://dl.example.com/tool.tar.gz") || policy.ForbidsURL("https://notexample.com/tool") {
+		t.Errorf("%+v matches the wrong hosts", policy)
+	}
+	if got := policy.AllowedVersions("GO"); got != ">=1.22, <1.24" {
+		t.Errorf("AllowedVersions(GO) = %q", got)
+	}
+
+	for _, bad := range []string{`enforcement = "ignore"`, "[versions]\ngo = \"~1.22\"", "[versions]\ngo = 1"} {
+		if err := os.WriteFile(path, []byte(bad), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadPolicy(); err == nil {
+			t.Errorf("LoadPolicy() accepted %q", bad)
+		}
+	}
+}
+
+func TestVersionNumber(t *testing.T) {
+	tests := map[string]string{
+		"go version go1.25.5 linux/amd64":     "1.25.5",
+		"rustc 1.81.0 (eeb90cda1 2024-09-04)": "1.81.0",
+		`openjdk version "21.0.2" 2024-01-16`: "21.0.2",
+		"Python 3.13.0":                       "3.13.0",
+		"v3.13.1":                             "3.13.1",
+		"command not found":                   "",
+	}
+	for output, want := range tests {
+		if got := VersionNumber(output); got != want {
+			t.Errorf("VersionNumber(%q) = %q, want %q", output, got, want)
+		}
+	}
+}
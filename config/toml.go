@@ -0,0 +1,252 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// table is a parsed TOML table. Values are string, bool, int64, []any, table or []table.
+type table map[string]any
+
+// parseTOML parses the subset of TOML decor's files use: comments, [tables],
+// [[arrays of tables]], and keys holding strings, booleans, integers or arrays of those
+func parseTOML(data string) (table, error) {
+	root := table{}
+	current := root
+
+	for i, raw := range strings.Split(data, "\n") {
+		lineNum := i + 1
+		line := strings.TrimSpace(stripComment(raw))
+		if line == "" {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "[["):
+			if !strings.HasSuffix(line, "]]") {
+				return nil, fmt.Errorf("line %d: unterminated table header", lineNum)
+			}
+			parent, key, err := walkTables(root, strings.TrimSpace(line[2:len(line)-2]))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			entries, _ := parent[key].([]table)
+			current = table{}
+			parent[key] = append(entries, current)
+		case strings.HasPrefix(line, "["):
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: unterminated table header", lineNum)
+			}
+			parent, key, err := walkTables(root, strings.TrimSpace(line[1:len(line)-1]))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			existing, ok := parent[key].(table)
+			if !ok {
+				existing = table{}
+				parent[key] = existing
+			}
+			current = existing
+		default:
+			key, value, found := strings.Cut(line, "=")
+			if !found {
+				return nil, fmt.Errorf("line %d: expected key = value", lineNum)
+			}
+			key = unquoteKey(strings.TrimSpace(key))
+			parsed, err := parseValue(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s: %w", lineNum, key, err)
+			}
+			current[key] = parsed
+		}
+	}
+	return root, nil
+}
+
+// walkTables resolves a dotted header like "a.b.c" to the parent table of "c", creating tables as needed
+func walkTables(root table, header string) (table, string, error) {
+	if header == "" {
+		return nil, "", fmt.Errorf("empty table name")
+	}
+	parts := strings.Split(header, ".")
+	current := root
+	for _, part := range parts[:len(parts)-1] {
+		part = unquoteKey(strings.TrimSpace(part))
+		switch next := current[part].(type) {
+		case table:
+			current = next
+		case []table:
+			current = next[len(next)-1]
+		case nil:
+			created := table{}
+			current[part] = created
+			current = created
+		default:
+			return nil, "", fmt.Errorf("%s is not a table", part)
+		}
+	}
+	return current, unquoteKey(strings.TrimSpace(parts[len(parts)-1])), nil
+}
+
+// stripComment removes a trailing # comment that isn't inside a string
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// unquoteKey strips quotes from a quoted key
+func unquoteKey(key string) string {
+	if len(key) >= 2 && (key[0] == '"' || key[0] == '\'') && key[len(key)-1] == key[0] {
+		return key[1 : len(key)-1]
+	}
+	return key
+}
+
+// parseValue parses a single TOML value
+func parseValue(value string) (any, error) {
+	switch {
+	case value == "":
+		return nil, fmt.Errorf("missing value")
+	case value == "true":
+		return true, nil
+	case value == "false":
+		return false, nil
+	case strings.HasPrefix(value, `"`):
+		return strconv.Unquote(value)
+	case strings.HasPrefix(value, "'"):
+		if len(value) < 2 || !strings.HasSuffix(value, "'") {
+			return nil, fmt.Errorf("unterminated string")
+		}
+		return value[1 : len(value)-1], nil
+	case strings.HasPrefix(value, "["):
+		if !strings.HasSuffix(value, "]") {
+			return nil, fmt.Errorf("unterminated array (arrays must fit on one line)")
+		}
+		var items []any
+		for _, item := range splitArray(value[1 : len(value)-1]) {
+			parsed, err := parseValue(item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, parsed)
+		}
+		return items, nil
+	}
+
+	n, err := strconv.ParseInt(strings.ReplaceAll(value, "_", ""), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported value %s", value)
+	}
+	return n, nil
+}
+
+// splitArray splits the inside of an array on commas that aren't inside strings
+func splitArray(inner string) []string {
+	var items []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(inner); i++ {
+		c := inner[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ',':
+			items = append(items, inner[start:i])
+			start = i + 1
+		}
+	}
+	items = append(items, inner[start:])
+
+	var trimmed []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			trimmed = append(trimmed, item)
+		}
+	}
+	return trimmed
+}
+
+// getString reads a string key, falling back to def
+func (t table) getString(key, def string) string {
+	if v, ok := t[key].(string); ok {
+		return v
+	}
+	return def
+}
+
+// getStrings reads an array of strings
+func (t table) getStrings(key string) ([]string, error) {
+	items, ok := t[key].([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s: expected an array of strings like [\"up\", \"k\"], got %v", key, t[key])
+	}
+	strs := make([]string, len(items))
+	for i, item := range items {
+		if strs[i], ok = item.(string); !ok {
+			return nil, fmt.Errorf("%s: expected an array of strings like [\"up\", \"k\"], got %v", key, t[key])
+		}
+	}
+	return strs, nil
+}
+
+// getBool reads a boolean key, falling back to def
+func (t table) getBool(key string, def bool) bool {
+	if v, ok := t[key].(bool); ok {
+		return v
+	}
+	return def
+}
+
+// getInt reads a non-negative integer key, falling back to def
+func (t table) getInt(key string, def int64) (int64, error) {
+	v, ok := t[key]
+	if !ok {
+		return def, nil
+	}
+	n, ok := v.(int64)
+	if !ok || n < 0 {
+		return def, fmt.Errorf("%s: expected a whole number like 2000, got %v", key, v)
+	}
+	return n, nil
+}
+
+// getDuration reads a duration key such as "10s" or "30m", falling back to def
+func (t table) getDuration(key string, def time.Duration) (time.Duration, error) {
+	v, ok := t[key].(string)
+	if !ok {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return def, fmt.Errorf("%s: expected a positive duration like \"10s\", got %q", key, v)
+	}
+	return d, nil
+}
+
+// quote formats a string as a TOML basic string
+func quote(s string) string {
+	return strconv.Quote(s)
+}
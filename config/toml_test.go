@@ -0,0 +1,235 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTOMLValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		key   string
+		want  any
+	}{
+		{"basic string", `theme = "mono"`, "theme", "mono"},
+		{"literal string", `prefix = '~/.local'`, "prefix", "~/.local"},
+		{"escapes", `path = "C:\\tools\t\"x\""`, "path", "C:\\tools\t\"x\""},
+		{"unicode escape", `name = "caf\u00e9"`, "name", "café"},
+		{"hash inside string", `url = "https://example.com/#top"`, "url", "https://example.com/#top"},
+		{"trailing comment", `telemetry = true # opt in`, "telemetry", true},
+		{"comment after string", `channel = "lts" # not "stable"`, "channel", "lts"},
+		{"false", `starter_configs = false`, "starter_configs", false},
+		{"integer", `retries = 3`, "retries", int64(3)},
+		{"integer with underscores", `size = 1_000_000`, "size", int64(1000000)},
+		{"negative integer", `offset = -2`, "offset", int64(-2)},
+		{"quoted key", `"odd key" = "v"`, "odd key", "v"},
+		{"array", `langs = ["go", 'rust', "c,++"]`, "langs", []any{"go", "rust", "c,++"}},
+		{"empty array", `langs = []`, "langs", []any(nil)},
+		{"spaces around equals", "  theme   =   \"default\"  ", "theme", "default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parseTOML(tt.input)
+			if err != nil {
+				t.Fatalf("parseTOML(%q): %v", tt.input, err)
+			}
+			if got := doc[tt.key]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTOML(%q)[%q] = %#v, want %#v", tt.input, tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTOMLTables(t *testing.T) {
+	doc, err := parseTOML(`
+# decor configuration
+theme = "mono"
+
+[pins]
+go = "1.25.5"
+
+[pins.python]
+version = "3.12"
+
+[[team.rules]]
+item = "Go"
+
+[[team.rules]]
+item = "Rust"
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := doc.getString("theme", ""); got != "mono" {
+		t.Errorf("theme = %q, want mono", got)
+	}
+	pins, ok := doc["pins"].(table)
+	if !ok {
+		t.Fatalf("pins = %#v, want a table", doc["pins"])
+	}
+	if got := pins.getString("go", ""); got != "1.25.5" {
+		t.Errorf("pins.go = %q, want 1.25.5", got)
+	}
+	if python, _ := pins["python"].(table); python.getString("version", "") != "3.12" {
+		t.Errorf("pins.python = %#v, want version 3.12", pins["python"])
+	}
+	team, _ := doc["team"].(table)
+	rules, ok := team["rules"].([]table)
+	if !ok || len(rules) != 2 || rules[0]["item"] != "Go" || rules[1]["item"] != "Rust" {
+		t.Errorf("team.rules = %#v, want two tables for Go and Rust", team["rules"])
+	}
+}
+
+func TestParseTOMLErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"missing equals", "theme", "line 1: expected key = value"},
+		{"missing value", "theme =", "missing value"},
+		{"unterminated basic string", `theme = "mono`, "line 1: theme"},
+		{"unterminated literal string", `theme = 'mono`, "unterminated string"},
+		{"junk after string", `theme = "mono" x`, "line 1: theme"},
+		{"unterminated array", `langs = ["go",`, "unterminated array"},
+		{"bad element", `langs = ["go", maybe]`, "unsupported value maybe"},
+		{"bare word", `theme = mono`, "unsupported value mono"},
+		{"unterminated header", "[pins", "unterminated table header"},
+		{"unterminated array header", "[[rules]", "unterminated table header"},
+		{"empty header", "[]", "empty table name"},
+		{"key used as table", "pins = 1\n[pins.go]", "line 2: pins is not a table"},
+		{"error line number", "theme = \"a\"\n\n# note\nchannel = ", "line 4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseTOML(tt.input)
+			if err == nil {
+				t.Fatalf("parseTOML(%q) succeeded, want an error containing %q", tt.input, tt.want)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parseTOML(%q) = %v, want an error containing %q", tt.input, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetDuration(t *testing.T) {
+	tests := []struct {
+		value   any
+		want    time.Duration
+		wantErr bool
+	}{
+		{"10s", 10 * time.Second, false},
+		{"30m", 30 * time.Minute, false},
+		{nil, time.Minute, false},
+		{"soon", time.Minute, true},
+		{"-5s", time.Minute, true},
+		{"0s", time.Minute, true},
+	}
+	for _, tt := range tests {
+		doc := table{}
+		if tt.value != nil {
+			doc["timeout"] = tt.value
+		}
+		got, err := doc.getDuration("timeout", time.Minute)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("getDuration(%v) = %v, %v; want %v, error %t", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home+"/config")
+	t.Setenv("AppData", home+"/config")
+
+	cfg := Default()
+	cfg.InstallPrefix = `~/tools "quoted" \ dir`
+	cfg.Theme = "mono"
+	cfg.Mouse = true
+	cfg.Language = "es"
+	cfg.StarterConfigs = true
+	cfg.ProjectDir = "~/src"
+	cfg.Manifest = "/etc/decor/team.tools"
+	cfg.DetectTimeout = 3 * time.Second
+	cfg.HTTPTimeout = 5 * time.Second
+	cfg.DownloadIdle = 0
+	cfg.MetadataMaxAge = 90 * time.Minute
+	cfg.DownloadLimit = 2000
+	cfg.DownloadConns = 8
+	cfg.KeepVersions = 3
+	cfg.LocalMetrics = true
+	cfg.KeyStyle = "emacs"
+	cfg.Keys = map[string][]string{"quit": {"ctrl+q"}, "toggle": {" ", "x"}}
+	cfg.Components = map[string][]string{"python": {"venv", "tkinter"}, "node.js": {}}
+	cfg.Tools = []Tool{
+		{Name: "lazygit", Repo: "jesseduffield/lazygit", Asset: "lazygit_*_{os}_{arch}.tar.gz"},
+		{Name: "just", Description: "a command runner", Category: "Build tools", Version: []string{"just", "-V"}, Repo: "casey/just"},
+		{Name: "staticcheck", GoInstall: "honnef.co/go/tools/cmd/staticcheck@latest"},
+		{Name: "prettier", Npm: "prettier"},
+		{Name: "zig", Archive: "https://ziglang.org/download/0.13.0/zig-{os}-{arch}-0.13.0.tar.xz", Strip: 1, Links: []string{"zig"}},
+	}
+	cfg.Pins = []Pin{
+		{Item: "Go", Version: "1.25.5", URL: "https://mirror.internal/go1.25.5.linux-amd64.tar.gz", SHA256: strings.Repeat("ab", 32)},
+		{Item: "kubectl", URL: "https://dl.k8s.io/release/v1.31.0/bin/linux/amd64/kubectl", SHA256: strings.Repeat("01", 32)},
+	}
+	if err := Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	loaded, exists, err := Load()
+	if err != nil || !exists {
+		t.Fatalf("Load() = %v, %t, %v", loaded, exists, err)
+	}
+	if !reflect.DeepEqual(loaded, cfg) {
+		t.Errorf("Load() = %+v, want %+v", loaded, cfg)
+	}
+}
+
+func TestParseTools(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string // the error, or empty
+	}{
+		{"[[tools]]\nname = \"lazygit\"\nrepo = \"jesseduffield/lazygit\"\n", ""},
+		{"[[tools]]\nrepo = \"jesseduffield/lazygit\"\n", "tools[0] has no name"},
+		{"[[tools]]\nname = \"lazygit\"\n", "says nothing about how to install it"},
+		{"[[tools]]\nname = \"just\"\nrepo = \"casey/just\"\nversion = \"just -V\"\n", "tools[0].version: expected an array"},
+	}
+	for _, tt := range tests {
+		doc, err := parseTOML(tt.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = parseTools(doc)
+		if tt.want == "" && err != nil || tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)) {
+			t.Errorf("parseTools(%q) = %v, want %q", tt.input, err, tt.want)
+		}
+	}
+}
+
+func TestParsePins(t *testing.T) {
+	sum := strings.Repeat("ab", 32)
+	tests := []struct {
+		input string
+		want  string // the error, or empty
+	}{
+		{"[[pins]]\nitem = \"go\"\nurl = \"https://go.dev/dl/go1.25.5.linux-amd64.tar.gz\"\nsha256 = \"" + sum + "\"\n", ""},
+		{"[[pins]]\nurl = \"https://example.com/x\"\nsha256 = \"" + sum + "\"\n", "pins[0] has no item"},
+		{"[[pins]]\nitem = \"go\"\nsha256 = \"" + sum + "\"\n", "pins[0] (go) has no url"},
+		{"[[pins]]\nitem = \"go\"\nurl = \"https://example.com/x\"\nsha256 = \"abc\"\n", "pins[0].sha256: expected 64 hex digits"},
+	}
+	for _, tt := range tests {
+		doc, err := parseTOML(tt.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = parsePins(doc)
+		if tt.want == "" && err != nil || tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)) {
+			t.Errorf("parsePins(%q) = %v, want %q", tt.input, err, tt.want)
+		}
+	}
+}
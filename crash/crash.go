@@ -0,0 +1,199 @@
+package crash
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"decor/paths"
+	"decor/platform"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DirName is the directory in decor's state directory crash reports are saved to
+const DirName = "crashes"
+
+// logLines is how much of the end of the command log a report includes
+const logLines = 50
+
+// Dir returns where crash reports are saved
+func Dir() (string, error) {
+	dir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, DirName), nil
+}
+
+// Save writes a report of a panic with value and stack, including the last lines of the command log at
+// logPath, and returns the report's path
+func Save(value any, stack []byte, logPath string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	var b strings.Builder
+	fmt.Fprintf(&b, "decor crashed at %s\n", now.Format(time.RFC3339))
+	fmt.Fprintf(&b, "%s, %s\n", platform.Current(), runtime.Version())
+	if info, ok := debug.ReadBuildInfo(); ok {
+		fmt.Fprintf(&b, "decor %s\n", info.Main.Version)
+	}
+	fmt.Fprintf(&b, "\npanic: %v\n\n%s\n", value, stack)
+	if tail := logTail(logPath, logLines); tail != "" {
+		fmt.Fprintf(&b, "\nLast lines of %s:\n%s\n", logPath, tail)
+	}
+
+	path := filepath.Join(dir, "crash-"+now.Format("20060102-150405")+".txt")
+	return path, os.WriteFile(path, []byte(b.String()), 0o644)
+}
+
+// logTail returns the last n lines of the file at path, or nothing if it can't be read
+func logTail(path string, n int) string {
+	if path == "" {
+		return ""
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	return strings.Join(lines[max(0, len(lines)-n):], "\n")
+}
+
+// crashedMsg stops the program after a panic that couldn't return tea.Quit itself
+type crashedMsg struct{}
+
+// Guard wraps the TUI's top model so a panic in Update, View or a command saves a crash report and quits
+// the program cleanly, restoring the terminal, instead of leaving it in raw mode. Run the program with
+// tea.WithoutCatchPanics so the guard sees the panics.
+type Guard struct {
+	model tea.Model
+	state *state
+}
+
+// Crash is a panic the guard recovered from
+type Crash struct {
+	Value  any
+	Stack  []byte
+	Report string // where the crash report was saved
+	Err    error  // why the report couldn't be saved
+}
+
+// state is shared by every copy of the guard
+type state struct {
+	mu      sync.Mutex
+	logPath string
+	send    func(tea.Msg)
+	crash   *Crash // the first panic
+}
+
+// NewGuard wraps model, including the command log at logPath in crash reports
+func NewGuard(model tea.Model, logPath string) Guard {
+	return Guard{model: model, state: &state{logPath: logPath}}
+}
+
+// SetSend gives the guard a way to stop the program after a panic in View, normally the program's Send
+func (g Guard) SetSend(send func(tea.Msg)) {
+	g.state.mu.Lock()
+	defer g.state.mu.Unlock()
+	g.state.send = send
+}
+
+// Crashed returns the first panic the program had, and false if it didn't panic
+func (g Guard) Crashed() (Crash, bool) {
+	g.state.mu.Lock()
+	defer g.state.mu.Unlock()
+	if g.state.crash == nil {
+		return Crash{}, false
+	}
+	return *g.state.crash, true
+}
+
+// recovered saves a report of the first panic; later ones are usually knock-on effects
+func (g Guard) recovered(value any) {
+	stack := debug.Stack()
+	g.state.mu.Lock()
+	defer g.state.mu.Unlock()
+	if g.state.crash != nil {
+		return
+	}
+	report, err := Save(value, stack, g.state.logPath)
+	g.state.crash = &Crash{Value: value, Stack: stack, Report: report, Err: err}
+}
+
+func (g Guard) Init() (cmd tea.Cmd) {
+	defer func() {
+		if r := recover(); r != nil {
+			g.recovered(r)
+			cmd = tea.Quit
+		}
+	}()
+	return g.guard(g.model.Init())
+}
+
+func (g Guard) Update(msg tea.Msg) (model tea.Model, cmd tea.Cmd) {
+	if _, ok := msg.(crashedMsg); ok {
+		return g, tea.Quit
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			g.recovered(r)
+			model, cmd = g, tea.Quit
+		}
+	}()
+	updated, cmd := g.model.Update(msg)
+	g.model = updated
+	return g, g.guard(cmd)
+}
+
+func (g Guard) View() (view string) {
+	defer func() {
+		if r := recover(); r != nil {
+			g.recovered(r)
+			g.state.mu.Lock()
+			send := g.state.send
+			g.state.mu.Unlock()
+			if send != nil {
+				go send(crashedMsg{})
+			}
+			view = ""
+		}
+	}()
+	return g.model.View()
+}
+
+// guard wraps a command so a panic while it runs is saved and stops the program, including the commands
+// of a batch it returns
+func (g Guard) guard(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() (msg tea.Msg) {
+		defer func() {
+			if r := recover(); r != nil {
+				g.recovered(r)
+				msg = crashedMsg{}
+			}
+		}()
+		msg = cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			guarded := make(tea.BatchMsg, len(batch))
+			for i, inner := range batch {
+				guarded[i] = g.guard(inner)
+			}
+			return guarded
+		}
+		return msg
+	}
+}
@@ -0,0 +1,101 @@
+package crash
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tempState points the state directory at a fresh temporary directory
+func tempState(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_STATE_HOME", dir)
+	t.Setenv("LOCALAPPDATA", dir)
+}
+
+// panicky panics on the message "boom", and returns a panicking command on "later"
+type panicky struct{}
+
+func (panicky) Init() tea.Cmd { return nil }
+
+func (m panicky) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg {
+	case "boom":
+		panic("index out of range")
+	case "later":
+		return m, tea.Batch(func() tea.Msg { return "fine" }, func() tea.Msg { panic("in a command") })
+	}
+	return m, nil
+}
+
+func (panicky) View() string { return "ok" }
+
+func TestGuardUpdate(t *testing.T) {
+	tempState(t)
+	logPath := filepath.Join(t.TempDir(), "decor.log")
+	var log strings.Builder
+	for i := range logLines + 10 {
+		fmt.Fprintf(&log, "line %d\n", i)
+	}
+	os.WriteFile(logPath, []byte(log.String()), 0o644)
+
+	guard := NewGuard(panicky{}, logPath)
+	if _, cmd := guard.Update("hello"); cmd != nil {
+		t.Fatal("a quiet update returned a command")
+	}
+	if _, crashed := guard.Crashed(); crashed {
+		t.Fatal("crashed before any panic")
+	}
+
+	_, cmd := guard.Update("boom")
+	if cmd == nil || cmd() != tea.Quit() {
+		t.Error("a panic in Update didn't quit")
+	}
+	crash, crashed := guard.Crashed()
+	if !crashed || crash.Err != nil {
+		t.Fatalf("got %+v, %t", crash, crashed)
+	}
+	data, err := os.ReadFile(crash.Report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := string(data)
+	for _, want := range []string{"panic: index out of range", "crash.panicky.Update", fmt.Sprintf("line %d", logLines+9)} {
+		if !strings.Contains(report, want) {
+			t.Errorf("the report is missing %q:\n%s", want, report)
+		}
+	}
+	if strings.Contains(report, "line 9\n") {
+		t.Error("the report has more of the log than its last lines")
+	}
+}
+
+func TestGuardCommand(t *testing.T) {
+	tempState(t)
+	guard := NewGuard(panicky{}, "")
+
+	_, cmd := guard.Update("later")
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok || len(batch) != 2 {
+		t.Fatalf("the batch came back as %#v", batch)
+	}
+	if msg := batch[0](); msg != "fine" {
+		t.Errorf("a guarded command returned %v", msg)
+	}
+	msg := batch[1]()
+	if _, ok := msg.(crashedMsg); !ok {
+		t.Errorf("a panicking command returned %#v", msg)
+	}
+	if _, cmd := guard.Update(msg); cmd == nil || cmd() != tea.Quit() {
+		t.Error("the crash didn't quit the program")
+	}
+	if crash, crashed := guard.Crashed(); !crashed || crash.Value != "in a command" {
+		t.Errorf("got %+v, %t", crash, crashed)
+	}
+}
@@ -0,0 +1,98 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// dialTimeout bounds how long connecting to the daemon may take
+const dialTimeout = 2 * time.Second
+
+// Client calls a running daemon. Each call uses its own connection, so a Client is safe for concurrent use.
+type Client struct {
+	path string
+}
+
+// Dial returns a client for the daemon listening on the default socket, or an error if none is running
+func Dial() (*Client, error) {
+	path, err := SocketPath()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.DialTimeout("unix", path, dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("no decor daemon running on %s: %w", path, err)
+	}
+	conn.Close()
+	return &Client{path: path}, nil
+}
+
+// Plan checks the languages and returns the action the daemon would take for each
+func (c *Client) Plan(languages []string) ([]PlanItem, error) {
+	resp, err := c.call(Request{Method: MethodPlan, Languages: languages})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Plan, nil
+}
+
+// Apply starts installing the languages with the given choices; poll Status for progress
+func (c *Client) Apply(languages []string, choices map[string]string) error {
+	_, err := c.call(Request{Method: MethodApply, Languages: languages, Choices: choices})
+	return err
+}
+
+// Status returns the progress of the current or last run
+func (c *Client) Status() (Status, error) {
+	resp, err := c.call(Request{Method: MethodStatus})
+	if err != nil {
+		return Status{}, err
+	}
+	if resp.Status == nil {
+		return Status{}, errors.New("daemon sent no status")
+	}
+	return *resp.Status, nil
+}
+
+// Cancel stops the current run
+func (c *Client) Cancel() error {
+	_, err := c.call(Request{Method: MethodCancel})
+	return err
+}
+
+// Queue changes the current run's queue: "skip", "earlier" or "later" for language, or "pause" or "resume"
+func (c *Client) Queue(change, language string) error {
+	_, err := c.call(Request{Method: MethodQueue, Queue: change, Language: language})
+	return err
+}
+
+// call sends one request and waits for its response
+func (c *Client) call(req Request) (Response, error) {
+	conn, err := net.DialTimeout("unix", c.path, dialTimeout)
+	if err != nil {
+		return Response{}, fmt.Errorf("connecting to decor daemon: %w", err)
+	}
+	defer conn.Close()
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return Response{}, fmt.Errorf("sending %s request: %w", req.Method, err)
+	}
+
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		return Response{}, fmt.Errorf("reading %s response: %w", req.Method, err)
+	}
+	var resp Response
+	if err := json.Unmarshal(line, &resp); err != nil {
+		return Response{}, fmt.Errorf("decoding %s response: %w", req.Method, err)
+	}
+	if resp.Error != "" {
+		return resp, errors.New(resp.Error)
+	}
+	return resp, nil
+}
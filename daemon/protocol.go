@@ -0,0 +1,59 @@
+package daemon
+
+import (
+	"path/filepath"
+
+	"decor/installer"
+	"decor/paths"
+)
+
+// Methods the daemon understands
+const (
+	MethodPlan   = "plan"   // check languages and propose an action for each
+	MethodApply  = "apply"  // start installing with the given choices
+	MethodStatus = "status" // report progress of the current or last run
+	MethodCancel = "cancel" // stop the current run
+	MethodQueue  = "queue"  // change the current run's queue of items waiting to install
+)
+
+// Request is a single call to the daemon, sent as one line of JSON
+type Request struct {
+	Method    string            `json:"method"`
+	Languages []string          `json:"languages,omitempty"`
+	Choices   map[string]string `json:"choices,omitempty"`  // "install", "update", "remove" or "skip" per language
+	Queue     string            `json:"queue,omitempty"`    // for MethodQueue: "skip", "earlier", "later", "pause" or "resume"
+	Language  string            `json:"language,omitempty"` // the item a queue change is for
+}
+
+// Response answers a Request, also as one line of JSON. Error is set when the call failed.
+type Response struct {
+	Error  string     `json:"error,omitempty"`
+	Plan   []PlanItem `json:"plan,omitempty"`
+	Status *Status    `json:"status,omitempty"`
+}
+
+// PlanItem is a language's installation status and the action decor would take by default
+type PlanItem struct {
+	installer.InstallationStatus
+	Action string `json:"action"`
+}
+
+// Status describes the daemon's current or most recent run
+type Status struct {
+	State     string                                `json:"state"` // "idle", "running" or "done"
+	Languages []string                              `json:"languages,omitempty"`
+	Choices   map[string]string                     `json:"choices,omitempty"`
+	Progress  map[string]installer.ProgressSnapshot `json:"progress,omitempty"`
+	Results   map[string]string                     `json:"results,omitempty"`
+	Queued    []string                              `json:"queued,omitempty"` // items yet to start installing, in the order they will
+	Paused    bool                                  `json:"paused,omitempty"`
+}
+
+// SocketPath returns the Unix socket the daemon listens on, in a directory only the user can enter
+func SocketPath() (string, error) {
+	dir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "daemon", "decor.sock"), nil
+}
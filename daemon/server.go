@@ -0,0 +1,269 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"decor/config"
+	"decor/installer"
+)
+
+// Server runs installs on behalf of clients, one run at a time
+type Server struct {
+	mu        sync.Mutex
+	state     string // "idle", "running" or "done"
+	languages []string
+	choices   map[string]string
+	trackers  map[string]*installer.LanguageProgress
+	results   map[string]string
+	cancel    context.CancelFunc
+}
+
+// NewServer creates a server with nothing running
+func NewServer() *Server {
+	return &Server{state: "idle"}
+}
+
+// Listen opens the daemon socket, replacing a stale one left by a daemon that didn't shut down cleanly
+func Listen() (net.Listener, error) {
+	path, err := SocketPath()
+	if err != nil {
+		return nil, err
+	}
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("a decor daemon is already listening on %s", path)
+	}
+	os.Remove(path)
+	// The socket is created with the umask's permissions, so keep others out of its directory until
+	// it's locked down
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("listening on %s: %w", path, err)
+	}
+	// Only the owning user may drive installs
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// Serve answers clients on listener until ctx is done, then cancels any running install
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+		s.mu.Lock()
+		if s.cancel != nil {
+			s.cancel()
+		}
+		s.mu.Unlock()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go s.handle(conn)
+	}
+}
+
+// handle answers each request line on conn until the client disconnects
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	encoder := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var req Request
+		var resp Response
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			resp.Error = fmt.Sprintf("invalid request: %v", err)
+		} else {
+			resp = s.Dispatch(req)
+		}
+		if err := encoder.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// Dispatch runs a single request, for frontends that embed the engine instead of dialing the socket
+func (s *Server) Dispatch(req Request) Response {
+	var err error
+	var resp Response
+	switch req.Method {
+	case MethodPlan:
+		resp.Plan, err = s.plan(req.Languages)
+	case MethodApply:
+		err = s.apply(req.Languages, req.Choices)
+	case MethodStatus:
+		status := s.status()
+		resp.Status = &status
+	case MethodCancel:
+		err = s.stop()
+	case MethodQueue:
+		err = s.changeQueue(req.Queue, req.Language)
+	default:
+		err = fmt.Errorf("unknown method %q", req.Method)
+	}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	return resp
+}
+
+// plan checks each language and proposes the default action for it
+func (s *Server) plan(languages []string) ([]PlanItem, error) {
+	if len(languages) == 0 {
+		return nil, errors.New("no languages given")
+	}
+	status := installer.Check(languages)
+	plan := make([]PlanItem, 0, len(languages))
+	for _, lang := range languages {
+		plan = append(plan, PlanItem{
+			InstallationStatus: *status[lang],
+			Action:             installer.DefaultChoice(status[lang]),
+		})
+	}
+	return plan, nil
+}
+
+// apply starts a run in the background; only one run may be in progress
+func (s *Server) apply(languages []string, choices map[string]string) error {
+	if len(languages) == 0 {
+		return errors.New("no languages given")
+	}
+	if err := checkChoices(languages, choices); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state == "running" {
+		return errors.New("an install is already running")
+	}
+
+	// Components are picked in decor and saved to the settings just before it applies
+	if cfg, _, err := config.Load(); err == nil {
+		installer.SetComponents(cfg.Components)
+	}
+	// The daemon has no terminal to prompt on, so credentials must already be cached
+	privileged := installer.Privileged()
+	if installer.NeedsRoot(languages, choices) && !privileged.Authenticated() {
+		return fmt.Errorf("some steps need root but %s would prompt for a password; run the daemon as root or authenticate %s in its terminal first", privileged.Elevator, privileged.Elevator)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	trackers := installer.NewTrackers(languages, choices)
+	s.state = "running"
+	s.languages = languages
+	s.choices = choices
+	s.trackers = trackers
+	s.results = nil
+	s.cancel = cancel
+
+	go func() {
+		results := installer.Run(ctx, languages, choices, trackers)
+		cancel()
+
+		s.mu.Lock()
+		s.state = "done"
+		s.results = results
+		s.cancel = nil
+		s.mu.Unlock()
+	}()
+	return nil
+}
+
+// validChoices are the actions apply accepts for a language
+var validChoices = map[string]bool{"install": true, "update": true, "remove": true, "skip": true}
+
+// checkChoices rejects a missing or unknown choice for any language, and choices for languages that
+// aren't being applied
+func checkChoices(languages []string, choices map[string]string) error {
+	for _, lang := range languages {
+		choice, ok := choices[lang]
+		if !ok {
+			return fmt.Errorf("no choice given for %s", lang)
+		}
+		if !validChoices[choice] {
+			return fmt.Errorf("invalid choice %q for %s, expected install, update, remove or skip", choice, lang)
+		}
+	}
+	if len(choices) > len(languages) {
+		listed := make(map[string]bool)
+		for _, lang := range languages {
+			listed[lang] = true
+		}
+		for lang := range choices {
+			if !listed[lang] {
+				return fmt.Errorf("choice given for %s, which isn't in languages", lang)
+			}
+		}
+	}
+	return nil
+}
+
+// status snapshots the current or last run
+func (s *Server) status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := Status{
+		State:     s.state,
+		Languages: s.languages,
+		Choices:   s.choices,
+		Results:   s.results,
+		Progress:  make(map[string]installer.ProgressSnapshot),
+	}
+	for lang, tracker := range s.trackers {
+		status.Progress[lang] = tracker.Snapshot()
+	}
+	if s.state == "running" {
+		status.Queued, status.Paused = installer.Queued()
+	}
+	return status
+}
+
+// stop cancels the current run
+func (s *Server) stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.state != "running" || s.cancel == nil {
+		return errors.New("nothing is running")
+	}
+	s.cancel()
+	return nil
+}
+
+// changeQueue skips or moves an item of the current run that hasn't started installing, or pauses or
+// resumes the run's queue
+func (s *Server) changeQueue(change, language string) error {
+	s.mu.Lock()
+	running := s.state == "running"
+	s.mu.Unlock()
+	if !running {
+		return errors.New("nothing is running")
+	}
+	return installer.ChangeQueue(change, language)
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !freebsd && !windows
+
+package doctor
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// FreeSpace is not implemented on this platform
+func FreeSpace(dir string) (uint64, error) {
+	return 0, fmt.Errorf("free space lookup not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,514 @@
+package doctor
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"decor/config"
+	"decor/installed"
+	"decor/pkgmgr"
+	"decor/platform"
+	"decor/runner"
+	"decor/symbols"
+	"decor/wsl"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Status is the outcome of a single diagnostic check
+type Status int
+
+const (
+	StatusOK Status = iota
+	StatusWarn
+	StatusFail
+)
+
+// Result holds the outcome of a diagnostic check and how to fix it
+type Result struct {
+	Name   string
+	Status Status
+	Detail string
+	Fix    string
+}
+
+// probe runs the commands checks need; they only read, so it never elevates
+var probe = runner.New("none")
+
+// probeTimeout bounds each of those commands
+const probeTimeout = 10 * time.Second
+
+// minFreeBytes is the free space below which disk checks fail
+const minFreeBytes = 2 << 30 // 2 GiB
+
+// toolchainBinaries are the binaries decor installs, checked for conflicting copies on PATH
+var toolchainBinaries = []string{"go", "python3", "rustc", "cargo", "java", "javac", "g++", "clang"}
+
+// Run executes every diagnostic check and returns their results in order
+func Run() []Result {
+	var results []Result
+	results = append(results, checkPath())
+	results = append(results, checkConflictingToolchains()...)
+	results = append(results, checkWSL()...)
+	results = append(results, checkPlatform()...)
+	results = append(results, checkRosetta()...)
+	results = append(results, checkPolicy()...)
+	results = append(results, checkCompilers())
+	results = append(results, checkBrokenSymlinks())
+	results = append(results, checkProxy())
+	results = append(results, checkDiskSpace())
+	results = append(results, checkGPUs()...)
+	return results
+}
+
+// HasFailures reports whether any result failed
+func HasFailures(results []Result) bool {
+	for _, r := range results {
+		if r.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// Format renders the results as a human readable report under the given title
+func Format(title string, results []Result) string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("11")). // Yellow
+		MarginBottom(1)
+
+	fixStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")). // Gray
+		PaddingLeft(5)
+
+	var output string
+	output += titleStyle.Render(title) + "\n"
+
+	for _, r := range results {
+		var icon string
+		switch r.Status {
+		case StatusOK:
+			icon = symbols.OK.String()
+		case StatusWarn:
+			icon = symbols.Warning.String()
+		case StatusFail:
+			icon = symbols.Failed.String()
+		}
+		output += fmt.Sprintf("  %s %s: %s\n", icon, r.Name, r.Detail)
+		if r.Fix != "" && r.Status != StatusOK {
+			output += fixStyle.Render(fmt.Sprintf("%s %s", symbols.Arrow, r.Fix)) + "\n"
+		}
+	}
+	return output
+}
+
+// pathEntries returns the directories listed in PATH, in order
+func pathEntries() []string {
+	return filepath.SplitList(os.Getenv("PATH"))
+}
+
+// checkPath looks for empty, relative, missing and duplicate PATH entries
+func checkPath() Result {
+	entries := pathEntries()
+	if len(entries) == 0 {
+		return Result{
+			Name:   "PATH",
+			Status: StatusFail,
+			Detail: "PATH is empty",
+			Fix:    "Set PATH in your shell profile (e.g. export PATH=/usr/local/bin:/usr/bin:/bin)",
+		}
+	}
+
+	var problems []string
+	seen := make(map[string]bool)
+	for _, dir := range entries {
+		switch {
+		case dir == "":
+			problems = append(problems, "empty entry (acts like '.')")
+			continue
+		case !filepath.IsAbs(dir):
+			problems = append(problems, fmt.Sprintf("relative entry %q", dir))
+		}
+		if seen[dir] {
+			problems = append(problems, fmt.Sprintf("duplicate entry %s", dir))
+			continue
+		}
+		seen[dir] = true
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			problems = append(problems, fmt.Sprintf("missing directory %s", dir))
+		}
+	}
+
+	if len(problems) == 0 {
+		return Result{Name: "PATH", Status: StatusOK, Detail: fmt.Sprintf("%d entries look fine", len(entries))}
+	}
+	return Result{
+		Name:   "PATH",
+		Status: StatusWarn,
+		Detail: strings.Join(problems, "; "),
+		Fix:    "Remove the listed entries from PATH in your shell profile (~/.bashrc, ~/.zshrc)",
+	}
+}
+
+// findAll returns every executable named binary on PATH, in lookup order
+func findAll(binary string) []string {
+	if runtime.GOOS == "windows" {
+		binary += ".exe"
+	}
+	var found []string
+	seen := make(map[string]bool)
+	for _, dir := range pathEntries() {
+		if dir == "" {
+			continue
+		}
+		candidate := filepath.Join(dir, binary)
+		info, err := os.Stat(candidate)
+		if err != nil || info.IsDir() || info.Mode()&0111 == 0 {
+			continue
+		}
+		// Symlinks to the same binary (e.g. /bin -> /usr/bin) aren't conflicts
+		resolved, err := filepath.EvalSymlinks(candidate)
+		if err != nil {
+			resolved = candidate
+		}
+		if seen[resolved] {
+			continue
+		}
+		seen[resolved] = true
+		found = append(found, candidate)
+	}
+	return found
+}
+
+// checkWSL warns about Windows toolchains on PATH inside WSL: WSL appends Windows' PATH, so a Windows Go
+// or Node.js can shadow the missing Linux one, or be run by accident as go.exe
+func checkWSL() []Result {
+	if !wsl.Detected() {
+		return nil
+	}
+	var found []string
+	for _, dir := range pathEntries() {
+		if !wsl.IsWindowsPath(dir) {
+			continue
+		}
+		for _, binary := range append(toolchainBinaries, "node", "npm") {
+			for _, name := range []string{binary, binary + ".exe"} {
+				if info, err := os.Stat(filepath.Join(dir, name)); err == nil && !info.IsDir() {
+					found = append(found, filepath.Join(dir, name))
+				}
+			}
+		}
+	}
+	if len(found) == 0 {
+		return []Result{{Name: "WSL", Status: StatusOK, Detail: "no Windows toolchains on PATH"}}
+	}
+	return []Result{{
+		Name:   "WSL",
+		Status: StatusWarn,
+		Detail: "Windows toolchains on PATH: " + strings.Join(found, ", "),
+		Fix:    "decor installs and uses the Linux ones; to keep the Windows copies out of reach, set appendWindowsPath = false under [interop] in /etc/wsl.conf and run wsl --shutdown",
+	}}
+}
+
+// checkPlatform warns about platforms many official builds skip: ARMv6 boards like the first Raspberry
+// Pis, and musl distributions like Alpine, which glibc builds don't run on
+func checkPlatform() []Result {
+	p := platform.Current()
+	switch {
+	case p.Musl:
+		return []Result{{
+			Name:   "Platform",
+			Status: StatusWarn,
+			Detail: p.String() + ": builds linked against glibc won't run here, so decor picks musl builds and reports when there's none",
+			Fix:    "decor installs system packages with apt-get; on Alpine, install Python and the compilers with apk add python3 build-base",
+		}}
+	case p.Arch == "armv6":
+		return []Result{{
+			Name:   "Platform",
+			Status: StatusWarn,
+			Detail: p.String() + ": most official builds need ARMv7 or newer, so some tools and JDKs have none to install",
+		}}
+	}
+	return nil
+}
+
+// checkRosetta reports what runs as x86_64 under Rosetta on Apple silicon: decor itself, which its
+// terminal passes on to what it starts, Homebrew in /usr/local, and toolchains on PATH
+func checkRosetta() []Result {
+	p := platform.Current()
+	if p.OS != "darwin" || p.Arch != "arm64" {
+		return nil
+	}
+	var results []Result
+	if p.Rosetta {
+		results = append(results, Result{
+			Name:   "Rosetta",
+			Status: StatusWarn,
+			Detail: "decor runs as x86_64 under Rosetta, and so do programs started from this terminal where they can",
+			Fix:    "Install the arm64 build of decor, and turn off Open using Rosetta in the terminal's Get Info window",
+		})
+	}
+	if pkgmgr.BrewPrefix() == "/usr/local" {
+		results = append(results, Result{
+			Name:   "Homebrew",
+			Status: StatusWarn,
+			Detail: "Homebrew in /usr/local is the Intel one, so what it installs runs under Rosetta",
+			Fix:    "Install Homebrew for Apple silicon in /opt/homebrew, put it first on PATH and reinstall your formulae with it",
+		})
+	}
+	var translated []string
+	for _, binary := range toolchainBinaries {
+		path, err := exec.LookPath(binary)
+		if err != nil {
+			continue
+		}
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			path = resolved
+		}
+		if platform.Translated(platform.BinaryArch(path)) {
+			translated = append(translated, path)
+		}
+	}
+	if len(translated) > 0 {
+		results = append(results, Result{
+			Name:   "Rosetta",
+			Status: StatusWarn,
+			Detail: "x86_64 only, running under Rosetta: " + strings.Join(translated, ", "),
+			Fix:    "Run decor outdated to reinstall what decor installed as arm64 builds; reinstall the rest natively",
+		})
+	}
+	return results
+}
+
+// checkPolicy reports the organization's policy, if there is one, and what decor installed before it
+// that breaks it
+func checkPolicy() []Result {
+	policy, err := config.LoadPolicy()
+	if err != nil {
+		return []Result{{Name: "Policy", Status: StatusFail, Detail: err.Error(), Fix: "Ask whoever manages the policy file to fix it"}}
+	}
+	if policy.Path == "" {
+		return nil
+	}
+	records, _ := installed.List()
+	var breaking []string
+	for _, r := range records {
+		if policy.ForbidsMethod(r.Method) {
+			breaking = append(breaking, fmt.Sprintf("%s (installed with %s)", r.Name, r.Method))
+			continue
+		}
+		// Records keep what the version command printed, e.g. "go version go1.25.5 linux/amd64"
+		if version, allowed := config.VersionNumber(r.Version), policy.AllowedVersions(r.Name); allowed != "" && version != "" {
+			if ok, err := config.VersionAllowed(allowed, version); err == nil && !ok {
+				breaking = append(breaking, fmt.Sprintf("%s %s (allowed: %s)", r.Name, version, allowed))
+			}
+		}
+	}
+	if len(breaking) > 0 {
+		return []Result{{
+			Name:   "Policy",
+			Status: StatusWarn,
+			Detail: fmt.Sprintf("%s forbids what decor installed: %s", policy.Path, strings.Join(breaking, ", ")),
+			Fix:    "Update or remove those items with decor, or ask whoever manages the policy for an exception",
+		}}
+	}
+	return []Result{{Name: "Policy", Status: StatusOK, Detail: fmt.Sprintf("what decor installed follows %s (enforcement: %s)", policy.Path, policy.Enforcement)}}
+}
+
+// checkConflictingToolchains reports toolchain binaries found in more than one place on PATH
+func checkConflictingToolchains() []Result {
+	var results []Result
+	for _, binary := range toolchainBinaries {
+		found := findAll(binary)
+		if len(found) < 2 {
+			continue
+		}
+		results = append(results, Result{
+			Name:   "Toolchain " + binary,
+			Status: StatusWarn,
+			Detail: fmt.Sprintf("%d copies on PATH, %s wins (%s)", len(found), found[0], strings.Join(found[1:], ", ")),
+			Fix:    fmt.Sprintf("Remove the unused copies of %s or reorder PATH so the one you want comes first", binary),
+		})
+	}
+	if len(results) == 0 {
+		results = append(results, Result{Name: "Toolchains", Status: StatusOK, Detail: "no conflicting copies on PATH"})
+	}
+	return results
+}
+
+// checkCompilers verifies a C/C++ compiler is available, which many toolchains need
+func checkCompilers() Result {
+	for _, compiler := range []string{"cc", "gcc", "clang"} {
+		if path, err := exec.LookPath(compiler); err == nil {
+			return Result{Name: "C compiler", Status: StatusOK, Detail: path}
+		}
+	}
+
+	var fix string
+	switch runtime.GOOS {
+	case "darwin":
+		fix = "Install the Command Line Tools: xcode-select --install"
+	case "windows":
+		fix = "Install MSYS2 or Visual Studio Build Tools"
+	default:
+		fix = "Install a compiler: sudo apt-get install -y build-essential"
+	}
+	return Result{
+		Name:   "C compiler",
+		Status: StatusFail,
+		Detail: "no cc, gcc or clang found on PATH",
+		Fix:    fix,
+	}
+}
+
+// checkBrokenSymlinks looks for dangling symlinks inside PATH directories
+func checkBrokenSymlinks() Result {
+	var broken []string
+	for _, dir := range pathEntries() {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.Type()&os.ModeSymlink == 0 {
+				continue
+			}
+			link := filepath.Join(dir, entry.Name())
+			if _, err := os.Stat(link); err != nil {
+				broken = append(broken, link)
+			}
+		}
+	}
+
+	if len(broken) == 0 {
+		return Result{Name: "Symlinks", Status: StatusOK, Detail: "no broken symlinks on PATH"}
+	}
+
+	shown := broken
+	if len(shown) > 5 {
+		shown = shown[:5]
+	}
+	detail := fmt.Sprintf("%d broken: %s", len(broken), strings.Join(shown, ", "))
+	if len(broken) > len(shown) {
+		detail += ", ..."
+	}
+	return Result{
+		Name:   "Symlinks",
+		Status: StatusWarn,
+		Detail: detail,
+		Fix:    "Remove the dangling links (rm <path>) or reinstall the tools they pointed to",
+	}
+}
+
+// checkProxy verifies any configured proxy is reachable and that the download hosts respond through it
+func checkProxy() Result {
+	req, err := http.NewRequest(http.MethodHead, "https://go.dev", nil)
+	if err != nil {
+		return Result{Name: "Network", Status: StatusFail, Detail: err.Error()}
+	}
+
+	proxyURL, err := http.ProxyFromEnvironment(req)
+	if err != nil {
+		return Result{
+			Name:   "Proxy",
+			Status: StatusFail,
+			Detail: fmt.Sprintf("invalid proxy setting: %v", err),
+			Fix:    "Fix the HTTPS_PROXY / HTTP_PROXY environment variables (expected http://host:port)",
+		}
+	}
+
+	if proxyURL != nil {
+		if err := dialProxy(proxyURL); err != nil {
+			return Result{
+				Name:   "Proxy",
+				Status: StatusFail,
+				Detail: fmt.Sprintf("%s is unreachable: %v", proxyURL.Host, err),
+				Fix:    "Check the proxy is running, or unset HTTPS_PROXY / HTTP_PROXY",
+			}
+		}
+	}
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		fix := "Check your internet connection and DNS settings"
+		if proxyURL != nil {
+			fix = "Check the proxy allows access to go.dev, or set NO_PROXY for it"
+		}
+		return Result{Name: "Network", Status: StatusFail, Detail: err.Error(), Fix: fix}
+	}
+	resp.Body.Close()
+
+	detail := "go.dev reachable directly"
+	if proxyURL != nil {
+		detail = fmt.Sprintf("go.dev reachable through %s", proxyURL.Host)
+	}
+	return Result{Name: "Network", Status: StatusOK, Detail: detail}
+}
+
+// dialProxy opens a TCP connection to the proxy to confirm it is listening
+func dialProxy(proxyURL *url.URL) error {
+	host := proxyURL.Host
+	if proxyURL.Port() == "" {
+		port := "80"
+		if proxyURL.Scheme == "https" {
+			port = "443"
+		}
+		host = net.JoinHostPort(proxyURL.Hostname(), port)
+	}
+	conn, err := net.DialTimeout("tcp", host, 3*time.Second)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// checkDiskSpace makes sure the home directory has room for toolchains
+func checkDiskSpace() Result {
+	dir, err := os.UserHomeDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+
+	free, err := FreeSpace(dir)
+	if err != nil {
+		return Result{Name: "Disk space", Status: StatusWarn, Detail: fmt.Sprintf("could not determine free space: %v", err)}
+	}
+
+	detail := fmt.Sprintf("%s free in %s", FormatBytes(free), dir)
+	if free < minFreeBytes {
+		return Result{
+			Name:   "Disk space",
+			Status: StatusFail,
+			Detail: detail,
+			Fix:    fmt.Sprintf("Free up at least %s before installing toolchains", FormatBytes(minFreeBytes)),
+		}
+	}
+	return Result{Name: "Disk space", Status: StatusOK, Detail: detail}
+}
+
+// FormatBytes formats a byte count using binary units
+func FormatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
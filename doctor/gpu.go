@@ -0,0 +1,144 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"decor/gpu"
+	"decor/runner"
+)
+
+// cudaItems and rocmItems are the catalog items that need a working GPU driver
+var (
+	cudaItems = []string{"cuda toolkit", "cudnn"}
+	rocmItems = []string{"rocm"}
+)
+
+// checkGPUs reports each GPU and its driver, or nothing on machines without one
+func checkGPUs() []Result {
+	var results []Result
+	for _, g := range gpu.Detect(context.Background(), probe) {
+		if g.Driver == "" {
+			results = append(results, Result{
+				Name:   g.Name,
+				Status: StatusWarn,
+				Detail: "no driver loaded",
+				Fix:    driverHint(g.Vendor),
+			})
+			continue
+		}
+		detail := "driver " + g.Driver
+		if g.CUDA != "" {
+			detail += fmt.Sprintf(" (CUDA up to %s)", g.CUDA)
+		}
+		results = append(results, Result{Name: g.Name, Status: StatusOK, Detail: detail})
+	}
+	return results
+}
+
+// checkGPUToolkits makes sure selected CUDA or ROCm items have a GPU and driver to run on
+func checkGPUToolkits(languages []string) []Result {
+	var results []Result
+	if selectsAny(languages, cudaItems) {
+		results = append(results, checkCUDA(gpu.Detect(context.Background(), probe)))
+	}
+	if selectsAny(languages, rocmItems) {
+		results = append(results, checkROCm(gpu.Detect(context.Background(), probe)))
+	}
+	return results
+}
+
+// checkCUDA compares the toolkit apt would install against the NVIDIA driver
+func checkCUDA(gpus []gpu.GPU) Result {
+	var nvidia *gpu.GPU
+	for i := range gpus {
+		if gpus[i].Vendor == "nvidia" {
+			nvidia = &gpus[i]
+			break
+		}
+	}
+	if nvidia == nil {
+		return Result{
+			Name:   "CUDA",
+			Status: StatusFail,
+			Detail: "no NVIDIA GPU found",
+			Fix:    "CUDA only runs on NVIDIA GPUs; pick ROCm for AMD cards",
+		}
+	}
+
+	if nvidia.Driver == "" {
+		return Result{Name: "CUDA", Status: StatusFail, Detail: "no NVIDIA driver is loaded", Fix: driverHint("nvidia")}
+	}
+
+	toolkit := cudaCandidate()
+	if toolkit == "" {
+		return Result{
+			Name:   "CUDA",
+			Status: StatusWarn,
+			Detail: fmt.Sprintf("driver %s found, but couldn't tell which toolkit version would be installed", nvidia.Driver),
+		}
+	}
+	if err := gpu.CheckCUDA(*nvidia, toolkit); err != nil {
+		return Result{Name: "CUDA", Status: StatusFail, Detail: err.Error(), Fix: driverHint("nvidia")}
+	}
+	return Result{Name: "CUDA", Status: StatusOK, Detail: fmt.Sprintf("driver %s supports CUDA %s", nvidia.Driver, toolkit)}
+}
+
+// checkROCm makes sure an AMD GPU with the amdgpu driver is present
+func checkROCm(gpus []gpu.GPU) Result {
+	for _, g := range gpus {
+		if g.Vendor != "amd" {
+			continue
+		}
+		if g.Driver == "" {
+			return Result{Name: "ROCm", Status: StatusFail, Detail: "the amdgpu driver isn't loaded", Fix: driverHint("amd")}
+		}
+		return Result{Name: "ROCm", Status: StatusOK, Detail: "amdgpu driver " + g.Driver}
+	}
+	return Result{
+		Name:   "ROCm",
+		Status: StatusFail,
+		Detail: "no AMD GPU found",
+		Fix:    "ROCm only runs on AMD GPUs; pick the CUDA Toolkit for NVIDIA cards",
+	}
+}
+
+// cudaCandidate returns the major.minor CUDA version apt would install, or "" if apt can't say
+func cudaCandidate() string {
+	out, err := probe.Run(context.Background(), runner.Spec{
+		Op:       "checking the CUDA toolkit apt offers",
+		Name:     "apt-cache",
+		Args:     []string{"policy", "nvidia-cuda-toolkit"},
+		ReadOnly: true,
+		Timeout:  probeTimeout,
+	})
+	if err != nil {
+		return ""
+	}
+	if match := regexp.MustCompile(`Candidate:\s*([0-9]+\.[0-9]+)`).FindSubmatch(out); match != nil {
+		return string(match[1])
+	}
+	return ""
+}
+
+// driverHint suggests how to install the vendor's driver
+func driverHint(vendor string) string {
+	if vendor == "amd" {
+		return "Load the amdgpu kernel module, e.g. install linux-modules-extra for your kernel and reboot"
+	}
+	return "Install or update the NVIDIA driver, e.g. sudo ubuntu-drivers install, then reboot"
+}
+
+// selectsAny reports whether any of the selected languages is one of names, ignoring case
+func selectsAny(languages []string, names []string) bool {
+	for _, lang := range languages {
+		for _, name := range names {
+			if strings.EqualFold(lang, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+package doctor
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// artifactSizes estimates the disk space each language needs (download plus install)
+var artifactSizes = map[string]uint64{
+	"go":     600 << 20,
+	"python": 250 << 20,
+	"rust":   1500 << 20,
+	"c++":    2 << 30,
+	"java":   700 << 20,
+}
+
+// downloadHosts lists the hosts each language's installer downloads from
+var downloadHosts = map[string][]string{
+	"go":   {"go.dev", "dl.google.com"},
+	"rust": {"static.rust-lang.org"},
+	"java": {"api.adoptium.net"},
+}
+
+// requiredTools lists the base tools each language's installer shells out to. decor unpacks archives
+// itself, so tar, unzip and xz aren't among them.
+var requiredTools = map[string][]string{
+	"go": {"curl"},
+}
+
+// Preflight checks free disk space, base tools, download host reachability and GPU drivers for the given languages
+func Preflight(languages []string) []Result {
+	var results []Result
+	results = append(results, checkInstallSpace(languages))
+	results = append(results, checkRequiredTools(languages)...)
+	results = append(results, checkDownloadHosts(languages)...)
+	results = append(results, checkGPUToolkits(languages)...)
+	return results
+}
+
+// estimatedSize sums the estimated sizes of the given languages
+func estimatedSize(languages []string) uint64 {
+	var total uint64
+	for _, lang := range languages {
+		total += artifactSizes[strings.ToLower(lang)]
+	}
+	return total
+}
+
+// checkInstallSpace compares the estimated install size against the free space in the home directory
+func checkInstallSpace(languages []string) Result {
+	needed := estimatedSize(languages)
+
+	dir, err := os.UserHomeDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+
+	free, err := FreeSpace(dir)
+	if err != nil {
+		return Result{Name: "Disk space", Status: StatusWarn, Detail: fmt.Sprintf("could not determine free space: %v", err)}
+	}
+
+	detail := fmt.Sprintf("~%s needed, %s free in %s", FormatBytes(needed), FormatBytes(free), dir)
+	if free < needed {
+		return Result{
+			Name:   "Disk space",
+			Status: StatusFail,
+			Detail: detail,
+			Fix:    fmt.Sprintf("Free up at least %s or deselect some languages", FormatBytes(needed-free)),
+		}
+	}
+	return Result{Name: "Disk space", Status: StatusOK, Detail: detail}
+}
+
+// checkRequiredTools confirms the base tools used by the installers exist on PATH
+func checkRequiredTools(languages []string) []Result {
+	needed := make(map[string]bool)
+	for _, lang := range languages {
+		for _, tool := range requiredTools[strings.ToLower(lang)] {
+			needed[tool] = true
+		}
+	}
+
+	var tools []string
+	for tool := range needed {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+
+	var results []Result
+	for _, tool := range tools {
+		if path, err := exec.LookPath(tool); err == nil {
+			results = append(results, Result{Name: tool, Status: StatusOK, Detail: path})
+			continue
+		}
+		results = append(results, Result{
+			Name:   tool,
+			Status: StatusFail,
+			Detail: "not found on PATH",
+			Fix:    installHint(tool),
+		})
+	}
+	return results
+}
+
+// installHint suggests how to install a base tool on this platform
+func installHint(tool string) string {
+	if tool == "sh" {
+		return "Install a POSIX shell (e.g. dash or bash)"
+	}
+	if runtime.GOOS == "darwin" {
+		return fmt.Sprintf("brew install %s", tool)
+	}
+	return fmt.Sprintf("sudo apt-get install -y %s", tool)
+}
+
+// checkDownloadHosts checks each download host responds, in parallel
+func checkDownloadHosts(languages []string) []Result {
+	seen := make(map[string]bool)
+	var hosts []string
+	for _, lang := range languages {
+		for _, host := range downloadHosts[strings.ToLower(lang)] {
+			if !seen[host] {
+				seen[host] = true
+				hosts = append(hosts, host)
+			}
+		}
+	}
+
+	results := make([]Result, len(hosts))
+	client := &http.Client{Timeout: 5 * time.Second}
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		go func(i int, host string) {
+			defer wg.Done()
+			resp, err := client.Head("https://" + host)
+			if err != nil {
+				results[i] = Result{
+					Name:   host,
+					Status: StatusFail,
+					Detail: "unreachable",
+					Fix:    "Check your connection or proxy settings (run decor doctor for details)",
+				}
+				return
+			}
+			resp.Body.Close()
+			results[i] = Result{Name: host, Status: StatusOK, Detail: "reachable"}
+		}(i, host)
+	}
+	wg.Wait()
+	return results
+}
+
+// AllOK reports whether every result passed
+func AllOK(results []Result) bool {
+	for _, r := range results {
+		if r.Status != StatusOK {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,475 @@
+package download
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"decor/errs"
+	"decor/paths"
+)
+
+// client downloads artifacts. It has no overall timeout since toolchain archives
+// can take minutes, only a limit on how long the server takes to start responding.
+var client = &http.Client{
+	Transport: &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		TLSClientConfig: &tls.Config{
+			MinVersion: tls.VersionTLS12, // Minimum TLS 1.2
+		},
+		ResponseHeaderTimeout: 30 * time.Second,
+		IdleConnTimeout:       90 * time.Second,
+	},
+}
+
+// metadataTimeout bounds each attempt at a small request, like a version lookup or a checksum, which
+// should answer in seconds
+var metadataTimeout = 30 * time.Second
+
+// idleTimeout is how long a download may go without receiving anything before it's given up on, and
+// retried; 0 waits as long as the connection stays open. Downloads have no overall limit, since a JDK
+// on a slow link takes many minutes.
+var idleTimeout = time.Minute
+
+// errStalled cancels a download that's stopped receiving data
+var errStalled = errors.New("stalled")
+
+// SetTimeouts sets how long each attempt at a version lookup or other small request may take, and how
+// long a download may go without receiving data
+func SetTimeouts(metadata, idle time.Duration) {
+	metadataTimeout, idleTimeout = metadata, idle
+}
+
+// connections is how many ranged requests Fetch splits a large download into at once; 1 downloads it
+// with one request
+var connections = 1
+
+// maxConnections caps connections, since servers throttle or refuse clients that open many more
+const maxConnections = 16
+
+// segmentMin is the smallest download Fetch splits; below it the extra requests cost more than they save
+var segmentMin int64 = 32 << 20
+
+// SetConnections sets how many ranged requests Fetch splits a large download into, for high-latency
+// links where one connection can't use the bandwidth. 1 or less turns it off.
+func SetConnections(n int) {
+	connections = max(1, min(n, maxConnections))
+}
+
+// Dir returns the directory downloads are stored in, inside the cache directory
+func Dir() (string, error) {
+	cacheDir, err := paths.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(cacheDir, "downloads")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// Retry is a request that failed in a way worth trying again, about to be
+type Retry struct {
+	Op       string // e.g. "downloading https://go.dev/dl/go1.25.5.linux-amd64.tar.gz"
+	Attempt  int    // the attempt about to be made, from 2
+	Attempts int    // how many it will make at most
+	Wait     time.Duration
+	Err      error
+}
+
+// retryKey holds the function a context's requests report their retries to
+type retryKey struct{}
+
+// OnRetry returns a context whose requests call notify before each retry, to show it alongside progress
+func OnRetry(ctx context.Context, notify func(Retry)) context.Context {
+	return context.WithValue(ctx, retryKey{}, notify)
+}
+
+// attempts is how many times a request that fails transiently is tried before its error is returned
+var attempts = 4
+
+// firstBackoff is the wait before the first retry, which doubles for each one after it up to maxBackoff;
+// tests shorten it
+var firstBackoff = time.Second
+
+// maxBackoff caps the wait between retries
+const maxBackoff = 30 * time.Second
+
+// transient reports whether err is a failure that may not happen again: a network failure, like a reset
+// connection or a 5xx response, or a server that took too long
+func transient(err error) bool {
+	return errors.Is(err, errs.ErrNetworkFailure) || errors.Is(err, errs.ErrTimedOut)
+}
+
+// withRetries calls try until it succeeds, fails for good, or has failed attempts times, waiting longer
+// each time. The waits are jittered so clients that failed together don't all retry together.
+func withRetries[T any](ctx context.Context, op string, try func() (T, error)) (T, error) {
+	backoff := firstBackoff
+	for attempt := 1; ; attempt++ {
+		result, err := try()
+		if err == nil || attempt >= attempts || !transient(err) || ctx.Err() != nil {
+			return result, err
+		}
+		wait := backoff/2 + rand.N(backoff/2+1)
+		if notify, ok := ctx.Value(retryKey{}).(func(Retry)); ok {
+			notify(Retry{Op: op, Attempt: attempt + 1, Attempts: attempts, Wait: wait, Err: err})
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return result, err
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// statusError is the error for a response that isn't 200 OK. 5xx responses and 429 Too Many Requests are
+// network failures, which are retried.
+func statusError(op string, resp *http.Response) error {
+	err := fmt.Errorf("%s", resp.Status)
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+		return errs.New(errs.ErrNetworkFailure, op, err)
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+// Fetch downloads url into a new file in the downloads directory and returns its path, which ends in
+// the URL's file name. The caller removes the file once it's done with it. Transient failures are
+// retried, starting the download again.
+func Fetch(ctx context.Context, url string) (string, error) {
+	return withRetries(ctx, "downloading "+url, func() (string, error) {
+		return fetch(ctx, url)
+	})
+}
+
+// fetch makes one attempt at downloading url for Fetch
+func fetch(ctx context.Context, url string) (file string, err error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	ctx, watch := watchStalls(ctx)
+	defer func() {
+		if err != nil && errors.Is(context.Cause(ctx), errStalled) {
+			err = errs.New(errs.ErrTimedOut, "downloading "+url, fmt.Errorf("nothing arrived for %s", idleTimeout))
+		}
+		watch.stop()
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	op := "downloading " + url
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", errs.Classify(op, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", statusError(op, resp)
+	}
+
+	// Every fetch writes its own file, so concurrent downloads of the same URL (parallel installs, or the
+	// daemon and the TUI) never replace or delete each other's. It keeps a .part suffix until it's
+	// complete so an interrupted download never looks finished.
+	name := path.Base(req.URL.Path)
+	if name == "/" || name == "." {
+		name = req.URL.Host
+	}
+	tmp, err := os.CreateTemp(dir, "*-"+name+".part")
+	if err != nil {
+		return "", err
+	}
+	dest := strings.TrimSuffix(tmp.Name(), ".part")
+
+	if n := segments(resp); n > 1 {
+		err = fetchSegments(ctx, resp, tmp, n, watch)
+	} else {
+		_, err = io.Copy(tmp, watch.reader(resp.Body))
+	}
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", errs.Classify(op, err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return dest, nil
+}
+
+// segments returns how many ranged requests to download resp's body with: connections for a large file
+// from a server that takes ranges, otherwise 1
+func segments(resp *http.Response) int {
+	if connections < 2 || resp.ContentLength < segmentMin || resp.Header.Get("Accept-Ranges") != "bytes" {
+		return 1
+	}
+	return connections
+}
+
+// fetchSegments downloads resp's body into file in n parts at once, each written where it goes: the first
+// from resp itself, which is already streaming, and the rest with ranged requests to where resp came from
+func fetchSegments(ctx context.Context, resp *http.Response, file *os.File, n int, watch *watchdog) error {
+	if err := file.Truncate(resp.ContentLength); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	part := (resp.ContentLength + int64(n) - 1) / int64(n)
+	done := make(chan error, n)
+	go func() {
+		done <- copyExactly(io.NewOffsetWriter(file, 0), watch.reader(resp.Body), part)
+	}()
+	for i := 1; i < n; i++ {
+		start := int64(i) * part
+		end := min(start+part, resp.ContentLength) - 1
+		go func() {
+			done <- fetchRange(ctx, resp, file, start, end, watch)
+		}()
+	}
+
+	var first error
+	for range n {
+		if err := <-done; err != nil && first == nil {
+			// One part failing fails the download, so stop the others rather than wait for them
+			first = err
+			cancel()
+			resp.Body.Close()
+		}
+	}
+	return first
+}
+
+// fetchRange downloads bytes start to end, inclusive, of what resp came from into the same place in file.
+// If-Range makes sure they're from the same file, should it change on the server in the meantime.
+func fetchRange(ctx context.Context, resp *http.Response, file *os.File, start, end int64, watch *watchdog) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, resp.Request.URL.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+	if validator := resp.Header.Get("ETag"); validator != "" {
+		req.Header.Set("If-Range", validator)
+	} else if validator := resp.Header.Get("Last-Modified"); validator != "" {
+		req.Header.Set("If-Range", validator)
+	}
+	part, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer part.Body.Close()
+	want := fmt.Sprintf("bytes %d-%d/", start, end)
+	if part.StatusCode != http.StatusPartialContent || !strings.HasPrefix(part.Header.Get("Content-Range"), want) {
+		return fmt.Errorf("asked for bytes %d-%d, got %s %s", start, end, part.Status, part.Header.Get("Content-Range"))
+	}
+	return copyExactly(io.NewOffsetWriter(file, start), watch.reader(part.Body), end-start+1)
+}
+
+// watchdog cancels a download that's received nothing for idleTimeout; every read that gets data puts
+// it off
+type watchdog struct {
+	mu     sync.Mutex
+	timer  *time.Timer // nil when idleTimeout is 0
+	cancel context.CancelCauseFunc
+}
+
+// watchStalls returns a context for a download that the watchdog cancels with errStalled
+func watchStalls(ctx context.Context) (context.Context, *watchdog) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	w := &watchdog{}
+	if idleTimeout > 0 {
+		w.timer = time.AfterFunc(idleTimeout, func() { cancel(errStalled) })
+	}
+	w.cancel = cancel
+	return ctx, w
+}
+
+// reader returns r, putting the watchdog off whenever reading from it gets data
+func (w *watchdog) reader(r io.Reader) io.Reader {
+	if w.timer == nil {
+		return r
+	}
+	return readerFunc(func(p []byte) (int, error) {
+		n, err := r.Read(p)
+		if n > 0 {
+			w.mu.Lock()
+			w.timer.Reset(idleTimeout)
+			w.mu.Unlock()
+		}
+		return n, err
+	})
+}
+
+// stop stops the watchdog once the download's over
+func (w *watchdog) stop() {
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.cancel(nil)
+}
+
+// readerFunc is a function that reads like an io.Reader
+type readerFunc func(p []byte) (int, error)
+
+func (f readerFunc) Read(p []byte) (int, error) { return f(p) }
+
+// copyExactly copies n bytes from r to w, failing if r ends first
+func copyExactly(w io.Writer, r io.Reader, n int64) error {
+	written, err := io.Copy(w, io.LimitReader(r, n))
+	if err == nil && written < n {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// maxTextSize caps how much of a small text resource Text will read
+const maxTextSize = 1 << 20
+
+// Text fetches a small text resource such as a published checksum or a release API's answer, retrying
+// transient failures
+func Text(ctx context.Context, url string) (string, error) {
+	return withRetries(ctx, "fetching "+url, func() (string, error) {
+		return text(ctx, url)
+	})
+}
+
+// text makes one attempt at fetching url for Text
+func text(ctx context.Context, url string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, metadataTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	op := "fetching " + url
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", errs.Classify(op, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", statusError(op, resp)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxTextSize))
+	if err != nil {
+		return "", errs.Classify(op, err)
+	}
+	return string(body), nil
+}
+
+// Size asks for the length of the resource at url without downloading it, following redirects and
+// retrying transient failures
+func Size(ctx context.Context, url string) (int64, error) {
+	return withRetries(ctx, "checking the size of "+url, func() (int64, error) {
+		return size(ctx, url)
+	})
+}
+
+// size makes one attempt at asking for url's length for Size
+func size(ctx context.Context, url string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, metadataTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	op := "checking the size of " + url
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, errs.Classify(op, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, statusError(op, resp)
+	}
+	if resp.ContentLength < 0 {
+		return 0, fmt.Errorf("%s: the server didn't say", op)
+	}
+	return resp.ContentLength, nil
+}
+
+// VerifySHA256 checks the file's SHA-256 digest against the expected hex string, which may be a
+// sha256sum line with the file name after the digest
+func VerifySHA256(file, expected string) error {
+	if fields := strings.Fields(expected); len(fields) > 0 {
+		expected = fields[0]
+	}
+	actual, err := SHA256(file)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(actual, strings.TrimSpace(expected)) {
+		return errs.New(errs.ErrChecksumMismatch, "verifying "+filepath.Base(file),
+			fmt.Errorf("expected sha256 %s, got %s", expected, actual))
+	}
+	return nil
+}
+
+// SHA256 returns the file's SHA-256 digest as a hex string
+func SHA256(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Purge removes everything in the cache directory, returning the bytes freed and the directory purged
+func Purge() (int64, string, error) {
+	cacheDir, err := paths.CacheDir()
+	if err != nil {
+		return 0, "", err
+	}
+
+	var freed int64
+	filepath.WalkDir(cacheDir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			freed += info.Size()
+		}
+		return nil
+	})
+
+	entries, err := os.ReadDir(cacheDir)
+	if os.IsNotExist(err) {
+		return 0, cacheDir, nil
+	}
+	if err != nil {
+		return 0, cacheDir, err
+	}
+	for _, entry := range entries {
+		if err := os.RemoveAll(filepath.Join(cacheDir, entry.Name())); err != nil {
+			return freed, cacheDir, err
+		}
+	}
+	return freed, cacheDir, nil
+}
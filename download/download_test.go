@@ -0,0 +1,380 @@
+package download
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"decor/errs"
+)
+
+func TestVerifySHA256(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "go1.25.5.linux-amd64.tar.gz")
+	content := []byte("not really a tarball\n")
+	if err := os.WriteFile(file, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(content)
+	digest := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name     string
+		file     string
+		expected string
+		wantErr  error // nil for success
+	}{
+		{"matches", file, digest, nil},
+		{"uppercase", file, strings.ToUpper(digest), nil},
+		{"surrounding whitespace", file, "  " + digest + "\n", nil},
+		{"sha256sum line", file, digest + "  OpenJDK21U-jdk_x64_linux_hotspot.tar.gz\n", nil},
+		{"mismatch", file, strings.Repeat("0", 64), errs.ErrChecksumMismatch},
+		{"truncated digest", file, digest[:32], errs.ErrChecksumMismatch},
+		{"empty digest", file, "", errs.ErrChecksumMismatch},
+		{"missing file", file + ".missing", digest, os.ErrNotExist},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifySHA256(tt.file, tt.expected)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("VerifySHA256() = %v, want success", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifySHA256() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFetchConcurrent(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("LocalAppData", t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("archive contents"))
+	}))
+	defer server.Close()
+
+	// Each caller gets its own file, so removing one never pulls another's out from under it
+	const callers = 4
+	files := make([]string, callers)
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			file, err := Fetch(context.Background(), server.URL+"/go1.25.5.linux-amd64.tar.gz")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			files[i] = file
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool)
+	for _, file := range files {
+		if seen[file] {
+			t.Fatalf("two fetches returned %s", file)
+		}
+		seen[file] = true
+		if !strings.HasSuffix(file, "go1.25.5.linux-amd64.tar.gz") {
+			t.Errorf("Fetch() = %s, want it to keep the URL's file name", file)
+		}
+	}
+	os.Remove(files[0])
+	for _, file := range files[1:] {
+		if data, err := os.ReadFile(file); err != nil || string(data) != "archive contents" {
+			t.Errorf("%s = %q, %v after another caller removed its download", file, data, err)
+		}
+	}
+}
+
+// quickRetries shortens the waits between retries for the test
+func quickRetries(t *testing.T) {
+	original := firstBackoff
+	firstBackoff = time.Millisecond
+	t.Cleanup(func() { firstBackoff = original })
+}
+
+func TestFetchStatus(t *testing.T) {
+	quickRetries(t)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("LocalAppData", t.TempDir())
+
+	tests := []struct {
+		status      int
+		wantNetwork bool
+	}{
+		{http.StatusNotFound, false},
+		{http.StatusBadGateway, true},
+	}
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+		_, err := Fetch(context.Background(), server.URL+"/file")
+		server.Close()
+		if err == nil {
+			t.Errorf("Fetch() with status %d succeeded", tt.status)
+			continue
+		}
+		if got := errors.Is(err, errs.ErrNetworkFailure); got != tt.wantNetwork {
+			t.Errorf("Fetch() with status %d = %v, network failure %t, want %t", tt.status, err, got, tt.wantNetwork)
+		}
+	}
+
+	dir, _ := Dir()
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("failed fetches left %d files in %s", len(entries), dir)
+	}
+}
+
+func TestFetchSegments(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("LocalAppData", t.TempDir())
+	originalMin := segmentMin
+	t.Cleanup(func() { segmentMin = originalMin; SetConnections(1) })
+	segmentMin = 100
+	SetConnections(4)
+
+	contents := []byte(strings.Repeat("0123456789abcdef", 100))
+	tests := []struct {
+		name       string
+		ranges     bool // whether the server takes ranged requests
+		lies       bool // whether it says it does, then sends the whole file
+		size       int
+		wantRanges int32
+	}{
+		{"split", true, false, len(contents), 3},
+		{"small", true, false, 50, 0},
+		{"no ranges", false, false, len(contents), 0},
+		{"ignored ranges", true, true, len(contents), 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ranged atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Range") != "" {
+					ranged.Add(1)
+				}
+				if !tt.ranges || tt.lies && r.Header.Get("Range") != "" {
+					w.Write(contents[:tt.size])
+					return
+				}
+				http.ServeContent(w, r, "jdk.tar.gz", time.Unix(0, 0), strings.NewReader(string(contents[:tt.size])))
+			}))
+			defer server.Close()
+
+			file, err := Fetch(context.Background(), server.URL+"/jdk.tar.gz")
+			if tt.lies {
+				dir, _ := Dir()
+				if entries, _ := os.ReadDir(dir); err == nil || len(entries) != 0 {
+					t.Errorf("Fetch() = %v, leaving %d files, from a server ignoring ranges", err, len(entries))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.Remove(file)
+			if data, _ := os.ReadFile(file); string(data) != string(contents[:tt.size]) {
+				t.Errorf("downloaded %d bytes that don't match the %d served", len(data), tt.size)
+			}
+			if got := ranged.Load(); got != tt.wantRanges {
+				t.Errorf("made %d ranged requests, want %d", got, tt.wantRanges)
+			}
+		})
+	}
+}
+
+func TestSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/moved":
+			http.Redirect(w, r, "/go.tar.gz", http.StatusFound)
+		case "/go.tar.gz":
+			if r.Method != http.MethodHead {
+				t.Errorf("got a %s request", r.Method)
+			}
+			w.Header().Set("Content-Length", "1234")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	for _, path := range []string{"/go.tar.gz", "/moved"} {
+		if size, err := Size(context.Background(), server.URL+path); err != nil || size != 1234 {
+			t.Errorf("Size(%s) = %d, %v", path, size, err)
+		}
+	}
+	if _, err := Size(context.Background(), server.URL+"/missing"); err == nil {
+		t.Error("a missing file has a size")
+	}
+}
+
+func TestRetries(t *testing.T) {
+	quickRetries(t)
+	tests := []struct {
+		failures, status int
+		wantRequests     int
+		wantErr          bool
+	}{
+		{2, http.StatusServiceUnavailable, 3, false},
+		{2, http.StatusTooManyRequests, 3, false},
+		{5, http.StatusBadGateway, attempts, true},
+		// A missing file won't turn up by asking again
+		{1, http.StatusNotFound, 1, true},
+	}
+	for _, tt := range tests {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) <= int32(tt.failures) {
+				w.WriteHeader(tt.status)
+				return
+			}
+			w.Write([]byte("ok"))
+		}))
+		var retries []int
+		ctx := OnRetry(context.Background(), func(r Retry) { retries = append(retries, r.Attempt) })
+		got, err := Text(ctx, server.URL)
+		server.Close()
+		if (err != nil) != tt.wantErr || (err == nil && got != "ok") {
+			t.Errorf("after %d × %d, Text = %q, %v", tt.failures, tt.status, got, err)
+		}
+		if int(requests.Load()) != tt.wantRequests || len(retries) != tt.wantRequests-1 {
+			t.Errorf("after %d × %d, made %d requests with retries %v, want %d", tt.failures, tt.status, requests.Load(), retries, tt.wantRequests)
+		}
+	}
+}
+
+func TestTimeouts(t *testing.T) {
+	quickRetries(t)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("LocalAppData", t.TempDir())
+	originalMetadata, originalIdle := metadataTimeout, idleTimeout
+	t.Cleanup(func() { SetTimeouts(originalMetadata, originalIdle) })
+	SetTimeouts(50*time.Millisecond, 50*time.Millisecond)
+
+	// The first attempt stalls halfway and is given up on; the second arrives slowly but steadily, which
+	// a download may take as long as it likes over
+	var requests atomic.Int32
+	release := make(chan struct{})
+	defer close(release)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "8")
+		w.Write([]byte("half"))
+		w.(http.Flusher).Flush()
+		if requests.Add(1) == 1 {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+			return
+		}
+		for _, b := range []byte("done") {
+			time.Sleep(30 * time.Millisecond)
+			w.Write([]byte{b})
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+	file, err := Fetch(context.Background(), server.URL+"/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(file); string(got) != "halfdone" || requests.Load() != 2 {
+		t.Errorf("after %d requests, fetched %q", requests.Load(), got)
+	}
+
+	// A version lookup that doesn't answer in time times out, on every attempt
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer slow.Close()
+	if _, err := Text(context.Background(), slow.URL); !errors.Is(err, errs.ErrTimedOut) {
+		t.Errorf("Text from a server that doesn't answer = %v, want a timeout", err)
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	quickRetries(t)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("LocalAppData", t.TempDir())
+	original := metadataMaxAge
+	t.Cleanup(func() { SetMetadataMaxAge(original) })
+	SetMetadataMaxAge(time.Hour)
+
+	var requests atomic.Int32
+	var down atomic.Bool
+	var sent sync.Map // the If-None-Match each request sent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		sent.Store(n, r.Header.Get("If-None-Match"))
+		switch {
+		case down.Load():
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.Header.Get("If-None-Match") == `"v1"`:
+			w.WriteHeader(http.StatusNotModified)
+		default:
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte(`{"latest":"1.2.3"}`))
+		}
+	}))
+	defer server.Close()
+	url := server.URL + "/releases/latest"
+
+	check := func(ctx context.Context, step string, wantRequests int32) time.Time {
+		t.Helper()
+		body, fetched, err := Metadata(ctx, url)
+		if err != nil || body != `{"latest":"1.2.3"}` || fetched.IsZero() {
+			t.Fatalf("%s: got %q fetched %v, %v", step, body, fetched, err)
+		}
+		if got := requests.Load(); got != wantRequests {
+			t.Errorf("%s: %d requests made, want %d", step, got, wantRequests)
+		}
+		return fetched
+	}
+	first := check(context.Background(), "first lookup", 1)
+	if again := check(context.Background(), "lookup within the max age", 1); !again.Equal(first) {
+		t.Errorf("a cached answer was fetched at %v, want %v", again, first)
+	}
+
+	// Past the max age, or when refreshed, the answer is revalidated, and an unchanged one comes back a 304
+	SetMetadataMaxAge(0)
+	check(context.Background(), "lookup past the max age", 2)
+	SetMetadataMaxAge(time.Hour)
+	check(Refresh(context.Background()), "refreshed lookup", 3)
+	if etag, _ := sent.Load(int32(3)); etag != `"v1"` {
+		t.Errorf("a revalidation sent If-None-Match %q, want the cached ETag", etag)
+	}
+
+	// With the server down the cached answer is used however old it is, and without one the lookup fails
+	down.Store(true)
+	SetMetadataMaxAge(0)
+	if body, _, err := Metadata(context.Background(), url); err != nil || body == "" {
+		t.Errorf("with the server down got %q, %v, want the cached answer", body, err)
+	}
+	if _, _, err := Metadata(context.Background(), server.URL+"/uncached"); err == nil {
+		t.Error("an uncached lookup with the server down succeeded")
+	}
+}
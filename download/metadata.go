@@ -0,0 +1,141 @@
+package download
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"decor/errs"
+	"decor/paths"
+)
+
+// metadataMaxAge is how long a version API's answer is used without asking again; after that it's
+// revalidated with its ETag or Last-Modified date
+var metadataMaxAge = 6 * time.Hour
+
+// SetMetadataMaxAge sets how long a version API's answer is used before it's asked for again
+func SetMetadataMaxAge(d time.Duration) {
+	metadataMaxAge = d
+}
+
+// cachedMetadata is a version API's answer as it's kept under the cache directory
+type cachedMetadata struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	Fetched      time.Time `json:"fetched"` // when the server last said this was current
+	Body         string    `json:"body"`
+}
+
+// refreshKey marks a context whose metadata is asked for again however recently it was fetched
+type refreshKey struct{}
+
+// Refresh returns a context whose calls to Metadata ask the server again, as a refresh key does
+func Refresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, refreshKey{}, true)
+}
+
+// Metadata fetches a version API's answer, like a project's latest release, keeping it under the cache
+// directory so repeated launches don't ask again: an answer fetched within metadataMaxAge is used as it
+// is, and an older one is revalidated, so an unchanged one costs a 304 rather than the whole body. When
+// the server can't be reached the cached answer is used however old it is. It returns when the answer
+// was fetched, or last confirmed current, for showing how old it is.
+func Metadata(ctx context.Context, url string) (string, time.Time, error) {
+	path := metadataPath(url)
+	cached, ok := loadMetadata(path, url)
+	if ok && ctx.Value(refreshKey{}) == nil && time.Since(cached.Fetched) < metadataMaxAge {
+		return cached.Body, cached.Fetched, nil
+	}
+
+	fresh, err := withRetries(ctx, "fetching "+url, func() (cachedMetadata, error) {
+		return revalidate(ctx, url, cached)
+	})
+	if err != nil {
+		if ok && ctx.Err() == nil {
+			return cached.Body, cached.Fetched, nil
+		}
+		return "", time.Time{}, err
+	}
+	saveMetadata(path, fresh)
+	return fresh.Body, fresh.Fetched, nil
+}
+
+// revalidate asks for url, sending what's known of cached so an unchanged answer comes back as a 304,
+// and returns the answer to keep
+func revalidate(ctx context.Context, url string, cached cachedMetadata) (cachedMetadata, error) {
+	ctx, cancel := context.WithTimeout(ctx, metadataTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return cached, err
+	}
+	if cached.ETag != "" {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+	if cached.LastModified != "" {
+		req.Header.Set("If-Modified-Since", cached.LastModified)
+	}
+	op := "fetching " + url
+	resp, err := client.Do(req)
+	if err != nil {
+		return cached, errs.Classify(op, err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached.Body != "":
+		cached.Fetched = time.Now()
+		return cached, nil
+	case resp.StatusCode != http.StatusOK:
+		return cached, statusError(op, resp)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxTextSize))
+	if err != nil {
+		return cached, errs.Classify(op, err)
+	}
+	return cachedMetadata{
+		URL:          url,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Fetched:      time.Now(),
+		Body:         string(body),
+	}, nil
+}
+
+// metadataPath is where url's answer is kept, named for a hash of it
+func metadataPath(url string) string {
+	cacheDir, err := paths.CacheDir()
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(cacheDir, "metadata", hex.EncodeToString(sum[:8])+".json")
+}
+
+// loadMetadata reads the answer kept at path, if it's there and for url
+func loadMetadata(path, url string) (cachedMetadata, bool) {
+	var cached cachedMetadata
+	data, err := os.ReadFile(path)
+	if path == "" || err != nil || json.Unmarshal(data, &cached) != nil || cached.URL != url {
+		return cachedMetadata{}, false
+	}
+	return cached, true
+}
+
+// saveMetadata keeps an answer at path. Failing to is no reason to fail the lookup, so it's ignored:
+// the next launch asks again.
+func saveMetadata(path string, cached cachedMetadata) {
+	data, err := json.Marshal(cached)
+	if path == "" || err != nil || os.MkdirAll(filepath.Dir(path), 0o755) != nil {
+		return
+	}
+	tmp := path + ".tmp"
+	if os.WriteFile(tmp, data, 0o644) == nil {
+		os.Rename(tmp, path)
+	}
+}
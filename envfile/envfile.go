@@ -0,0 +1,337 @@
+// Package envfile keeps the environment variables and PATH directories decor's installs need, like
+// JAVA_HOME or ~/.cargo/bin, in one generated script per shell. A shell's startup file sources the
+// script with one line, rather than decor editing every startup file itself.
+package envfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"decor/paths"
+)
+
+// FileName is the record of what each item sets, in decor's state directory
+const FileName = "env.json"
+
+// Shells are the shells a script is written for: POSIX sh, which bash and zsh source too, fish and
+// PowerShell
+var Shells = []string{"sh", "fish", "pwsh"}
+
+// scripts are each shell's script, in decor's config directory
+var scripts = map[string]string{"sh": "env.sh", "fish": "env.fish", "pwsh": "env.ps1"}
+
+// Entry is what an item sets: variables, directories it puts at the front of PATH, directories of
+// completion functions zsh looks in, and programs whose shell hook is loaded, with `program hook shell`
+// as direnv prints it
+type Entry struct {
+	Vars  map[string]string `json:"vars,omitempty"`
+	Path  []string          `json:"path,omitempty"`
+	FPath []string          `json:"fpath,omitempty"`
+	Hooks []string          `json:"hooks,omitempty"`
+}
+
+// Empty reports whether the entry sets nothing
+func (e Entry) Empty() bool {
+	return len(e.Vars) == 0 && len(e.Path) == 0 && len(e.FPath) == 0 && len(e.Hooks) == 0
+}
+
+// Applied reports whether the current environment already has the entry's variables and PATH
+// directories, as it does once a shell has sourced the script. Hooks leave no trace in the
+// environment, so they aren't checked.
+func (e Entry) Applied() bool {
+	for name, value := range e.Vars {
+		if os.Getenv(name) != value {
+			return false
+		}
+	}
+	path := filepath.SplitList(os.Getenv("PATH"))
+	for _, dir := range e.Path {
+		if !slices.Contains(path, dir) {
+			return false
+		}
+	}
+	return true
+}
+
+// file is the on-disk layout, versioned so the format can change later
+type file struct {
+	Version int              `json:"version"`
+	Items   map[string]Entry `json:"items"` // keyed by lowercased item name
+}
+
+// mu serializes read-modify-write cycles, since languages install concurrently
+var mu sync.Mutex
+
+// recordPath returns where the entries are kept
+func recordPath() (string, error) {
+	dir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// Script returns where shell's script is written
+func Script(shell string) (string, error) {
+	name, ok := scripts[shell]
+	if !ok {
+		return "", fmt.Errorf("no environment script for %s; the shells are %s", shell, strings.Join(Shells, ", "))
+	}
+	dir, err := paths.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// SourceLine returns the line that loads shell's script, for the shell's startup file
+func SourceLine(shell string) (string, error) {
+	script, err := Script(shell)
+	if err != nil {
+		return "", err
+	}
+	switch shell {
+	case "fish":
+		return "source " + fishQuote(script), nil
+	case "pwsh":
+		return ". " + pwshQuote(script), nil
+	}
+	return ". " + shQuote(script), nil
+}
+
+// Shell picks the script for a login shell path like $SHELL's: fish, pwsh for PowerShell, and sh for
+// anything else. With no shell, it's pwsh on Windows.
+func Shell(login, goos string) string {
+	switch strings.TrimSuffix(filepath.Base(login), ".exe") {
+	case "fish":
+		return "fish"
+	case "pwsh", "powershell":
+		return "pwsh"
+	case ".", "":
+		if goos == "windows" {
+			return "pwsh"
+		}
+	}
+	return "sh"
+}
+
+// load reads the entries, returning none if there are none yet; the caller holds mu
+func load() (file, error) {
+	db := file{Version: 1, Items: make(map[string]Entry)}
+	path, err := recordPath()
+	if err != nil {
+		return db, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return db, nil
+	}
+	if err != nil {
+		return db, err
+	}
+	if err := json.Unmarshal(data, &db); err != nil {
+		return db, err
+	}
+	if db.Items == nil {
+		db.Items = make(map[string]Entry)
+	}
+	return db, nil
+}
+
+// save writes the entries and every shell's script; the caller holds mu
+func save(db file) error {
+	path, err := recordPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(db, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeAtomic(path, append(data, '\n')); err != nil {
+		return err
+	}
+	for _, shell := range Shells {
+		script, err := Script(shell)
+		if err != nil {
+			return err
+		}
+		if err := writeAtomic(script, []byte(Render(db.Items, shell))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeAtomic replaces path's contents, so a crash never leaves half a file
+func writeAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Set replaces what item sets, and rewrites the scripts
+func Set(item string, entry Entry) error {
+	mu.Lock()
+	defer mu.Unlock()
+	db, err := load()
+	if err != nil {
+		return err
+	}
+	db.Items[strings.ToLower(item)] = entry
+	return save(db)
+}
+
+// Remove drops what item sets, for when it's uninstalled, and rewrites the scripts
+func Remove(item string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	db, err := load()
+	if err != nil {
+		return err
+	}
+	key := strings.ToLower(item)
+	if _, ok := db.Items[key]; !ok {
+		return nil
+	}
+	delete(db.Items, key)
+	return save(db)
+}
+
+// Refresh rewrites the scripts from what the items set, creating them if nothing has been set yet, so
+// the line sourcing them always works
+func Refresh() error {
+	mu.Lock()
+	defer mu.Unlock()
+	db, err := load()
+	if err != nil {
+		return err
+	}
+	return save(db)
+}
+
+// Load returns what each item sets, keyed by lowercased item name
+func Load() (map[string]Entry, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	db, err := load()
+	return db.Items, err
+}
+
+// Render writes items' variables and PATH directories as a script for shell. Items are taken in name
+// order, and a directory more than one item adds is added once. Each directory is only added when
+// PATH doesn't already have it, so sourcing the script twice changes nothing.
+func Render(items map[string]Entry, shell string) string {
+	names := make([]string, 0, len(items))
+	for name := range items {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	vars := make(map[string]string)
+	var dirs, functions, hooks []string
+	for _, name := range names {
+		for variable, value := range items[name].Vars {
+			vars[variable] = value
+		}
+		for _, dir := range items[name].Path {
+			if !slices.Contains(dirs, dir) {
+				dirs = append(dirs, dir)
+			}
+		}
+		for _, dir := range items[name].FPath {
+			if !slices.Contains(functions, dir) {
+				functions = append(functions, dir)
+			}
+		}
+		for _, program := range items[name].Hooks {
+			if !slices.Contains(hooks, program) {
+				hooks = append(hooks, program)
+			}
+		}
+	}
+	variables := make([]string, 0, len(vars))
+	for variable := range vars {
+		variables = append(variables, variable)
+	}
+	sort.Strings(variables)
+
+	var b strings.Builder
+	b.WriteString("# Written by decor, which rewrites it as items are installed and removed\n")
+	for _, variable := range variables {
+		switch shell {
+		case "fish":
+			fmt.Fprintf(&b, "set -gx %s %s\n", variable, fishQuote(vars[variable]))
+		case "pwsh":
+			fmt.Fprintf(&b, "$env:%s = %s\n", variable, pwshQuote(vars[variable]))
+		default:
+			fmt.Fprintf(&b, "export %s=%s\n", variable, shQuote(vars[variable]))
+		}
+	}
+	// Prepending in reverse leaves the directories in order at the front of PATH
+	for i := len(dirs) - 1; i >= 0; i-- {
+		switch dir := dirs[i]; shell {
+		case "fish":
+			fmt.Fprintf(&b, "contains -- %[1]s $PATH; or set -gx PATH %[1]s $PATH\n", fishQuote(dir))
+		case "pwsh":
+			fmt.Fprintf(&b, "if (($env:PATH -split [IO.Path]::PathSeparator) -notcontains %[1]s) { $env:PATH = %[1]s + [IO.Path]::PathSeparator + $env:PATH }\n", pwshQuote(dir))
+		default:
+			fmt.Fprintf(&b, "case \":$PATH:\" in *:%[1]s:*) ;; *) export PATH=%[1]s:\"$PATH\" ;; esac\n", shQuote(dir))
+		}
+	}
+	// Only zsh has fpath, and eval keeps its array syntax from other shells' parsers; typeset -U drops
+	// the copies sourcing the script again adds
+	for i := len(functions) - 1; i >= 0 && shell == "sh"; i-- {
+		fmt.Fprintf(&b, "if [ -n \"${ZSH_VERSION-}\" ]; then eval \"fpath=(%s \\$fpath); typeset -U fpath\"; fi\n", shQuote(functions[i]))
+	}
+	// Hooks come last, once PATH finds the programs, and are skipped while a program is missing. The sh
+	// script asks for bash's or zsh's hook, whichever is sourcing it.
+	for _, program := range hooks {
+		switch shell {
+		case "fish":
+			fmt.Fprintf(&b, "command -q %[1]s; and %[1]s hook fish | source\n", program)
+		case "pwsh":
+			fmt.Fprintf(&b, "if (Get-Command %[1]s -ErrorAction SilentlyContinue) { Invoke-Expression (& %[1]s hook pwsh | Out-String) }\n", program)
+		default:
+			fmt.Fprintf(&b, "if command -v %[1]s >/dev/null 2>&1; then if [ -n \"${ZSH_VERSION-}\" ]; then eval \"$(%[1]s hook zsh)\"; elif [ -n \"${BASH_VERSION-}\" ]; then eval \"$(%[1]s hook bash)\"; fi; fi\n", program)
+		}
+	}
+	return b.String()
+}
+
+// shQuote quotes s for POSIX sh
+func shQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishQuote quotes s for fish, where backslashes and quotes are escaped inside single quotes
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
+
+// pwshQuote quotes s for PowerShell, where a quote inside single quotes is doubled
+func pwshQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
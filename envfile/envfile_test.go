@@ -0,0 +1,139 @@
+package envfile
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// tempDirs points the state and config directories at a fresh temporary directory
+func tempDirs(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_STATE_HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("LOCALAPPDATA", dir)
+	t.Setenv("APPDATA", dir)
+}
+
+func TestRender(t *testing.T) {
+	items := map[string]Entry{
+		"java": {Vars: map[string]string{"JAVA_HOME": "/opt/java/current"}, Path: []string{"/opt/java/current/bin"}},
+		"rust": {Path: []string{"/home/me/.cargo/bin", "/home/me/.local/bin"}},
+		"jq":   {Path: []string{"/home/me/.local/bin"}},
+	}
+	tests := []struct {
+		shell string
+		want  []string
+	}{
+		{"sh", []string{
+			"export JAVA_HOME='/opt/java/current'",
+			`case ":$PATH:" in *:'/home/me/.cargo/bin':*) ;; *) export PATH='/home/me/.cargo/bin':"$PATH" ;; esac`,
+			`case ":$PATH:" in *:'/home/me/.local/bin':*) ;; *) export PATH='/home/me/.local/bin':"$PATH" ;; esac`,
+			`case ":$PATH:" in *:'/opt/java/current/bin':*) ;; *) export PATH='/opt/java/current/bin':"$PATH" ;; esac`,
+		}},
+		{"fish", []string{
+			"set -gx JAVA_HOME '/opt/java/current'",
+			"contains -- '/home/me/.cargo/bin' $PATH; or set -gx PATH '/home/me/.cargo/bin' $PATH",
+			"contains -- '/home/me/.local/bin' $PATH; or set -gx PATH '/home/me/.local/bin' $PATH",
+			"contains -- '/opt/java/current/bin' $PATH; or set -gx PATH '/opt/java/current/bin' $PATH",
+		}},
+		{"pwsh", []string{
+			"$env:JAVA_HOME = '/opt/java/current'",
+			"if (($env:PATH -split [IO.Path]::PathSeparator) -notcontains '/home/me/.cargo/bin') { $env:PATH = '/home/me/.cargo/bin' + [IO.Path]::PathSeparator + $env:PATH }",
+			"if (($env:PATH -split [IO.Path]::PathSeparator) -notcontains '/home/me/.local/bin') { $env:PATH = '/home/me/.local/bin' + [IO.Path]::PathSeparator + $env:PATH }",
+			"if (($env:PATH -split [IO.Path]::PathSeparator) -notcontains '/opt/java/current/bin') { $env:PATH = '/opt/java/current/bin' + [IO.Path]::PathSeparator + $env:PATH }",
+		}},
+	}
+	for _, tt := range tests {
+		// Directories are prepended last first, so java's, first by item name, ends up at the front
+		lines := strings.Split(strings.TrimSpace(Render(items, tt.shell)), "\n")[1:]
+		if strings.Join(lines, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("Render for %s =\n%s\nwant\n%s", tt.shell, strings.Join(lines, "\n"), strings.Join(tt.want, "\n"))
+		}
+	}
+}
+
+func TestRenderFPath(t *testing.T) {
+	items := map[string]Entry{"zsh completions": {FPath: []string{"/home/me/.local/share/zsh/site-functions"}}}
+	want := `if [ -n "${ZSH_VERSION-}" ]; then eval "fpath=('/home/me/.local/share/zsh/site-functions' \$fpath); typeset -U fpath"; fi`
+	if got := Render(items, "sh"); !strings.Contains(got, want+"\n") {
+		t.Errorf("Render for sh =\n%s\nwant the line\n%s", got, want)
+	}
+	if got := Render(items, "fish"); strings.Contains(got, "fpath") {
+		t.Errorf("Render for fish sets zsh's fpath:\n%s", got)
+	}
+}
+
+func TestRenderHooks(t *testing.T) {
+	items := map[string]Entry{"direnv": {Hooks: []string{"direnv"}}}
+	tests := []struct {
+		shell, want string
+	}{
+		{"sh", `if command -v direnv >/dev/null 2>&1; then if [ -n "${ZSH_VERSION-}" ]; then eval "$(direnv hook zsh)"; elif [ -n "${BASH_VERSION-}" ]; then eval "$(direnv hook bash)"; fi; fi`},
+		{"fish", "command -q direnv; and direnv hook fish | source"},
+		{"pwsh", "if (Get-Command direnv -ErrorAction SilentlyContinue) { Invoke-Expression (& direnv hook pwsh | Out-String) }"},
+	}
+	for _, tt := range tests {
+		if got := Render(items, tt.shell); !strings.Contains(got, tt.want+"\n") {
+			t.Errorf("Render for %s =\n%s\nwant the line\n%s", tt.shell, got, tt.want)
+		}
+	}
+}
+
+func TestQuote(t *testing.T) {
+	if got := shQuote("it's"); got != `'it'\''s'` {
+		t.Errorf("shQuote = %s", got)
+	}
+	if got := fishQuote(`C:\it's`); got != `'C:\\it\'s'` {
+		t.Errorf("fishQuote = %s", got)
+	}
+	if got := pwshQuote("it's"); got != "'it''s'" {
+		t.Errorf("pwshQuote = %s", got)
+	}
+}
+
+func TestShell(t *testing.T) {
+	tests := []struct {
+		login, goos, want string
+	}{
+		{"/bin/bash", "linux", "sh"},
+		{"/usr/bin/zsh", "darwin", "sh"},
+		{"/usr/local/bin/fish", "linux", "fish"},
+		{"/usr/bin/pwsh", "linux", "pwsh"},
+		{"", "windows", "pwsh"},
+		{"", "linux", "sh"},
+	}
+	for _, tt := range tests {
+		if got := Shell(tt.login, tt.goos); got != tt.want {
+			t.Errorf("Shell(%q, %s) = %s, want %s", tt.login, tt.goos, got, tt.want)
+		}
+	}
+}
+
+func TestSetRemove(t *testing.T) {
+	tempDirs(t)
+
+	if err := Set("Java", Entry{Vars: map[string]string{"JAVA_HOME": "/opt/java/current"}}); err != nil {
+		t.Fatal(err)
+	}
+	script, err := Script("sh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(script)
+	if err != nil || !strings.Contains(string(data), "JAVA_HOME") {
+		t.Fatalf("after Set the script is %q, %v", data, err)
+	}
+
+	if err := Remove("java"); err != nil {
+		t.Fatal(err)
+	}
+	if items, err := Load(); err != nil || len(items) != 0 {
+		t.Errorf("after Remove the items are %v, %v", items, err)
+	}
+	if data, _ := os.ReadFile(script); strings.Contains(string(data), "JAVA_HOME") {
+		t.Errorf("after Remove the script still sets JAVA_HOME:\n%s", data)
+	}
+}
@@ -0,0 +1,184 @@
+package errs
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"syscall"
+)
+
+// Failure classes installers report, so the TUI can suggest a fix for each
+var (
+	ErrNetworkFailure      = errors.New("network failure")
+	ErrPermissionDenied    = errors.New("permission denied")
+	ErrChecksumMismatch    = errors.New("checksum mismatch")
+	ErrUnsupportedPlatform = errors.New("unsupported platform")
+	ErrTimedOut            = errors.New("timed out")
+	ErrUntrustedCert       = errors.New("untrusted certificate")
+	ErrBlocked             = errors.New("blocked by Gatekeeper")
+	ErrPolicy              = errors.New("forbidden by policy")
+	ErrPinMismatch         = errors.New("doesn't match its pin")
+)
+
+// Error is an installer failure tagged with its class
+type Error struct {
+	Class error  // one of the Err* failure classes
+	Op    string // what was being done, e.g. "downloading go1.25.5"
+	Err   error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %v", e.Op, e.Err)
+}
+
+// Unwrap lets errors.Is match both the class and the underlying error
+func (e *Error) Unwrap() []error {
+	return []error{e.Class, e.Err}
+}
+
+// New tags err with a failure class
+func New(class error, op string, err error) error {
+	return &Error{Class: class, Op: op, Err: err}
+}
+
+// networkMessages and permissionMessages are command output fragments that identify a failure class
+var networkMessages = []string{
+	"Could not resolve host",
+	"Temporary failure resolving",
+	"Failed to fetch",
+	"Connection refused",
+	"Connection timed out",
+	"Network is unreachable",
+}
+
+var certificateMessages = []string{
+	"SSL certificate problem",
+	"certificate verify failed",
+	"x509: certificate",
+}
+
+var permissionMessages = []string{
+	"Permission denied",
+	"Operation not permitted",
+	"are you root?",
+	"a password is required",
+	"Authorization required",
+}
+
+// Classify tags err with a failure class when one can be determined from its type. Cancellation is
+// left untagged, since it's what the user asked for.
+func Classify(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var tagged *Error
+	if errors.As(err, &tagged) {
+		return err
+	}
+	if errors.Is(err, context.Canceled) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return New(ErrTimedOut, op, err)
+	}
+
+	// Every *url.Error is a net.Error, so look at what it wraps instead
+	cause := err
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		cause = urlErr.Err
+	}
+
+	// syscall.Errno is a net.Error too, so permission errors are checked before network ones
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	switch {
+	case isCertificateError(cause):
+		return New(ErrUntrustedCert, op, err)
+	case errors.Is(cause, os.ErrPermission), errors.Is(cause, syscall.EACCES), errors.Is(cause, syscall.EPERM):
+		return New(ErrPermissionDenied, op, err)
+	case errors.As(cause, &dnsErr), errors.As(cause, &opErr), errors.Is(cause, io.ErrUnexpectedEOF),
+		errors.Is(cause, syscall.ECONNREFUSED), errors.Is(cause, syscall.ECONNRESET):
+		return New(ErrNetworkFailure, op, err)
+	case errors.As(cause, &netErr) && netErr.Timeout():
+		// e.g. http.Client's own timeout
+		return New(ErrTimedOut, op, err)
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+// isCertificateError reports whether err is a TLS certificate that couldn't be verified
+func isCertificateError(err error) bool {
+	var verifyErr *tls.CertificateVerificationError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalid x509.CertificateInvalidError
+	return errors.As(err, &verifyErr) || errors.As(err, &unknownAuthority) ||
+		errors.As(err, &hostname) || errors.As(err, &invalid)
+}
+
+// FromOutput classifies a failed command using its combined output, keeping the last line as context
+func FromOutput(op string, output []byte, err error) error {
+	if err == nil {
+		return nil
+	}
+	if line := lastLine(output); line != "" {
+		err = fmt.Errorf("%w: %s", err, line)
+	}
+	for _, msg := range certificateMessages {
+		if bytes.Contains(output, []byte(msg)) {
+			return New(ErrUntrustedCert, op, err)
+		}
+	}
+	for _, msg := range networkMessages {
+		if bytes.Contains(output, []byte(msg)) {
+			return New(ErrNetworkFailure, op, err)
+		}
+	}
+	for _, msg := range permissionMessages {
+		if bytes.Contains(output, []byte(msg)) {
+			return New(ErrPermissionDenied, op, err)
+		}
+	}
+	return Classify(op, err)
+}
+
+// Hint returns a remediation suggestion for err's failure class, or "" if it has none
+func Hint(err error) string {
+	switch {
+	case errors.Is(err, ErrNetworkFailure):
+		return "Check your internet connection and proxy settings (HTTPS_PROXY), then retry. Run decor doctor for details."
+	case errors.Is(err, ErrPermissionDenied):
+		return "Run with a sudo policy that can elevate (press s for settings), or pick an install prefix you own."
+	case errors.Is(err, ErrChecksumMismatch):
+		return "The download was corrupted or tampered with. Run decor clean and retry; if it persists, check your proxy isn't rewriting downloads."
+	case errors.Is(err, ErrUnsupportedPlatform):
+		return "This tool can't be installed automatically here. Install it manually from its website."
+	case errors.Is(err, ErrUntrustedCert):
+		return "The server's certificate wasn't trusted. If a proxy inspects HTTPS traffic, add its CA certificate to the system trust store (or point SSL_CERT_FILE at it), and check the system clock is right."
+	case errors.Is(err, ErrBlocked):
+		return "macOS refused to run the program. Clear its quarantine with xattr -d com.apple.quarantine <program>, or allow it under System Settings > Privacy & Security."
+	case errors.Is(err, ErrPolicy):
+		return "Your organization's decor policy forbids this. Ask whoever manages it, or run decor with --override-policy, which is logged."
+	case errors.Is(err, ErrPinMismatch):
+		return "What upstream serves isn't what config.toml pins: it changed the file, or released a newer version. Check the change, then update the [[pins]] entry; decor pins prints what's installed now."
+	case errors.Is(err, ErrTimedOut):
+		return "The command hung and was stopped. Check it isn't waiting on a prompt or an unreachable network drive, or raise install_timeout in config.toml."
+	}
+	return ""
+}
+
+// lastLine returns the last non-empty line of command output
+func lastLine(output []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
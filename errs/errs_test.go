@@ -0,0 +1,121 @@
+package errs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	urlError := func(err error) error {
+		return &url.Error{Op: "Get", URL: "https://go.dev/dl/", Err: err}
+	}
+	certErr := &tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}
+
+	tests := []struct {
+		name string
+		err  error
+		want error // nil means no class
+	}{
+		{"dns", urlError(&net.DNSError{Err: "no such host", Name: "go.dev"}), ErrNetworkFailure},
+		{"connection refused", urlError(&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}), ErrNetworkFailure},
+		{"connection reset", fmt.Errorf("read: %w", syscall.ECONNRESET), ErrNetworkFailure},
+		{"body cut short", io.ErrUnexpectedEOF, ErrNetworkFailure},
+		{"untrusted certificate", urlError(certErr), ErrUntrustedCert},
+		{"wrong hostname", urlError(x509.HostnameError{Host: "go.dev"}), ErrUntrustedCert},
+		{"expired certificate", urlError(x509.CertificateInvalidError{Reason: x509.Expired}), ErrUntrustedCert},
+		{"deadline", urlError(context.DeadlineExceeded), ErrTimedOut},
+		{"bare deadline", context.DeadlineExceeded, ErrTimedOut},
+		{"cancelled", urlError(context.Canceled), nil},
+		{"permission", &os.PathError{Op: "open", Path: "/usr/local/go", Err: syscall.EACCES}, ErrPermissionDenied},
+		{"not permitted", fmt.Errorf("chown: %w", syscall.EPERM), ErrPermissionDenied},
+		{"plain url error", urlError(errors.New("unsupported protocol scheme")), nil},
+		{"unclassified", errors.New("exit status 2"), nil},
+	}
+	classes := []error{ErrNetworkFailure, ErrPermissionDenied, ErrChecksumMismatch, ErrUnsupportedPlatform, ErrTimedOut, ErrUntrustedCert}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Classify("downloading go", tt.err)
+			if !errors.Is(got, tt.err) {
+				t.Errorf("Classify() = %v, which doesn't wrap %v", got, tt.err)
+			}
+			for _, class := range classes {
+				if want := class == tt.want; errors.Is(got, class) != want {
+					t.Errorf("Classify(%v): errors.Is(%v) = %t, want %t", tt.err, class, !want, want)
+				}
+			}
+		})
+	}
+}
+
+func TestClassifyKeepsTaggedErrors(t *testing.T) {
+	tagged := New(ErrChecksumMismatch, "verifying go", errors.New("expected abc, got def"))
+	if got := Classify("installing go", tagged); got != tagged {
+		t.Errorf("Classify() = %v, want the already tagged error unchanged", got)
+	}
+	if Classify("installing go", nil) != nil {
+		t.Error("Classify(nil) != nil")
+	}
+}
+
+func TestFromOutput(t *testing.T) {
+	failed := errors.New("exit status 1")
+	tests := []struct {
+		output string
+		want   error
+	}{
+		{"curl: (6) Could not resolve host: go.dev", ErrNetworkFailure},
+		{"E: Failed to fetch http://archive.ubuntu.com/ubuntu/pool/main/p/python3", ErrNetworkFailure},
+		{"curl: (60) SSL certificate problem: unable to get local issuer certificate", ErrUntrustedCert},
+		{"E: Could not open lock file - open (13: Permission denied)", ErrPermissionDenied},
+		{"sudo: a password is required", ErrPermissionDenied},
+		{"E: Unable to locate package nosuchpackage", nil},
+	}
+	for _, tt := range tests {
+		got := FromOutput("installing python", []byte("Reading package lists...\n"+tt.output+"\n"), failed)
+		if tt.want != nil && !errors.Is(got, tt.want) {
+			t.Errorf("FromOutput(%q) = %v, want %v", tt.output, got, tt.want)
+		}
+		var tagged *Error
+		if tt.want == nil && errors.As(got, &tagged) {
+			t.Errorf("FromOutput(%q) = %v, want no class", tt.output, got)
+		}
+		if !errors.Is(got, failed) {
+			t.Errorf("FromOutput(%q) = %v, want it to wrap the command's error", tt.output, got)
+		}
+	}
+}
+
+func TestHint(t *testing.T) {
+	for _, class := range []error{ErrNetworkFailure, ErrPermissionDenied, ErrChecksumMismatch, ErrUnsupportedPlatform, ErrTimedOut, ErrUntrustedCert, ErrBlocked, ErrPolicy, ErrPinMismatch} {
+		if Hint(New(class, "op", errors.New("failed"))) == "" {
+			t.Errorf("no hint for %v", class)
+		}
+	}
+	if hint := Hint(errors.New("exit status 2")); hint != "" {
+		t.Errorf("Hint() = %q for an unclassified error, want none", hint)
+	}
+}
+
+// timeoutError is a net.Error that timed out, like http.Client's own timeout
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "Client.Timeout exceeded while awaiting headers" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestClassifyClientTimeout(t *testing.T) {
+	err := &url.Error{Op: "Get", URL: "https://go.dev/dl/", Err: timeoutError{}}
+	if got := Classify("fetching releases", err); !errors.Is(got, ErrTimedOut) || errors.Is(got, ErrNetworkFailure) {
+		t.Errorf("Classify() = %v, want a timeout", got)
+	}
+}
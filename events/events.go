@@ -0,0 +1,68 @@
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Type identifies what an event reports
+type Type string
+
+const (
+	CheckResult  Type = "check-result"
+	InstallStart Type = "install-start"
+	Progress     Type = "progress"
+	InstallDone  Type = "install-done"
+	Summary      Type = "summary"
+	Error        Type = "error"
+)
+
+// Event is one line of the JSON event stream. Fields that don't apply to the event's type are omitted.
+type Event struct {
+	Type      Type      `json:"type"`
+	Time      time.Time `json:"time"`
+	Item      string    `json:"item,omitempty"`
+	Installed *bool     `json:"installed,omitempty"`
+	TimedOut  bool      `json:"timed_out,omitempty"`
+	Version   string    `json:"version,omitempty"`
+	Latest    string    `json:"latest,omitempty"`
+	Service   string    `json:"service,omitempty"`
+	Managed   bool      `json:"managed,omitempty"` // decor installed the item
+	Action    string    `json:"action,omitempty"`
+	Progress  *float64  `json:"progress,omitempty"`
+	Step      string    `json:"step,omitempty"`
+	Phase     string    `json:"phase,omitempty"` // queued, downloading, installing, verifying, done or failed
+	Result    string    `json:"result,omitempty"`
+	Notes     []string  `json:"notes,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Hint      string    `json:"hint,omitempty"`
+
+	// Timings are the seconds an item spent in each phase, Downloaded the bytes decor fetched for it,
+	// and Elapsed the wall-clock seconds of the item or, in a summary, the whole run
+	Timings    map[string]float64 `json:"timings,omitempty"`
+	Downloaded int64              `json:"downloaded,omitempty"`
+	Elapsed    float64            `json:"elapsed,omitempty"`
+}
+
+// Emitter writes events as newline-delimited JSON. It's safe for concurrent use.
+type Emitter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewEmitter creates an emitter writing to w
+func NewEmitter(w io.Writer) *Emitter {
+	return &Emitter{enc: json.NewEncoder(w)}
+}
+
+// Emit writes a single event, stamping its time if unset
+func (e *Emitter) Emit(event Event) error {
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.enc.Encode(event)
+}
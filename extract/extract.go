@@ -0,0 +1,265 @@
+// Package extract unpacks the archives decor downloads, .tar.gz, .tar.xz, .tar.zst and .zip, in process
+// rather than with the system's tar or unzip, which differ between platforms or may be missing. Entries
+// that would land outside the destination, through their paths or through links, are refused.
+package extract
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// Types are the kinds of archive Unpack reads, named by their file suffix
+var Types = []string{"tar.gz", "tgz", "tar.xz", "tar.zst", "zip"}
+
+// Type returns the kind of archive name is from its suffix, or "" when it's none of Types
+func Type(name string) string {
+	lower := strings.ToLower(name)
+	for _, kind := range Types {
+		if strings.HasSuffix(lower, "."+kind) {
+			return kind
+		}
+	}
+	return ""
+}
+
+// Unpack extracts file, an archive of kind, into dir, which must exist, dropping strip leading directories
+// from its entries' paths. Entries with no more than strip directories are skipped.
+func Unpack(ctx context.Context, file, kind, dir string, strip int) error {
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return err
+	}
+	defer root.Close()
+	if kind == "zip" {
+		err = unpackZip(ctx, file, root, strip)
+	} else {
+		err = unpackTar(ctx, file, kind, root, strip)
+	}
+	if err != nil {
+		return fmt.Errorf("unpacking %s: %w", filepath.Base(file), err)
+	}
+	return nil
+}
+
+// Find calls use with the contents of the first regular file in the archive whose path match accepts,
+// returning fs.ErrNotExist when there's none
+func Find(file, kind string, match func(name string) bool, use func(io.Reader) error) error {
+	if kind == "zip" {
+		zr, err := zip.OpenReader(file)
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+		for _, entry := range zr.File {
+			if !entry.Mode().IsRegular() || !match(entry.Name) {
+				continue
+			}
+			src, err := entry.Open()
+			if err != nil {
+				return err
+			}
+			defer src.Close()
+			return use(src)
+		}
+		return fs.ErrNotExist
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r, err := decompress(f, kind)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return fs.ErrNotExist
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag == tar.TypeReg && match(header.Name) {
+			return use(tr)
+		}
+	}
+}
+
+// decompress reads the tarball inside a compressed archive of kind
+func decompress(r io.Reader, kind string) (io.ReadCloser, error) {
+	r = bufio.NewReader(r)
+	switch kind {
+	case "tar.gz", "tgz":
+		return gzip.NewReader(r)
+	case "tar.xz":
+		xr, err := xz.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(xr), nil
+	case "tar.zst":
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	}
+	return nil, fmt.Errorf("can't unpack a %q archive; the types are %s", kind, strings.Join(Types, ", "))
+}
+
+func unpackTar(ctx context.Context, file, kind string, root *os.Root, strip int) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r, err := decompress(f, kind)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	tr := tar.NewReader(r)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name, err := entryPath(header.Name, strip)
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			continue
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = root.MkdirAll(name, 0o755)
+		case tar.TypeReg:
+			err = writeFile(root, name, tr, header.FileInfo().Mode())
+		case tar.TypeSymlink:
+			err = symlink(root, name, header.Linkname)
+		case tar.TypeLink:
+			var target string
+			if target, err = entryPath(header.Linkname, strip); err == nil && target != "" {
+				root.Remove(name)
+				err = root.Link(target, name)
+			}
+		}
+		// Devices, FIFOs and the like are skipped: nothing decor installs ships them
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func unpackZip(ctx context.Context, file string, root *os.Root, strip int) error {
+	zr, err := zip.OpenReader(file)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+	for _, entry := range zr.File {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		name, err := entryPath(entry.Name, strip)
+		if err != nil {
+			return err
+		}
+		if name == "" {
+			continue
+		}
+		mode := entry.Mode()
+		if mode.IsDir() {
+			if err := root.MkdirAll(name, 0o755); err != nil {
+				return err
+			}
+			continue
+		}
+		src, err := entry.Open()
+		if err != nil {
+			return err
+		}
+		if mode&fs.ModeSymlink != 0 {
+			var target []byte
+			if target, err = io.ReadAll(src); err == nil {
+				err = symlink(root, name, string(target))
+			}
+		} else {
+			err = writeFile(root, name, src, mode)
+		}
+		src.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// entryPath returns where an archive entry goes, relative to the destination, with strip leading
+// directories dropped; "" means it's skipped. A path that climbs out of the destination or is absolute is
+// refused outright, since it's how a malicious archive overwrites files elsewhere.
+func entryPath(name string, strip int) (string, error) {
+	if !filepath.IsLocal(filepath.FromSlash(name)) && path.Clean(name) != "." {
+		return "", fmt.Errorf("%q would be unpacked outside the destination", name)
+	}
+	parts := strings.Split(path.Clean(name), "/")
+	if len(parts) <= strip || parts[0] == "." {
+		return "", nil
+	}
+	return path.Join(parts[strip:]...), nil
+}
+
+// writeFile writes an entry's contents to name, replacing whatever's there rather than writing through
+// it, with its permission bits but never setuid or setgid
+func writeFile(root *os.Root, name string, src io.Reader, mode fs.FileMode) error {
+	if err := root.MkdirAll(path.Dir(name), 0o755); err != nil {
+		return err
+	}
+	root.Remove(name)
+	f, err := root.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode.Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, src); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// symlink links name to target, which must be relative and stay inside the destination
+func symlink(root *os.Root, name, target string) error {
+	if path.IsAbs(target) || !filepath.IsLocal(filepath.FromSlash(path.Join(path.Dir(name), target))) {
+		return fmt.Errorf("%q links to %q, outside the destination", name, target)
+	}
+	if err := root.MkdirAll(path.Dir(name), 0o755); err != nil {
+		return err
+	}
+	root.Remove(name)
+	return root.Symlink(target, name)
+}
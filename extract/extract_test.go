@@ -0,0 +1,169 @@
+package extract
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// entry is a file, directory or symlink in a test archive
+type entry struct {
+	name string
+	body string // the file's contents, or a symlink's target
+	link bool
+}
+
+// tarball writes entries as a tarball compressed as kind
+func tarball(t *testing.T, kind string, entries []entry) []byte {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	var err error
+	switch kind {
+	case "tar.gz":
+		w = gzip.NewWriter(&buf)
+	case "tar.xz":
+		w, err = xz.NewWriter(&buf)
+	case "tar.zst":
+		w, err = zstd.NewWriter(&buf)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	tw := tar.NewWriter(w)
+	for _, e := range entries {
+		header := &tar.Header{Name: e.name, Mode: 0o755, Size: int64(len(e.body)), Typeflag: tar.TypeReg}
+		switch {
+		case e.link:
+			header.Typeflag, header.Linkname, header.Size = tar.TypeSymlink, e.body, 0
+		case strings.HasSuffix(e.name, "/"):
+			header.Typeflag = tar.TypeDir
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if header.Typeflag == tar.TypeReg {
+			tw.Write([]byte(e.body))
+		}
+	}
+	tw.Close()
+	w.Close()
+	return buf.Bytes()
+}
+
+// zipball writes entries as a zip
+func zipball(t *testing.T, entries []entry) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		header := &zip.FileHeader{Name: e.name}
+		header.SetMode(0o755)
+		if e.link {
+			header.SetMode(os.ModeSymlink | 0o777)
+		}
+		w, err := zw.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(e.body))
+	}
+	zw.Close()
+	return buf.Bytes()
+}
+
+func TestUnpack(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need privileges on Windows")
+	}
+	toolchain := []entry{
+		{name: "go/"},
+		{name: "go/bin/go", body: "#!/bin/sh\n"},
+		{name: "go/VERSION", body: "go1.25.5"},
+		{name: "go/bin/gofmt", body: "go", link: true},
+	}
+	for _, kind := range []string{"tar.gz", "tar.xz", "tar.zst", "zip"} {
+		t.Run(kind, func(t *testing.T) {
+			var data []byte
+			if kind == "zip" {
+				data = zipball(t, toolchain)
+			} else {
+				data = tarball(t, kind, toolchain)
+			}
+			file := filepath.Join(t.TempDir(), "go."+kind)
+			os.WriteFile(file, data, 0o644)
+
+			dir := t.TempDir()
+			if err := Unpack(context.Background(), file, Type(file), dir, 1); err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := os.ReadFile(filepath.Join(dir, "VERSION")); string(got) != "go1.25.5" {
+				t.Errorf("VERSION = %q, want the go directory stripped", got)
+			}
+			if info, err := os.Stat(filepath.Join(dir, "bin", "go")); err != nil || info.Mode().Perm()&0o100 == 0 {
+				t.Errorf("bin/go = %v, %v, want it executable", info, err)
+			}
+			if target, err := os.Readlink(filepath.Join(dir, "bin", "gofmt")); err != nil || target != "go" {
+				t.Errorf("bin/gofmt links to %q, %v, want go", target, err)
+			}
+		})
+	}
+}
+
+func TestUnpackRefusesEscapes(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []entry
+	}{
+		{"climbs out", []entry{{name: "../evil", body: "x"}}},
+		{"absolute", []entry{{name: "/tmp/evil", body: "x"}}},
+		{"links out", []entry{{name: "escape", body: "../..", link: true}}},
+		{"links absolute", []entry{{name: "escape", body: "/etc", link: true}}},
+	}
+	for _, tt := range tests {
+		for _, kind := range []string{"tar.gz", "zip"} {
+			var data []byte
+			if kind == "zip" {
+				data = zipball(t, tt.entries)
+			} else {
+				data = tarball(t, kind, tt.entries)
+			}
+			parent := t.TempDir()
+			file := filepath.Join(parent, "evil."+kind)
+			os.WriteFile(file, data, 0o644)
+			dir := filepath.Join(parent, "dest")
+			os.Mkdir(dir, 0o755)
+
+			if err := Unpack(context.Background(), file, kind, dir, 0); err == nil {
+				t.Errorf("%s %s: unpacked", tt.name, kind)
+			}
+			if _, err := os.Lstat(filepath.Join(parent, "evil")); err == nil {
+				t.Errorf("%s %s: wrote outside the destination", tt.name, kind)
+			}
+		}
+	}
+}
+
+func TestType(t *testing.T) {
+	for name, want := range map[string]string{
+		"go1.25.5.linux-amd64.tar.gz": "tar.gz",
+		"zig-0.13.0.tar.xz":           "tar.xz",
+		"tool-1.0-x86_64.TAR.ZST":     "tar.zst",
+		"jdk.tgz":                     "tgz",
+		"tool.zip":                    "zip",
+		"tool":                        "",
+	} {
+		if got := Type(name); got != want {
+			t.Errorf("Type(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Package ghactions turns a manifest into GitHub Actions steps that set up the same toolchain with the
+// setup-go, setup-python, setup-java and setup-node actions, so CI builds with the versions decor
+// installs locally, from the one file
+package ghactions
+
+import (
+	"fmt"
+	"strings"
+
+	"decor/manifest"
+)
+
+// action is a setup action, the input it takes the version in, and the version to ask for when the
+// manifest takes any
+type action struct {
+	uses, input, any string
+}
+
+// actions are the setup actions by the lowercased tool names a manifest may use
+var actions = map[string]action{
+	"go":     {"actions/setup-go@v5", "go-version", "stable"},
+	"python": {"actions/setup-python@v5", "python-version", "3.x"},
+	"java":   {"actions/setup-java@v4", "java-version", "21"},
+	"node":   {"actions/setup-node@v4", "node-version", "lts/*"},
+}
+
+// aliases are other names for the tools in actions
+var aliases = map[string]string{"golang": "go", "jdk": "java", "nodejs": "node", "node.js": "node"}
+
+// Steps returns the steps setting up the tools in entries, read from source, as YAML to put under a
+// job's steps:. javaVendor is the setup-java distribution, like temurin. Tools without a setup action
+// are listed in a comment, to install in a step of their own.
+func Steps(source string, entries []manifest.Entry, javaVendor string) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# The toolchain in %s, written by decor export-gha\n", source)
+	var others []string
+	for _, e := range entries {
+		tool := strings.ToLower(e.Name)
+		if alias, ok := aliases[tool]; ok {
+			tool = alias
+		}
+		a, ok := actions[tool]
+		if !ok {
+			others = append(others, e.Name)
+			continue
+		}
+		version, err := actionVersion(tool, e.Version)
+		if err != nil {
+			return "", err
+		}
+		if version == "" {
+			version = a.any
+		}
+		fmt.Fprintf(&b, "- uses: %s\n  with:\n", a.uses)
+		if tool == "java" {
+			fmt.Fprintf(&b, "    distribution: %s\n", javaVendor)
+		}
+		fmt.Fprintf(&b, "    %s: %q\n", a.input, version)
+	}
+	if len(others) > 0 {
+		fmt.Fprintf(&b, "# No setup action for %s; install them in a step of their own\n", strings.Join(others, ", "))
+	}
+	return b.String(), nil
+}
+
+// actionVersion turns a version from a manifest into one tool's setup action takes. setup-go,
+// setup-python and setup-node read ranges like >=1.22 as they are, with spaces between the parts, but
+// setup-java only takes versions, so a minimum is asked for as itself.
+func actionVersion(tool, want string) (string, error) {
+	want = strings.TrimPrefix(want, "=")
+	if tool != "java" {
+		return strings.Join(strings.FieldsFunc(want, func(r rune) bool { return r == ',' || r == ' ' }), " "), nil
+	}
+	switch {
+	case strings.ContainsAny(want, "<,"):
+		return "", fmt.Errorf("java %s: setup-java can't install from a range; give a version or a minimum", want)
+	case strings.HasPrefix(want, ">="):
+		return strings.TrimPrefix(want, ">="), nil
+	}
+	return want, nil
+}
@@ -0,0 +1,39 @@
+package ghactions
+
+import (
+	"strings"
+	"testing"
+
+	"decor/manifest"
+)
+
+func TestSteps(t *testing.T) {
+	entries, err := manifest.Parse(strings.NewReader("go 1.22\npython >=3.11,<3.13\nnode\njdk >=21\nripgrep\njq 1.7\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# The toolchain in .decor, written by decor export-gha
+- uses: actions/setup-go@v5
+  with:
+    go-version: "1.22"
+- uses: actions/setup-python@v5
+  with:
+    python-version: ">=3.11 <3.13"
+- uses: actions/setup-node@v4
+  with:
+    node-version: "lts/*"
+- uses: actions/setup-java@v4
+  with:
+    distribution: zulu
+    java-version: "21"
+# No setup action for ripgrep, jq; install them in a step of their own
+`
+	got, err := Steps(".decor", entries, "zulu")
+	if err != nil || got != want {
+		t.Errorf("Steps = %v\n%s\nwant\n%s", err, got, want)
+	}
+
+	if _, err := Steps(".decor", []manifest.Entry{{Name: "java", Version: ">=17,<22"}}, "temurin"); err == nil {
+		t.Error("Steps took a Java range setup-java can't install")
+	}
+}
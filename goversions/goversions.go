@@ -0,0 +1,187 @@
+package goversions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"decor/runner"
+)
+
+// versionPattern matches the golang.org/dl wrapper names, e.g. go1.22.5, go1.23rc1
+var versionPattern = regexp.MustCompile(`^go1\.\d+(\.\d+|rc\d+|beta\d+)?$`)
+
+// programs are linked into the bin directory when a version becomes the default
+var programs = []string{"go", "gofmt"}
+
+// Version is a Go toolchain downloaded with a golang.org/dl wrapper
+type Version struct {
+	Name    string // e.g. go1.22.5
+	Dir     string // its GOROOT
+	Default bool   // go on PATH is linked to it
+}
+
+// Normalize turns 1.22.5 or go1.22.5 into the wrapper name go1.22.5
+func Normalize(version string) (string, error) {
+	name := "go" + strings.TrimPrefix(version, "go")
+	if !versionPattern.MatchString(name) {
+		return "", fmt.Errorf("%q isn't a Go version like 1.22.5", version)
+	}
+	return name, nil
+}
+
+// SDKDir returns ~/sdk, where the wrappers download their toolchains
+func SDKDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "sdk"), nil
+}
+
+// BinDir returns ~/.local/bin, where the default version's programs are linked
+func BinDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "bin"), nil
+}
+
+// List returns the downloaded versions, marking the one that's linked as the default
+func List() ([]Version, error) {
+	sdk, err := SDKDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(sdk)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	current := linked()
+
+	var versions []Version
+	for _, entry := range entries {
+		dir := filepath.Join(sdk, entry.Name())
+		if !versionPattern.MatchString(entry.Name()) || !downloaded(dir) {
+			continue
+		}
+		versions = append(versions, Version{Name: entry.Name(), Dir: dir, Default: dir == current})
+	}
+	return versions, nil
+}
+
+// downloaded reports whether the wrapper finished downloading into dir; it writes .unpacked-success last
+func downloaded(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".unpacked-success"))
+	return err == nil
+}
+
+// linked returns the GOROOT the go link in BinDir points into, or "" if there's no such link
+func linked() string {
+	bin, err := BinDir()
+	if err != nil {
+		return ""
+	}
+	target, err := os.Readlink(filepath.Join(bin, "go"))
+	if err != nil {
+		return ""
+	}
+	return filepath.Dir(filepath.Dir(target))
+}
+
+// gobin returns where go install puts programs: GOBIN, or the first GOPATH entry's bin directory
+func gobin(ctx context.Context, r *runner.Runner) (string, error) {
+	output, err := r.Run(ctx, runner.Spec{Op: "finding GOBIN", Name: "go", Args: []string{"env", "GOBIN", "GOPATH"}, ReadOnly: true})
+	if err != nil {
+		return "", err
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(lines) == 2 && strings.TrimSpace(lines[0]) != "" {
+		return strings.TrimSpace(lines[0]), nil
+	}
+	gopath := filepath.SplitList(strings.TrimSpace(lines[len(lines)-1]))
+	if len(gopath) == 0 || gopath[0] == "" {
+		return "", errors.New("go env reports neither GOBIN nor GOPATH")
+	}
+	return filepath.Join(gopath[0], "bin"), nil
+}
+
+// Install builds the version's golang.org/dl wrapper with the go on PATH and downloads the toolchain
+// into SDKDir, next to any others
+func Install(ctx context.Context, r *runner.Runner, version string) error {
+	name, err := Normalize(version)
+	if err != nil {
+		return err
+	}
+	wrapper := runner.Spec{Op: "installing the " + name + " wrapper", Name: "go", Args: []string{"install", "golang.org/dl/" + name + "@latest"}}
+	if _, err := r.Run(ctx, wrapper); err != nil {
+		return err
+	}
+	bin, err := gobin(ctx, r)
+	if err != nil {
+		return err
+	}
+	_, err = r.Run(ctx, runner.Spec{Op: "downloading " + name, Name: filepath.Join(bin, name), Args: []string{"download"}})
+	return err
+}
+
+// Use links go and gofmt in BinDir to the downloaded version, or removes the links for "default" so the
+// go decor installed in the install prefix is found again
+func Use(r *runner.Runner, version string) error {
+	bin, err := BinDir()
+	if err != nil {
+		return err
+	}
+	sdk, err := SDKDir()
+	if err != nil {
+		return err
+	}
+
+	var goroot string
+	if version != "default" {
+		name, err := Normalize(version)
+		if err != nil {
+			return err
+		}
+		goroot = filepath.Join(sdk, name)
+		if !downloaded(goroot) {
+			return fmt.Errorf("%s isn't downloaded, run decor goversions install %s first", name, strings.TrimPrefix(name, "go"))
+		}
+	}
+
+	if r.DryRun {
+		runner.Logf("dry run: linking go and gofmt in %s to %s", bin, goroot)
+		return nil
+	}
+	if err := os.MkdirAll(bin, 0o755); err != nil {
+		return err
+	}
+	for _, program := range programs {
+		link := filepath.Join(bin, program)
+		// Only replace links decor made, never a real go binary the user put there
+		if target, err := os.Readlink(link); err == nil && strings.HasPrefix(target, sdk+string(filepath.Separator)) {
+			if err := os.Remove(link); err != nil {
+				return err
+			}
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%s exists and isn't a link decor made, remove it first", link)
+		}
+		if goroot == "" {
+			continue
+		}
+		if err := os.Symlink(filepath.Join(goroot, "bin", program), link); err != nil {
+			return err
+		}
+	}
+	runner.Logf("linked go and gofmt in %s to %s", bin, goroot)
+	return nil
+}
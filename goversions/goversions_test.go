@@ -0,0 +1,119 @@
+package goversions
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"decor/runner"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+		ok      bool
+	}{
+		{"1.22.5", "go1.22.5", true},
+		{"go1.22.5", "go1.22.5", true},
+		{"1.23rc1", "go1.23rc1", true},
+		{"1.21", "go1.21", true},
+		{"latest", "", false},
+		{"1.22.5; rm -rf ~", "", false},
+		{"2.0", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, err := Normalize(tt.version)
+			if (err == nil) != tt.ok || got != tt.want {
+				t.Errorf("got %q, %v, want %q, ok=%t", got, err, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+// fakeSDK creates downloaded toolchains in a temporary home directory
+func fakeSDK(t *testing.T, names ...string) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	for _, name := range names {
+		dir := filepath.Join(home, "sdk", name)
+		if err := os.MkdirAll(filepath.Join(dir, "bin"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, ".unpacked-success"), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A wrapper that never finished downloading
+	if err := os.MkdirAll(filepath.Join(home, "sdk", "go1.20.1"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return home
+}
+
+func TestListAndUse(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs symlinks")
+	}
+	home := fakeSDK(t, "go1.21.13", "go1.22.5")
+	r := &runner.Runner{}
+
+	versions, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 || versions[0].Name != "go1.21.13" || versions[0].Default || versions[1].Default {
+		t.Fatalf("List() = %+v, want go1.21.13 and go1.22.5, neither the default", versions)
+	}
+
+	if err := Use(r, "1.22.5"); err != nil {
+		t.Fatal(err)
+	}
+	target, err := os.Readlink(filepath.Join(home, ".local", "bin", "gofmt"))
+	if err != nil || target != filepath.Join(home, "sdk", "go1.22.5", "bin", "gofmt") {
+		t.Errorf("gofmt links to %q, %v", target, err)
+	}
+	versions, _ = List()
+	if !versions[1].Default {
+		t.Errorf("go1.22.5 isn't the default after Use: %+v", versions)
+	}
+
+	// Switching replaces decor's links
+	if err := Use(r, "go1.21.13"); err != nil {
+		t.Fatal(err)
+	}
+	if versions, _ = List(); !versions[0].Default || versions[1].Default {
+		t.Errorf("go1.21.13 isn't the only default: %+v", versions)
+	}
+
+	if err := Use(r, "1.20.1"); err == nil {
+		t.Error("switched to a version that never finished downloading")
+	}
+
+	if err := Use(r, "default"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Lstat(filepath.Join(home, ".local", "bin", "go")); !os.IsNotExist(err) {
+		t.Errorf("the go link is still there after use default: %v", err)
+	}
+}
+
+func TestUseKeepsUserBinaries(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs symlinks")
+	}
+	home := fakeSDK(t, "go1.22.5")
+	bin := filepath.Join(home, ".local", "bin")
+	if err := os.MkdirAll(bin, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(bin, "go"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := Use(&runner.Runner{}, "1.22.5"); err == nil {
+		t.Error("replaced a go binary decor didn't link")
+	}
+}
@@ -0,0 +1,150 @@
+package gpu
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"decor/runner"
+)
+
+// Vendor PCI IDs as listed in /sys/class/drm/card*/device/vendor
+var vendorIDs = map[string]string{
+	"0x10de": "nvidia",
+	"0x1002": "amd",
+}
+
+// minDrivers is the oldest Linux NVIDIA driver each CUDA major version runs on
+var minDrivers = map[int]string{
+	11: "450.80.02",
+	12: "525.60.13",
+	13: "580.65.06",
+}
+
+// GPU describes a graphics card and the driver loaded for it
+type GPU struct {
+	Vendor string // "nvidia" or "amd"
+	Name   string
+	Driver string // driver version, empty if no driver is loaded
+	CUDA   string // newest CUDA version the NVIDIA driver supports
+}
+
+// Vendors returns the vendors of the GPUs in this machine, read from sysfs so it's cheap enough to call at startup
+func Vendors() map[string]bool {
+	vendors := make(map[string]bool)
+	paths, _ := filepath.Glob("/sys/class/drm/card*/device/vendor")
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if vendor, ok := vendorIDs[strings.TrimSpace(string(data))]; ok {
+			vendors[vendor] = true
+		}
+	}
+	return vendors
+}
+
+// queryTimeout bounds nvidia-smi, which hangs when the driver is wedged
+const queryTimeout = 10 * time.Second
+
+// Detect asks the vendor tools about each GPU and its driver
+func Detect(ctx context.Context, r *runner.Runner) []GPU {
+	var gpus []GPU
+	vendors := Vendors()
+	if vendors["nvidia"] {
+		gpus = append(gpus, detectNvidia(ctx, r)...)
+	}
+	if vendors["amd"] {
+		gpus = append(gpus, detectAMD())
+	}
+	return gpus
+}
+
+// detectNvidia reads names and driver versions from nvidia-smi, which also reports the newest CUDA the driver supports
+func detectNvidia(ctx context.Context, r *runner.Runner) []GPU {
+	out, err := r.Run(ctx, runner.Spec{
+		Op:       "querying NVIDIA GPUs",
+		Name:     "nvidia-smi",
+		Args:     []string{"--query-gpu=name,driver_version", "--format=csv,noheader"},
+		ReadOnly: true,
+		Timeout:  queryTimeout,
+	})
+	if err != nil {
+		// The card is there but the driver isn't loaded
+		return []GPU{{Vendor: "nvidia", Name: "NVIDIA GPU"}}
+	}
+
+	cuda := ""
+	header, err := r.Run(ctx, runner.Spec{Op: "querying the NVIDIA driver", Name: "nvidia-smi", ReadOnly: true, Timeout: queryTimeout})
+	if err == nil {
+		if match := regexp.MustCompile(`CUDA Version:\s*([0-9.]+)`).FindSubmatch(header); match != nil {
+			cuda = string(match[1])
+		}
+	}
+
+	var gpus []GPU
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		name, driver, _ := strings.Cut(line, ",")
+		gpus = append(gpus, GPU{Vendor: "nvidia", Name: strings.TrimSpace(name), Driver: strings.TrimSpace(driver), CUDA: cuda})
+	}
+	return gpus
+}
+
+// detectAMD reports the amdgpu kernel module's version, if it's loaded
+func detectAMD() GPU {
+	gpu := GPU{Vendor: "amd", Name: "AMD GPU"}
+	if data, err := os.ReadFile("/sys/module/amdgpu/version"); err == nil {
+		gpu.Driver = strings.TrimSpace(string(data))
+	} else if _, err := os.Stat("/sys/module/amdgpu"); err == nil {
+		gpu.Driver = "amdgpu"
+	}
+	return gpu
+}
+
+// CheckCUDA reports whether the GPU's driver can run the given CUDA toolkit version, e.g. "12.4"
+func CheckCUDA(gpu GPU, toolkit string) error {
+	if gpu.Vendor != "nvidia" {
+		return fmt.Errorf("CUDA needs an NVIDIA GPU, found %s", gpu.Name)
+	}
+	if gpu.Driver == "" {
+		return fmt.Errorf("no NVIDIA driver is loaded for %s", gpu.Name)
+	}
+
+	major, _, _ := strings.Cut(toolkit, ".")
+	if n, err := strconv.Atoi(major); err == nil {
+		if required, ok := minDrivers[n]; ok && compareVersions(gpu.Driver, required) < 0 {
+			return fmt.Errorf("CUDA %s needs driver %s or newer, %s has %s", toolkit, required, gpu.Name, gpu.Driver)
+		}
+	}
+	if gpu.CUDA != "" && compareVersions(gpu.CUDA, toolkit) < 0 {
+		return fmt.Errorf("driver %s supports CUDA up to %s, not %s", gpu.Driver, gpu.CUDA, toolkit)
+	}
+	return nil
+}
+
+// compareVersions compares dotted numeric versions, returning -1, 0 or 1
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
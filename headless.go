@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"decor/catalog"
+	"decor/events"
+	"decor/installer"
+	"decor/runner"
+	"decor/symbols"
+)
+
+// runHeadless checks and installs the given languages without the TUI, writing
+// newline-delimited JSON events to stdout. Unless confirmed is set, deleting or editing anything outside
+// decor's own files waits for "yes" on stdin. It returns the process exit code.
+func runHeadless(items []string, confirmed bool) int {
+	emitter := events.NewEmitter(os.Stdout)
+
+	languages, err := resolveLanguages(items)
+	if err != nil {
+		emitter.Emit(events.Event{Type: events.Error, Error: err.Error()})
+		return 2
+	}
+	languages, _ = installer.WithDependencies(languages)
+
+	status := installer.Check(languages)
+	choices := make(map[string]string)
+	for _, lang := range languages {
+		s := status[lang]
+		installed := s.Installed
+		emitter.Emit(events.Event{
+			Type:      events.CheckResult,
+			Item:      lang,
+			Installed: &installed,
+			TimedOut:  s.TimedOut,
+			Version:   s.Version,
+			Latest:    s.LatestVersion,
+			Error:     s.Error,
+			Service:   s.Service,
+			Managed:   s.Managed,
+		})
+		choices[lang] = installer.DefaultChoice(s)
+	}
+
+	// Prompt on the terminal before any output that wrappers parse, keeping stdout pure JSON
+	if touches := installer.Touches(installer.Plan(languages, choices)); len(touches) > 0 && !confirmed {
+		if err := confirmTouches(os.Stdin, os.Stderr, languages, touches); err != nil {
+			emitter.Emit(events.Event{Type: events.Error, Error: err.Error()})
+			return 1
+		}
+	}
+	privileged := installer.Privileged()
+	if privileged.NeedsElevation() && installer.NeedsRoot(languages, choices) {
+		cmd := privileged.AuthCommand()
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stderr, os.Stderr
+		if err := runner.CheckAuth(cmd.Run()); err != nil {
+			emitter.Emit(events.Event{Type: events.Error, Error: err.Error()})
+			return 1
+		}
+	}
+
+	trackers := installer.NewTrackers(languages, choices)
+	for _, lang := range languages {
+		tracker, ok := trackers[lang]
+		if !ok {
+			continue
+		}
+		tracker.OnChange = func(s installer.ProgressSnapshot) {
+			progress := s.Progress
+			emitter.Emit(events.Event{Type: events.Progress, Item: s.Language, Progress: &progress, Step: s.CurrentStep, Phase: s.Phase})
+		}
+		emitter.Emit(events.Event{Type: events.InstallStart, Item: lang, Action: choices[lang]})
+	}
+
+	started := time.Now()
+	results := installer.Run(context.Background(), languages, choices, trackers)
+	elapsed := time.Since(started)
+
+	exitCode := 0
+	for _, lang := range languages {
+		done := events.Event{Type: events.InstallDone, Item: lang, Action: choices[lang], Result: results[lang]}
+		if tracker, ok := trackers[lang]; ok {
+			s := tracker.Snapshot()
+			if s.ErrorMessage != "" {
+				emitter.Emit(events.Event{Type: events.Error, Item: lang, Error: s.ErrorMessage, Hint: s.Hint})
+				exitCode = 1
+			}
+			done.Notes = s.Notes
+			done.Downloaded = s.Downloaded
+			done.Elapsed = s.Elapsed().Seconds()
+			done.Timings = make(map[string]float64)
+			for phase, d := range s.Timings {
+				done.Timings[phase] = d.Seconds()
+			}
+		}
+		emitter.Emit(done)
+	}
+	emitter.Emit(events.Event{Type: events.Summary, Elapsed: elapsed.Seconds()})
+	return exitCode
+}
+
+// confirmTouches lists what the run deletes, replaces or edits outside decor's own files on out, and
+// fails unless "yes" is typed on in
+func confirmTouches(in io.Reader, out io.Writer, languages []string, touches map[string][]string) error {
+	fmt.Fprintln(out, "This changes things outside decor's own files:")
+	for _, lang := range languages {
+		for _, touch := range touches[lang] {
+			fmt.Fprintf(out, "  %s %s: %s\n", symbols.Warning, lang, touch)
+		}
+	}
+	fmt.Fprint(out, "Type yes to continue: ")
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	if strings.TrimSpace(answer) != "yes" {
+		return fmt.Errorf("not confirmed, nothing was changed; pass --yes to confirm up front")
+	}
+	return nil
+}
+
+// resolveLanguages maps case-insensitive item names like "go" or "c++", and preset names
+// like "data science", to the items decor supports
+func resolveLanguages(items []string) ([]string, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no languages given, e.g. decor --json go python")
+	}
+
+	var languages []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			languages = append(languages, name)
+		}
+	}
+	for _, name := range items {
+		if preset, ok := catalog.FindPreset(name); ok {
+			for _, presetItem := range installer.PresetItems(preset) {
+				add(presetItem)
+			}
+			continue
+		}
+		item, ok := catalog.Find(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown item %q, expected one of %s", name, strings.Join(catalog.Names(), ", "))
+		}
+		add(item.Name)
+	}
+	return languages, nil
+}
@@ -0,0 +1,168 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"decor/paths"
+)
+
+// DirName is the directory in decor's state directory that holds a report per run
+const DirName = "history"
+
+// maxRuns bounds how many reports are kept; older ones are removed as new runs are saved
+const maxRuns = 200
+
+// Run is the report of one install or update run
+type Run struct {
+	Started time.Time     `json:"started"`
+	Elapsed time.Duration `json:"elapsed"`
+	Items   []Item        `json:"items"`
+	Log     string        `json:"log,omitempty"` // the commands the run ran, from the command log
+
+	File string `json:"-"` // where the report was read from
+}
+
+// Item is what happened to one item during a run
+type Item struct {
+	Name       string                   `json:"name"`
+	Choice     string                   `json:"choice"` // "install" or "update"
+	Result     string                   `json:"result"` // "installed", "updated" or "error: ..."
+	Hint       string                   `json:"hint,omitempty"`
+	Notes      []string                 `json:"notes,omitempty"`
+	Timings    map[string]time.Duration `json:"timings,omitempty"`
+	Downloaded int64                    `json:"downloaded,omitempty"`
+}
+
+// Failed reports whether the item's install failed
+func (i Item) Failed() bool {
+	return strings.HasPrefix(i.Result, "error")
+}
+
+// Outcomes are the results Outcome counts, in the order it lists them
+var Outcomes = []string{"installed", "updated", "removed", "failed"}
+
+// Counts returns how many of the run's items had each result, with every failure counted as "failed"
+func (r Run) Counts() map[string]int {
+	counts := make(map[string]int)
+	for _, item := range r.Items {
+		if item.Failed() {
+			counts["failed"]++
+		} else {
+			counts[item.Result]++
+		}
+	}
+	return counts
+}
+
+// Outcome sums up the run, e.g. "2 installed, 1 failed"
+func (r Run) Outcome() string {
+	counts := r.Counts()
+	var parts []string
+	for _, result := range Outcomes {
+		if counts[result] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[result], result))
+		}
+	}
+	if len(parts) == 0 {
+		return "nothing to do"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Dir returns the directory reports are kept in
+func Dir() (string, error) {
+	dir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, DirName), nil
+}
+
+// Save writes the run's report, named after its start time, and removes the oldest reports beyond maxRuns
+func Save(run Run) error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return err
+	}
+	name := run.Started.UTC().Format("20060102T150405.000000000Z") + ".json"
+	if err := os.WriteFile(filepath.Join(dir, name), append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return prune(dir)
+}
+
+// reports lists the report files in dir, oldest first
+func reports(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// prune removes the oldest reports beyond maxRuns
+func prune(dir string) error {
+	names, err := reports(dir)
+	if err != nil {
+		return err
+	}
+	for len(names) > maxRuns {
+		if err := os.Remove(filepath.Join(dir, names[0])); err != nil {
+			return err
+		}
+		names = names[1:]
+	}
+	return nil
+}
+
+// List reads every saved run, newest first. Reports that can't be read are skipped.
+func List() ([]Run, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	names, err := reports(dir)
+	if err != nil {
+		return nil, err
+	}
+	var runs []Run
+	for i := len(names) - 1; i >= 0; i-- {
+		path := filepath.Join(dir, names[i])
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var run Run
+		if err := json.Unmarshal(data, &run); err != nil {
+			continue
+		}
+		run.File = path
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
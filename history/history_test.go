@@ -0,0 +1,99 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// tempState points the state directory at a fresh temporary directory
+func tempState(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_STATE_HOME", dir)
+	t.Setenv("LOCALAPPDATA", dir)
+}
+
+func TestSaveList(t *testing.T) {
+	tempState(t)
+
+	runs, err := List()
+	if err != nil || len(runs) != 0 {
+		t.Fatalf("an empty history listed %v, %v", runs, err)
+	}
+
+	start := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	for i, result := range []string{"installed", "error: download failed"} {
+		run := Run{
+			Started: start.Add(time.Duration(i) * time.Hour),
+			Elapsed: time.Minute,
+			Items:   []Item{{Name: "Go", Choice: "install", Result: result}},
+			Log:     "extracting Go: tar -C /usr/local -xzf go.tar.gz: ok in 2s\n",
+		}
+		if err := Save(run); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runs, err = List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("listed %d runs, want 2", len(runs))
+	}
+	if !runs[0].Started.After(runs[1].Started) {
+		t.Errorf("runs aren't newest first: %v, %v", runs[0].Started, runs[1].Started)
+	}
+	if runs[0].Outcome() != "1 failed" || runs[1].Outcome() != "1 installed" {
+		t.Errorf("outcomes %q and %q", runs[0].Outcome(), runs[1].Outcome())
+	}
+	if runs[1].Log == "" || runs[1].File == "" {
+		t.Errorf("a listed run lost its log or file: %+v", runs[1])
+	}
+}
+
+func TestPrune(t *testing.T) {
+	tempState(t)
+
+	start := time.Date(2026, 9, 1, 12, 0, 0, 0, time.UTC)
+	for i := range maxRuns + 3 {
+		if err := Save(Run{Started: start.Add(time.Duration(i) * time.Minute)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dir, _ := Dir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != maxRuns {
+		t.Errorf("kept %d reports, want %d", len(entries), maxRuns)
+	}
+	oldest := start.Add(3*time.Minute).Format("20060102T150405.000000000Z") + ".json"
+	if _, err := os.Stat(filepath.Join(dir, oldest)); err != nil {
+		t.Errorf("the oldest kept report is missing: %v", err)
+	}
+}
+
+func TestOutcome(t *testing.T) {
+	tests := []struct {
+		results []string
+		want    string
+	}{
+		{nil, "nothing to do"},
+		{[]string{"installed", "updated", "installed"}, "2 installed, 1 updated"},
+		{[]string{"error: cancelled", "updated"}, "1 updated, 1 failed"},
+	}
+	for _, tt := range tests {
+		var run Run
+		for _, result := range tt.results {
+			run.Items = append(run.Items, Item{Result: result})
+		}
+		if got := run.Outcome(); got != tt.want {
+			t.Errorf("Outcome(%v) = %q, want %q", tt.results, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// Fallback is the locale every message is written in first, and used for anything a translation is missing
+const Fallback = "en"
+
+// locales holds a message catalog per locale, named like es.json: message IDs mapped to fmt formats.
+// Adding a translation is adding a file here.
+//
+//go:embed locales/*.json
+var locales embed.FS
+
+// catalogs are the parsed message catalogs by locale
+var catalogs = mustLoad()
+
+// current is the catalog messages are looked up in first
+var current atomic.Pointer[map[string]string]
+
+// mustLoad parses the embedded catalogs; a broken one is a bug caught by the tests
+func mustLoad() map[string]map[string]string {
+	files, err := locales.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	loaded := make(map[string]map[string]string)
+	for _, file := range files {
+		data, err := locales.ReadFile(path.Join("locales", file.Name()))
+		if err != nil {
+			panic(err)
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("locales/%s: %v", file.Name(), err))
+		}
+		loaded[strings.TrimSuffix(file.Name(), ".json")] = messages
+	}
+	return loaded
+}
+
+// Locales lists the locales decor has messages for, English first
+func Locales() []string {
+	var names []string
+	for name := range catalogs {
+		if name != Fallback {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return append([]string{Fallback}, names...)
+}
+
+// Detect picks the locale for setting, the language in the settings: "auto" reads DECOR_LANG, then LC_ALL,
+// LC_MESSAGES and LANG, like "es_ES.UTF-8", falling back to English when decor doesn't speak it
+func Detect(setting string) string {
+	if setting != "" && setting != "auto" {
+		return setting
+	}
+	for _, name := range []string{"DECOR_LANG", "LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		// Only the first variable set counts, as for gettext
+		lang, _, _ := strings.Cut(value, ".")
+		lang, _, _ = strings.Cut(lang, "_")
+		if _, ok := catalogs[strings.ToLower(lang)]; ok {
+			return strings.ToLower(lang)
+		}
+		return Fallback
+	}
+	return Fallback
+}
+
+// SetLocale makes T look messages up in locale's catalog
+func SetLocale(locale string) error {
+	messages, ok := catalogs[locale]
+	if !ok {
+		current.Store(nil)
+		return fmt.Errorf("decor has no %q translation, it has %s", locale, strings.Join(Locales(), ", "))
+	}
+	current.Store(&messages)
+	return nil
+}
+
+// T returns the message with id in the current locale, formatted with args like fmt.Sprintf. A message
+// missing from the locale comes from English, and one missing from English is shown as its id.
+func T(id string, args ...any) string {
+	format, ok := "", false
+	if messages := current.Load(); messages != nil {
+		format, ok = (*messages)[id]
+	}
+	if !ok {
+		if format, ok = catalogs[Fallback][id]; !ok {
+			format = id
+		}
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Word translates a word decor keeps in English, like a result or a phase, using the message kind.word
+// with spaces in word as underscores. A word with no message is returned as it is.
+func Word(kind, word string) string {
+	id := kind + "." + strings.ReplaceAll(word, " ", "_")
+	if message := T(id); message != id {
+		return message
+	}
+	return word
+}
@@ -0,0 +1,92 @@
+package i18n
+
+import (
+	"regexp"
+	"testing"
+)
+
+// verbs matches fmt verbs, skipping escaped percent signs
+var verbs = regexp.MustCompile(`%(\[\d+\])?[-+# 0-9.]*[a-zA-Z%]`)
+
+// count returns how many arguments a message's format takes
+func count(format string) int {
+	n := 0
+	for _, verb := range verbs.FindAllString(format, -1) {
+		if verb != "%%" {
+			n++
+		}
+	}
+	return n
+}
+
+func TestCatalogs(t *testing.T) {
+	english := catalogs[Fallback]
+	if len(english) == 0 {
+		t.Fatal("there are no English messages")
+	}
+	for _, locale := range Locales() {
+		for id, format := range catalogs[locale] {
+			want, ok := english[id]
+			if !ok {
+				t.Errorf("%s has %s, which isn't in English", locale, id)
+				continue
+			}
+			if count(format) != count(want) {
+				t.Errorf("%s's %s takes %d arguments, English takes %d", locale, id, count(format), count(want))
+			}
+		}
+		if len(catalogs[locale]) != len(english) {
+			t.Logf("%s has %d of %d messages", locale, len(catalogs[locale]), len(english))
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	defer SetLocale(Fallback)
+
+	if err := SetLocale("es"); err != nil {
+		t.Fatal(err)
+	}
+	if got := T("plan.install", "Go"); got != "Instalar Go:" {
+		t.Errorf("T(plan.install) = %q", got)
+	}
+	if got := Word("result", "not finished"); got != "sin terminar" {
+		t.Errorf("Word(result, not finished) = %q", got)
+	}
+	if got := Word("result", "error: exit status 1"); got != "error: exit status 1" {
+		t.Errorf("an unknown word became %q", got)
+	}
+	if got := T("no.such.message"); got != "no.such.message" {
+		t.Errorf("a missing message became %q", got)
+	}
+
+	if err := SetLocale("xx"); err == nil {
+		t.Error("set a locale with no messages")
+	}
+	if got := T("plan.install", "Go"); got != "Install Go:" {
+		t.Errorf("an unknown locale didn't fall back to English: %q", got)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		setting, decorLang, lang string
+		want                     string
+	}{
+		{"es", "", "en_US.UTF-8", "es"},
+		{"auto", "", "es_MX.UTF-8", "es"},
+		{"auto", "", "de_DE.UTF-8", "en"},
+		{"auto", "es", "en_US.UTF-8", "es"},
+		{"auto", "", "C", "en"},
+		{"auto", "", "", "en"},
+	}
+	for _, tt := range tests {
+		t.Setenv("DECOR_LANG", tt.decorLang)
+		t.Setenv("LC_ALL", "")
+		t.Setenv("LC_MESSAGES", "")
+		t.Setenv("LANG", tt.lang)
+		if got := Detect(tt.setting); got != tt.want {
+			t.Errorf("Detect(%q) with DECOR_LANG=%q LANG=%q = %q, want %q", tt.setting, tt.decorLang, tt.lang, got, tt.want)
+		}
+	}
+}
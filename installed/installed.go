@@ -0,0 +1,215 @@
+package installed
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"decor/paths"
+)
+
+// FileName is the database's file in decor's state directory
+const FileName = "installed.json"
+
+// Record is something decor installed: when, how and at what version, and where it came from
+type Record struct {
+	Name        string    `json:"name"`
+	Version     string    `json:"version,omitempty"`
+	Method      string    `json:"method"` // how it was installed, e.g. "brew", "apt", "tarball"
+	InstalledAt time.Time `json:"installed_at"`
+	UpdatedAt   time.Time `json:"updated_at,omitzero"`
+	Packages    []string  `json:"packages,omitempty"` // the package manager's names for it, e.g. Homebrew formulae
+	Sources     []Source  `json:"sources,omitempty"`  // what decor downloaded to install it
+}
+
+// Source is a file decor downloaded to install an item
+type Source struct {
+	URL    string `json:"url"`
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// file is the on-disk layout, versioned so the format can change later
+type file struct {
+	Version int               `json:"version"`
+	Items   map[string]Record `json:"items"`            // keyed by lowercased name
+	Checked time.Time         `json:"checked,omitzero"` // when the items were last checked for updates
+}
+
+// mu serializes read-modify-write cycles, since languages install concurrently
+var mu sync.Mutex
+
+// Path returns the database's location
+func Path() (string, error) {
+	dir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// load reads the database, returning an empty one if it doesn't exist yet; the caller holds mu
+func load() (file, error) {
+	db := file{Version: 1, Items: make(map[string]Record)}
+	path, err := Path()
+	if err != nil {
+		return db, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return db, nil
+	}
+	if err != nil {
+		return db, err
+	}
+	if err := json.Unmarshal(data, &db); err != nil {
+		return db, err
+	}
+	if db.Items == nil {
+		db.Items = make(map[string]Record)
+	}
+	return db, nil
+}
+
+// save writes the database atomically, so a crash never leaves half a file; the caller holds mu
+func save(db file) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(db, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), FileName+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Add records that decor installed or updated name with method, at version. An update keeps the
+// original install time.
+func Add(name, method, version string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	db, err := load()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	key := strings.ToLower(name)
+	record, exists := db.Items[key]
+	if exists {
+		record.UpdatedAt = now
+	} else {
+		record.InstalledAt = now
+	}
+	record.Name, record.Method, record.Version = name, method, version
+	db.Items[key] = record
+	return save(db)
+}
+
+// SetOrigin records the packages and downloads the latest install or update of name came from,
+// replacing those of the one before
+func SetOrigin(name string, packages []string, sources []Source) error {
+	mu.Lock()
+	defer mu.Unlock()
+	db, err := load()
+	if err != nil {
+		return err
+	}
+	key := strings.ToLower(name)
+	record, ok := db.Items[key]
+	if !ok {
+		return nil
+	}
+	record.Packages, record.Sources = packages, sources
+	db.Items[key] = record
+	return save(db)
+}
+
+// Remove forgets name, for when it's uninstalled
+func Remove(name string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	db, err := load()
+	if err != nil {
+		return err
+	}
+	key := strings.ToLower(name)
+	if _, ok := db.Items[key]; !ok {
+		return nil
+	}
+	delete(db.Items, key)
+	return save(db)
+}
+
+// Get returns the record for name, and false if decor didn't install it
+func Get(name string) (Record, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	db, err := load()
+	if err != nil {
+		return Record{}, false
+	}
+	record, ok := db.Items[strings.ToLower(name)]
+	return record, ok
+}
+
+// List returns every record, sorted by name
+func List() ([]Record, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	db, err := load()
+	if err != nil {
+		return nil, err
+	}
+	records := make([]Record, 0, len(db.Items))
+	for _, record := range db.Items {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Name < records[j].Name })
+	return records, nil
+}
+
+// LastChecked returns when the items were last checked for updates, or the zero time if never
+func LastChecked() time.Time {
+	mu.Lock()
+	defer mu.Unlock()
+	db, err := load()
+	if err != nil {
+		return time.Time{}
+	}
+	return db.Checked
+}
+
+// MarkChecked records that the items were just checked for updates
+func MarkChecked() error {
+	mu.Lock()
+	defer mu.Unlock()
+	db, err := load()
+	if err != nil {
+		return err
+	}
+	db.Checked = time.Now().UTC().Truncate(time.Second)
+	return save(db)
+}
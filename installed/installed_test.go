@@ -0,0 +1,82 @@
+package installed
+
+import (
+	"testing"
+)
+
+// tempState points the state directory at a fresh temporary directory
+func tempState(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_STATE_HOME", dir)
+	t.Setenv("LOCALAPPDATA", dir)
+}
+
+func TestAddGetRemove(t *testing.T) {
+	tempState(t)
+
+	if _, ok := Get("go"); ok {
+		t.Fatal("found go in an empty database")
+	}
+	if err := Add("Go", "tarball", "1.22.4"); err != nil {
+		t.Fatal(err)
+	}
+	first, ok := Get("go")
+	if !ok || first.Name != "Go" || first.Method != "tarball" || first.Version != "1.22.4" {
+		t.Fatalf("after install got %+v, %t", first, ok)
+	}
+	if first.InstalledAt.IsZero() || !first.UpdatedAt.IsZero() {
+		t.Errorf("a fresh install has times %v and %v", first.InstalledAt, first.UpdatedAt)
+	}
+
+	if err := Add("Go", "tarball", "1.22.5"); err != nil {
+		t.Fatal(err)
+	}
+	updated, _ := Get("go")
+	if updated.Version != "1.22.5" || !updated.InstalledAt.Equal(first.InstalledAt) || updated.UpdatedAt.IsZero() {
+		t.Errorf("an update changed the record to %+v", updated)
+	}
+
+	if err := Add("Rust", "rustup", "1.79.0"); err != nil {
+		t.Fatal(err)
+	}
+	records, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Name != "Go" || records[1].Name != "Rust" {
+		t.Errorf("List() = %+v", records)
+	}
+
+	if err := Remove("go"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := Get("Go"); ok {
+		t.Error("go is still recorded after Remove")
+	}
+	if err := Remove("go"); err != nil {
+		t.Errorf("removing something unrecorded: %v", err)
+	}
+}
+
+func TestSetOrigin(t *testing.T) {
+	tempState(t)
+
+	sources := []Source{{URL: "https://go.dev/dl/go1.25.5.linux-amd64.tar.gz", SHA256: "abc123"}}
+	if err := SetOrigin("Go", nil, sources); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := Get("go"); ok {
+		t.Fatal("SetOrigin recorded something that wasn't installed")
+	}
+	if err := Add("Go", "tarball", "1.25.5"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetOrigin("go", nil, sources); err != nil {
+		t.Fatal(err)
+	}
+	if record, _ := Get("Go"); len(record.Sources) != 1 || record.Sources[0] != sources[0] {
+		t.Errorf("after SetOrigin the sources are %+v", record.Sources)
+	}
+}
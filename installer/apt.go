@@ -0,0 +1,37 @@
+package installer
+
+import (
+	"context"
+	"sync"
+)
+
+// aptIndexes records whether this run has refreshed apt's package lists, which a fresh image doesn't have
+// and an old one has stale
+var aptIndexes struct {
+	sync.Mutex
+	fresh bool
+}
+
+// forgetAptIndexes makes the next apt-get install refresh the package lists again, at the start of a run
+func forgetAptIndexes() {
+	aptIndexes.Lock()
+	defer aptIndexes.Unlock()
+	aptIndexes.fresh = false
+}
+
+// refreshAptIndexes runs apt-get update through attempt, unless it already ran this run
+func refreshAptIndexes(ctx context.Context, progress *LanguageProgress, attempt func(ctx context.Context, args []string) error) error {
+	aptIndexes.Lock()
+	defer aptIndexes.Unlock()
+	if aptIndexes.fresh {
+		return nil
+	}
+	step := progress.Snapshot().CurrentStep
+	progress.Set(progress.Snapshot().Progress, "Updating apt's package lists...")
+	if err := attempt(ctx, []string{"update"}); err != nil {
+		return err
+	}
+	progress.Set(progress.Snapshot().Progress, step)
+	aptIndexes.fresh = true
+	return nil
+}
@@ -0,0 +1,213 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"decor/catalog"
+	"decor/config"
+	"decor/download"
+	"decor/extract"
+	"decor/platform"
+	"decor/runner"
+)
+
+// archiveURL fills in a URL template's {version}, {os} and {arch}, calling the architecture what arches
+// says if it names it
+func archiveURL(template, version string, arches map[string]string) string {
+	p := platform.Current()
+	arch := p.Arch
+	if name, ok := arches[arch]; ok {
+		arch = name
+	}
+	return strings.NewReplacer("{version}", version, "{os}", p.OS, "{arch}", arch).Replace(template)
+}
+
+// archiveType is the archive's declared type, or the one its URL ends with
+func archiveType(archive catalog.Archive) string {
+	if archive.Type != "" {
+		return archive.Type
+	}
+	return extract.Type(archive.URL)
+}
+
+// archiveDir is where an archive is unpacked
+func archiveDir(archive catalog.Archive) string {
+	if strings.HasPrefix(archive.Dir, "~") || filepath.IsAbs(archive.Dir) {
+		return config.ExpandHome(archive.Dir)
+	}
+	return filepath.Join(settings.Prefix(), archive.Dir)
+}
+
+// archiveLink is where a program from an archive is linked
+func archiveLink(program string) string {
+	return filepath.Join(config.ExpandHome("~/.local/bin"), filepath.Base(program))
+}
+
+// unpack extracts file, an archive of kind, into dir, dropping strip leading directories. decor unpacks
+// archives itself, so where dir needs root they're unpacked to a staging directory in the cache first and
+// copied into place as root.
+func unpack(ctx context.Context, file, kind, dir string, strip int, privileged bool) error {
+	if dryRun {
+		runner.Logf("dry run: unpacking %s into %s", filepath.Base(file), dir)
+		return nil
+	}
+	runner.Logf("unpacking %s into %s", filepath.Base(file), dir)
+	if !privileged {
+		return extract.Unpack(ctx, file, kind, dir, strip)
+	}
+	cache, err := download.Dir()
+	if err != nil {
+		return err
+	}
+	staging, err := os.MkdirTemp(cache, "unpack-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(staging)
+	if err := extract.Unpack(ctx, file, kind, staging, strip); err != nil {
+		return err
+	}
+	return runCommand(ctx, runner.Spec{
+		Op:   "copying " + filepath.Base(dir) + " into place",
+		Name: "cp",
+		Args: []string{"-R", staging + string(filepath.Separator) + ".", dir},
+		Root: true,
+	})
+}
+
+// unpackSteps describes unpack for a plan
+func unpackSteps(file, kind, dir string, strip int) []string {
+	if kind == "" {
+		return []string{"nothing: can't tell what kind of archive it is; set its type to one of " + strings.Join(extract.Types, ", ")}
+	}
+	step := fmt.Sprintf("unpack %s into %s", file, dir)
+	switch {
+	case strip == 1:
+		step += ", dropping each path's top directory"
+	case strip > 1:
+		step += fmt.Sprintf(", dropping each path's top %d directories", strip)
+	}
+	if !writable(filepath.Dir(dir)) {
+		return []string{step + " by way of the cache", fmt.Sprintf("copy it into %s%s", dir, asRoot(true))}
+	}
+	return []string{step}
+}
+
+// installArchive downloads the archive for version and checks it against its published checksum, unpacks
+// it to a directory of its own beside the versions before and switches its directory to it, and links its
+// programs into ~/.local/bin. check runs once it's switched to, if not nil; if it fails the version before
+// is switched back to.
+func installArchive(ctx context.Context, archive catalog.Archive, version string, progress *LanguageProgress, check func(ctx context.Context) error) error {
+	dir := archiveDir(archive)
+	privileged := !writable(filepath.Dir(dir))
+	url := archiveURL(archive.URL, version, archive.Arches)
+	checksum := ""
+	if archive.Checksum != "" {
+		checksum = archiveURL(archive.Checksum, version, archive.Arches)
+	} else if err := requireChecksum(url, progress); err != nil {
+		return err
+	}
+	if err := checkVersion(progress.Language, version, progress); err != nil {
+		return err
+	}
+
+	progress.SetPhase(PhaseDownloading)
+	progress.Set(0.2, fmt.Sprintf("Downloading %s...", filepath.Base(url)))
+	file, err := fetch(ctx, progress, url, checksum)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file)
+	kind := archiveType(archive)
+	if kind == "" {
+		return fmt.Errorf("can't tell what kind of archive %s is; set its type to one of %s", archive.URL, strings.Join(extract.Types, ", "))
+	}
+
+	progress.Set(0.6, fmt.Sprintf("Extracting %s...", filepath.Base(url)))
+	return serialize(ctx, progress, func(ctx context.Context) error {
+		// Unpacking checks what it can before the version's switched to; check runs once it's in use
+		unpackTo := func(into string) error {
+			mkdir := runner.Spec{Op: "creating " + into, Name: "mkdir", Args: []string{"-p", into}, Root: privileged}
+			if err := runCommand(ctx, mkdir); err != nil {
+				return err
+			}
+			if err := unpack(ctx, file, kind, into, archive.Strip, privileged); err != nil {
+				return err
+			}
+			if err := unquarantine(ctx, into, privileged); err != nil {
+				return err
+			}
+			for _, program := range archive.Links {
+				if err := ensureSigned(ctx, filepath.Join(into, program), privileged); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		var err error
+		if keepsVersions() {
+			err = installVersion(ctx, dir, versionName(version, file), privileged, progress, unpackTo, check)
+		} else {
+			err = replaceDir(ctx, dir, privileged, func() error {
+				if err := unpackTo(dir); err != nil || check == nil || dryRun {
+					return err
+				}
+				progress.SetPhase(PhaseVerifying)
+				return check(ctx)
+			})
+		}
+		if err != nil || len(archive.Links) == 0 {
+			return err
+		}
+		if !dryRun {
+			if err := os.MkdirAll(config.ExpandHome("~/.local/bin"), 0o755); err != nil {
+				return err
+			}
+		}
+		for _, program := range archive.Links {
+			link := runner.Spec{Op: "linking " + filepath.Base(program), Name: "ln", Args: []string{"-sfn", filepath.Join(dir, program), archiveLink(program)}}
+			if err := runCommand(ctx, link); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// archiveSteps describes installArchive for a plan
+func archiveSteps(a *Action, archive catalog.Archive, version string) []string {
+	dir := archiveDir(archive)
+	url := archiveURL(archive.URL, version, archive.Arches)
+	a.URLs = append(a.URLs, url)
+	steps := []string{"download " + url}
+	if archive.Checksum != "" {
+		steps[0] += " and check it against " + archiveURL(archive.Checksum, version, archive.Arches)
+	}
+	if !keepsVersions() {
+		steps = append(steps, fmt.Sprintf("move any existing %s aside, to be restored if the install fails", dir))
+		steps = append(steps, unpackSteps(filepath.Base(url), archiveType(archive), dir, archive.Strip)...)
+		if exists(dir) {
+			a.Touches = append(a.Touches, fmt.Sprintf("replace %s, deleting what's in it once the new copy is in place", dir))
+		}
+		steps = append(steps, quarantineSteps(dir)...)
+	} else {
+		into := filepath.Join(versionsDir(dir), version)
+		if version == "" {
+			into = filepath.Join(versionsDir(dir), "<checksum>")
+		}
+		steps = append(steps, unpackSteps(filepath.Base(url), archiveType(archive), into, archive.Strip)...)
+		steps = append(steps, quarantineSteps(into)...)
+		steps = append(steps, fmt.Sprintf("switch %s to it by swapping the link %s, keeping the version before to switch back to if the install fails or with decor rollback", dir, filepath.Join(versionsDir(dir), "current")))
+		if info, err := os.Lstat(dir); err == nil && info.Mode()&os.ModeSymlink == 0 {
+			a.Touches = append(a.Touches, fmt.Sprintf("replace %s with a link, deleting what's in it once the new version is in place", dir))
+		}
+	}
+	for _, program := range archive.Links {
+		steps = append(steps, fmt.Sprintf("link %s to %s", archiveLink(program), filepath.Join(dir, program)))
+	}
+	return steps
+}
@@ -0,0 +1,46 @@
+package installer
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"decor/catalog"
+	"decor/config"
+)
+
+func TestArchiveType(t *testing.T) {
+	original := settings
+	t.Cleanup(func() { settings = original })
+	settings = config.Default()
+	settings.InstallPrefix = "/opt"
+
+	tests := []struct {
+		archive catalog.Archive
+		want    string // the archive's type, or nothing if it can't be told
+		dir     string
+	}{
+		{catalog.Archive{URL: "https://go.dev/dl/go{version}.{os}-{arch}.tar.gz", Strip: 1, Dir: "go"}, "tar.gz", "/opt/go"},
+		{catalog.Archive{URL: "https://example.com/tool.tar.xz", Dir: "/srv/tool"}, "tar.xz", "/srv/tool"},
+		{catalog.Archive{URL: "https://example.com/tool-1.0.tar.zst", Dir: "tool"}, "tar.zst", "/opt/tool"},
+		{catalog.Archive{URL: "https://example.com/download?os=linux", Type: "tgz", Dir: "tool"}, "tgz", "/opt/tool"},
+		{catalog.Archive{URL: "https://example.com/download?os=linux", Dir: "tool"}, "", "/opt/tool"},
+		{catalog.Archive{URL: "https://example.com/tool.zip", Strip: 1, Dir: "tool"}, "zip", "/opt/tool"},
+	}
+	for _, tt := range tests {
+		if got := archiveType(tt.archive); got != tt.want {
+			t.Errorf("archiveType(%+v) = %q, want %q", tt.archive, got, tt.want)
+		}
+		if got := archiveDir(tt.archive); got != tt.dir {
+			t.Errorf("archiveDir(%+v) = %s, want %s", tt.archive, got, tt.dir)
+		}
+		steps := unpackSteps("a", archiveType(tt.archive), archiveDir(tt.archive), tt.archive.Strip)
+		if got := strings.HasPrefix(steps[0], "nothing"); got != (tt.want == "") {
+			t.Errorf("unpackSteps(%+v) = %q", tt.archive, steps)
+		}
+	}
+
+	if got, want := archiveURL(goArchive.URL, "1.25.5", goArchive.Arches), "https://go.dev/dl/go1.25.5."+runtime.GOOS+"-"+runtime.GOARCH+".tar.gz"; got != want {
+		t.Errorf("archiveURL = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,93 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"decor/errs"
+	"decor/pkgmgr"
+)
+
+// isBSD reports whether decor runs on a BSD, where the core languages come from the system's packages
+func isBSD() bool {
+	return runtime.GOOS == "freebsd" || runtime.GOOS == "openbsd"
+}
+
+// bsdPackages returns the packages a core language comes in on goos, or nil for the C++ compilers,
+// which both base systems ship, and for anything that isn't a core language
+func bsdPackages(goos, language string) []string {
+	java := strings.TrimSuffix(settings.JavaVersion, "latest")
+	if java == "" {
+		java = "21"
+	}
+	packages := map[string]map[string]string{
+		"freebsd": {"go": "go", "python": "python3", "rust": "rust", "java": "openjdk" + java},
+		"openbsd": {"go": "go", "python": "python%3", "rust": "rust", "java": "jdk%" + java},
+	}
+	if name, ok := packages[goos][strings.ToLower(language)]; ok {
+		return []string{name}
+	}
+	return nil
+}
+
+// coreLanguage reports whether language has a dedicated installer rather than a catalog strategy
+func coreLanguage(language string) bool {
+	switch strings.ToLower(language) {
+	case "go", "python", "rust", "c++", "java":
+		return true
+	}
+	return false
+}
+
+// installBSD installs or upgrades a core language with pkg on FreeBSD or pkg_add on OpenBSD
+func installBSD(ctx context.Context, language string, update bool, progress *LanguageProgress) error {
+	packages := bsdPackages(runtime.GOOS, language)
+	if packages == nil {
+		if strings.ToLower(language) == "c++" {
+			return errs.New(errs.ErrUnsupportedPlatform, "installing "+language, fmt.Errorf("the %s base system ships clang as cc and c++; install its compiler set if they're missing", runtime.GOOS))
+		}
+		return fmt.Errorf("unsupported language: %s", language)
+	}
+	verb := "install"
+	if update {
+		verb = "upgrade"
+	}
+	name, args, _ := pkgmgr.BSDCommand(runtime.GOOS, verb, packages)
+	progress.Set(0.3, fmt.Sprintf("Running %s for %s...", name, language))
+	if err := runPackageManager(ctx, progress, name, args...); err != nil {
+		return err
+	}
+	if strings.ToLower(language) == "java" && settings.JavaVendor != "temurin" {
+		progress.AddNote(fmt.Sprintf("installed the %s OpenJDK package; the %s vendor setting applies to downloaded JDKs only", runtime.GOOS, settings.JavaVendor))
+	}
+	return nil
+}
+
+// bsdJava finds the java OpenBSD's jdk packages install outside PATH, as /usr/local/jdk-<version>/bin/java,
+// preferring the newest. FreeBSD's javavmwrapper puts java on PATH already.
+func bsdJava() string {
+	matches, _ := filepath.Glob("/usr/local/jdk-*/bin/java")
+	if len(matches) == 0 {
+		return "java"
+	}
+	return matches[len(matches)-1]
+}
+
+// bsdSteps describes installing or upgrading a core language from the BSD's packages
+func bsdSteps(a *Action, update bool) {
+	packages := bsdPackages(runtime.GOOS, a.Language)
+	if packages == nil {
+		a.Steps = []string{fmt.Sprintf("nothing: the %s base system ships the compilers", runtime.GOOS)}
+		return
+	}
+	verb := "install"
+	if update {
+		verb = "upgrade"
+	}
+	name, args, _ := pkgmgr.BSDCommand(runtime.GOOS, verb, packages)
+	a.Packages = packages
+	a.Steps = []string{run(true, append([]string{name}, args...)...)}
+}
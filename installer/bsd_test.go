@@ -0,0 +1,32 @@
+package installer
+
+import (
+	"slices"
+	"testing"
+
+	"decor/config"
+)
+
+func TestBSDPackages(t *testing.T) {
+	original := settings
+	t.Cleanup(func() { settings = original })
+	settings = config.Default()
+	settings.JavaVersion = "17"
+
+	tests := []struct {
+		goos, language string
+		want           []string
+	}{
+		{"freebsd", "Python", []string{"python3"}},
+		{"openbsd", "python", []string{"python%3"}},
+		{"freebsd", "java", []string{"openjdk17"}},
+		{"openbsd", "java", []string{"jdk%17"}},
+		{"freebsd", "c++", nil},
+		{"linux", "go", nil},
+	}
+	for _, tt := range tests {
+		if got := bsdPackages(tt.goos, tt.language); !slices.Equal(got, tt.want) {
+			t.Errorf("bsdPackages(%s, %s) = %v, want %v", tt.goos, tt.language, got, tt.want)
+		}
+	}
+}
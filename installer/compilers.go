@@ -0,0 +1,81 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"decor/runner"
+)
+
+// alternativePriority is the priority decor registers compilers with; the explicit --set is what
+// picks one, so it only matters if the user switches back to automatic mode
+const alternativePriority = "50"
+
+// compilerPattern matches the cpp_compiler setting: gcc or clang, optionally with a major version
+var compilerPattern = regexp.MustCompile(`^(gcc|clang)(?:-(\d+))?$`)
+
+// cppToolchain returns the apt packages for the chosen compiler, e.g. gcc-13 or clang, and the paths
+// cc and c++ should point at
+func cppToolchain(compiler string) ([]string, string, string, error) {
+	match := compilerPattern.FindStringSubmatch(compiler)
+	if match == nil {
+		return nil, "", "", fmt.Errorf("unknown C++ compiler %q, expected gcc or clang with an optional version like gcc-13", compiler)
+	}
+	suffix := ""
+	if match[2] != "" {
+		suffix = "-" + match[2]
+	}
+	packages := []string{"build-essential"}
+	if match[1] == "clang" {
+		return append(packages, "clang"+suffix), "/usr/bin/clang" + suffix, "/usr/bin/clang++" + suffix, nil
+	}
+	if suffix != "" {
+		packages = append(packages, "gcc"+suffix, "g++"+suffix)
+	}
+	return packages, "/usr/bin/gcc" + suffix, "/usr/bin/g++" + suffix, nil
+}
+
+// selectCompiler registers the compilers with update-alternatives and points cc and c++ at them
+func selectCompiler(ctx context.Context, cc, cxx string) error {
+	links := []struct{ name, path string }{{"cc", cc}, {"c++", cxx}}
+	for _, link := range links {
+		install := runner.Spec{
+			Op:   "registering " + link.path + " as " + link.name,
+			Name: "update-alternatives",
+			Args: []string{"--install", "/usr/bin/" + link.name, link.name, link.path, alternativePriority},
+			Root: true,
+		}
+		if err := runCommand(ctx, install); err != nil {
+			return err
+		}
+		set := runner.Spec{
+			Op:   "pointing " + link.name + " at " + link.path,
+			Name: "update-alternatives",
+			Args: []string{"--set", link.name, link.path},
+			Root: true,
+		}
+		if err := runCommand(ctx, set); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reportCompiler notes the first line of cc --version, so the summary shows what cc ended up being
+func reportCompiler(ctx context.Context, progress *LanguageProgress) {
+	output, err := commands.Run(ctx, runner.Spec{
+		Op:       "checking cc",
+		Name:     "cc",
+		Args:     []string{"--version"},
+		Timeout:  settings.DetectTimeout,
+		ReadOnly: true,
+	})
+	if err != nil {
+		progress.AddNote(fmt.Sprintf("couldn't run cc --version: %v", err))
+		return
+	}
+	first, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	progress.AddNote("cc is now " + first)
+}
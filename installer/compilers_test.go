@@ -0,0 +1,35 @@
+package installer
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCppToolchain(t *testing.T) {
+	tests := []struct {
+		compiler string
+		packages []string
+		cc, cxx  string
+		ok       bool
+	}{
+		{"gcc", []string{"build-essential"}, "/usr/bin/gcc", "/usr/bin/g++", true},
+		{"gcc-13", []string{"build-essential", "gcc-13", "g++-13"}, "/usr/bin/gcc-13", "/usr/bin/g++-13", true},
+		{"clang", []string{"build-essential", "clang"}, "/usr/bin/clang", "/usr/bin/clang++", true},
+		{"clang-18", []string{"build-essential", "clang-18"}, "/usr/bin/clang-18", "/usr/bin/clang++-18", true},
+		{"", nil, "", "", false},
+		{"gcc-", nil, "", "", false},
+		{"icc", nil, "", "", false},
+		{"clang-18; rm -rf /", nil, "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.compiler, func(t *testing.T) {
+			packages, cc, cxx, err := cppToolchain(tt.compiler)
+			if (err == nil) != tt.ok {
+				t.Fatalf("got error %v, want ok=%t", err, tt.ok)
+			}
+			if !slices.Equal(packages, tt.packages) || cc != tt.cc || cxx != tt.cxx {
+				t.Errorf("got %v, %s, %s, want %v, %s, %s", packages, cc, cxx, tt.packages, tt.cc, tt.cxx)
+			}
+		})
+	}
+}
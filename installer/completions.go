@@ -0,0 +1,127 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"decor/catalog"
+	"decor/config"
+	"decor/envfile"
+	"decor/runner"
+)
+
+// completionsName is the component offered for items whose program prints its own completion script
+const completionsName = "completions"
+
+// completionShells are the shells decor installs completions for
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// completionShell returns the login shell completions are installed for, or "" when $SHELL isn't one
+// of completionShells
+func completionShell() string {
+	shell := filepath.Base(os.Getenv("SHELL"))
+	for _, s := range completionShells {
+		if shell == s {
+			return shell
+		}
+	}
+	return ""
+}
+
+// completionComponent is the completions component for an item whose program prints its completion
+// script, and false when the item's doesn't or the shell isn't supported
+func completionComponent(item catalog.Item) (catalog.Component, bool) {
+	shell := completionShell()
+	if len(item.Completion) == 0 || shell == "" {
+		return catalog.Component{}, false
+	}
+	return catalog.Component{Name: completionsName, Description: shell + " completions for " + item.Completion[0], Default: true}, true
+}
+
+// completionPath returns where a program's completion script for shell goes: bash-completion's user
+// directory, a directory decor's environment script adds to zsh's fpath, or fish's completions directory,
+// each of which loads the script when the program is first completed
+func completionPath(shell, program string) string {
+	data := os.Getenv("XDG_DATA_HOME")
+	if data == "" || !filepath.IsAbs(data) {
+		data = config.ExpandHome("~/.local/share")
+	}
+	switch shell {
+	case "bash":
+		return filepath.Join(data, "bash-completion", "completions", program)
+	case "zsh":
+		return filepath.Join(data, "zsh", "site-functions", "_"+program)
+	}
+	conf := os.Getenv("XDG_CONFIG_HOME")
+	if conf == "" || !filepath.IsAbs(conf) {
+		conf = config.ExpandHome("~/.config")
+	}
+	return filepath.Join(conf, "fish", "completions", program+".fish")
+}
+
+// completionCommand returns the item's completion command for shell
+func completionCommand(item catalog.Item, shell string) []string {
+	args := make([]string, len(item.Completion))
+	for i, arg := range item.Completion {
+		args[i] = strings.ReplaceAll(arg, "{shell}", shell)
+	}
+	return args
+}
+
+// installCompletion writes the item's completion script for the login shell. It's written to a
+// temporary file first, so a failing command leaves any earlier script alone. Failing is noted rather
+// than failing an install that worked.
+func installCompletion(ctx context.Context, item catalog.Item, progress *LanguageProgress) {
+	shell := completionShell()
+	dest := completionPath(shell, item.Completion[0])
+	if !dryRun {
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			progress.AddNote(fmt.Sprintf("couldn't install %s's %s completions: %v", item.Name, shell, err))
+			return
+		}
+	}
+	command := completionCommand(item, shell)
+	script := `"$@" > "$0.tmp" && mv "$0.tmp" "$0" || { rm -f "$0.tmp"; exit 1; }`
+	spec := runner.Spec{Op: fmt.Sprintf("writing %s's %s completions", item.Name, shell), Name: "sh", Args: append([]string{"-c", script, dest, lookPath(command[0])}, command[1:]...)}
+	if err := runCommand(ctx, spec); err != nil {
+		progress.AddNote(fmt.Sprintf("couldn't install %s's %s completions: %v", item.Name, shell, err))
+		return
+	}
+	if shell == "zsh" && !dryRun {
+		if err := envfile.Set("zsh completions", envfile.Entry{FPath: []string{filepath.Dir(dest)}}); err != nil {
+			progress.AddNote(fmt.Sprintf("couldn't add %s to zsh's fpath in decor's environment script: %v", filepath.Dir(dest), err))
+		}
+	}
+}
+
+// removeCompletions deletes the completion scripts decor wrote for language's program, for every shell,
+// since the login shell may have changed since
+func removeCompletions(language string, progress *LanguageProgress) {
+	item, ok := catalog.Find(language)
+	if !ok || len(item.Completion) == 0 || dryRun {
+		return
+	}
+	for _, shell := range completionShells {
+		err := os.Remove(completionPath(shell, item.Completion[0]))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			progress.AddNote(fmt.Sprintf("couldn't delete %s's %s completions: %v", item.Name, shell, err))
+		}
+	}
+}
+
+// completionSteps describes installing the item's completions, or deleting them when they aren't picked
+func completionSteps(a *Action, item catalog.Item, picked bool) {
+	shell := completionShell()
+	dest := completionPath(shell, item.Completion[0])
+	switch {
+	case picked:
+		a.Steps = append(a.Steps, fmt.Sprintf("write what `%s` prints to %s", strings.Join(completionCommand(item, shell), " "), dest))
+	case exists(dest):
+		a.Steps = append(a.Steps, "delete "+dest)
+	}
+}
@@ -0,0 +1,187 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"decor/catalog"
+	"decor/jdk"
+	"decor/runner"
+)
+
+// Components returns the item's optional components this platform can install: those in packages for
+// the package manager in use, run as a command, or left out of an install decor made itself, and the
+// login shell's completions when the item's program prints them
+func Components(language string) []catalog.Component {
+	item, ok := catalog.Find(language)
+	if !ok {
+		return nil
+	}
+	var offered []catalog.Component
+	for _, c := range item.Components {
+		_, packages := componentPackages(c)
+		if len(packages) > 0 || len(c.Command) > 0 || len(c.Omit) > 0 && componentRoot(language) != "" {
+			offered = append(offered, c)
+		}
+	}
+	if c, ok := completionComponent(item); ok {
+		offered = append(offered, c)
+	}
+	return offered
+}
+
+// PickedComponents returns the names of the item's components the settings pick, or its defaults when
+// the settings don't say
+func PickedComponents(language string) []string {
+	offered := Components(language)
+	chosen, ok := settings.Components[strings.ToLower(language)]
+	var picked []string
+	for _, c := range offered {
+		if ok && containsFold(chosen, c.Name) || !ok && c.Default {
+			picked = append(picked, c.Name)
+		}
+	}
+	return picked
+}
+
+// SetComponents replaces the components picked for each item, for a daemon to pick up what was saved
+// after it started
+func SetComponents(components map[string][]string) {
+	settings.Components = components
+}
+
+// containsFold reports whether names holds name, ignoring case
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// componentPackages returns the package manager command a component's packages install through, and
+// the packages
+func componentPackages(c catalog.Component) (string, []string) {
+	switch {
+	case usesBrew():
+		return "brew", c.Brew
+	case usesApt():
+		return "apt-get", c.Apt
+	}
+	return "", nil
+}
+
+// componentRoot is the directory under the install prefix a core language is installed in, which Omit
+// paths are relative to, or "" when decor doesn't install it into a directory of its own on this platform
+func componentRoot(language string) string {
+	if strings.ToLower(language) != "java" || isBSD() {
+		return ""
+	}
+	return jdk.Home(filepath.Join(javaRoot(), "current"))
+}
+
+// installComponents installs the picked components once their item is installed, and deletes the files
+// of those left out. Packages of components left out aren't removed, since something else may need them.
+func installComponents(ctx context.Context, language string, progress *LanguageProgress) error {
+	picked := PickedComponents(language)
+	var manager string
+	var packages []string
+	for _, c := range Components(language) {
+		if !containsFold(picked, c.Name) {
+			continue
+		}
+		name, p := componentPackages(c)
+		if len(p) > 0 {
+			manager, packages = name, append(packages, p...)
+		}
+	}
+	if len(packages) > 0 {
+		progress.Set(0.9, fmt.Sprintf("Installing %s components...", language))
+		args := []string{"install"}
+		if manager == "apt-get" {
+			args = append(args, "-y")
+		}
+		if err := runPackageManager(ctx, progress, manager, append(args, packages...)...); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range Components(language) {
+		op := fmt.Sprintf("installing %s's %s", language, c.Name)
+		switch {
+		case c.Name == completionsName && containsFold(picked, c.Name):
+			item, _ := catalog.Find(language)
+			installCompletion(ctx, item, progress)
+		case c.Name == completionsName:
+			removeCompletions(language, progress)
+		case containsFold(picked, c.Name) && len(c.Command) > 0:
+			if err := runCommand(ctx, runner.Spec{Op: op, Name: lookPath(c.Command[0]), Args: expandArgs(c.Command[1:])}); err != nil {
+				return err
+			}
+		case !containsFold(picked, c.Name) && len(c.Omit) > 0:
+			root := componentRoot(language)
+			for _, path := range c.Omit {
+				spec := runner.Spec{Op: "leaving out " + c.Name, Name: "rm", Args: []string{"-rf", filepath.Join(root, path)}, Root: !writable(settings.Prefix())}
+				if err := runCommand(ctx, spec); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// componentSteps adds installing the picked components, and leaving out the rest, to a plan action
+func componentSteps(a *Action) {
+	if len(a.Steps) > 0 && strings.HasPrefix(a.Steps[0], "nothing") {
+		return
+	}
+	picked := PickedComponents(a.Language)
+	var manager string
+	var packages []string
+	for _, c := range Components(a.Language) {
+		if name, p := componentPackages(c); containsFold(picked, c.Name) && len(p) > 0 {
+			manager, packages = name, append(packages, p...)
+		}
+	}
+	switch manager {
+	case "brew":
+		a.Steps = append(a.Steps, run(false, append([]string{"brew", "install"}, packages...)...))
+	case "apt-get":
+		a.Packages = append(a.Packages, packages...)
+		a.Steps = append(a.Steps, run(true, append([]string{"apt-get", "install", "-y"}, packages...)...))
+	}
+
+	for _, c := range Components(a.Language) {
+		switch {
+		case c.Name == completionsName:
+			item, _ := catalog.Find(a.Language)
+			completionSteps(a, item, containsFold(picked, c.Name))
+		case containsFold(picked, c.Name) && len(c.Command) > 0:
+			a.Steps = append(a.Steps, run(false, expandArgs(c.Command)...))
+		case !containsFold(picked, c.Name) && len(c.Omit) > 0:
+			root := componentRoot(a.Language)
+			for _, path := range c.Omit {
+				a.Steps = append(a.Steps, fmt.Sprintf("leave out %s by deleting %s%s", c.Name, filepath.Join(root, path), asRoot(!writable(settings.Prefix()))))
+			}
+		}
+	}
+}
+
+// componentsNeedRoot reports whether installing the item's picked components, or leaving out the rest,
+// runs privileged commands
+func componentsNeedRoot(language string) bool {
+	picked := PickedComponents(language)
+	for _, c := range Components(language) {
+		if name, p := componentPackages(c); containsFold(picked, c.Name) && name == "apt-get" && len(p) > 0 {
+			return true
+		}
+		if !containsFold(picked, c.Name) && len(c.Omit) > 0 && !writable(settings.Prefix()) {
+			return true
+		}
+	}
+	return false
+}
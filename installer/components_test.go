@@ -0,0 +1,79 @@
+package installer
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"decor/catalog"
+	"decor/config"
+)
+
+func TestPickedComponents(t *testing.T) {
+	original := settings
+	t.Cleanup(func() { settings = original })
+	settings = config.Default()
+	t.Setenv("SHELL", "/bin/sh")
+
+	tests := []struct {
+		packageManager string
+		components     map[string][]string
+		language       string
+		want           []string
+	}{
+		{"apt", nil, "Python", []string{"venv"}},
+		{"apt", map[string][]string{"python": {"TKinter", "nonsense"}}, "Python", []string{"tkinter"}},
+		{"apt", map[string][]string{"python": {}}, "Python", nil},
+		// Homebrew's Python comes with venv, so there's nothing to pick
+		{"brew", nil, "Python", nil},
+		{"apt", nil, "Rust", nil},
+		{"apt", nil, "jq", nil},
+	}
+	for _, tt := range tests {
+		settings.PackageManager, settings.Components = tt.packageManager, tt.components
+		if got := PickedComponents(tt.language); !slices.Equal(got, tt.want) {
+			t.Errorf("PickedComponents(%s) with %s and %v = %q, want %q", tt.language, tt.packageManager, tt.components, got, tt.want)
+		}
+	}
+}
+
+func TestCompletions(t *testing.T) {
+	original := settings
+	t.Cleanup(func() { settings = original })
+	settings = config.Default()
+	t.Setenv("XDG_DATA_HOME", "/data")
+	t.Setenv("XDG_CONFIG_HOME", "/config")
+
+	tests := []struct {
+		shell   string
+		want    string
+		command string
+	}{
+		{"/bin/bash", "/data/bash-completion/completions/kubectl", "kubectl completion bash"},
+		{"/usr/bin/zsh", "/data/zsh/site-functions/_kubectl", "kubectl completion zsh"},
+		{"/usr/bin/fish", "/config/fish/completions/kubectl.fish", "kubectl completion fish"},
+		{"/bin/tcsh", "", ""},
+	}
+	item, _ := catalog.Find("kubectl")
+	for _, tt := range tests {
+		t.Setenv("SHELL", tt.shell)
+		want := []string{"completions"}
+		if tt.want == "" {
+			want = nil
+		}
+		if got := PickedComponents("kubectl"); !slices.Equal(got, want) {
+			t.Errorf("with %s the picked components are %q, want %q", tt.shell, got, want)
+		}
+		if tt.want == "" {
+			continue
+		}
+		shell := filepath.Base(tt.shell)
+		if got := completionPath(shell, "kubectl"); got != tt.want {
+			t.Errorf("completionPath(%s) = %s, want %s", shell, got, tt.want)
+		}
+		if got := strings.Join(completionCommand(item, shell), " "); got != tt.command {
+			t.Errorf("completionCommand(%s) = %s, want %s", shell, got, tt.command)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"decor/catalog"
+	"decor/config"
+	"decor/errs"
+	"decor/runner"
+)
+
+// customItems turns the [[tools]] tables in the settings into catalog items
+func customItems(tools []config.Tool) []catalog.Item {
+	items := make([]catalog.Item, len(tools))
+	for i, tool := range tools {
+		item := catalog.Item{
+			Name:        tool.Name,
+			Category:    tool.Category,
+			Description: tool.Description,
+			Version:     tool.Version,
+			Repo:        tool.Repo,
+			Release:     tool.Asset,
+			GoInstall:   tool.GoInstall,
+		}
+		if item.Category == "" {
+			item.Category = "Custom"
+		}
+		if len(item.Version) == 0 {
+			item.Version = []string{tool.Name, "--version"}
+		}
+		if item.Repo != "" && item.Release == "" {
+			item.Release = "*"
+		}
+		switch {
+		case tool.GoInstall != "":
+			item.Requires = []string{"Go"}
+			if !strings.Contains(item.GoInstall, "@") {
+				item.GoInstall += "@latest"
+			}
+		case tool.Pipx != "":
+			item.Pipx = []string{tool.Pipx}
+			item.Requires = []string{"pipx"}
+		case tool.Npm != "":
+			item.Npm = []string{tool.Npm}
+			item.Requires = []string{"Node.js"}
+		case tool.Cargo != "":
+			item.Cargo = []string{tool.Cargo, "--locked"}
+			item.Requires = []string{"Rust"}
+		case tool.Archive != "":
+			item.Archive = catalog.Archive{URL: tool.Archive, Checksum: tool.Checksum, Type: tool.ArchiveType, Strip: tool.Strip, Dir: tool.Dir, Links: tool.Links}
+			if item.Archive.Dir == "" {
+				item.Archive.Dir = "~/.local/opt/" + tool.Name
+			}
+			if len(item.Archive.Links) == 0 {
+				item.Archive.Links = []string{item.Version[0]}
+			}
+		}
+		items[i] = item
+	}
+	return items
+}
+
+// goCommand finds go on PATH, or where decor installs Go if it isn't on PATH yet
+func goCommand() string {
+	if path, err := exec.LookPath("go"); err == nil {
+		return path
+	}
+	return filepath.Join(settings.Prefix(), "go", "bin", "go")
+}
+
+// goBin is where go install puts programs: GOBIN, or the bin directory of the first GOPATH, which
+// defaults to ~/go
+func goBin() string {
+	if bin := os.Getenv("GOBIN"); bin != "" {
+		return bin
+	}
+	if gopath := filepath.SplitList(os.Getenv("GOPATH")); len(gopath) > 0 && gopath[0] != "" {
+		return filepath.Join(gopath[0], "bin")
+	}
+	return config.ExpandHome("~/go/bin")
+}
+
+// goInstall builds an item's package with go install, then checks the program landed in goBin
+func goInstall(ctx context.Context, op string, item catalog.Item, progress *LanguageProgress) error {
+	goTool := goCommand()
+	progress.Set(0.3, fmt.Sprintf("Building %s...", item.GoInstall))
+	err := serialize(ctx, progress, func(ctx context.Context) error {
+		return runCommand(ctx, runner.Spec{Op: op, Name: goTool, Args: []string{"install", item.GoInstall}})
+	})
+	if err != nil || dryRun {
+		return err
+	}
+
+	bin := goBin()
+	program := filepath.Join(bin, item.Version[0])
+	if runtime.GOOS == "windows" {
+		program += ".exe"
+	}
+	if _, err := os.Stat(program); err != nil {
+		return errs.Classify(op, fmt.Errorf("go install finished, but %s isn't in %s", item.Version[0], bin))
+	}
+	if found, err := exec.LookPath(item.Version[0]); err != nil || found != program {
+		progress.AddNote(fmt.Sprintf("installed at %s; add %s to PATH to run it", program, bin))
+	}
+	return nil
+}
+
+// npmEnv installs global npm packages under ~/.local, next to decor's other programs, unless npm comes
+// from Homebrew, whose global directory the user owns
+func npmEnv() []string {
+	if usesBrew() {
+		return nil
+	}
+	return []string{"npm_config_prefix=" + config.ExpandHome("~/.local")}
+}
+
+// npmArgs returns npm's arguments for installing or updating the item's packages globally
+func npmArgs(item catalog.Item, update bool) []string {
+	if update {
+		return append([]string{"update", "-g"}, item.Npm...)
+	}
+	return append([]string{"install", "-g"}, item.Npm...)
+}
+
+// cargoArgs returns cargo's arguments for building the item's crate. cargo install replaces an older
+// build, so updating is installing again.
+func cargoArgs(item catalog.Item) []string {
+	return append([]string{"install"}, item.Cargo...)
+}
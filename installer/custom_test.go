@@ -0,0 +1,47 @@
+package installer
+
+import (
+	"slices"
+	"testing"
+
+	"decor/config"
+)
+
+func TestCustomItems(t *testing.T) {
+	original := settings
+	t.Cleanup(func() { settings = original })
+	settings = config.Default()
+	t.Setenv("GOBIN", "/opt/gobin")
+
+	items := customItems([]config.Tool{
+		{Name: "lazygit", Repo: "jesseduffield/lazygit"},
+		{Name: "staticcheck", GoInstall: "honnef.co/go/tools/cmd/staticcheck"},
+		{Name: "httpie", Pipx: "httpie", Version: []string{"http", "--version"}},
+		{Name: "prettier", Npm: "prettier"},
+		{Name: "just", Cargo: "just"},
+	})
+	if items[0].Release != "*" || itemStrategy(items[0]) != "release" {
+		t.Errorf("a repo tool became %+v, installed by %q", items[0], itemStrategy(items[0]))
+	}
+
+	tests := []struct {
+		item     int
+		requires string
+		steps    []string
+	}{
+		{1, "Go", []string{"run `go install honnef.co/go/tools/cmd/staticcheck@latest`", "check staticcheck is in /opt/gobin"}},
+		{2, "pipx", []string{"run `pipx install httpie`"}},
+		{3, "Node.js", []string{"run `npm install -g prettier`"}},
+		{4, "Rust", []string{"run `cargo install just --locked`"}},
+	}
+	for _, tt := range tests {
+		item := items[tt.item]
+		if !slices.Equal(item.Requires, []string{tt.requires}) {
+			t.Errorf("%s requires %q, want %s", item.Name, item.Requires, tt.requires)
+		}
+		action := Action{Language: item.Name, Choice: "install"}
+		if itemSteps(&action, item); !slices.Equal(action.Steps, tt.steps) {
+			t.Errorf("%s install steps = %q, want %q", item.Name, action.Steps, tt.steps)
+		}
+	}
+}
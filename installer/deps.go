@@ -0,0 +1,86 @@
+package installer
+
+import "decor/catalog"
+
+// prerequisites returns what an item requires, with alternatives swapped for the ones the settings pick
+func prerequisites(name string) []string {
+	item, ok := catalog.Find(name)
+	if !ok {
+		return nil
+	}
+	required := make([]string, 0, len(item.Requires)+1)
+	for _, name := range item.Requires {
+		required = append(required, Alternative(name))
+	}
+	if needsBrew(item) && !brewInstalled() {
+		required = append(required, "Homebrew")
+	}
+	return required
+}
+
+// WithDependencies adds every prerequisite of the selected items that isn't selected, and orders the
+// result so prerequisites come before the items that need them, keeping the selection's order otherwise.
+// added maps each item that was added to the item that needed it. Installed prerequisites are added too:
+// checking them is what decides whether there's anything to do.
+func WithDependencies(selected []string) (ordered []string, added map[string]string) {
+	wanted := make(map[string]bool)
+	for _, name := range selected {
+		wanted[name] = true
+	}
+	added = make(map[string]string)
+	visited := make(map[string]bool)
+
+	var visit func(name, neededBy string)
+	visit = func(name, neededBy string) {
+		// Marking before recursing ignores a cycle rather than looping on it; TestCatalogDependencies keeps
+		// the catalog free of them
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+		if !wanted[name] {
+			added[name] = neededBy
+		}
+		for _, required := range prerequisites(name) {
+			visit(required, name)
+		}
+		ordered = append(ordered, name)
+	}
+	for _, name := range selected {
+		visit(name, "")
+	}
+	return ordered, added
+}
+
+// Conflict is a pair of items chosen together that clash
+type Conflict struct {
+	First, Second string
+	Reason        string
+}
+
+// Conflicts returns the clashes among the languages that will be installed or updated, in selection order
+func Conflicts(languages []string, choices map[string]string) []Conflict {
+	var conflicts []Conflict
+	for i, first := range languages {
+		for _, second := range languages[i+1:] {
+			if choices[first] == "skip" || choices[second] == "skip" {
+				continue
+			}
+			if reason := conflictReason(first, second); reason != "" {
+				conflicts = append(conflicts, Conflict{First: first, Second: second, Reason: reason})
+			}
+		}
+	}
+	return conflicts
+}
+
+// conflictReason returns why two items clash, whichever of them declares it, or "" if they don't
+func conflictReason(a, b string) string {
+	if item, ok := catalog.Find(a); ok && item.Conflicts[b] != "" {
+		return item.Conflicts[b]
+	}
+	if item, ok := catalog.Find(b); ok {
+		return item.Conflicts[a]
+	}
+	return ""
+}
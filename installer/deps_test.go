@@ -0,0 +1,102 @@
+package installer
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"decor/catalog"
+	"decor/config"
+)
+
+func TestWithDependencies(t *testing.T) {
+	original := settings
+	t.Cleanup(func() { settings = original })
+	settings = config.Default()
+
+	tests := []struct {
+		name     string
+		selected []string
+		want     []string
+		added    map[string]string
+	}{
+		{"nothing needed", []string{"Go", "ripgrep"}, []string{"Go", "ripgrep"}, map[string]string{}},
+		{"prerequisite added", []string{"rust-analyzer"}, []string{"Rust", "rust-analyzer"}, map[string]string{"Rust": "rust-analyzer"}},
+		{"prerequisite reordered", []string{"wasm-pack", "Rust"}, []string{"Rust", "wasm-pack"}, map[string]string{}},
+		{"transitive", []string{"Jupyter"}, []string{"Python", "pipx", "Jupyter"}, map[string]string{"pipx": "Jupyter", "Python": "pipx"}},
+		{"shared prerequisite", []string{"wasm-pack", "rust-analyzer"}, []string{"Rust", "wasm-pack", "rust-analyzer"}, map[string]string{"Rust": "wasm-pack"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, added := WithDependencies(tt.selected)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+			if !maps.Equal(added, tt.added) {
+				t.Errorf("added = %v, want %v", added, tt.added)
+			}
+		})
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	languages := []string{"Docker", "ripgrep", "uv", "Podman", "Miniforge"}
+	tests := []struct {
+		name    string
+		choices map[string]string
+		want    []string
+	}{
+		{"both chosen", map[string]string{"Docker": "install", "Podman": "update", "uv": "skip"}, []string{"Docker/Podman"}},
+		{"declared on the other side", map[string]string{"Docker": "skip", "uv": "install", "Miniforge": "install"}, []string{"uv/Miniforge"}},
+		{"one skipped", map[string]string{"Docker": "install", "Podman": "skip", "uv": "skip"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, conflict := range Conflicts(languages, tt.choices) {
+				if conflict.Reason == "" {
+					t.Errorf("%s and %s conflict without a reason", conflict.First, conflict.Second)
+				}
+				got = append(got, conflict.First+"/"+conflict.Second)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCatalogDependencies checks every prerequisite and conflict exists, and no prerequisites require each other in a cycle
+func TestCatalogDependencies(t *testing.T) {
+	for _, item := range catalog.Items {
+		for name := range item.Conflicts {
+			if _, ok := catalog.Find(name); !ok {
+				t.Errorf("%s conflicts with %s, which isn't in the catalog", item.Name, name)
+			}
+		}
+		for _, required := range item.Requires {
+			if _, ok := catalog.Find(required); !ok {
+				t.Errorf("%s requires %s, which isn't in the catalog", item.Name, required)
+			}
+		}
+
+		// Walk the prerequisites; reaching the item again means a cycle
+		seen := make(map[string]bool)
+		queue := slices.Clone(item.Requires)
+		for len(queue) > 0 {
+			name := queue[0]
+			queue = queue[1:]
+			if name == item.Name {
+				t.Errorf("%s requires itself through its prerequisites", item.Name)
+				break
+			}
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if required, ok := catalog.Find(name); ok {
+				queue = append(queue, required.Requires...)
+			}
+		}
+	}
+}
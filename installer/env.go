@@ -0,0 +1,128 @@
+package installer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"decor/catalog"
+	"decor/config"
+	"decor/envfile"
+	"decor/jdk"
+	"decor/pkgmgr"
+)
+
+// envEntry returns the variables and PATH directories language needs once installed, for decor's
+// environment scripts
+func envEntry(language string) envfile.Entry {
+	if isBSD() && coreLanguage(language) {
+		// The system's packages put everything in /usr/local/bin
+		return envfile.Entry{}
+	}
+	switch strings.ToLower(language) {
+	case "go":
+		return envfile.Entry{Path: []string{filepath.Join(archiveDir(goArchive), "bin"), goBin()}}
+	case "java":
+		home := jdk.Home(filepath.Join(javaRoot(), "current"))
+		return envfile.Entry{Vars: map[string]string{"JAVA_HOME": home}, Path: []string{filepath.Join(home, "bin")}}
+	case "rust":
+		// rustup-init leaves PATH alone, so ~/.cargo/bin goes here
+		entry := envfile.Entry{Path: []string{filepath.Join(config.ExpandHome("~/.cargo"), "bin")}}
+		if home := os.Getenv("CARGO_HOME"); home != "" {
+			entry.Vars = map[string]string{"CARGO_HOME": home}
+			entry.Path = []string{filepath.Join(home, "bin")}
+		}
+		return entry
+	case "direnv":
+		// Its hook, loaded by the script, is what makes entering a directory with an .envrc load it
+		return envfile.Entry{Hooks: []string{"direnv"}}
+	case "homebrew":
+		prefix := pkgmgr.BrewPrefix()
+		if prefix == "" {
+			return envfile.Entry{}
+		}
+		return envfile.Entry{Vars: map[string]string{"HOMEBREW_PREFIX": prefix}, Path: []string{filepath.Join(prefix, "bin"), filepath.Join(prefix, "sbin")}}
+	}
+	item, ok := catalog.Find(language)
+	if !ok {
+		return envfile.Entry{}
+	}
+	switch itemStrategy(item) {
+	case "binary", "release":
+		return envfile.Entry{Path: []string{config.ExpandHome("~/.local/bin")}}
+	case "archive":
+		if len(item.Archive.Links) > 0 {
+			return envfile.Entry{Path: []string{config.ExpandHome("~/.local/bin")}}
+		}
+	case "go":
+		return envfile.Entry{Path: []string{goBin()}}
+	}
+	return envfile.Entry{}
+}
+
+// setEnv writes what an installed item needs into decor's environment scripts, noting the line that
+// loads them when this shell hasn't. Failing is noted rather than failing an install that worked.
+func setEnv(language string, progress *LanguageProgress) {
+	entry := envEntry(language)
+	if entry.Empty() {
+		return
+	}
+	if err := envfile.Set(language, entry); err != nil {
+		progress.AddNote(fmt.Sprintf("couldn't add %s to decor's environment scripts: %v", language, err))
+		return
+	}
+	if entry.Applied() {
+		return
+	}
+	shell := envfile.Shell(os.Getenv("SHELL"), runtime.GOOS)
+	line, err := envfile.SourceLine(shell)
+	if err != nil {
+		return
+	}
+	progress.AddNote(fmt.Sprintf("%s set in decor's environment script; add `%s` to your shell's startup file, or run it now", envSummary(entry), line))
+}
+
+// unsetEnv drops a removed item from decor's environment scripts
+func unsetEnv(language string, progress *LanguageProgress) {
+	if err := envfile.Remove(language); err != nil {
+		progress.AddNote(fmt.Sprintf("couldn't drop %s from decor's environment scripts: %v", language, err))
+	}
+}
+
+// envSummary names what an entry sets, like "JAVA_HOME and PATH"
+func envSummary(entry envfile.Entry) string {
+	names := make([]string, 0, len(entry.Vars)+1)
+	for name := range entry.Vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(entry.Path) > 0 {
+		names = append(names, "PATH")
+	}
+	for _, program := range entry.Hooks {
+		names = append(names, program+"'s hook")
+	}
+	if len(names) == 1 {
+		return names[0]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+// envSteps adds writing what the item needs into decor's environment script to a plan action
+func envSteps(a *Action) {
+	if len(a.Steps) > 0 && strings.HasPrefix(a.Steps[0], "nothing") {
+		return
+	}
+	entry := envEntry(a.Language)
+	if entry.Empty() {
+		return
+	}
+	script, err := envfile.Script(envfile.Shell(os.Getenv("SHELL"), runtime.GOOS))
+	if err != nil {
+		return
+	}
+	a.Steps = append(a.Steps, fmt.Sprintf("set %s in %s", envSummary(entry), script))
+}
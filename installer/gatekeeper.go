@@ -0,0 +1,111 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+
+	"decor/catalog"
+	"decor/errs"
+	"decor/platform"
+	"decor/runner"
+)
+
+// quarantine is the extended attribute macOS puts on downloaded files, which has Gatekeeper check a
+// program's signature and notarization the first time it runs
+const quarantine = "com.apple.quarantine"
+
+// gatekeeperMessages are what macOS says when Gatekeeper or code signing stops a program
+var gatekeeperMessages = []string{
+	"cannot be opened because",
+	"developer cannot be verified",
+	"is damaged and can't be opened",
+	"Code Signature Invalid",
+}
+
+// unquarantine clears the quarantine attribute from path, a program or a directory holding programs, on
+// macOS. decor has checked the download against its checksum, so Gatekeeper's check on first run would
+// only stop a program decor reported as installed.
+func unquarantine(ctx context.Context, path string, root bool) error {
+	if runtime.GOOS != "darwin" {
+		return nil
+	}
+	return runCommand(ctx, runner.Spec{
+		Op:   "clearing macOS's quarantine from " + path,
+		Name: "find",
+		Args: []string{path, "-xattrname", quarantine, "-exec", "xattr", "-d", quarantine, "{}", "+"},
+		Root: root,
+	})
+}
+
+// ensureSigned signs an unsigned program ad hoc on Apple silicon, where macOS kills unsigned programs
+// as soon as they start
+func ensureSigned(ctx context.Context, program string, root bool) error {
+	if runtime.GOOS != "darwin" || platform.Current().Arch != "arm64" {
+		return nil
+	}
+	verify := runner.Spec{Op: "checking " + program + "'s signature", Name: "codesign", Args: []string{"--verify", program}, ReadOnly: true}
+	if runCommand(ctx, verify) == nil {
+		return nil
+	}
+	return runCommand(ctx, runner.Spec{Op: "signing " + program + " ad hoc", Name: "codesign", Args: []string{"--force", "--sign", "-", program}, Root: root})
+}
+
+// prepareProgram clears the quarantine from a downloaded program and makes sure it's signed
+func prepareProgram(ctx context.Context, program string, root bool) error {
+	if err := unquarantine(ctx, program, root); err != nil {
+		return err
+	}
+	return ensureSigned(ctx, program, root)
+}
+
+// quarantineSteps describes unquarantine and ensureSigned for a plan, on macOS
+func quarantineSteps(path string) []string {
+	if runtime.GOOS != "darwin" {
+		return nil
+	}
+	steps := []string{"clear macOS's quarantine attribute from " + path}
+	if platform.Current().Arch == "arm64" {
+		steps = append(steps, "sign programs without a signature ad hoc, so Apple silicon runs them")
+	}
+	return steps
+}
+
+// checkGatekeeper runs the item's version command once it's installed from a download, and reports
+// plainly when macOS refuses to run the program, rather than decor reporting success and the user
+// meeting "cannot be opened" later
+func checkGatekeeper(ctx context.Context, item catalog.Item) error {
+	if runtime.GOOS != "darwin" || len(item.Version) == 0 {
+		return nil
+	}
+	program := lookPath(item.Version[0])
+	output, err := commands.Run(ctx, runner.Spec{
+		Op:       "running " + item.Version[0],
+		Name:     program,
+		Args:     item.Version[1:],
+		Timeout:  settings.DetectTimeout,
+		ReadOnly: true,
+	})
+	if gatekeeperBlocked(output, err) {
+		return errs.New(errs.ErrBlocked, "installing "+item.Name, fmt.Errorf("macOS won't run %s", program))
+	}
+	return nil
+}
+
+// gatekeeperBlocked reports whether a program failed to run because macOS stopped it: Gatekeeper's
+// messages, or the SIGKILL a program with a bad signature gets on Apple silicon
+func gatekeeperBlocked(output []byte, err error) bool {
+	if err == nil {
+		return false
+	}
+	if strings.Contains(err.Error(), "signal: killed") {
+		return true
+	}
+	for _, msg := range gatekeeperMessages {
+		if strings.Contains(string(output), msg) {
+			return true
+		}
+	}
+	return false
+}
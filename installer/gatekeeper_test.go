@@ -0,0 +1,24 @@
+package installer
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGatekeeperBlocked(t *testing.T) {
+	tests := []struct {
+		output string
+		err    error
+		want   bool
+	}{
+		{"kubectl: v1.31.1", nil, false},
+		{"", errors.New("running kubectl: signal: killed"), true},
+		{`"kind" cannot be opened because the developer cannot be verified.`, errors.New("exit status 1"), true},
+		{"error: unknown flag --short", errors.New("exit status 1"), false},
+	}
+	for _, tt := range tests {
+		if got := gatekeeperBlocked([]byte(tt.output), tt.err); got != tt.want {
+			t.Errorf("gatekeeperBlocked(%q, %v) = %t, want %t", tt.output, tt.err, got, tt.want)
+		}
+	}
+}
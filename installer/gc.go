@@ -0,0 +1,132 @@
+package installer
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"decor/config"
+	"decor/installed"
+	"decor/runner"
+)
+
+// OldVersion is a version of an item kept beside the one in use, which decor gc can delete
+type OldVersion struct {
+	Item    string
+	Version string // its directory's name, e.g. 1.24.0 or temurin-21.0.4
+	Dir     string
+	Size    int64 // bytes it takes up
+}
+
+// OldVersions lists the versions of what decor installed that garbage collection deletes: all but the
+// keep most recently installed of each item, never the one in use or one its [[pins]] are for
+func OldVersions(keep int) ([]OldVersion, error) {
+	if !keepsVersions() {
+		return nil, nil
+	}
+	records, err := installed.List()
+	if err != nil {
+		return nil, err
+	}
+	var old []OldVersion
+	for _, record := range records {
+		root := versionsRoot(record.Name)
+		if root == "" {
+			continue
+		}
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			continue
+		}
+		current := filepath.Base(linkedVersion(root, "current"))
+		var versions []fs.FileInfo
+		for _, entry := range entries {
+			info, err := entry.Info()
+			// The links, and a link left half-swapped, aren't versions
+			if err != nil || !info.IsDir() || strings.HasSuffix(entry.Name(), ".decor-new") {
+				continue
+			}
+			versions = append(versions, info)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i].ModTime().After(versions[j].ModTime()) })
+
+		// The one in use counts toward those kept, so keep 1 leaves only it
+		kept := 1
+		for _, info := range versions {
+			name := info.Name()
+			switch {
+			case name == current, pinnedVersion(record.Name, name):
+				continue
+			case kept < keep:
+				kept++
+				continue
+			}
+			dir := filepath.Join(root, name)
+			old = append(old, OldVersion{Item: record.Name, Version: name, Dir: dir, Size: dirSize(dir)})
+		}
+	}
+	return old, nil
+}
+
+// pinnedVersion reports whether a [[pins]] table is for the version of language in the directory
+// named name, by its version or, for archives with none, the checksum it's named after
+func pinnedVersion(language, name string) bool {
+	version := strings.TrimLeftFunc(name, func(r rune) bool { return !unicode.IsDigit(r) })
+	for _, p := range settings.Pins {
+		if !strings.EqualFold(p.Item, language) {
+			continue
+		}
+		if p.Version != "" && version != "" {
+			if ok, _ := config.VersionAllowed(p.Version, version); ok {
+				return true
+			}
+		}
+		// versionName names an unversioned archive's directory after the first 12 digits of its checksum
+		if len(name) == 12 && strings.HasPrefix(strings.ToLower(p.SHA256), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// dirSize adds up the sizes of the files under dir, not following links
+func dirSize(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// Prune deletes old versions that OldVersions listed, returning the bytes freed. A previous link left
+// pointing at a deleted version is removed too, since there's nothing to roll back to.
+func Prune(ctx context.Context, versions []OldVersion) (int64, error) {
+	var freed int64
+	for _, v := range versions {
+		root := filepath.Dir(v.Dir)
+		privileged := !writable(root)
+		specs := []runner.Spec{{Op: "removing " + v.Item + " " + v.Version, Name: "rm", Args: []string{"-rf", v.Dir}, Root: privileged}}
+		if filepath.Base(linkedVersion(root, "previous")) == v.Version {
+			specs = append(specs, runner.Spec{Op: "removing the previous link", Name: "rm", Args: []string{"-f", filepath.Join(root, "previous")}, Root: privileged})
+		}
+		for _, spec := range specs {
+			if err := runCommand(ctx, spec); err != nil {
+				return freed, err
+			}
+		}
+		if !dryRun {
+			freed += v.Size
+		}
+	}
+	return freed, nil
+}
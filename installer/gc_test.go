@@ -0,0 +1,90 @@
+package installer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+
+	"decor/config"
+	"decor/installed"
+	"decor/runner"
+)
+
+func TestOldVersions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("versions aren't kept on Windows")
+	}
+	tempState(t)
+	original, originalCommands := settings, commands
+	t.Cleanup(func() { settings, commands = original, originalCommands })
+	settings = config.Default()
+	settings.InstallPrefix = t.TempDir()
+	settings.Pins = []config.Pin{{Item: "Go", Version: "1.22.0"}}
+	commands = &runner.Runner{}
+
+	if err := installed.Add("Go", "tarball", "1.25.0"); err != nil {
+		t.Fatal(err)
+	}
+	root := versionsDir(filepath.Join(settings.Prefix(), "go"))
+	// Oldest first, each with a 100-byte file
+	installedAt := time.Now().Add(-time.Hour)
+	for _, version := range []string{"1.22.0", "1.23.0", "1.25.0", "1.24.0"} {
+		dir := filepath.Join(root, version)
+		os.MkdirAll(dir, 0o755)
+		os.WriteFile(filepath.Join(dir, "VERSION"), make([]byte, 100), 0o644)
+		os.Chtimes(dir, installedAt, installedAt)
+		installedAt = installedAt.Add(time.Minute)
+	}
+	// 1.24.0 was installed last, then rolled back from
+	os.Symlink("1.25.0", filepath.Join(root, "current"))
+	os.Symlink("1.24.0", filepath.Join(root, "previous"))
+
+	versions := func(old []OldVersion) []string {
+		var names []string
+		for _, v := range old {
+			names = append(names, v.Version)
+		}
+		return names
+	}
+	tests := []struct {
+		keep int
+		want []string
+	}{
+		{3, nil},
+		{2, []string{"1.23.0"}},
+		{1, []string{"1.24.0", "1.23.0"}},
+	}
+	for _, tt := range tests {
+		old, err := OldVersions(tt.keep)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := versions(old); !slices.Equal(got, tt.want) {
+			t.Errorf("keeping %d, OldVersions = %v, want %v", tt.keep, got, tt.want)
+		}
+	}
+
+	old, _ := OldVersions(1)
+	freed, err := Prune(context.Background(), old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if freed != 200 {
+		t.Errorf("Prune freed %d bytes, want 200", freed)
+	}
+	for _, version := range []string{"1.23.0", "1.24.0"} {
+		if exists(filepath.Join(root, version)) {
+			t.Errorf("%s wasn't deleted", version)
+		}
+	}
+	if _, err := os.Lstat(filepath.Join(root, "previous")); err == nil {
+		t.Error("the previous link to a deleted version was left")
+	}
+	if linkedVersion(root, "current") != "1.25.0" || !exists(filepath.Join(root, "1.22.0")) {
+		t.Error("the version in use or the pinned one was deleted")
+	}
+}
@@ -0,0 +1,105 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"decor/catalog"
+	"decor/download"
+	"decor/errs"
+	"decor/pkgmgr"
+	"decor/platform"
+	"decor/release"
+	"decor/runner"
+)
+
+// brewInstalled reports whether Homebrew is installed, on PATH or where its installer puts it; tests
+// replace it
+var brewInstalled = func() bool {
+	return lookPath("brew") != "brew"
+}
+
+// needsBrew reports whether installing the item means running brew: it installs through Homebrew and
+// has no other way to be installed
+func needsBrew(item catalog.Item) bool {
+	if strings.EqualFold(item.Name, "Homebrew") {
+		return false
+	}
+	if strings.EqualFold(item.Name, "Python") {
+		return usesBrew()
+	}
+	return itemStrategy(item) == "brew"
+}
+
+// installHomebrew installs Homebrew from the .pkg installer its latest release publishes, checked against
+// the asset's SHA-256 like any other release download. Homebrew's own install.sh isn't piped into a shell.
+func installHomebrew(ctx context.Context, progress *LanguageProgress) error {
+	op := "installing Homebrew"
+	if runtime.GOOS != "darwin" {
+		return errs.New(errs.ErrUnsupportedPlatform, op, fmt.Errorf("decor installs Homebrew on macOS only"))
+	}
+
+	progress.Set(0.1, "Finding the latest Homebrew release...")
+	latest, err := release.Latest(ctx, "Homebrew/brew")
+	if err != nil {
+		return errs.Classify(op, err)
+	}
+	asset, err := release.Pick(latest.Assets, "Homebrew-*.pkg", platform.Current())
+	if err != nil {
+		return errs.New(errs.ErrUnsupportedPlatform, op, err)
+	}
+	sum, err := release.Checksum(ctx, latest, asset)
+	if err != nil {
+		return errs.Classify(op, err)
+	}
+	if sum == "" {
+		return errs.New(errs.ErrChecksumMismatch, op, fmt.Errorf("Homebrew %s publishes no checksum for %s, and decor won't run an unverified installer as root", latest.Tag, asset.Name))
+	}
+
+	progress.SetPhase(PhaseDownloading)
+	progress.Set(0.2, fmt.Sprintf("Downloading %s...", asset.Name))
+	pkg, err := fetch(ctx, progress, asset.URL, "")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(pkg)
+	if !dryRun {
+		if err := download.VerifySHA256(pkg, sum); err != nil {
+			return err
+		}
+	}
+
+	progress.Set(0.6, "Running the Homebrew installer...")
+	err = serialize(ctx, progress, func(ctx context.Context) error {
+		return runCommand(ctx, runner.Spec{Op: op, Name: "installer", Args: []string{"-pkg", pkg, "-target", "/"}, Root: true})
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// brewPathAdvice tells the user how to put Homebrew's programs on PATH, or returns "" if they're on it
+// or Homebrew isn't installed
+func brewPathAdvice() string {
+	prefix := pkgmgr.BrewPrefix()
+	if prefix == "" {
+		return ""
+	}
+	bin := filepath.Join(prefix, "bin")
+	if slices.Contains(filepath.SplitList(os.Getenv("PATH")), bin) {
+		return ""
+	}
+	return fmt.Sprintf("add eval \"$(%s shellenv)\" to your shell profile to put %s on PATH", filepath.Join(bin, "brew"), bin)
+}
+
+// updateHomebrew updates Homebrew itself and its list of formulae
+func updateHomebrew(ctx context.Context, progress *LanguageProgress) error {
+	progress.Set(0.3, "Updating Homebrew...")
+	return runPackageManager(ctx, progress, "brew", "update")
+}
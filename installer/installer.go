@@ -0,0 +1,941 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"decor/catalog"
+	"decor/config"
+	"decor/download"
+	"decor/errs"
+	"decor/history"
+	"decor/installed"
+	"decor/metrics"
+	"decor/pkgmgr"
+	"decor/platform"
+	"decor/runner"
+	"decor/services"
+)
+
+// InstallationStatus represents the status of a language installation
+type InstallationStatus struct {
+	Language      string    `json:"language"`
+	Installed     bool      `json:"installed"`
+	Version       string    `json:"version,omitempty"`
+	LatestVersion string    `json:"latest,omitempty"`
+	LatestFetched time.Time `json:"latest_fetched,omitzero"` // when LatestVersion was looked up, a while ago if it was cached
+	TimedOut      bool      `json:"timed_out,omitempty"`     // the version check hung and was killed
+	Error         string    `json:"error,omitempty"`
+	Service       string    `json:"service,omitempty"` // state of the item's service, e.g. "running"
+	Managed       bool      `json:"managed,omitempty"` // decor installed it, rather than finding it on the system
+	Source        string    `json:"source,omitempty"`  // what installed it: "brew", "apt", "manual", or how decor did
+	Arch          string    `json:"arch,omitempty"`    // the architectures its program is built for, e.g. "arm64" or "amd64+arm64"
+	Rosetta       bool      `json:"rosetta,omitempty"` // its program is x86_64 only, running under Rosetta on Apple silicon
+}
+
+// LanguageProgress tracks download/install progress for a language
+type LanguageProgress struct {
+	Language       string
+	Progress       float64 // 0.0 to 1.0
+	CurrentStep    string  // "downloading", "installing", "complete", "error"
+	Phase          string  // where the item is in the run's pipeline: PhaseQueued, PhaseDownloading, ...
+	TotalSteps     int
+	CurrentStepNum int
+	ErrorMessage   string
+	Hint           string                   // remediation suggestion for ErrorMessage
+	Notes          []string                 // extra outcomes for the summary, e.g. a smoke test result or a re-login warning
+	Waiting        bool                     // blocked on another process holding the package manager lock
+	Timings        map[string]time.Duration // time spent in each phase so far
+	Downloaded     int64                    // bytes fetched by decor itself; package managers' downloads aren't counted
+	Output         string                   // the commands the item ran and everything they printed, kept once it fails
+	OnChange       func(ProgressSnapshot)
+	mu             sync.Mutex
+	phaseStart     time.Time
+	sources        []installed.Source // files fetched for the item, recorded once it's installed
+	retryBase      string             // the step a request being retried was made in
+	retryStep      string             // what the step was set to for the retry
+}
+
+// ProgressSnapshot is a copy of a language's progress that can be read without locking
+type ProgressSnapshot struct {
+	Language     string   `json:"language"`
+	Progress     float64  `json:"progress"`
+	CurrentStep  string   `json:"step"`
+	Phase        string   `json:"phase"`
+	ErrorMessage string   `json:"error,omitempty"`
+	Hint         string   `json:"hint,omitempty"`
+	Notes        []string `json:"notes,omitempty"`
+	Waiting      bool     `json:"waiting,omitempty"`
+
+	Timings    map[string]time.Duration `json:"timings,omitempty"`
+	Downloaded int64                    `json:"downloaded,omitempty"`
+	Output     string                   `json:"output,omitempty"`
+}
+
+// NewProgress creates a progress tracker for a language that hasn't started yet
+func NewProgress(language string) *LanguageProgress {
+	return &LanguageProgress{
+		Language:    language,
+		Progress:    0.0,
+		CurrentStep: "starting",
+		Phase:       PhaseQueued,
+		TotalSteps:  3,
+	}
+}
+
+// Snapshot returns a copy of the current progress
+func (p *LanguageProgress) Snapshot() ProgressSnapshot {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.snapshot()
+}
+
+// snapshot copies the progress; the caller holds p.mu
+func (p *LanguageProgress) snapshot() ProgressSnapshot {
+	return ProgressSnapshot{
+		Language:     p.Language,
+		Progress:     p.Progress,
+		CurrentStep:  p.CurrentStep,
+		Phase:        p.Phase,
+		ErrorMessage: p.ErrorMessage,
+		Hint:         p.Hint,
+		Notes:        slices.Clone(p.Notes),
+		Waiting:      p.Waiting,
+		Timings:      maps.Clone(p.Timings),
+		Downloaded:   p.Downloaded,
+		Output:       p.Output,
+	}
+}
+
+// update applies change under the lock and notifies OnChange
+func (p *LanguageProgress) update(change func()) {
+	p.mu.Lock()
+	change()
+	snapshot := p.snapshot()
+	onChange := p.OnChange
+	p.mu.Unlock()
+
+	if onChange != nil {
+		onChange(snapshot)
+	}
+}
+
+// Set updates the progress fraction and current step
+func (p *LanguageProgress) Set(progress float64, step string) {
+	p.update(func() {
+		p.Progress = progress
+		p.CurrentStep = step
+	})
+}
+
+// retrying shows a request being retried in the step, e.g. "Downloading Go... (retrying in 2s, attempt 2
+// of 4)", and logs why
+func (p *LanguageProgress) retrying(r download.Retry) {
+	runner.Logf("%v; retrying in %s", r.Err, r.Wait.Round(time.Second/10))
+	p.update(func() {
+		if p.CurrentStep != p.retryStep {
+			p.retryBase = p.CurrentStep
+		}
+		p.CurrentStep = fmt.Sprintf("%s (retrying in %s, attempt %d of %d)", p.retryBase, r.Wait.Round(time.Second/10), r.Attempt, r.Attempts)
+		p.retryStep = p.CurrentStep
+	})
+}
+
+// AddNote records an outcome to show in the summary
+func (p *LanguageProgress) AddNote(note string) {
+	p.update(func() {
+		p.Notes = append(p.Notes, note)
+	})
+}
+
+// settings are the preferences every installer consults
+var settings = config.Default()
+
+// commands runs every command the installers need, elevating those that need root through sudo or doas
+// so decor itself never has to
+var commands = runner.New("")
+
+// dryRun makes installers log the commands they would run instead of changing anything
+var dryRun bool
+
+// Configure makes the installers respect the given preferences, and adds the custom tools in them to
+// the catalog. Custom tools named like one of decor's items are left out and returned as an error.
+func Configure(cfg config.Config) error {
+	settings = cfg
+	commands = runner.New(cfg.SudoPolicy)
+	download.SetConnections(cfg.DownloadConns)
+	download.SetTimeouts(cfg.HTTPTimeout, cfg.DownloadIdle)
+	download.SetMetadataMaxAge(cfg.MetadataMaxAge)
+	commands.DryRun = dryRun
+	return catalog.SetCustom(customItems(cfg.Tools))
+}
+
+// SetDryRun turns dry runs on or off
+func SetDryRun(on bool) {
+	dryRun = on
+	commands.DryRun = on
+}
+
+// Privileged returns the runner used for commands, for callers that need to authenticate it
+func Privileged() *runner.Runner {
+	return commands
+}
+
+// usesBrew reports whether package installs should go through Homebrew rather than apt: always on macOS,
+// and on Linux once Linuxbrew is installed, unless the settings pick one
+func usesBrew() bool {
+	switch settings.PackageManager {
+	case "brew":
+		return true
+	case "apt":
+		return false
+	default:
+		return runtime.GOOS == "darwin" || runtime.GOOS == "linux" && pkgmgr.BrewPrefix() != ""
+	}
+}
+
+// usesApt reports whether apt installs what Homebrew doesn't: everywhere but macOS and the BSDs, so on
+// Linux items without a formula still install through apt alongside Linuxbrew
+func usesApt() bool {
+	return runtime.GOOS == "linux" || !usesBrew() && !isBSD()
+}
+
+// NeedsRoot reports whether any chosen install, update, rollback or garbage collection runs privileged
+// commands on this platform
+func NeedsRoot(languages []string, choices map[string]string) bool {
+	for _, lang := range languages {
+		switch choices[lang] {
+		case "skip":
+			continue
+		case "rollback", "gc":
+			if root := versionsRoot(lang); root != "" && !writable(root) {
+				return true
+			}
+			continue
+		case "update":
+			if strings.ToLower(lang) == "c++" {
+				return true
+			}
+		}
+		if isBSD() && coreLanguage(lang) {
+			return true
+		}
+		if choices[lang] != "remove" && componentsNeedRoot(lang) {
+			return true
+		}
+		switch strings.ToLower(lang) {
+		case "go":
+			if !writable(settings.Prefix()) {
+				return true
+			}
+		case "python":
+			if !usesBrew() {
+				return true
+			}
+		case "java":
+			if !writable(settings.Prefix()) {
+				return true
+			}
+		case "c++":
+			if runtime.GOOS != "darwin" {
+				return true
+			}
+		case "homebrew":
+			if choices[lang] == "install" {
+				return true
+			}
+		default:
+			item, ok := catalog.Find(lang)
+			if !ok {
+				continue
+			}
+			if itemStrategy(item) == "apt" || itemStrategy(item) == "deb" {
+				return true
+			}
+			if runtime.GOOS == "linux" && (item.Services["linux"] != "" || item.UserGroup != "") {
+				return true
+			}
+			if runtime.GOOS == "windows" && item.Services["windows"] != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkWorkers bounds how many detection commands run at once
+const checkWorkers = 4
+
+// Check checks which languages are installed
+func Check(languages []string) map[string]*InstallationStatus {
+	status := make(map[string]*InstallationStatus)
+	for result := range CheckStream(languages) {
+		status[result.Language] = result
+	}
+	return status
+}
+
+// CheckStream checks the languages concurrently and sends each status as soon as it's known.
+// The channel is closed once every language has been checked.
+func CheckStream(languages []string) <-chan *InstallationStatus {
+	jobs := make(chan string, len(languages))
+	for _, lang := range languages {
+		jobs <- lang
+	}
+	close(jobs)
+
+	results := make(chan *InstallationStatus, len(languages))
+	var wg sync.WaitGroup
+	for i := 0; i < min(checkWorkers, len(languages)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for lang := range jobs {
+				results <- checkLanguageInstallation(lang)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+// checkLanguageInstallation checks if a language is installed and gets its version,
+// killing the check if it runs longer than the configured detect timeout
+func checkLanguageInstallation(language string) *InstallationStatus {
+	status := &InstallationStatus{Language: language}
+
+	spec := runner.Spec{Op: "checking " + language, Timeout: settings.DetectTimeout, ReadOnly: true}
+	var item catalog.Item
+	switch strings.ToLower(language) {
+	case "go":
+		spec.Name, spec.Args = "go", []string{"version"}
+	case "python":
+		spec.Name, spec.Args = "python3", []string{"--version"}
+	case "rust":
+		spec.Name, spec.Args = "rustc", []string{"--version"}
+	case "c++":
+		switch {
+		case runtime.GOOS == "darwin":
+			spec.Name, spec.Args = "clang", []string{"--version"}
+		case isBSD():
+			// The base system's compiler is clang, installed as c++ rather than g++
+			spec.Name, spec.Args = "c++", []string{"--version"}
+		default:
+			spec.Name, spec.Args = "g++", []string{"--version"}
+		}
+	case "java":
+		spec.Name, spec.Args = lookPath("java"), []string{"-version"}
+		if runtime.GOOS == "openbsd" && spec.Name == "java" {
+			spec.Name = bsdJava()
+		}
+	default:
+		var ok bool
+		if item, ok = catalog.Find(language); !ok {
+			return status
+		}
+		if spec.Name, spec.Args, ok = itemVersionCommand(item); !ok {
+			return status
+		}
+	}
+
+	output, err := commands.Run(context.Background(), spec)
+	if errors.Is(err, errs.ErrTimedOut) {
+		status.TimedOut = true
+		status.Error = fmt.Sprintf("no answer within %s", settings.DetectTimeout)
+		return status
+	}
+	if err != nil {
+		return status
+	}
+	// Commands that list things, like rustup's targets, succeed either way; the item's line is what counts
+	expect := item.Expect
+	if item.Winget != "" && itemStrategy(item) == "winget" {
+		expect = item.Winget
+	}
+	if expect != "" {
+		output = matchingLine(output, expect)
+		if output == nil {
+			return status
+		}
+	}
+
+	status.Installed = true
+	status.Version = parseVersion(string(output), language)
+	status.LatestVersion, status.LatestFetched = latestVersion(language)
+	if record, ok := installed.Get(language); ok {
+		status.Managed, status.Source = true, record.Method
+	} else {
+		status.Source = source(context.Background(), spec.Name)
+	}
+	if name := item.Services[runtime.GOOS]; name != "" {
+		status.Service = serviceState(name, item.HealthCheck)
+	}
+	status.Arch = programArch(spec.Name)
+	status.Rosetta = platform.Translated(status.Arch)
+	return status
+}
+
+// serviceState reports whether a service is running, and whether it answers its health check
+func serviceState(name string, healthCheck []string) string {
+	state := services.State(context.Background(), commands, name)
+	if state != services.StateRunning || len(healthCheck) == 0 {
+		return state
+	}
+	check := append([]string{lookPath(healthCheck[0])}, healthCheck[1:]...)
+	if err := services.Healthy(context.Background(), commands, check); err != nil {
+		return "running, not responding"
+	}
+	return state
+}
+
+// matchingLine returns the first line of output containing text, or nil if none does
+func matchingLine(output []byte, text string) []byte {
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.Contains(line, text) {
+			return []byte(line)
+		}
+	}
+	return nil
+}
+
+// parseVersion extracts version from command output
+func parseVersion(output, language string) string {
+	lines := strings.Split(output, "\n")
+	if len(lines) > 0 {
+		return strings.TrimSpace(lines[0])
+	}
+	return "unknown"
+}
+
+// ltsVersions are the oldest still-supported releases, used on the "lts" channel
+var ltsVersions = map[string]string{
+	"go":     "1.24.11",
+	"python": "3.12.0",
+}
+
+// getLatestVersion gets the latest version of a language on the configured channel
+func getLatestVersion(language string) string {
+	version, _ := latestVersion(language)
+	return version
+}
+
+// latestVersion gets the latest version of a language on the configured channel, and when it was looked
+// up, which is zero for the versions decor has built in
+func latestVersion(language string) (string, time.Time) {
+	if settings.Channel == "lts" {
+		if version, ok := ltsVersions[strings.ToLower(language)]; ok {
+			return version, time.Time{}
+		}
+	}
+	found := lookupLatest(context.Background(), language)
+	return found.version, found.fetched
+}
+
+// pythonFormula returns the Homebrew formula for the channel's Python release, e.g. python@3.13
+func pythonFormula() string {
+	parts := strings.SplitN(getLatestVersion("python"), ".", 3)
+	if len(parts) < 2 {
+		return "python3"
+	}
+	return fmt.Sprintf("python@%s.%s", parts[0], parts[1])
+}
+
+// writable reports whether the current user can create files in dir without root
+func writable(dir string) bool {
+	probe, err := os.CreateTemp(dir, ".decor-write-test-*")
+	if err != nil {
+		return false
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+	return true
+}
+
+// DefaultChoice returns the default choice based on installation status
+func DefaultChoice(status *InstallationStatus) string {
+	// A hung check usually means a broken or unreachable install, which is safer left alone
+	if status.TimedOut {
+		return "skip"
+	}
+	if !status.Installed {
+		return "install"
+	}
+	// Something decor didn't install belongs to whoever did; updating it is opt-in
+	if !status.Managed {
+		return "skip"
+	}
+	// Without a known latest release there's nothing to compare against
+	if status.LatestVersion != "" && status.Version != status.LatestVersion {
+		return "update"
+	}
+	return "skip"
+}
+
+// updateCheckIntervals maps the update check setting to how long decor waits between checks
+var updateCheckIntervals = map[string]time.Duration{
+	"daily":  24 * time.Hour,
+	"weekly": 7 * 24 * time.Hour,
+}
+
+// UpdateCheckDue reports whether the update check setting calls for checking the managed items now
+func UpdateCheckDue() bool {
+	interval, ok := updateCheckIntervals[settings.UpdateCheck]
+	return ok && time.Since(installed.LastChecked()) >= interval
+}
+
+// Outdated checks every item decor installed and returns those with a newer release, or running under
+// Rosetta when a reinstall would bring a native build, recording when the check ran. Items found on the
+// system are left to whoever installed them.
+func Outdated() ([]*InstallationStatus, error) {
+	records, err := installed.List()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	names := make([]string, len(records))
+	for i, record := range records {
+		names[i] = record.Name
+	}
+
+	var outdated []*InstallationStatus
+	status := Check(names)
+	for _, name := range names {
+		if s := status[name]; s.Installed && s.LatestVersion != "" && s.Version != s.LatestVersion || s.Reinstallable() {
+			outdated = append(outdated, s)
+		}
+	}
+	return outdated, installed.MarkChecked()
+}
+
+// NewTrackers creates progress trackers for every language that isn't skipped
+func NewTrackers(languages []string, choices map[string]string) map[string]*LanguageProgress {
+	trackers := make(map[string]*LanguageProgress)
+	for _, lang := range languages {
+		if choices[lang] != "skip" {
+			trackers[lang] = NewProgress(lang)
+		}
+	}
+	return trackers
+}
+
+// ErrCancelled is returned for languages whose install was stopped by cancelling the run
+var ErrCancelled = errors.New("installation cancelled")
+
+// Run installs or updates every language concurrently according to choices, reporting through
+// the trackers from NewTrackers, and returns each language's result once all have finished.
+// Cancelling ctx stops any commands still running.
+func Run(ctx context.Context, languages []string, choices map[string]string, trackers map[string]*LanguageProgress) map[string]string {
+	results := make(map[string]string)
+	var resultsMu sync.Mutex
+	var wg sync.WaitGroup
+
+	// Keep what the run does for its history report; dry runs change nothing, so they aren't kept
+	started := time.Now()
+	if !dryRun {
+		log := runner.Capture()
+		defer func() { saveRun(started, log(), languages, choices, results, trackers) }()
+	}
+
+	// Keep cached sudo credentials fresh for the whole run
+	forgetAptIndexes()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go commands.KeepAlive(ctx)
+
+	// Items wait for prerequisites that are being installed in the same run
+	finished := make(map[string]chan struct{})
+	failed := make(map[string]bool) // guarded by resultsMu
+	// Items not yet installing can be skipped, which stops them with ErrSkipped
+	skip := make(map[string]context.Context)
+	for _, lang := range languages {
+		if choices[lang] != "skip" {
+			finished[lang] = make(chan struct{})
+			itemCtx, cancelItem := context.WithCancelCause(ctx)
+			defer cancelItem(nil)
+			skip[lang] = itemCtx
+			queue.track(lang, cancelItem)
+		}
+	}
+
+	for _, lang := range languages {
+		choice := choices[lang]
+		if choice == "skip" {
+			results[lang] = "skipped"
+			continue
+		}
+
+		wg.Add(1)
+		go func(language, choiceType string, prog *LanguageProgress) {
+			defer wg.Done()
+			defer close(finished[language])
+			defer queue.untrack(language)
+			begun := time.Now()
+			ctx := skip[language]
+
+			// Start the clock; the installers move the item through its phases from here
+			prog.SetPhase(PhaseQueued)
+			err := waitForPrerequisites(ctx, language, prog, finished, func(name string) bool {
+				resultsMu.Lock()
+				defer resultsMu.Unlock()
+				return failed[name]
+			})
+
+			langCtx, cancelLang := context.WithTimeout(ctx, settings.InstallTimeout)
+			defer cancelLang()
+			langCtx, output := runner.CaptureOutput(langCtx)
+			langCtx = download.OnRetry(langCtx, prog.retrying)
+
+			var done string
+			switch {
+			case err != nil:
+			case choiceType == "install":
+				if err = installLanguageWithProgress(langCtx, language, prog); err == nil {
+					err = installComponents(langCtx, language, prog)
+				}
+				done = "installed"
+			case choiceType == "update":
+				if err = updateLanguageWithProgress(langCtx, language, prog); err == nil {
+					err = installComponents(langCtx, language, prog)
+				}
+				done = "updated"
+			case choiceType == "remove":
+				err = removeLanguageWithProgress(langCtx, language, prog)
+				done = "removed"
+			}
+			switch {
+			case err == nil:
+			case errors.Is(context.Cause(ctx), ErrSkipped):
+				err = ErrSkipped
+			case ctx.Err() != nil:
+				err = ErrCancelled
+			case langCtx.Err() == context.DeadlineExceeded:
+				err = errs.New(errs.ErrTimedOut, "installing "+language, fmt.Errorf("stopped after %s", settings.InstallTimeout))
+			}
+
+			switch {
+			case err != nil || dryRun:
+			case choiceType == "remove":
+				forget(language, prog)
+			default:
+				record(language, prog)
+				// Most versions aren't known until they're installed; one the policy or a pin forbids is
+				// recorded all the same, so decor can remove it
+				if err = checkInstalledVersion(language, prog); err == nil {
+					err = checkPinnedVersion(language)
+				}
+			}
+			// A cancelled run or skipped item says nothing about how reliable the installer is
+			if settings.LocalMetrics && !dryRun && choiceType != "remove" && !errors.Is(err, ErrCancelled) && !errors.Is(err, ErrSkipped) {
+				if metricsErr := metrics.Record(language, installMethod(language), time.Since(begun), err != nil); metricsErr != nil {
+					runner.Logf("couldn't update the local metrics: %v", metricsErr)
+				}
+			}
+
+			resultsMu.Lock()
+			switch {
+			case errors.Is(err, ErrSkipped):
+				// What needs it isn't installed either
+				results[language] = "skipped"
+				failed[language] = true
+			case err != nil:
+				results[language] = fmt.Sprintf("error: %v", err)
+				failed[language] = true
+			default:
+				results[language] = done
+			}
+			resultsMu.Unlock()
+
+			prog.update(func() {
+				if errors.Is(err, ErrSkipped) {
+					prog.CurrentStep = "skipped"
+					prog.enterPhase(PhaseSkipped)
+				} else if err != nil {
+					prog.CurrentStep = "error"
+					prog.enterPhase(PhaseFailed)
+					prog.ErrorMessage = err.Error()
+					prog.Hint = errs.Hint(err)
+					prog.Output = output()
+				} else {
+					prog.CurrentStep = "complete"
+					prog.enterPhase(PhaseDone)
+					prog.Progress = 1.0
+				}
+			})
+		}(lang, choice, trackers[lang])
+	}
+
+	wg.Wait()
+	return results
+}
+
+// record adds a finished install to the installed-items database, so later runs know decor manages it,
+// and what it needs to decor's environment scripts. Failing to record is noted rather than failing an
+// install that worked.
+func record(language string, progress *LanguageProgress) {
+	version := checkLanguageInstallation(language).Version
+	method := installMethod(language)
+	progress.mu.Lock()
+	sources := progress.sources
+	progress.mu.Unlock()
+	err := installed.Add(language, method, version)
+	if err == nil {
+		err = installed.SetOrigin(language, packagesOf(language, method), sources)
+	}
+	if err != nil {
+		progress.AddNote(fmt.Sprintf("couldn't record %s as installed by decor: %v", language, err))
+	}
+	setEnv(language, progress)
+}
+
+// forget removes an uninstalled item from the installed-items database and the environment scripts,
+// and deletes its completions, noting rather than failing if it can't
+func forget(language string, progress *LanguageProgress) {
+	if err := installed.Remove(language); err != nil {
+		progress.AddNote(fmt.Sprintf("couldn't forget %s in the installed items: %v", language, err))
+	}
+	unsetEnv(language, progress)
+	removeCompletions(language, progress)
+}
+
+// saveRun writes the run's history report. Skipped languages are left out, and failing to save is
+// only logged, since the run itself is over.
+func saveRun(started time.Time, log string, languages []string, choices map[string]string, results map[string]string, trackers map[string]*LanguageProgress) {
+	run := history.Run{Started: started, Elapsed: time.Since(started), Log: log}
+	for _, lang := range languages {
+		tracker, ok := trackers[lang]
+		if !ok {
+			continue
+		}
+		s := tracker.Snapshot()
+		run.Items = append(run.Items, history.Item{
+			Name:       lang,
+			Choice:     choices[lang],
+			Result:     results[lang],
+			Hint:       s.Hint,
+			Notes:      s.Notes,
+			Timings:    s.Timings,
+			Downloaded: s.Downloaded,
+		})
+	}
+	if len(run.Items) == 0 {
+		return
+	}
+	if err := history.Save(run); err != nil {
+		runner.Logf("couldn't save the run's history: %v", err)
+	}
+}
+
+// installMethod names how language was installed on this machine, for the installed-items database
+func installMethod(language string) string {
+	if isBSD() && coreLanguage(language) {
+		name, _, _ := pkgmgr.BSDCommand(runtime.GOOS, "install", nil)
+		return name
+	}
+	switch strings.ToLower(language) {
+	case "go", "java":
+		return "tarball"
+	case "rust":
+		return "rustup"
+	case "homebrew":
+		return "pkg"
+	case "python":
+		if usesBrew() {
+			return "brew"
+		}
+		return "apt"
+	case "c++":
+		if runtime.GOOS == "darwin" {
+			return "xcode-select"
+		}
+		return "apt"
+	}
+	if item, ok := catalog.Find(language); ok {
+		return itemStrategy(item)
+	}
+	return ""
+}
+
+// waitForPrerequisites blocks until the item's prerequisites in this run have finished, failing if any of them failed
+func waitForPrerequisites(ctx context.Context, language string, progress *LanguageProgress, finished map[string]chan struct{}, failed func(string) bool) error {
+	for _, required := range prerequisites(language) {
+		done, ok := finished[required]
+		if !ok {
+			continue
+		}
+		progress.Set(0, fmt.Sprintf("Waiting for %s...", required))
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if failed(required) {
+			return fmt.Errorf("%s wasn't installed because %s wasn't", language, required)
+		}
+	}
+	return nil
+}
+
+// installLanguageWithProgress downloads and installs a language with progress tracking
+func installLanguageWithProgress(ctx context.Context, language string, progress *LanguageProgress) error {
+	if err := checkPlatform(language); err != nil {
+		return err
+	}
+	if err := checkMethod(language, progress); err != nil {
+		return err
+	}
+	if isBSD() && coreLanguage(language) {
+		return installBSD(ctx, language, false, progress)
+	}
+	switch strings.ToLower(language) {
+	case "go":
+		return installGoWithProgress(ctx, progress)
+	case "python":
+		return installPythonWithProgress(ctx, progress)
+	case "rust":
+		return installRustWithProgress(ctx, progress)
+	case "c++":
+		return installCppWithProgress(ctx, progress)
+	case "java":
+		return installJavaWithProgress(ctx, progress)
+	case "homebrew":
+		return installHomebrew(ctx, progress)
+	}
+	if item, ok := catalog.Find(language); ok {
+		return installItemWithProgress(ctx, item, false, progress)
+	}
+	return fmt.Errorf("unsupported language: %s", language)
+}
+
+// updateLanguageWithProgress updates an existing language installation with progress
+func updateLanguageWithProgress(ctx context.Context, language string, progress *LanguageProgress) error {
+	if err := checkPlatform(language); err != nil {
+		return err
+	}
+	if err := checkMethod(language, progress); err != nil {
+		return err
+	}
+	if isBSD() && coreLanguage(language) {
+		return installBSD(ctx, language, true, progress)
+	}
+	switch strings.ToLower(language) {
+	case "go":
+		return updateGoWithProgress(ctx, progress)
+	case "python":
+		return updatePythonWithProgress(ctx, progress)
+	case "rust":
+		return updateRustWithProgress(ctx, progress)
+	case "c++":
+		return updateCppWithProgress(ctx, progress)
+	case "java":
+		return updateJavaWithProgress(ctx, progress)
+	case "homebrew":
+		return updateHomebrew(ctx, progress)
+	}
+	if item, ok := catalog.Find(language); ok {
+		return installItemWithProgress(ctx, item, true, progress)
+	}
+	return fmt.Errorf("unsupported language: %s", language)
+}
+
+// checkPlatform rejects platforms the installers have no strategy for
+func checkPlatform(language string) error {
+	if runtime.GOOS == "windows" {
+		return errs.New(errs.ErrUnsupportedPlatform, "installing "+language, fmt.Errorf("%s/%s", runtime.GOOS, runtime.GOARCH))
+	}
+	return nil
+}
+
+// runCommand runs a command for an installer, discarding its output
+func runCommand(ctx context.Context, spec runner.Spec) error {
+	_, err := commands.Run(ctx, spec)
+	return err
+}
+
+// lockWaitTimeout bounds how long an installer waits for another process to release the package manager
+const lockWaitTimeout = 10 * time.Minute
+
+// runPackageManager runs a brew, apt-get, pkg or pkg_add command once no other item is installing, showing a waiting
+// state while another process holds its lock
+func runPackageManager(ctx context.Context, progress *LanguageProgress, name string, args ...string) error {
+	manager, _ := pkgmgr.ForCommand(name)
+
+	var previousStep string
+	onWait := func(holder string) {
+		progress.update(func() {
+			if !progress.Waiting {
+				previousStep = progress.CurrentStep
+			}
+			progress.Waiting = true
+			progress.CurrentStep = fmt.Sprintf("Waiting for package manager (%s)...", holder)
+		})
+	}
+
+	// brew refuses to run as root, the others always need it, and mustn't stop to ask anything
+	var output []byte
+	attempt := func(ctx context.Context, args []string) error {
+		return pkgmgr.Run(ctx, manager, lockWaitTimeout, func() ([]byte, error) {
+			// The package manager's own progress moves the item's on, from wherever it's got to
+			tracker := pkgmgr.NewTracker(manager)
+			snapshot := progress.Snapshot()
+			base := min(snapshot.Progress, 0.95)
+			spec := runner.Spec{Name: lookPath(name), Args: args, Lines: func(line string) bool {
+				if tracker.Line(line) {
+					step := tracker.Step
+					if step == "" {
+						step = snapshot.CurrentStep
+					}
+					progress.Set(base+tracker.Fraction*(0.95-base), step)
+				}
+				return !pkgmgr.AptStatus(line)
+			}}
+			switch manager {
+			case pkgmgr.Apt:
+				spec.Args = append(append(append([]string{}, pkgmgr.AptOptions...), pkgmgr.AptStatusOptions...), args...)
+				spec.Env = pkgmgr.AptEnv
+				spec.Root = true
+			case pkgmgr.Pkg:
+				spec.Env = pkgmgr.PkgEnv
+				spec.Root = true
+			case pkgmgr.PkgAdd:
+				spec.Root = true
+			}
+			var err error
+			output, err = commands.Run(ctx, spec)
+			return output, err
+		}, onWait)
+	}
+	err := serialize(ctx, progress, func(ctx context.Context) error {
+		if manager == pkgmgr.Apt && pkgmgr.NeedsIndexes(args) {
+			if err := refreshAptIndexes(ctx, progress, attempt); err != nil {
+				return err
+			}
+		}
+		return attempt(ctx, args)
+	})
+	if err != nil && manager == pkgmgr.Apt {
+		if explained := pkgmgr.ExplainApt(output); explained != "" {
+			err = fmt.Errorf("%s (%w)", explained, err)
+		}
+	}
+
+	progress.update(func() {
+		if progress.Waiting {
+			progress.Waiting = false
+			progress.CurrentStep = previousStep
+		}
+	})
+	return err
+}
@@ -0,0 +1,25 @@
+package installer
+
+import "testing"
+
+func TestDefaultChoice(t *testing.T) {
+	tests := []struct {
+		name   string
+		status InstallationStatus
+		want   string
+	}{
+		{"missing", InstallationStatus{}, "install"},
+		{"timed out", InstallationStatus{TimedOut: true}, "skip"},
+		{"managed and current", InstallationStatus{Installed: true, Managed: true, Version: "1.0", LatestVersion: "1.0"}, "skip"},
+		{"managed and outdated", InstallationStatus{Installed: true, Managed: true, Version: "1.0", LatestVersion: "1.1"}, "update"},
+		{"found on system and outdated", InstallationStatus{Installed: true, Version: "1.0", LatestVersion: "1.1"}, "skip"},
+		{"latest unknown", InstallationStatus{Installed: true, Managed: true, Version: "1.0"}, "skip"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DefaultChoice(&tt.status); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,595 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"decor/catalog"
+	"decor/config"
+	"decor/errs"
+	"decor/gpu"
+	"decor/jdk"
+	"decor/pkgmgr"
+	"decor/platform"
+	"decor/runner"
+	"decor/services"
+	"decor/symbols"
+	"decor/wsl"
+)
+
+// extraBinDirs are where user-level installers put programs before the shell's PATH picks them up
+var extraBinDirs = []string{
+	"~/.local/bin", "~/.cargo/bin", "~/go/bin", "~/miniforge3/bin", "~/.wasmtime/bin", "~/.wasmer/bin", "~/vcpkg",
+	"~/.sdkman/candidates/gradle/current/bin", "~/.sdkman/candidates/maven/current/bin",
+}
+
+// brewBinDirs are where Homebrew puts programs, wherever it's installed, or nothing without Homebrew
+func brewBinDirs() []string {
+	prefix := pkgmgr.BrewPrefix()
+	if prefix == "" {
+		return nil
+	}
+	// Homebrew's LLVM is keg-only, so it isn't linked into bin
+	return []string{filepath.Join(prefix, "bin"), filepath.Join(prefix, "sbin"), filepath.Join(prefix, "opt", "llvm", "bin")}
+}
+
+// lookPath finds a program on PATH or in extraBinDirs, returning name unchanged if it's in neither.
+// A path starting with ~ is only expanded, and in WSL only .exe programs are looked for on Windows drives.
+func lookPath(name string) string {
+	if strings.HasPrefix(name, "~/") {
+		return config.ExpandHome(name)
+	}
+	// In WSL, Windows programs on PATH aren't the Linux tools decor installs and checks
+	find := exec.LookPath
+	if wsl.Detected() && !strings.HasSuffix(name, ".exe") {
+		find = wsl.LookPath
+	}
+	if path, err := find(name); err == nil {
+		return path
+	}
+	dirs := append([]string{filepath.Join(jdk.Home(filepath.Join(javaRoot(), "current")), "bin")}, extraBinDirs...)
+	dirs = append(dirs, brewBinDirs()...)
+	for _, dir := range dirs {
+		path := filepath.Join(config.ExpandHome(dir), name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return name
+}
+
+// Offered returns the names of the items worth showing on this machine, leaving out GPU toolkits without a
+// matching GPU and items for other operating systems
+func Offered() []string {
+	vendors := gpu.Vendors()
+	var names []string
+	for _, item := range catalog.Items {
+		if (item.Hardware == "" || vendors[item.Hardware]) && (item.OS == "" || item.OS == runtime.GOOS) {
+			names = append(names, item.Name)
+		}
+	}
+	return names
+}
+
+// OfferedPresets returns the presets whose items are all offered on this machine
+func OfferedPresets() []catalog.Preset {
+	offered := make(map[string]bool)
+	for _, name := range Offered() {
+		offered[name] = true
+	}
+	var presets []catalog.Preset
+	for _, preset := range catalog.Presets {
+		ok := true
+		for _, name := range PresetItems(preset) {
+			if !offered[name] {
+				ok = false
+			}
+		}
+		if ok {
+			presets = append(presets, preset)
+		}
+	}
+	return presets
+}
+
+// groupChoice returns the item the user's settings pick from a group of alternatives
+func groupChoice(group string) string {
+	switch group {
+	case "python-manager":
+		if settings.PythonManager == "conda" {
+			return "Miniforge"
+		}
+		return "uv"
+	case "local-cluster":
+		if settings.LocalCluster == "minikube" {
+			return "minikube"
+		}
+		return "kind"
+	case "cpp-packages":
+		if settings.CppPackages == "conan" {
+			return "Conan"
+		}
+		return "vcpkg"
+	}
+	return ""
+}
+
+// Alternative returns the item the settings pick in place of name, which is name itself unless it's one
+// of a group of alternatives
+func Alternative(name string) string {
+	if item, ok := catalog.Find(name); ok && item.Group != "" {
+		if choice := groupChoice(item.Group); choice != "" {
+			return choice
+		}
+	}
+	return name
+}
+
+// PresetItems returns the preset's items, swapping each alternative for the one the settings pick
+func PresetItems(preset catalog.Preset) []string {
+	items := make([]string, 0, len(preset.Items))
+	for _, name := range preset.Items {
+		items = append(items, Alternative(name))
+	}
+	return items
+}
+
+// itemStrategy picks how an item is installed on this platform: "winget", "brew", "apt", "deb", "binary", "release",
+// "archive", "go", "pipx", "npm", "cargo", "script", "command", or "" if none fits
+func itemStrategy(item catalog.Item) string {
+	if item.Winget != "" && settings.WSLWinget && wsl.Detected() {
+		return "winget"
+	}
+	if usesBrew() && len(item.Brew) > 0 {
+		// Without Homebrew, a direct download beats installing Homebrew first
+		if strategy := directStrategy(item); !brewInstalled() && strategy != "" {
+			return strategy
+		}
+		return "brew"
+	}
+	return directStrategy(item)
+}
+
+// directStrategy picks how an item is installed without Homebrew
+func directStrategy(item catalog.Item) string {
+	switch {
+	case usesApt() && len(item.Apt) > 0:
+		return "apt"
+	case usesApt() && platformURL(item.Debs) != "":
+		return "deb"
+	case platformURL(item.Binaries) != "" && len(item.Version) > 0:
+		return "binary"
+	case item.Repo != "" && item.Release != "" && len(item.Version) > 0:
+		return "release"
+	case item.Archive.URL != "":
+		return "archive"
+	case item.GoInstall != "" && len(item.Version) > 0:
+		return "go"
+	case len(item.Pipx) > 0:
+		return "pipx"
+	case len(item.Npm) > 0:
+		return "npm"
+	case len(item.Cargo) > 0:
+		return "cargo"
+	case platformURL(item.Scripts) != "":
+		return "script"
+	case len(item.Command) > 0:
+		return "command"
+	}
+	return ""
+}
+
+// platformURL picks this platform's URL from a "GOOS/GOARCH" keyed map, falling back to "*". It's the
+// native architecture's even when decor runs under Rosetta.
+func platformURL(urls map[string]string) string {
+	p := platform.Current()
+	if url, ok := urls[p.OS+"/"+p.GOARCH()]; ok {
+		return url
+	}
+	return urls["*"]
+}
+
+// itemVersionCommand returns the command that detects an item: its version command, or a package manager query
+func itemVersionCommand(item catalog.Item) (string, []string, bool) {
+	switch {
+	case itemStrategy(item) == "winget":
+		return lookPath("winget.exe"), []string{"list", "--exact", "--id", item.Winget}, true
+	case len(item.Version) > 0:
+		return lookPath(item.Version[0]), item.Version[1:], true
+	case usesBrew() && len(item.Brew) > 0:
+		args := []string{"list", "--versions"}
+		if item.BrewCask {
+			args = append(args, "--cask")
+		}
+		return "brew", append(args, item.Brew...), true
+	case usesApt() && len(item.Apt) > 0:
+		return "dpkg-query", append([]string{"-W", "-f=${Package} ${Version}\n"}, item.Apt...), true
+	}
+	return "", nil, false
+}
+
+// installItemWithProgress installs or updates an item with the first strategy that fits, then verifies it
+func installItemWithProgress(ctx context.Context, item catalog.Item, update bool, progress *LanguageProgress) error {
+	op := "installing " + item.Name
+	if update {
+		op = "updating " + item.Name
+	}
+
+	var err error
+	switch itemStrategy(item) {
+	case "winget":
+		// What's set up after installing, like services and starter configs, belongs to the Linux side
+		progress.Set(0.3, fmt.Sprintf("Running winget on Windows for %s...", item.Name))
+		return serialize(ctx, progress, func(ctx context.Context) error {
+			return runCommand(ctx, runner.Spec{Op: op, Name: lookPath("winget.exe"), Args: wingetArgs(item, update)})
+		})
+	case "brew":
+		progress.Set(0.3, fmt.Sprintf("Running brew for %s...", item.Name))
+		args := []string{"install"}
+		if update {
+			args = []string{"upgrade"}
+		}
+		if item.BrewCask {
+			args = append(args, "--cask")
+		}
+		err = runPackageManager(ctx, progress, "brew", append(args, item.Brew...)...)
+		if advice := brewPathAdvice(); err == nil && advice != "" && !dryRun {
+			progress.AddNote(advice)
+		}
+	case "apt":
+		progress.Set(0.3, fmt.Sprintf("Running apt-get for %s...", item.Name))
+		args := []string{"install", "-y"}
+		if update {
+			args = append(args, "--only-upgrade")
+		}
+		err = runPackageManager(ctx, progress, "apt-get", append(args, item.Apt...)...)
+	case "pipx":
+		progress.Set(0.3, fmt.Sprintf("Running pipx for %s...", item.Name))
+		args := append([]string{"install"}, item.Pipx...)
+		if update {
+			args = []string{"upgrade", item.Pipx[0]}
+		}
+		err = serialize(ctx, progress, func(ctx context.Context) error {
+			return runCommand(ctx, runner.Spec{Op: op, Name: lookPath("pipx"), Args: args})
+		})
+	case "npm":
+		progress.Set(0.3, fmt.Sprintf("Running npm for %s...", item.Name))
+		err = serialize(ctx, progress, func(ctx context.Context) error {
+			return runCommand(ctx, runner.Spec{Op: op, Name: lookPath("npm"), Args: npmArgs(item, update), Env: npmEnv()})
+		})
+	case "cargo":
+		progress.Set(0.3, fmt.Sprintf("Building %s with cargo...", item.Name))
+		err = serialize(ctx, progress, func(ctx context.Context) error {
+			return runCommand(ctx, runner.Spec{Op: op, Name: lookPath("cargo"), Args: cargoArgs(item)})
+		})
+	case "script":
+		progress.Set(0.2, fmt.Sprintf("Downloading %s installer...", item.Name))
+		progress.SetPhase(PhaseDownloading)
+		url := platformURL(item.Scripts)
+		if policyErr := requireChecksum(url, progress); policyErr != nil {
+			return policyErr
+		}
+		script, fetchErr := fetch(ctx, progress, url, "")
+		if fetchErr != nil {
+			return fetchErr
+		}
+		defer os.Remove(script)
+
+		progress.Set(0.5, fmt.Sprintf("Running %s installer...", item.Name))
+		args := append([]string{script}, expandArgs(item.Args)...)
+		var env []string
+		for _, pair := range item.Env {
+			key, value, _ := strings.Cut(pair, "=")
+			env = append(env, key+"="+config.ExpandHome(value))
+		}
+		shell := item.Shell
+		if shell == "" {
+			shell = "sh"
+		}
+		err = serialize(ctx, progress, func(ctx context.Context) error {
+			return runCommand(ctx, runner.Spec{Op: op, Name: shell, Args: args, Env: env})
+		})
+	case "deb":
+		progress.Set(0.2, fmt.Sprintf("Downloading %s package...", item.Name))
+		progress.SetPhase(PhaseDownloading)
+		url := platformURL(item.Debs)
+		if policyErr := requireChecksum(url, progress); policyErr != nil {
+			return policyErr
+		}
+		deb, fetchErr := fetch(ctx, progress, url, "")
+		if fetchErr != nil {
+			return fetchErr
+		}
+		defer os.Remove(deb)
+
+		progress.Set(0.5, fmt.Sprintf("Installing %s package...", item.Name))
+		err = runPackageManager(ctx, progress, "apt-get", "install", "-y", deb)
+	case "binary":
+		progress.Set(0.2, fmt.Sprintf("Downloading %s...", item.Name))
+		progress.SetPhase(PhaseDownloading)
+		url := platformURL(item.Binaries)
+		if policyErr := requireChecksum(url, progress); policyErr != nil {
+			return policyErr
+		}
+		binary, fetchErr := fetch(ctx, progress, url, "")
+		if fetchErr != nil {
+			return fetchErr
+		}
+		defer os.Remove(binary)
+
+		progress.Set(0.6, fmt.Sprintf("Installing %s...", item.Name))
+		dir := config.ExpandHome("~/.local/bin")
+		if !dryRun {
+			if mkErr := os.MkdirAll(dir, 0o755); mkErr != nil {
+				return errs.Classify(op, mkErr)
+			}
+		}
+		err = serialize(ctx, progress, func(ctx context.Context) error {
+			program := filepath.Join(dir, item.Version[0])
+			if err := runCommand(ctx, runner.Spec{Op: op, Name: "install", Args: []string{"-m", "755", binary, program}}); err != nil {
+				return err
+			}
+			return prepareProgram(ctx, program, false)
+		})
+	case "release":
+		err = installRelease(ctx, op, item, progress)
+	case "archive":
+		err = installArchive(ctx, item.Archive, "", progress, nil)
+	case "go":
+		err = goInstall(ctx, op, item, progress)
+	case "command":
+		progress.Set(0.3, fmt.Sprintf("Running %s...", item.Command[0]))
+		err = serialize(ctx, progress, func(ctx context.Context) error {
+			return runCommand(ctx, runner.Spec{Op: op, Name: lookPath(item.Command[0]), Args: expandArgs(item.Command[1:])})
+		})
+	default:
+		if item.Manual != "" {
+			return errs.New(errs.ErrUnsupportedPlatform, op, fmt.Errorf("decor can't do this for you: %s", item.Manual))
+		}
+		return errs.New(errs.ErrUnsupportedPlatform, op, fmt.Errorf("no install strategy for %s", platform.Current()))
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, command := range item.Configure {
+		progress.Set(0.8, fmt.Sprintf("Configuring %s...", item.Name))
+		spec := runner.Spec{Op: "configuring " + item.Name, Name: lookPath(command[0]), Args: expandArgs(command[1:])}
+		if err := runCommand(ctx, spec); err != nil {
+			return err
+		}
+	}
+
+	if settings.StarterConfigs {
+		writeStarterConfigs(item, progress)
+	}
+	if name := item.Services[runtime.GOOS]; name != "" {
+		startService(ctx, item, name, progress)
+	}
+	if runtime.GOOS == "linux" && item.UserGroup != "" {
+		joinGroup(ctx, item, progress)
+	}
+
+	if dryRun {
+		return nil
+	}
+	progress.SetPhase(PhaseVerifying)
+	switch itemStrategy(item) {
+	case "binary", "release", "archive":
+		if err := checkGatekeeper(ctx, item); err != nil {
+			return err
+		}
+	}
+	if len(item.Verify) > 0 {
+		progress.Set(0.9, "Verifying installation...")
+		verify := runner.Spec{
+			Op:       "verifying " + item.Name,
+			Name:     lookPath(item.Verify[0]),
+			Args:     item.Verify[1:],
+			Timeout:  settings.DetectTimeout,
+			ReadOnly: true,
+		}
+		if err := runCommand(ctx, verify); err != nil {
+			return fmt.Errorf("%s was installed but `%s` failed: %w", item.Name, strings.Join(item.Verify, " "), err)
+		}
+	}
+	if len(item.SmokeTest) > 0 {
+		smokeTest(ctx, item, progress)
+	}
+	if item.UsesJDK {
+		checkJDK(ctx, item, progress)
+	}
+	if item.ReportPath && len(item.Version) > 0 {
+		reportPath(item.Version[0], progress)
+	}
+	return nil
+}
+
+// expandArgs expands a leading ~ in each argument
+func expandArgs(args []string) []string {
+	expanded := make([]string, len(args))
+	for i, arg := range args {
+		expanded[i] = config.ExpandHome(arg)
+	}
+	return expanded
+}
+
+// reportPath notes where a program ended up, and whether PATH finds it there
+func reportPath(program string, progress *LanguageProgress) {
+	path := lookPath(program)
+	if !filepath.IsAbs(path) {
+		progress.AddNote(fmt.Sprintf("couldn't find %s on PATH or in %s", program, strings.Join(extraBinDirs, ", ")))
+		return
+	}
+	if _, err := exec.LookPath(program); err != nil {
+		if advice := brewPathAdvice(); advice != "" && strings.HasPrefix(path, pkgmgr.BrewPrefix()+string(filepath.Separator)) {
+			progress.AddNote(fmt.Sprintf("installed at %s; %s so editors find it", path, advice))
+			return
+		}
+		progress.AddNote(fmt.Sprintf("installed at %s; add %s to PATH so editors find it", path, filepath.Dir(path)))
+		return
+	}
+	progress.AddNote("installed at " + path)
+}
+
+// LoginCommand returns the item's login command to run on the terminal, or nil if it has none
+func LoginCommand(name string) *runner.Attached {
+	item, ok := catalog.Find(name)
+	if !ok || len(item.Login) == 0 {
+		return nil
+	}
+	return commands.Attach(runner.Spec{
+		Op:   "logging in to " + item.Name,
+		Name: lookPath(item.Login[0]),
+		Args: item.Login[1:],
+	})
+}
+
+// LoggedIn reports whether the user is logged in to the item's service
+func LoggedIn(name string) bool {
+	item, ok := catalog.Find(name)
+	if !ok || len(item.LoginStatus) == 0 {
+		return false
+	}
+	spec := runner.Spec{
+		Op:       "checking the " + item.Name + " login",
+		Name:     lookPath(item.LoginStatus[0]),
+		Args:     item.LoginStatus[1:],
+		Timeout:  settings.DetectTimeout,
+		ReadOnly: true,
+	}
+	return runCommand(context.Background(), spec) == nil
+}
+
+// writeStarterConfigs writes the item's starter config files, leaving any the user already has alone
+func writeStarterConfigs(item catalog.Item, progress *LanguageProgress) {
+	for dest, file := range item.Starter {
+		path := config.ExpandHome(dest)
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		if dryRun {
+			runner.Logf("dry run: writing a starter config to %s", path)
+			continue
+		}
+
+		data, err := catalog.StarterConfig(file)
+		if err == nil {
+			if err = os.MkdirAll(filepath.Dir(path), 0o755); err == nil {
+				err = os.WriteFile(path, data, 0o644)
+			}
+		}
+		if err != nil {
+			progress.AddNote(fmt.Sprintf("couldn't write a starter config to %s: %v", dest, err))
+			continue
+		}
+		runner.Logf("wrote a starter config to %s", path)
+		progress.AddNote("wrote a starter config to " + dest)
+	}
+}
+
+// healthCheckAttempts and healthCheckInterval give a freshly started service time to accept connections
+const (
+	healthCheckAttempts = 5
+	healthCheckInterval = 2 * time.Second
+)
+
+// startService enables the item's service and waits for it to pass its health check, noting the outcome for
+// the summary. Services often fail to start for reasons outside decor, like WSL without systemd, so this
+// doesn't fail the install.
+func startService(ctx context.Context, item catalog.Item, name string, progress *LanguageProgress) {
+	progress.Set(0.85, fmt.Sprintf("Starting %s...", name))
+	if err := services.Enable(ctx, commands, name); err != nil {
+		progress.AddNote(fmt.Sprintf("couldn't start %s: %v", name, err))
+		return
+	}
+	if dryRun {
+		return
+	}
+	if len(item.HealthCheck) == 0 {
+		progress.AddNote(fmt.Sprintf("%s is enabled and running", name))
+		return
+	}
+
+	check := append([]string{lookPath(item.HealthCheck[0])}, item.HealthCheck[1:]...)
+	var err error
+	for attempt := 0; attempt < healthCheckAttempts; attempt++ {
+		if err = services.Healthy(ctx, commands, check); err == nil {
+			progress.AddNote(fmt.Sprintf("%s is enabled, running and accepting connections", name))
+			return
+		}
+		pause(ctx, healthCheckInterval)
+	}
+	progress.AddNote(fmt.Sprintf("%s is enabled but `%s` still fails: %v", name, strings.Join(item.HealthCheck, " "), err))
+}
+
+// joinGroup adds the user to the item's group so it works without sudo, noting that a new login is needed
+func joinGroup(ctx context.Context, item catalog.Item, progress *LanguageProgress) {
+	username, member, err := inGroup(item.UserGroup)
+	if err != nil {
+		progress.AddNote(fmt.Sprintf("couldn't check the %s group: %v", item.UserGroup, err))
+		return
+	}
+	if member {
+		return
+	}
+	progress.Set(0.88, fmt.Sprintf("Adding %s to the %s group...", username, item.UserGroup))
+	spec := runner.Spec{Op: "adding " + username + " to " + item.UserGroup, Name: "usermod", Args: []string{"-aG", item.UserGroup, username}, Root: true}
+	if err := runCommand(ctx, spec); err != nil {
+		progress.AddNote(fmt.Sprintf("couldn't add %s to the %s group: %v", username, item.UserGroup, err))
+		return
+	}
+	if dryRun {
+		return
+	}
+	progress.AddNote(fmt.Sprintf("%s added %s to the %s group: log out and back in (or run newgrp %s) to use %s without sudo",
+		symbols.Warning, username, item.UserGroup, item.UserGroup, item.Name))
+}
+
+// inGroup reports whether the user decor runs for, the one behind sudo if any, is in the named group
+func inGroup(group string) (string, bool, error) {
+	u, err := user.Current()
+	if err != nil {
+		return "", false, err
+	}
+	if sudoUser := os.Getenv("SUDO_USER"); sudoUser != "" && u.Uid == "0" {
+		if u, err = user.Lookup(sudoUser); err != nil {
+			return "", false, err
+		}
+	}
+
+	g, err := user.LookupGroup(group)
+	if _, unknown := err.(user.UnknownGroupError); unknown {
+		// Not created yet, as on a dry run before the package that adds it
+		return u.Username, false, nil
+	}
+	if err != nil {
+		return u.Username, false, err
+	}
+	ids, err := u.GroupIds()
+	if err != nil {
+		return u.Username, false, err
+	}
+	return u.Username, slices.Contains(ids, g.Gid), nil
+}
+
+// smokeTest runs the item's end-to-end check and notes the outcome for the summary. A failure often
+// means something else isn't ready, like the Docker daemon, so it doesn't fail the install.
+func smokeTest(ctx context.Context, item catalog.Item, progress *LanguageProgress) {
+	progress.Set(0.95, "Running smoke test...")
+	start := time.Now()
+	spec := runner.Spec{Op: "smoke testing " + item.Name, Name: lookPath(item.SmokeTest[0]), Args: item.SmokeTest[1:]}
+	if err := runCommand(ctx, spec); err != nil {
+		progress.AddNote(fmt.Sprintf("smoke test failed: %v", err))
+		return
+	}
+	progress.AddNote(fmt.Sprintf("smoke test passed in %s", time.Since(start).Round(time.Second)))
+}
@@ -0,0 +1,84 @@
+package installer
+
+import (
+	"runtime"
+	"slices"
+	"testing"
+
+	"decor/catalog"
+	"decor/config"
+)
+
+func TestAlternative(t *testing.T) {
+	original := settings
+	t.Cleanup(func() { settings = original })
+
+	tests := []struct {
+		name    string
+		setting func(*config.Config)
+		item    string
+		want    string
+	}{
+		{"default package manager", func(c *config.Config) {}, "vcpkg", "vcpkg"},
+		{"conan picked", func(c *config.Config) { c.CppPackages = "conan" }, "vcpkg", "Conan"},
+		{"conda picked", func(c *config.Config) { c.PythonManager = "conda" }, "uv", "Miniforge"},
+		{"not in a group", func(c *config.Config) { c.CppPackages = "conan" }, "CMake", "CMake"},
+		{"unknown item", func(c *config.Config) {}, "nonexistent", "nonexistent"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings = config.Default()
+			tt.setting(&settings)
+			if got := Alternative(tt.item); got != tt.want {
+				t.Errorf("Alternative(%s) = %s, want %s", tt.item, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithoutHomebrew(t *testing.T) {
+	original, originalBrew := settings, brewInstalled
+	t.Cleanup(func() { settings, brewInstalled = original, originalBrew })
+	settings = config.Default()
+	settings.PackageManager = "brew"
+	brewInstalled = func() bool { return false }
+
+	// A direct download is used instead, and only what brew alone installs waits for Homebrew. On Linux,
+	// apt installs what it has a package for.
+	direct := catalog.Item{Name: "tool", Brew: []string{"tool"}, Scripts: map[string]string{"*": "https://example.com/install.sh"}}
+	if got := itemStrategy(direct); got != "script" {
+		t.Errorf("without Homebrew, an item with an installer script is installed by %q", got)
+	}
+	if needsBrew(direct) {
+		t.Error("an item with an installer script needs Homebrew")
+	}
+	if got := prerequisites("jdtls"); !slices.Equal(got, []string{"Java", "Homebrew"}) {
+		t.Errorf("without Homebrew, jdtls requires %q", got)
+	}
+
+	brewInstalled = func() bool { return true }
+	if got := itemStrategy(direct); got != "brew" {
+		t.Errorf("with Homebrew, an item with a formula is installed by %q", got)
+	}
+	if got := prerequisites("jdtls"); !slices.Equal(got, []string{"Java"}) {
+		t.Errorf("with Homebrew, jdtls requires %q", got)
+	}
+}
+
+func TestUsesBrew(t *testing.T) {
+	original := settings
+	t.Cleanup(func() { settings = original })
+	settings = config.Default()
+
+	t.Setenv("HOMEBREW_PREFIX", "/home/linuxbrew/.linuxbrew")
+	if runtime.GOOS != "windows" && !usesBrew() {
+		t.Error("brew isn't used with Homebrew installed")
+	}
+	if runtime.GOOS == "linux" && !usesApt() {
+		t.Error("apt isn't used alongside Linuxbrew")
+	}
+	settings.PackageManager = "apt"
+	if usesBrew() {
+		t.Error("brew is used when the settings pick apt")
+	}
+}
@@ -0,0 +1,97 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"decor/catalog"
+	"decor/jdk"
+	"decor/runner"
+	"decor/symbols"
+)
+
+// toolJavaVersion finds the Java version a build tool reports running on in its version output:
+// Maven's "Java version: 21.0.5, vendor: ..." or Gradle's "Launcher JVM:  21.0.5 (...)", "JVM: 17.0.2 (...)"
+func toolJavaVersion(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		for _, prefix := range []string{"Java version:", "Launcher JVM:", "JVM:"} {
+			if rest, ok := strings.CutPrefix(line, prefix); ok {
+				if fields := strings.FieldsFunc(rest, func(r rune) bool { return r == ',' || r == ' ' }); len(fields) > 0 {
+					return fields[0]
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// javaMajor returns the feature release of a Java version, e.g. 21 for 21.0.5 and 8 for 1.8.0_392
+func javaMajor(version string) int {
+	version = strings.TrimPrefix(version, "1.")
+	end := strings.IndexFunc(version, func(r rune) bool { return r < '0' || r > '9' })
+	if end >= 0 {
+		version = version[:end]
+	}
+	major, _ := strconv.Atoi(version)
+	return major
+}
+
+// decorJDK returns JAVA_HOME and the feature release of the JDK decor installed, or "" and 0 if it
+// hasn't installed one
+func decorJDK() (string, int) {
+	current := filepath.Join(javaRoot(), "current")
+	target, err := filepath.EvalSymlinks(current)
+	if err != nil {
+		return "", 0
+	}
+	// Directories are named vendor-version, e.g. temurin-21
+	name := filepath.Base(target)
+	major, _ := strconv.Atoi(name[strings.LastIndex(name, "-")+1:])
+	return jdk.Home(target), major
+}
+
+// sameDir reports whether two paths are the same directory once symlinks are resolved
+func sameDir(a, b string) bool {
+	resolvedA, errA := filepath.EvalSymlinks(a)
+	resolvedB, errB := filepath.EvalSymlinks(b)
+	if errA != nil || errB != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return resolvedA == resolvedB
+}
+
+// checkJDK notes which Java a build tool runs on, flagging a tool that doesn't use decor's JDK and a
+// JAVA_HOME pointing somewhere else
+func checkJDK(ctx context.Context, item catalog.Item, progress *LanguageProgress) {
+	output, err := commands.Run(ctx, runner.Spec{
+		Op:       "checking which Java " + item.Name + " uses",
+		Name:     lookPath(item.Version[0]),
+		Args:     item.Version[1:],
+		Timeout:  settings.DetectTimeout,
+		ReadOnly: true,
+	})
+	version := toolJavaVersion(string(output))
+	if err != nil || version == "" {
+		progress.AddNote(fmt.Sprintf("couldn't tell which Java %s uses from `%s`", item.Name, strings.Join(item.Version, " ")))
+		return
+	}
+
+	home, major := decorJDK()
+	javaHome := os.Getenv("JAVA_HOME")
+	switch {
+	case major == 0:
+		progress.AddNote(fmt.Sprintf("%s runs on Java %s", item.Name, version))
+	case javaMajor(version) != major:
+		progress.AddNote(fmt.Sprintf("%s %s runs on Java %s, not the Java %d decor installed: set JAVA_HOME=%s", symbols.Warning, item.Name, version, major, home))
+	default:
+		progress.AddNote(fmt.Sprintf("%s runs on decor's Java %s", item.Name, version))
+	}
+	if major != 0 && javaHome != "" && !sameDir(javaHome, home) {
+		progress.AddNote(fmt.Sprintf("%s JAVA_HOME is %s, not decor's JDK at %s; build tools follow JAVA_HOME", symbols.Warning, javaHome, home))
+	}
+}
@@ -0,0 +1,33 @@
+package installer
+
+import "testing"
+
+func TestToolJavaVersion(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{"maven", "Apache Maven 3.9.9\nMaven home: /opt/maven\nJava version: 21.0.5, vendor: Eclipse Adoptium, runtime: /usr/local/java/temurin-21\n", "21.0.5"},
+		{"gradle 8", "Gradle 8.10.2\n\nKotlin:        1.9.24\nLauncher JVM:  21.0.5 (Eclipse Adoptium 21.0.5+11-LTS)\nDaemon JVM:    /usr/local/java/temurin-21\n", "21.0.5"},
+		{"gradle 7", "Gradle 7.6\n\nJVM:          17.0.2 (Oracle Corporation 17.0.2+8-86)\nOS:           Linux\n", "17.0.2"},
+		{"java 8", "Java version: 1.8.0_392, vendor: Temurin\n", "1.8.0_392"},
+		{"nothing", "command not found\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toolJavaVersion(tt.output); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJavaMajor(t *testing.T) {
+	tests := map[string]int{"21.0.5": 21, "17": 17, "1.8.0_392": 8, "23-ea": 23, "": 0}
+	for version, want := range tests {
+		if got := javaMajor(version); got != want {
+			t.Errorf("javaMajor(%q) = %d, want %d", version, got, want)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"decor/catalog"
+	"decor/download"
+	"decor/errs"
+	"decor/extract"
+	"decor/jdk"
+	"decor/platform"
+	"decor/runner"
+)
+
+// fetch downloads url into the cache, checking it against the SHA-256 published at checksumURL
+// unless that's empty, and counts its size towards the item's downloads. A file config.toml pins is
+// downloaded from the pin's URL and checked against its checksum instead. A dry run only logs the
+// download and returns a placeholder path.
+func fetch(ctx context.Context, progress *LanguageProgress, url, checksumURL string) (string, error) {
+	// A pin stands in for upstream's file and its published checksum, which needn't be reachable
+	pin, pinned, err := pinFor(progress.Language, url)
+	if err != nil {
+		return "", err
+	}
+	if pinned {
+		runner.Logf("%s is pinned to %s", url, pin.URL)
+		url, checksumURL = pin.URL, ""
+	}
+	if err := checkSource(url, progress); err != nil {
+		return "", err
+	}
+	if dryRun {
+		runner.Logf("dry run: downloading %s", url)
+		dir, err := download.Dir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, path.Base(url)), nil
+	}
+
+	var checksum string
+	if checksumURL != "" {
+		if checksum, err = download.Text(ctx, checksumURL); err != nil {
+			return "", err
+		}
+	}
+	file, err := download.Fetch(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case pinned:
+		err = verifyPin(file, pin)
+	case checksum != "":
+		err = download.VerifySHA256(file, checksum)
+	}
+	if err != nil {
+		os.Remove(file)
+		return "", err
+	}
+	if info, err := os.Stat(file); err == nil {
+		progress.addDownloaded(info.Size())
+	}
+	if sum, err := download.SHA256(file); err == nil {
+		progress.addSource(url, sum)
+	}
+	return file, nil
+}
+
+// pause waits for d, returning early if ctx is cancelled
+func pause(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+// goArchive is the Go toolchain as go.dev publishes it, unpacked to go under the install prefix
+var goArchive = catalog.Archive{
+	URL:      "https://go.dev/dl/go{version}.{os}-{arch}.tar.gz",
+	Checksum: "https://go.dev/dl/go{version}.{os}-{arch}.tar.gz.sha256",
+	Strip:    1,
+	Dir:      "go",
+	// go.dev only builds for ARMv6, which ARMv7 boards run as well
+	Arches: map[string]string{"armv6": "armv6l", "armv7": "armv6l"},
+}
+
+// Language-specific install functions with progress tracking
+func installGoWithProgress(ctx context.Context, progress *LanguageProgress) error {
+	goroot := archiveDir(goArchive)
+	return installArchive(ctx, goArchive, getLatestVersion("go"), progress, func(ctx context.Context) error {
+		// A toolchain that doesn't run is as broken as one that didn't extract
+		return runCommand(ctx, runner.Spec{
+			Op:       "verifying Go",
+			Name:     filepath.Join(goroot, "bin", "go"),
+			Args:     []string{"version"},
+			Timeout:  settings.DetectTimeout,
+			ReadOnly: true,
+		})
+	})
+}
+
+func installPythonWithProgress(ctx context.Context, progress *LanguageProgress) error {
+	progress.Set(0, "Installing Python...")
+
+	if usesBrew() {
+		return runPackageManager(ctx, progress, "brew", "install", pythonFormula())
+	}
+	return runPackageManager(ctx, progress, "apt-get", "install", "-y", "python3")
+}
+
+func installRustWithProgress(ctx context.Context, progress *LanguageProgress) error {
+	progress.Set(0, "Downloading the Rust installer...")
+
+	// rustup-init is run as a verified binary, never piped from curl into a shell
+	url, err := rustupInit()
+	if err != nil {
+		return errs.New(errs.ErrUnsupportedPlatform, "installing Rust", err)
+	}
+	progress.SetPhase(PhaseDownloading)
+	rustupInit, err := fetch(ctx, progress, url, url+".sha256")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(rustupInit)
+	if !dryRun {
+		if err := os.Chmod(rustupInit, 0o755); err != nil {
+			return err
+		}
+	}
+
+	return serialize(ctx, progress, func(ctx context.Context) error {
+		return runCommand(ctx, runner.Spec{Op: "running rustup-init", Name: rustupInit, Args: rustupInitArgs})
+	})
+}
+
+func installCppWithProgress(ctx context.Context, progress *LanguageProgress) error {
+	progress.Set(0, "Installing C++ compiler...")
+
+	if runtime.GOOS == "darwin" {
+		return serialize(ctx, progress, func(ctx context.Context) error {
+			return installCLT(ctx, progress)
+		})
+	}
+	packages, cc, cxx, err := cppToolchain(settings.CppCompiler)
+	if err != nil {
+		return err
+	}
+	err = serialize(ctx, progress, func(ctx context.Context) error {
+		if err := runPackageManager(ctx, progress, "apt-get", append([]string{"install", "-y"}, packages...)...); err != nil {
+			return err
+		}
+		return selectCompiler(ctx, cc, cxx)
+	})
+	if err != nil {
+		return err
+	}
+	if !dryRun {
+		progress.SetPhase(PhaseVerifying)
+		reportCompiler(ctx, progress)
+	}
+	return nil
+}
+
+func installJavaWithProgress(ctx context.Context, progress *LanguageProgress) error {
+	progress.Set(0, "Looking up the JDK release...")
+	progress.SetPhase(PhaseDownloading)
+	version, err := jdk.ParseVersion(ctx, settings.JavaVersion)
+	if err != nil {
+		return err
+	}
+	release, err := jdk.Resolve(ctx, settings.JavaVendor, version, platform.Current())
+	if err != nil {
+		return err
+	}
+	archive, err := fetch(ctx, progress, release.URL, release.ChecksumURL)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(archive)
+	if release.Checksum != "" && !dryRun {
+		if err := download.VerifySHA256(archive, release.Checksum); err != nil {
+			return err
+		}
+	}
+
+	// Each vendor and version gets its own directory, and current points at the one in use
+	root := javaRoot()
+	dir := jdk.Dir(root, release.Vendor, release.Version)
+	privileged := !writable(settings.Prefix())
+	err = serialize(ctx, progress, func(ctx context.Context) error {
+		err := replaceDir(ctx, dir, privileged, func() error {
+			if err := runCommand(ctx, runner.Spec{Op: "creating " + dir, Name: "mkdir", Args: []string{"-p", dir}, Root: privileged}); err != nil {
+				return err
+			}
+			if err := unpack(ctx, archive, jdkArchiveType(release.URL), dir, 1, privileged); err != nil {
+				return err
+			}
+			return unquarantine(ctx, dir, privileged)
+		})
+		if err != nil {
+			return err
+		}
+		// The JDK in use before is kept for decor rollback
+		previous := linkedVersion(root, "current")
+		if err := swapLink(ctx, dir, filepath.Join(root, "current"), privileged); err != nil {
+			return err
+		}
+		if previous == "" || previous == dir {
+			return nil
+		}
+		return swapLink(ctx, previous, filepath.Join(root, "previous"), privileged)
+	})
+	if err != nil {
+		return err
+	}
+
+	home := jdk.Home(filepath.Join(root, "current"))
+	progress.AddNote(fmt.Sprintf("installed %s %d in %s", release.Vendor, release.Version, home))
+	return nil
+}
+
+// jdkArchiveType is the kind of archive a JDK download is: what its URL ends with, or a tarball, which is
+// what the vendors' APIs serve from URLs without a suffix
+func jdkArchiveType(url string) string {
+	if kind := extract.Type(url); kind != "" {
+		return kind
+	}
+	return "tar.gz"
+}
+
+// javaRoot returns the directory JDKs are extracted to, under the install prefix
+func javaRoot() string {
+	return filepath.Join(settings.Prefix(), "java")
+}
+
+// Language-specific update functions with progress tracking
+func updateGoWithProgress(ctx context.Context, progress *LanguageProgress) error {
+	return installGoWithProgress(ctx, progress)
+}
+
+func updatePythonWithProgress(ctx context.Context, progress *LanguageProgress) error {
+	progress.Set(0, "Upgrading Python...")
+
+	if usesBrew() {
+		return runPackageManager(ctx, progress, "brew", "upgrade", pythonFormula())
+	}
+	return runPackageManager(ctx, progress, "apt-get", "upgrade", "-y", "python3")
+}
+
+func updateRustWithProgress(ctx context.Context, progress *LanguageProgress) error {
+	progress.Set(0, "Updating Rust...")
+
+	return serialize(ctx, progress, func(ctx context.Context) error {
+		return runCommand(ctx, runner.Spec{Op: "updating Rust", Name: "rustup", Args: []string{"update"}})
+	})
+}
+
+func updateCppWithProgress(ctx context.Context, progress *LanguageProgress) error {
+	progress.Set(0, "Installing updates...")
+
+	if runtime.GOOS == "darwin" {
+		return serialize(ctx, progress, func(ctx context.Context) error {
+			return runCommand(ctx, runner.Spec{Op: "running softwareupdate", Name: "softwareupdate", Args: []string{"-i", "-a"}, Root: true})
+		})
+	}
+	return runPackageManager(ctx, progress, "apt-get", "upgrade", "-y")
+}
+
+func updateJavaWithProgress(ctx context.Context, progress *LanguageProgress) error {
+	// Reinstalling fetches the newest build of the chosen vendor and version
+	return installJavaWithProgress(ctx, progress)
+}
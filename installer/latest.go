@@ -0,0 +1,160 @@
+package installer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"decor/download"
+	"decor/runner"
+	"decor/table"
+)
+
+// Where the newest stable releases of the languages decor installs from upstream are published,
+// replaced in tests
+var (
+	goReleases     = "https://go.dev/dl/?mode=json"
+	pythonReleases = "https://www.python.org/api/v2/downloads/release/?is_published=true&pre_release=false"
+	rustReleases   = "https://api.github.com/repos/rust-lang/rust/releases/latest"
+)
+
+// latestLookupTimeout bounds looking up a language's newest release, which a version check waits for
+const latestLookupTimeout = 10 * time.Second
+
+// latestReuse is how long a lookup is reused before the cache is read again, so a run's checks and
+// installs agree on the version and a failed lookup isn't waited for again each time, while a daemon
+// still notices newer releases
+const latestReuse = time.Minute
+
+// latestSources look up the newest stable release of a language, through download.Metadata so repeated
+// launches use the cached answer
+var latestSources = map[string]func(ctx context.Context) (string, time.Time, error){
+	"go":     latestGo,
+	"python": latestPython,
+	"rust":   latestRust,
+}
+
+// builtinLatest are the newest releases when decor was built, used when they can't be looked up
+var builtinLatest = map[string]string{
+	"go":     "1.25.5",
+	"python": "3.13.0",
+	"rust":   "1.81.0",
+	"c++":    "14",
+	"java":   "21",
+}
+
+// latestRelease is a language's newest release and when it was looked up, zero when it's built in
+type latestRelease struct {
+	version string
+	fetched time.Time
+	checked time.Time // when the lookup was made
+}
+
+// latest holds what's been looked up lately
+var latest = struct {
+	sync.Mutex
+	found map[string]latestRelease
+}{found: make(map[string]latestRelease)}
+
+// lookupLatest returns the newest release of language, reusing a lookup made within latestReuse. When it
+// can't be looked up the built-in version is used, and logged.
+func lookupLatest(ctx context.Context, language string) latestRelease {
+	language = strings.ToLower(language)
+	latest.Lock()
+	found, ok := latest.found[language]
+	latest.Unlock()
+	if ok && time.Since(found.checked) < latestReuse {
+		return found
+	}
+
+	found = latestRelease{version: builtinLatest[language], checked: time.Now()}
+	if source, ok := latestSources[language]; ok {
+		ctx, cancel := context.WithTimeout(ctx, latestLookupTimeout)
+		defer cancel()
+		if version, fetched, err := source(ctx); err != nil {
+			runner.Logf("looking up the latest %s: %v; using %s", language, err, found.version)
+		} else {
+			found.version, found.fetched = version, fetched
+		}
+	}
+	latest.Lock()
+	latest.found[language] = found
+	latest.Unlock()
+	return found
+}
+
+// RefreshLatest looks up the newest release of language again, asking upstream rather than the cache
+func RefreshLatest(ctx context.Context, language string) {
+	latest.Lock()
+	delete(latest.found, strings.ToLower(language))
+	latest.Unlock()
+	lookupLatest(download.Refresh(ctx), language)
+}
+
+// latestGo reads the newest stable Go release from go.dev's list of downloads, newest first
+func latestGo(ctx context.Context) (string, time.Time, error) {
+	body, fetched, err := download.Metadata(ctx, goReleases)
+	if err != nil {
+		return "", fetched, err
+	}
+	var releases []struct {
+		Version string `json:"version"`
+		Stable  bool   `json:"stable"`
+	}
+	if err := json.Unmarshal([]byte(body), &releases); err != nil {
+		return "", fetched, fmt.Errorf("unexpected answer from go.dev: %.100s", body)
+	}
+	for _, r := range releases {
+		if r.Stable && strings.HasPrefix(r.Version, "go") {
+			return strings.TrimPrefix(r.Version, "go"), fetched, nil
+		}
+	}
+	return "", fetched, fmt.Errorf("no stable release on go.dev: %.100s", body)
+}
+
+// pythonRelease matches a final Python 3 release's name on python.org, e.g. "Python 3.13.1"
+var pythonRelease = regexp.MustCompile(`^Python (3\.\d+\.\d+)$`)
+
+// latestPython reads the newest Python 3 release from python.org's list of releases
+func latestPython(ctx context.Context) (string, time.Time, error) {
+	body, fetched, err := download.Metadata(ctx, pythonReleases)
+	if err != nil {
+		return "", fetched, err
+	}
+	var releases []struct {
+		Name       string `json:"name"`
+		PreRelease bool   `json:"pre_release"`
+	}
+	if err := json.Unmarshal([]byte(body), &releases); err != nil {
+		return "", fetched, fmt.Errorf("unexpected answer from python.org: %.100s", body)
+	}
+	var newest string
+	for _, r := range releases {
+		if m := pythonRelease.FindStringSubmatch(r.Name); m != nil && !r.PreRelease && table.Compare(m[1], newest) > 0 {
+			newest = m[1]
+		}
+	}
+	if newest == "" {
+		return "", fetched, fmt.Errorf("no Python 3 release on python.org: %.100s", body)
+	}
+	return newest, fetched, nil
+}
+
+// latestRust reads the newest stable Rust release from rust-lang/rust's GitHub releases
+func latestRust(ctx context.Context) (string, time.Time, error) {
+	body, fetched, err := download.Metadata(ctx, rustReleases)
+	if err != nil {
+		return "", fetched, err
+	}
+	var release struct {
+		Tag string `json:"tag_name"`
+	}
+	if err := json.Unmarshal([]byte(body), &release); err != nil || release.Tag == "" {
+		return "", fetched, fmt.Errorf("unexpected answer from the GitHub API: %.100s", body)
+	}
+	return strings.TrimPrefix(release.Tag, "v"), fetched, nil
+}
@@ -0,0 +1,57 @@
+package installer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLookupLatest(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	mux := http.NewServeMux()
+	mux.HandleFunc("/go", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"version":"go1.26rc1","stable":false},{"version":"go1.25.6","stable":true},{"version":"go1.24.12","stable":true}]`))
+	})
+	mux.HandleFunc("/python", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"name":"Python 3.9.21"},{"name":"Python 3.13.2"},{"name":"Python 3.14.0a4","pre_release":true},{"name":"Python 3.12.9"},{"name":"Python 2.7.18"}]`))
+	})
+	mux.HandleFunc("/rust", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tag_name":"1.84.1"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	originalGo, originalPython, originalRust := goReleases, pythonReleases, rustReleases
+	t.Cleanup(func() { goReleases, pythonReleases, rustReleases = originalGo, originalPython, originalRust })
+	goReleases, pythonReleases, rustReleases = server.URL+"/go", server.URL+"/python", server.URL+"/rust"
+	reset := func() {
+		latest.Lock()
+		clear(latest.found)
+		latest.Unlock()
+	}
+	reset()
+	t.Cleanup(reset)
+
+	tests := []struct {
+		language, want string
+		looked         bool
+	}{
+		{"Go", "1.25.6", true},
+		{"python", "3.13.2", true},
+		{"Rust", "1.84.1", true},
+		{"java", "21", false},
+	}
+	for _, tt := range tests {
+		found := lookupLatest(context.Background(), tt.language)
+		if found.version != tt.want || found.fetched.IsZero() != !tt.looked {
+			t.Errorf("lookupLatest(%s) = %s fetched %v, want %s looked up %t", tt.language, found.version, found.fetched, tt.want, tt.looked)
+		}
+	}
+
+	// A lookup that fails falls back on the version decor was built with
+	goReleases = server.URL + "/missing"
+	reset()
+	if found := lookupLatest(context.Background(), "go"); found.version != builtinLatest["go"] || !found.fetched.IsZero() {
+		t.Errorf("lookupLatest(go) with go.dev missing = %s fetched %v, want the built-in %s", found.version, found.fetched, builtinLatest["go"])
+	}
+}
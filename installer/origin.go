@@ -0,0 +1,61 @@
+package installer
+
+import (
+	"runtime"
+	"strings"
+
+	"decor/catalog"
+	"decor/installed"
+)
+
+// addSource notes a file fetched for the item, for the installed-items database
+func (p *LanguageProgress) addSource(url, sha256 string) {
+	p.update(func() {
+		p.sources = append(p.sources, installed.Source{URL: url, SHA256: sha256})
+	})
+}
+
+// packagesOf returns the package manager's names for language, which decor installed with method, or
+// nil when it didn't come from a package manager
+func packagesOf(language, method string) []string {
+	switch method {
+	case "pkg", "pkg_add":
+		return bsdPackages(runtime.GOOS, language)
+	}
+	switch strings.ToLower(language) {
+	case "python":
+		if method == "brew" {
+			return []string{pythonFormula()}
+		}
+		return []string{"python3"}
+	case "c++":
+		if method == "apt" {
+			packages, _, _, _ := cppToolchain(settings.CppCompiler)
+			return packages
+		}
+		return nil
+	case "go", "java", "rust", "homebrew":
+		return nil
+	}
+	item, ok := catalog.Find(language)
+	if !ok {
+		return nil
+	}
+	switch method {
+	case "brew":
+		return item.Brew
+	case "apt":
+		return item.Apt
+	case "winget":
+		return []string{item.Winget}
+	case "pipx":
+		return item.Pipx[:1]
+	case "npm":
+		return item.Npm
+	case "cargo":
+		return item.Cargo[:1]
+	case "go":
+		return []string{item.GoInstall}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package installer
+
+import (
+	"context"
+	"time"
+)
+
+// Phases an item moves through during a run
+const (
+	PhaseQueued      = "queued"      // waiting for prerequisites, or for another item to finish installing
+	PhaseDownloading = "downloading" // fetching archives and installer scripts, alongside other items
+	PhaseInstalling  = "installing"  // extracting or running the package manager, one item at a time
+	PhaseVerifying   = "verifying"   // checking the install works
+	PhaseDone        = "done"
+	PhaseFailed      = "failed"
+	PhaseSkipped     = "skipped" // skipped while queued
+)
+
+// slotKey marks a context whose item holds the install slot
+type slotKey struct{}
+
+// SetPhase moves the item to a new phase of the run
+func (p *LanguageProgress) SetPhase(phase string) {
+	p.update(func() {
+		p.enterPhase(phase)
+	})
+}
+
+// enterPhase adds the time spent in the current phase to its timing and starts timing the next one.
+// The first call starts the clock. The caller holds p.mu.
+func (p *LanguageProgress) enterPhase(phase string) {
+	now := time.Now()
+	if !p.phaseStart.IsZero() {
+		if p.Timings == nil {
+			p.Timings = make(map[string]time.Duration)
+		}
+		p.Timings[p.Phase] += now.Sub(p.phaseStart)
+	}
+	p.Phase = phase
+	p.phaseStart = now
+}
+
+// addDownloaded counts bytes fetched for the item
+func (p *LanguageProgress) addDownloaded(n int64) {
+	p.update(func() {
+		p.Downloaded += n
+	})
+}
+
+// Elapsed is the item's total time across all phases
+func (s ProgressSnapshot) Elapsed() time.Duration {
+	var total time.Duration
+	for _, d := range s.Timings {
+		total += d
+	}
+	return total
+}
+
+// serialize runs install in the installing phase while holding the install slot, queueing until it's the
+// item's turn. Nested calls, like a package manager run inside an installer that holds the slot already,
+// run straight away.
+func serialize(ctx context.Context, progress *LanguageProgress, install func(ctx context.Context) error) error {
+	if ctx.Value(slotKey{}) != nil {
+		return install(ctx)
+	}
+
+	progress.SetPhase(PhaseQueued)
+	if err := queue.acquire(ctx, progress.Language); err != nil {
+		return err
+	}
+	defer queue.release()
+
+	progress.SetPhase(PhaseInstalling)
+	return install(context.WithValue(ctx, slotKey{}, true))
+}
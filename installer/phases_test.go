@@ -0,0 +1,86 @@
+package installer
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSerialize(t *testing.T) {
+	var running, most atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			progress := NewProgress("item")
+			err := serialize(context.Background(), progress, func(ctx context.Context) error {
+				n := running.Add(1)
+				defer running.Add(-1)
+				for {
+					m := most.Load()
+					if n <= m || most.CompareAndSwap(m, n) {
+						break
+					}
+				}
+				if phase := progress.Snapshot().Phase; phase != PhaseInstalling {
+					t.Errorf("phase while installing is %q", phase)
+				}
+				time.Sleep(10 * time.Millisecond)
+				// Package manager runs inside an installer that holds the slot mustn't deadlock
+				return serialize(ctx, progress, func(context.Context) error { return nil })
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if most.Load() != 1 {
+		t.Errorf("%d installs ran at once", most.Load())
+	}
+
+	// A cancelled run gives up waiting for the slot
+	queue.acquire(context.Background(), "other")
+	defer queue.release()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	progress := NewProgress("item")
+	err := serialize(ctx, progress, func(context.Context) error {
+		t.Error("ran without the slot")
+		return nil
+	})
+	if err == nil {
+		t.Error("a cancelled wait returned no error")
+	}
+	if phase := progress.Snapshot().Phase; phase != PhaseQueued {
+		t.Errorf("phase while waiting is %q", phase)
+	}
+}
+
+func TestPhaseTimings(t *testing.T) {
+	progress := NewProgress("item")
+	progress.SetPhase(PhaseQueued)
+	for _, phase := range []string{PhaseDownloading, PhaseInstalling, PhaseDownloading, PhaseVerifying, PhaseDone} {
+		time.Sleep(5 * time.Millisecond)
+		progress.SetPhase(phase)
+	}
+
+	snapshot := progress.Snapshot()
+	if _, ok := snapshot.Timings[PhaseDone]; ok {
+		t.Error("the final phase was timed")
+	}
+	for _, phase := range []string{PhaseQueued, PhaseDownloading, PhaseInstalling, PhaseVerifying} {
+		if snapshot.Timings[phase] < 5*time.Millisecond {
+			t.Errorf("%s took %s", phase, snapshot.Timings[phase])
+		}
+	}
+	if snapshot.Timings[PhaseDownloading] < 10*time.Millisecond {
+		t.Errorf("two downloading phases added up to %s", snapshot.Timings[PhaseDownloading])
+	}
+	if snapshot.Elapsed() < 25*time.Millisecond {
+		t.Errorf("elapsed %s", snapshot.Elapsed())
+	}
+}
@@ -0,0 +1,93 @@
+package installer
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"decor/config"
+	"decor/download"
+	"decor/errs"
+)
+
+// pinFor returns the pin standing in for a download of url for language: the item's pin with the same
+// file name. pinned is false when the item has no pins; when it has some but none covers url, upstream
+// has moved on from what was pinned, and that fails.
+func pinFor(language, url string) (pin config.Pin, pinned bool, err error) {
+	found := false
+	for _, p := range settings.Pins {
+		if !strings.EqualFold(p.Item, language) {
+			continue
+		}
+		found = true
+		if path.Base(p.URL) == path.Base(url) {
+			return p, true, nil
+		}
+	}
+	if !found {
+		return config.Pin{}, false, nil
+	}
+	return config.Pin{}, false, errs.New(errs.ErrPinMismatch, "downloading "+path.Base(url), fmt.Errorf("none of %s's pins is for %s", language, url))
+}
+
+// verifyPin checks a pinned download has the pin's checksum
+func verifyPin(file string, pin config.Pin) error {
+	sum, err := download.SHA256(file)
+	if err != nil {
+		return err
+	}
+	if sum != pin.SHA256 {
+		return errs.New(errs.ErrPinMismatch, "verifying "+path.Base(pin.URL), fmt.Errorf("pinned sha256 %s, got %s", pin.SHA256, sum))
+	}
+	return nil
+}
+
+// checkPinnedVersion checks an install or update left the version language's pins are for, if they
+// say
+func checkPinnedVersion(language string) error {
+	if dryRun || len(pinnedVersions(language)) == 0 {
+		return nil
+	}
+	return checkPin(language, checkLanguageInstallation(language).Version)
+}
+
+// pinnedVersions returns the versions language's pins are for
+func pinnedVersions(language string) []string {
+	var versions []string
+	for _, p := range settings.Pins {
+		if strings.EqualFold(p.Item, language) && p.Version != "" {
+			versions = append(versions, p.Version)
+		}
+	}
+	return versions
+}
+
+// checkPin checks output, what language's version command printed, is a version its pins are for
+func checkPin(language, output string) error {
+	versions := pinnedVersions(language)
+	if len(versions) == 0 {
+		return nil
+	}
+	version := config.VersionNumber(output)
+	if version == "" {
+		version = output
+	}
+	for _, want := range versions {
+		if ok, _ := config.VersionAllowed(want, version); ok {
+			return nil
+		}
+	}
+	return errs.New(errs.ErrPinMismatch, "installing "+language, fmt.Errorf("installed %s, but it's pinned to %s", version, strings.Join(versions, " or ")))
+}
+
+// pinSteps adds the files pinned for the item to a plan action
+func pinSteps(a *Action) {
+	if len(a.Steps) > 0 && strings.HasPrefix(a.Steps[0], "nothing") {
+		return
+	}
+	for _, p := range settings.Pins {
+		if strings.EqualFold(p.Item, a.Language) {
+			a.Steps = append(a.Steps, fmt.Sprintf("download %s in place of upstream's %s, failing unless its SHA-256 is %s", p.URL, path.Base(p.URL), p.SHA256))
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package installer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"decor/config"
+	"decor/errs"
+)
+
+func TestPinFor(t *testing.T) {
+	original := settings
+	t.Cleanup(func() { settings = original })
+	settings.Pins = []config.Pin{{Item: "Go", Version: "1.25.5", URL: "https://mirror.internal/go/go1.25.5.linux-amd64.tar.gz"}}
+
+	pin, pinned, err := pinFor("go", "https://go.dev/dl/go1.25.5.linux-amd64.tar.gz")
+	if err != nil || !pinned || pin.URL != settings.Pins[0].URL {
+		t.Errorf("pinFor(go1.25.5) = %+v, %t, %v, want the mirror", pin, pinned, err)
+	}
+	if _, pinned, err := pinFor("go", "https://go.dev/dl/go1.26.0.linux-amd64.tar.gz"); pinned || !errors.Is(err, errs.ErrPinMismatch) {
+		t.Errorf("pinFor(go1.26.0) = %t, %v, want it refused as unpinned", pinned, err)
+	}
+	if _, pinned, err := pinFor("kubectl", "https://dl.k8s.io/kubectl"); pinned || err != nil {
+		t.Errorf("pinFor(kubectl) = %t, %v, want it left alone", pinned, err)
+	}
+}
+
+func TestVerifyPin(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tool")
+	if err := os.WriteFile(file, []byte("hello\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pin := config.Pin{Item: "tool", URL: "https://example.com/tool", SHA256: "5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03"}
+	if err := verifyPin(file, pin); err != nil {
+		t.Errorf("verifyPin() = %v for the pinned file", err)
+	}
+	pin.SHA256 = "0000000000000000000000000000000000000000000000000000000000000000"
+	if err := verifyPin(file, pin); !errors.Is(err, errs.ErrPinMismatch) {
+		t.Errorf("verifyPin() = %v for a changed file, want a pin mismatch", err)
+	}
+}
+
+func TestCheckPin(t *testing.T) {
+	original := settings
+	t.Cleanup(func() { settings = original })
+	settings.Pins = []config.Pin{{Item: "Go", Version: "1.25.5", URL: "https://mirror.internal/go/go1.25.5.linux-amd64.tar.gz"}}
+
+	tests := []struct {
+		language, output string
+		ok               bool
+	}{
+		{"go", "go version go1.25.5 linux/amd64", true},
+		{"go", "go version go1.25.4 linux/amd64", false},
+		{"go", "1.25.5", true},
+		{"go", "", false},
+		{"rust", "rustc 1.81.0 (eeb90cda1 2024-09-04)", true},
+	}
+	for _, tt := range tests {
+		err := checkPin(tt.language, tt.output)
+		if (err == nil) != tt.ok || (err != nil && !errors.Is(err, errs.ErrPinMismatch)) {
+			t.Errorf("checkPin(%s, %q) = %v, want ok=%t", tt.language, tt.output, err, tt.ok)
+		}
+	}
+}
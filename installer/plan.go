@@ -0,0 +1,320 @@
+package installer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"decor/catalog"
+	"decor/config"
+	"decor/installed"
+	"decor/platform"
+)
+
+// Action is what a run will do for one language, for showing before anything changes
+type Action struct {
+	Language string   `json:"language"`
+	Choice   string   `json:"choice"` // "install", "update" or "remove"
+	Steps    []string `json:"steps"`  // e.g. "download https://go.dev/dl/go1.25.5.linux-amd64.tar.gz"
+	// What's deleted, replaced or edited outside decor's own files, confirmed before anything runs,
+	// e.g. "edit ~/.bashrc"
+	Touches []string `json:"touches,omitempty"`
+
+	// What's fetched, for estimating sizes: archives and packages decor downloads itself, and apt packages
+	URLs     []string `json:"urls,omitempty"`
+	Packages []string `json:"packages,omitempty"`
+	// Filled in by EstimateSizes; Sized is false when some of what's fetched couldn't be measured
+	Download int64 `json:"download,omitempty"`
+	Disk     int64 `json:"disk,omitempty"`
+	Sized    bool  `json:"sized,omitempty"`
+}
+
+// Plan describes what Run would do with the choices, without running anything. Languages that are
+// skipped are left out.
+func Plan(languages []string, choices map[string]string) []Action {
+	var plan []Action
+	for _, lang := range languages {
+		choice := choices[lang]
+		if choice != "install" && choice != "update" && choice != "remove" {
+			continue
+		}
+		action := Action{Language: lang, Choice: choice}
+		planSteps(&action)
+		if choice != "remove" {
+			componentSteps(&action)
+			envSteps(&action)
+			pinSteps(&action)
+			policySteps(&action)
+		}
+		plan = append(plan, action)
+	}
+	return plan
+}
+
+// run describes running a command, noting when it runs as root
+func run(root bool, args ...string) string {
+	return fmt.Sprintf("run `%s`%s", strings.Join(args, " "), asRoot(root))
+}
+
+// planSteps describes the steps installing or updating a language takes, mirroring the installers
+func planSteps(a *Action) {
+	language, update := a.Language, a.Choice == "update"
+	if checkPlatform(language) != nil {
+		a.Steps = []string{fmt.Sprintf("nothing: decor can't install %s on %s", language, runtime.GOOS)}
+		return
+	}
+	if a.Choice == "remove" {
+		removalSteps(a)
+		return
+	}
+	if isBSD() && coreLanguage(language) {
+		bsdSteps(a, update)
+		return
+	}
+	prefix := settings.Prefix()
+	privileged := !writable(prefix)
+
+	switch strings.ToLower(language) {
+	case "go":
+		a.Steps = append(archiveSteps(a, goArchive, getLatestVersion("go")), "check the new toolchain with `go version`")
+	case "python":
+		switch {
+		case usesBrew() && update:
+			a.Steps = []string{run(false, "brew", "upgrade", pythonFormula())}
+		case usesBrew():
+			a.Steps = []string{run(false, "brew", "install", pythonFormula())}
+		case update:
+			a.Packages = []string{"python3"}
+			a.Steps = []string{run(true, "apt-get", "upgrade", "-y", "python3")}
+		default:
+			a.Packages = []string{"python3"}
+			a.Steps = []string{run(true, "apt-get", "install", "-y", "python3")}
+		}
+	case "rust":
+		if update {
+			a.Steps = []string{run(false, "rustup", "update")}
+			return
+		}
+		url, err := rustupInit()
+		if err != nil {
+			a.Steps = []string{"nothing: " + err.Error()}
+			return
+		}
+		a.URLs = []string{url}
+		a.Steps = []string{
+			fmt.Sprintf("download %s and check it against %s.sha256", url, url),
+			run(false, append([]string{filepath.Base(url)}, rustupInitArgs...)...),
+		}
+	case "c++":
+		switch {
+		case runtime.GOOS == "darwin" && update:
+			a.Steps = []string{run(true, "softwareupdate", "-i", "-a")}
+			return
+		case runtime.GOOS == "darwin":
+			a.Steps = []string{run(false, "xcode-select", "--install"), "wait for Apple's installer dialog to finish, up to " + cltWait.String()}
+			return
+		case update:
+			a.Steps = []string{run(true, "apt-get", "upgrade", "-y")}
+			return
+		}
+		packages, cc, cxx, err := cppToolchain(settings.CppCompiler)
+		if err != nil {
+			a.Steps = []string{"nothing: " + err.Error()}
+			return
+		}
+		a.Packages = packages
+		a.Steps = []string{
+			run(true, append([]string{"apt-get", "install", "-y"}, packages...)...),
+			fmt.Sprintf("point cc at %s and c++ at %s with update-alternatives, as root", cc, cxx),
+		}
+	case "java":
+		version := settings.JavaVersion
+		if version == "latest" {
+			version = "<newest>"
+		}
+		dir := filepath.Join(javaRoot(), settings.JavaVendor+"-"+version)
+		a.Steps = []string{
+			fmt.Sprintf("download the newest %s %s JDK and check its SHA-256", settings.JavaVendor, settings.JavaVersion),
+			fmt.Sprintf("move any existing %s aside, to be restored if the install fails", dir),
+			fmt.Sprintf("extract it to %s%s", dir, asRoot(privileged)),
+			fmt.Sprintf("link %s to it%s", filepath.Join(javaRoot(), "current"), asRoot(privileged)),
+		}
+		if exists(dir) {
+			a.Touches = []string{fmt.Sprintf("replace %s, deleting the JDK in it once the new one works", dir)}
+		} else if installed, _ := filepath.Glob(filepath.Join(javaRoot(), settings.JavaVendor+"-*")); settings.JavaVersion == "latest" && len(installed) > 0 {
+			a.Touches = []string{fmt.Sprintf("replace %s if it already holds the newest JDK", dir)}
+		}
+	case "homebrew":
+		if update {
+			a.Steps = []string{run(false, "brew", "update")}
+			return
+		}
+		a.Steps = []string{
+			"download the .pkg installer from the latest Homebrew/brew release and check its SHA-256",
+			run(true, "installer", "-pkg", "Homebrew.pkg", "-target", "/"),
+		}
+	default:
+		item, ok := catalog.Find(language)
+		if !ok {
+			a.Steps = []string{"nothing: unknown item"}
+			return
+		}
+		itemSteps(a, item)
+	}
+}
+
+// rustupProfiles are the shell startup files rustup-init added ~/.cargo/bin to before decor ran it with
+// --no-modify-path, which rustup's uninstaller cleans up
+var rustupProfiles = []string{"~/.profile", "~/.bashrc", "~/.zshenv"}
+
+// exists reports whether path exists, for noting what an install replaces
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// edits describes editing each of files
+func edits(files []string) []string {
+	touches := make([]string, len(files))
+	for i, file := range files {
+		touches[i] = "edit " + file
+	}
+	return touches
+}
+
+// Touches lists what plan deletes, replaces or edits outside decor's own files, by language
+func Touches(plan []Action) map[string][]string {
+	touches := make(map[string][]string)
+	for _, action := range plan {
+		if len(action.Touches) > 0 {
+			touches[action.Language] = action.Touches
+		}
+	}
+	return touches
+}
+
+// removalSteps describes uninstalling a language, mirroring removeLanguageWithProgress
+func removalSteps(a *Action) {
+	if _, ok := installed.Get(a.Language); !ok {
+		a.Steps = []string{fmt.Sprintf("nothing: decor didn't install %s", a.Language)}
+		return
+	}
+	specs, err := removal(a.Language, removalMethod(a.Language))
+	if err != nil {
+		a.Steps = []string{"nothing: " + err.Error()}
+		return
+	}
+	for _, spec := range specs {
+		a.Steps = append(a.Steps, run(spec.Root, append([]string{filepath.Base(spec.Name)}, spec.Args...)...))
+		if spec.Name == "rm" {
+			a.Touches = append(a.Touches, "delete "+spec.Args[len(spec.Args)-1])
+		}
+	}
+	if strings.EqualFold(a.Language, "rust") {
+		a.Touches = append(a.Touches, edits(rustupProfiles)...)
+	}
+}
+
+// asRoot is appended to a step that runs as root
+func asRoot(root bool) string {
+	if root {
+		return ", as root"
+	}
+	return ""
+}
+
+// itemSteps describes the steps installing or updating a catalog item takes, mirroring installItemWithProgress
+func itemSteps(a *Action, item catalog.Item) {
+	update := a.Choice == "update"
+	var steps []string
+	switch itemStrategy(item) {
+	case "brew":
+		args := []string{"brew", "install"}
+		if update {
+			args = []string{"brew", "upgrade"}
+		}
+		if item.BrewCask {
+			args = append(args, "--cask")
+		}
+		steps = append(steps, run(false, append(args, item.Brew...)...))
+	case "apt":
+		args := []string{"apt-get", "install", "-y"}
+		if update {
+			args = append(args, "--only-upgrade")
+		}
+		a.Packages = item.Apt
+		steps = append(steps, run(true, append(args, item.Apt...)...))
+	case "pipx":
+		args := append([]string{"pipx", "install"}, item.Pipx...)
+		if update {
+			args = []string{"pipx", "upgrade", item.Pipx[0]}
+		}
+		steps = append(steps, run(false, args...))
+	case "winget":
+		steps = append(steps, run(false, append([]string{"winget.exe"}, wingetArgs(item, update)...)...)+" on Windows")
+	case "npm":
+		steps = append(steps, run(false, append([]string{"npm"}, npmArgs(item, update)...)...))
+	case "cargo":
+		steps = append(steps, run(false, append([]string{"cargo"}, cargoArgs(item)...)...))
+	case "script":
+		shell := item.Shell
+		if shell == "" {
+			shell = "sh"
+		}
+		url := platformURL(item.Scripts)
+		steps = append(steps, "download "+url, run(false, append([]string{shell, filepath.Base(url)}, expandArgs(item.Args)...)...))
+	case "deb":
+		url := platformURL(item.Debs)
+		a.URLs = []string{url}
+		steps = append(steps, "download "+url, run(true, "apt-get", "install", "-y", filepath.Base(url)))
+	case "binary":
+		a.URLs = []string{platformURL(item.Binaries)}
+		steps = append(steps, "download "+platformURL(item.Binaries), "save it as "+filepath.Join(config.ExpandHome("~/.local/bin"), item.Version[0]))
+		steps = append(steps, quarantineSteps(filepath.Join(config.ExpandHome("~/.local/bin"), item.Version[0]))...)
+	case "release":
+		steps = append(steps, fmt.Sprintf("download the latest %s release asset for %s, verifying its published checksum", item.Repo, platform.Current()),
+			"save "+item.Version[0]+" from it as "+filepath.Join(config.ExpandHome("~/.local/bin"), item.Version[0]))
+		steps = append(steps, quarantineSteps(filepath.Join(config.ExpandHome("~/.local/bin"), item.Version[0]))...)
+	case "archive":
+		steps = append(steps, archiveSteps(a, item.Archive, "")...)
+	case "go":
+		steps = append(steps, run(false, "go", "install", item.GoInstall), "check "+item.Version[0]+" is in "+goBin())
+	case "command":
+		steps = append(steps, run(false, expandArgs(item.Command)...))
+	default:
+		if item.Manual != "" {
+			a.Steps = []string{"nothing: decor can't do this for you: " + item.Manual}
+			return
+		}
+		a.Steps = []string{fmt.Sprintf("nothing: no install strategy for %s", platform.Current())}
+		return
+	}
+
+	for _, command := range item.Configure {
+		steps = append(steps, run(false, expandArgs(command)...))
+	}
+	// Only the installer script and the Configure commands edit files, not the package managers
+	if itemStrategy(item) == "script" || len(item.Configure) > 0 {
+		a.Touches = edits(item.Touches)
+	}
+	if settings.StarterConfigs {
+		var dests []string
+		for dest := range item.Starter {
+			dests = append(dests, dest)
+		}
+		slices.Sort(dests)
+		for _, dest := range dests {
+			steps = append(steps, fmt.Sprintf("write a starter config to %s unless it exists", dest))
+		}
+	}
+	if name := item.Services[runtime.GOOS]; name != "" {
+		steps = append(steps, fmt.Sprintf("enable and start the %s service%s", name, asRoot(runtime.GOOS != "darwin")))
+	}
+	if runtime.GOOS == "linux" && item.UserGroup != "" {
+		steps = append(steps, fmt.Sprintf("add you to the %s group, as root", item.UserGroup))
+	}
+	a.Steps = steps
+}
@@ -0,0 +1,131 @@
+package installer
+
+import (
+	"slices"
+	"testing"
+
+	"decor/catalog"
+	"decor/config"
+)
+
+func TestPlan(t *testing.T) {
+	original := settings
+	t.Cleanup(func() { settings = original })
+	settings = config.Default()
+	settings.PackageManager = "apt"
+	settings.Components = map[string][]string{"python": {"tkinter"}}
+
+	plan := Plan([]string{"ripgrep", "Python", "jq"}, map[string]string{"ripgrep": "install", "Python": "update", "jq": "skip"})
+	want := []Action{
+		{Language: "ripgrep", Choice: "install", Steps: []string{"run `apt-get install -y ripgrep`, as root"}, Packages: []string{"ripgrep"}},
+		{Language: "Python", Choice: "update", Steps: []string{"run `apt-get upgrade -y python3`, as root", "run `apt-get install -y python3-tk`, as root"}, Packages: []string{"python3", "python3-tk"}},
+	}
+	if len(plan) != len(want) {
+		t.Fatalf("got %d actions, want %d: %+v", len(plan), len(want), plan)
+	}
+	for i := range want {
+		if plan[i].Language != want[i].Language || plan[i].Choice != want[i].Choice || !slices.Equal(plan[i].Steps, want[i].Steps) ||
+			!slices.Equal(plan[i].Packages, want[i].Packages) {
+			t.Errorf("action %d = %+v, want %+v", i, plan[i], want[i])
+		}
+	}
+}
+
+func TestItemSteps(t *testing.T) {
+	original := settings
+	t.Cleanup(func() { settings = original })
+	settings = config.Default()
+	settings.PackageManager = "brew"
+	settings.StarterConfigs = true
+
+	item := catalog.Item{
+		Name:      "tool",
+		Brew:      []string{"tool"},
+		Configure: [][]string{{"tool", "init"}},
+		Starter:   map[string]string{"~/.toolrc": "toolrc", "~/.config/tool/a.toml": "a.toml"},
+	}
+	want := []string{
+		"run `brew upgrade tool`",
+		"run `tool init`",
+		"write a starter config to ~/.config/tool/a.toml unless it exists",
+		"write a starter config to ~/.toolrc unless it exists",
+	}
+	update := Action{Language: "tool", Choice: "update"}
+	if itemSteps(&update, item); !slices.Equal(update.Steps, want) {
+		t.Errorf("got %q, want %q", update.Steps, want)
+	}
+
+	manual := Action{Language: "manual", Choice: "install"}
+	if itemSteps(&manual, catalog.Item{Name: "manual", Manual: "see the website"}); !slices.Equal(manual.Steps, []string{"nothing: decor can't do this for you: see the website"}) {
+		t.Errorf("manual item steps = %q", manual.Steps)
+	}
+}
+
+func TestItemTouches(t *testing.T) {
+	original, originalBrew := settings, brewInstalled
+	t.Cleanup(func() { settings, brewInstalled = original, originalBrew })
+	settings = config.Default()
+	brewInstalled = func() bool { return true }
+
+	item := catalog.Item{Name: "tool", Brew: []string{"tool"}, Scripts: map[string]string{"*": "https://example.com/install.sh"}, Touches: []string{"~/.bashrc"}}
+	for _, tt := range []struct {
+		manager string
+		want    []string
+	}{
+		{"apt", []string{"edit ~/.bashrc"}},
+		{"brew", nil},
+	} {
+		settings.PackageManager = tt.manager
+		action := Action{Language: "tool", Choice: "install"}
+		if itemSteps(&action, item); !slices.Equal(action.Touches, tt.want) {
+			t.Errorf("with %s the install touches %q, want %q", tt.manager, action.Touches, tt.want)
+		}
+	}
+
+	plan := []Action{{Language: "jq"}, {Language: "tool", Touches: []string{"edit ~/.bashrc"}}}
+	if touches := Touches(plan); len(touches) != 1 || touches["tool"][0] != "edit ~/.bashrc" {
+		t.Errorf("Touches = %v", touches)
+	}
+}
+
+func TestParseAptSizes(t *testing.T) {
+	output := `Package: ripgrep
+Version: 14.1.0-1
+Installed-Size: 5058
+Size: 1589012
+
+Package: jq
+Installed-Size: 102
+Size: 64534
+Description: lightweight and flexible command-line JSON processor
+`
+	downloadSize, disk, records := parseAptSizes(output)
+	if downloadSize != 1589012+64534 || disk != (5058+102)<<10 || records != 2 {
+		t.Errorf("got %d, %d, %d", downloadSize, disk, records)
+	}
+}
+
+func TestSizeWarnings(t *testing.T) {
+	original := settings
+	t.Cleanup(func() { settings = original })
+	settings = config.Default()
+	settings.InstallPrefix = t.TempDir()
+
+	plan := []Action{{Language: "Go", Steps: []string{"download"}, Download: 300 << 20, Disk: 900 << 20, Sized: true}}
+	if warnings := SizeWarnings(plan); len(warnings) != 0 {
+		t.Errorf("no limit, but got %q", warnings)
+	}
+	settings.DownloadLimit = 100
+	if warnings := SizeWarnings(plan); len(warnings) != 1 {
+		t.Errorf("over the limit, but got %q", warnings)
+	}
+	plan[0].Disk = 1 << 60
+	if warnings := SizeWarnings(plan); len(warnings) != 2 {
+		t.Errorf("over the limit and the free space, but got %q", warnings)
+	}
+
+	downloadSize, disk, unsized := PlanSize(append(plan, Action{Language: "Rust", Steps: []string{"run rustup"}}))
+	if downloadSize != 300<<20 || disk != 1<<60 || !slices.Equal(unsized, []string{"Rust"}) {
+		t.Errorf("PlanSize = %d, %d, %v", downloadSize, disk, unsized)
+	}
+}
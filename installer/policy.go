@@ -0,0 +1,103 @@
+package installer
+
+import (
+	"fmt"
+
+	"decor/config"
+	"decor/errs"
+	"decor/runner"
+)
+
+// policy is the organization's policy the installers enforce; overridePolicy lets a run go ahead
+// where it's broken, logging each time it does
+var (
+	policy         config.Policy
+	overridePolicy bool
+)
+
+// SetPolicy makes the installers enforce p, or only log what breaks it when override is set
+func SetPolicy(p config.Policy, override bool) {
+	policy = p
+	overridePolicy = override
+}
+
+// breaksPolicy handles something an install does that the policy forbids. It's refused, unless the
+// policy only warns or the run overrides it, when it's logged and noted in the item's summary and the
+// run's history instead.
+func breaksPolicy(language string, progress *LanguageProgress, reason string) error {
+	switch {
+	case overridePolicy:
+		runner.Logf("overriding the policy in %s: %s", policy.Path, reason)
+		progress.AddNote(fmt.Sprintf("policy overridden: %s", reason))
+	case !policy.Refuses():
+		runner.Logf("breaking the policy in %s: %s", policy.Path, reason)
+		progress.AddNote(fmt.Sprintf("breaks policy: %s", reason))
+	default:
+		return errs.New(errs.ErrPolicy, "installing "+language, fmt.Errorf("%s, which the policy in %s forbids", reason, policy.Path))
+	}
+	return nil
+}
+
+// checkMethod checks the policy allows how language is installed, before anything is done
+func checkMethod(language string, progress *LanguageProgress) error {
+	if method := installMethod(language); policy.ForbidsMethod(method) {
+		return breaksPolicy(language, progress, fmt.Sprintf("%s is installed with %s", language, method))
+	}
+	return nil
+}
+
+// checkVersion checks the policy allows version of language, a version number or a version command's
+// output; a version that can't be told is let be
+func checkVersion(language, version string, progress *LanguageProgress) error {
+	version = config.VersionNumber(version)
+	allowed := policy.AllowedVersions(language)
+	if allowed == "" || version == "" {
+		return nil
+	}
+	if ok, err := config.VersionAllowed(allowed, version); err != nil || ok {
+		return nil
+	}
+	return breaksPolicy(language, progress, fmt.Sprintf("%s %s isn't in %s", language, version, allowed))
+}
+
+// checkInstalledVersion checks the version an install or update left, since most items' versions
+// aren't known until they're installed
+func checkInstalledVersion(language string, progress *LanguageProgress) error {
+	if dryRun || policy.AllowedVersions(language) == "" {
+		return nil
+	}
+	return checkVersion(language, checkLanguageInstallation(language).Version, progress)
+}
+
+// checkSource checks the policy allows downloading url, before it's fetched
+func checkSource(url string, progress *LanguageProgress) error {
+	if !policy.ForbidsURL(url) {
+		return nil
+	}
+	return breaksPolicy(progress.Language, progress, "it downloads from "+url)
+}
+
+// requireChecksum checks the policy allows downloading url with no published checksum to verify it
+// against, before it's fetched. A pinned download is checked against its pin.
+func requireChecksum(url string, progress *LanguageProgress) error {
+	if _, pinned, _ := pinFor(progress.Language, url); pinned || !policy.RequireChecksums {
+		return nil
+	}
+	return breaksPolicy(progress.Language, progress, url+" has no published checksum to verify it against")
+}
+
+// policySteps adds what the policy says about installing the item to a plan action
+func policySteps(a *Action) {
+	method := installMethod(a.Language)
+	if !policy.ForbidsMethod(method) {
+		return
+	}
+	switch {
+	case overridePolicy:
+		a.Steps = append(a.Steps, fmt.Sprintf("override the policy forbidding %s installs, logging it", method))
+	case policy.Refuses():
+		a.Steps = []string{fmt.Sprintf("nothing: the policy in %s forbids installing with %s", policy.Path, method)}
+	default:
+		a.Steps = append(a.Steps, fmt.Sprintf("warn that the policy in %s forbids installing with %s", policy.Path, method))
+	}
+}
@@ -0,0 +1,43 @@
+package installer
+
+import (
+	"errors"
+	"testing"
+
+	"decor/config"
+	"decor/errs"
+)
+
+func TestBreaksPolicy(t *testing.T) {
+	t.Cleanup(func() { SetPolicy(config.Policy{}, false) })
+	strict := config.Policy{Path: "/etc/decor/policy.toml", Enforcement: "refuse", Versions: map[string]string{"go": ">=1.22, <1.24"}}
+	tests := []struct {
+		name     string
+		policy   config.Policy
+		override bool
+		refused  bool
+	}{
+		{"refused", strict, false, true},
+		{"overridden", strict, true, false},
+		{"warned", config.Policy{Path: strict.Path, Enforcement: "warn", Versions: strict.Versions}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetPolicy(tt.policy, tt.override)
+			progress := NewProgress("Go")
+			if err := checkVersion("Go", "1.23.4", progress); err != nil {
+				t.Errorf("checkVersion(1.23.4) = %v, want it allowed", err)
+			}
+			if err := checkVersion("Go", "go version go1.23.4 linux/amd64", progress); err != nil {
+				t.Errorf("checkVersion(go version go1.23.4 linux/amd64) = %v, want it allowed", err)
+			}
+			err := checkVersion("Go", "1.25.5", progress)
+			if got := errors.Is(err, errs.ErrPolicy); got != tt.refused {
+				t.Errorf("checkVersion(1.25.5) = %v, want refused %t", err, tt.refused)
+			}
+			if notes := progress.Snapshot().Notes; !tt.refused && len(notes) != 1 {
+				t.Errorf("notes = %q, want the broken policy noted", notes)
+			}
+		})
+	}
+}
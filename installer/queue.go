@@ -0,0 +1,201 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// ErrSkipped is returned for items skipped while they were waiting their turn
+var ErrSkipped = errors.New("skipped while queued")
+
+// queue hands out the install slot, which lets one item at a time extract archives or run package managers
+// and installers. Downloads still overlap, but disk-heavy and lock-sensitive steps would only contend with
+// each other. Items get the slot in the run's order, which the user can change while it runs, and can be
+// skipped until they first have it.
+var queue = newScheduler()
+
+// scheduler is the install slot and the order items wait for it in
+type scheduler struct {
+	mu      sync.Mutex
+	busy    bool                               // an item holds the slot
+	paused  bool                               // nothing new gets the slot until the queue's resumed
+	order   []string                           // the run's items that haven't had the slot yet, in turn order
+	started map[string]bool                    // the run's items that have had it, which go first
+	waiting []waiter                           // calls waiting for the slot, in the order they came
+	cancels map[string]context.CancelCauseFunc // stops each item in order, when it's skipped
+}
+
+// waiter is a call waiting for the slot; ready is closed when it's given the slot
+type waiter struct {
+	language string
+	ready    chan struct{}
+}
+
+func newScheduler() *scheduler {
+	return &scheduler{cancels: make(map[string]context.CancelCauseFunc), started: make(map[string]bool)}
+}
+
+// track adds a run's item to the end of the order; cancel stops it if it's skipped
+func (q *scheduler) track(language string, cancel context.CancelCauseFunc) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.order = append(q.order, language)
+	q.cancels[language] = cancel
+}
+
+// untrack drops a finished item from the order. The queue resumes once the run has nothing left in it.
+func (q *scheduler) untrack(language string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.forget(language)
+	delete(q.started, language)
+	if len(q.cancels) == 0 && len(q.started) == 0 {
+		q.paused = false
+	}
+	q.dispatch()
+}
+
+// forget drops language from the order; the caller holds q.mu
+func (q *scheduler) forget(language string) {
+	q.order = slices.DeleteFunc(q.order, func(l string) bool { return l == language })
+	delete(q.cancels, language)
+}
+
+// rank is where a waiting call comes: items that have started installing first, so they finish, then
+// the run's items by their turn, then anything else in the order it came
+func (q *scheduler) rank(i int) int {
+	if q.started[q.waiting[i].language] {
+		return i - len(q.waiting)
+	}
+	if at := slices.Index(q.order, q.waiting[i].language); at >= 0 {
+		return at
+	}
+	return len(q.order) + i
+}
+
+// dispatch gives the free slot to the first waiting call, or while the queue's paused, to an item that's
+// started installing already; the caller holds q.mu
+func (q *scheduler) dispatch() {
+	if q.busy || len(q.waiting) == 0 {
+		return
+	}
+	next := 0
+	for i := range q.waiting {
+		if q.rank(i) < q.rank(next) {
+			next = i
+		}
+	}
+	w := q.waiting[next]
+	if q.paused && !q.started[w.language] {
+		return
+	}
+	q.waiting = slices.Delete(q.waiting, next, next+1)
+	// Once an item has had the slot it's started, and can't be skipped or reordered
+	if _, ok := q.cancels[w.language]; ok {
+		q.forget(w.language)
+		q.started[w.language] = true
+	}
+	q.busy = true
+	close(w.ready)
+}
+
+// acquire waits for language's turn with the slot, giving up if ctx is done first
+func (q *scheduler) acquire(ctx context.Context, language string) error {
+	ready := make(chan struct{})
+	q.mu.Lock()
+	q.waiting = append(q.waiting, waiter{language: language, ready: ready})
+	q.dispatch()
+	q.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	select {
+	case <-ready:
+		// Given the slot just as the wait ended, so pass it on
+		q.busy = false
+		q.dispatch()
+	default:
+		q.waiting = slices.DeleteFunc(q.waiting, func(w waiter) bool { return w.ready == ready })
+	}
+	if errors.Is(context.Cause(ctx), ErrSkipped) {
+		return ErrSkipped
+	}
+	return ctx.Err()
+}
+
+// release frees the slot for the next item
+func (q *scheduler) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.busy = false
+	q.dispatch()
+}
+
+// Skip stops an item of the running install that hasn't started installing yet, downloads and all
+func Skip(language string) error {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	cancel, ok := queue.cancels[language]
+	if !ok {
+		return fmt.Errorf("%s isn't waiting to install", language)
+	}
+	queue.forget(language)
+	cancel(ErrSkipped)
+	return nil
+}
+
+// Move moves an item of the running install that hasn't started installing yet by places in the queue:
+// a negative number moves it nearer the front
+func Move(language string, places int) error {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	at := slices.Index(queue.order, language)
+	if at < 0 {
+		return fmt.Errorf("%s isn't waiting to install", language)
+	}
+	to := max(0, min(at+places, len(queue.order)-1))
+	queue.order = slices.Insert(slices.Delete(queue.order, at, at+1), to, language)
+	return nil
+}
+
+// Pause stops items of the running install from starting to install, or lets them again. Items already
+// installing finish, and downloads carry on.
+func Pause(paused bool) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	queue.paused = paused
+	queue.dispatch()
+}
+
+// Queued returns the running install's items that haven't started installing, in the order they will,
+// and whether the queue's paused
+func Queued() ([]string, bool) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	return slices.Clone(queue.order), queue.paused
+}
+
+// ChangeQueue makes a change to the running install's queue by name, as the daemon and the TUI ask for
+// them: "skip", "earlier" or "later" for language, or "pause" or "resume"
+func ChangeQueue(change, language string) error {
+	switch change {
+	case "skip":
+		return Skip(language)
+	case "earlier":
+		return Move(language, -1)
+	case "later":
+		return Move(language, 1)
+	case "pause", "resume":
+		Pause(change == "pause")
+		return nil
+	}
+	return fmt.Errorf("unknown queue change %q, expected skip, earlier, later, pause or resume", change)
+}
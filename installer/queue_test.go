@@ -0,0 +1,62 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestQueue(t *testing.T) {
+	original := queue
+	t.Cleanup(func() { queue = original })
+	queue = newScheduler()
+
+	items := []string{"Go", "Rust", "jq", "ripgrep"}
+	contexts := make(map[string]context.Context)
+	for _, lang := range items {
+		ctx, cancel := context.WithCancelCause(context.Background())
+		contexts[lang] = ctx
+		queue.track(lang, cancel)
+	}
+	Move("ripgrep", -2)
+	if err := Skip("Rust"); err != nil {
+		t.Fatal(err)
+	}
+	if order, _ := Queued(); !slices.Equal(order, []string{"Go", "ripgrep", "jq"}) {
+		t.Errorf("queue is %v after moving ripgrep up and skipping Rust", order)
+	}
+
+	// With the queue paused, items wait for the slot in the queue's order, whatever order they ask in
+	Pause(true)
+	got := make(chan string, len(items))
+	for _, lang := range []string{"jq", "Rust", "ripgrep", "Go"} {
+		go func() {
+			if err := queue.acquire(contexts[lang], lang); err != nil {
+				if !errors.Is(err, ErrSkipped) || (lang != "Rust" && lang != "Go") {
+					t.Errorf("%s: %v", lang, err)
+				}
+				return
+			}
+			got <- lang
+			queue.release()
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	if len(got) != 0 {
+		t.Fatalf("%s got the slot while the queue was paused", <-got)
+	}
+	if err := Skip("Go"); err != nil {
+		t.Fatal(err)
+	}
+	Pause(false)
+	for _, want := range []string{"ripgrep", "jq"} {
+		if lang := <-got; lang != want {
+			t.Errorf("%s got the slot, want %s", lang, want)
+		}
+	}
+	if err := Skip("jq"); err == nil {
+		t.Error("skipped an item that had started installing")
+	}
+}
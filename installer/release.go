@@ -0,0 +1,72 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"decor/catalog"
+	"decor/config"
+	"decor/download"
+	"decor/errs"
+	"decor/platform"
+	"decor/release"
+	"decor/runner"
+)
+
+// installRelease installs the program an item publishes as a GitHub release asset into ~/.local/bin,
+// checking the asset against the checksum the release publishes alongside it
+func installRelease(ctx context.Context, op string, item catalog.Item, progress *LanguageProgress) error {
+	dest := filepath.Join(config.ExpandHome("~/.local/bin"), item.Version[0])
+	if dryRun {
+		runner.Logf("dry run: installing %s from the latest %s release asset matching %q", dest, item.Repo, item.Release)
+		return nil
+	}
+
+	progress.Set(0.1, fmt.Sprintf("Finding the latest release of %s...", item.Repo))
+	latest, err := release.Latest(ctx, item.Repo)
+	if err != nil {
+		return errs.Classify(op, err)
+	}
+	asset, err := release.Pick(latest.Assets, item.Release, platform.Current())
+	if err != nil {
+		return errs.New(errs.ErrUnsupportedPlatform, op, err)
+	}
+	sum, err := release.Checksum(ctx, latest, asset)
+	if err != nil {
+		return errs.Classify(op, err)
+	}
+	if sum == "" {
+		if err := requireChecksum(asset.URL, progress); err != nil {
+			return err
+		}
+	}
+
+	progress.SetPhase(PhaseDownloading)
+	progress.Set(0.2, fmt.Sprintf("Downloading %s %s...", asset.Name, latest.Tag))
+	file, err := fetch(ctx, progress, asset.URL, "")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file)
+	if sum == "" {
+		progress.AddNote(fmt.Sprintf("%s %s publishes no checksum for %s, so it wasn't verified", item.Repo, latest.Tag, asset.Name))
+	} else if err := download.VerifySHA256(file, sum); err != nil {
+		return errs.Classify(op, err)
+	}
+
+	progress.SetPhase(PhaseInstalling)
+	progress.Set(0.7, fmt.Sprintf("Installing %s...", item.Name))
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return errs.Classify(op, err)
+	}
+	if err := release.Extract(file, asset.Name, item.Version[0], dest); err != nil {
+		return errs.Classify(op, err)
+	}
+	if err := prepareProgram(ctx, dest, false); err != nil {
+		return err
+	}
+	runner.Logf("installed %s from %s %s", dest, item.Repo, asset.Name)
+	return nil
+}
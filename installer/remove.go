@@ -0,0 +1,123 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"decor/catalog"
+	"decor/config"
+	"decor/installed"
+	"decor/pkgmgr"
+	"decor/runner"
+)
+
+// removal returns the commands that uninstall language, which decor installed with method, or why
+// decor won't. Only what decor can cleanly undo is removed: system packages other software relies on,
+// and what an installer script set up, are left to the user.
+func removal(language, method string) ([]runner.Spec, error) {
+	op := "removing " + language
+	prefix := settings.Prefix()
+	if packages := bsdPackages(runtime.GOOS, language); packages != nil && (method == "pkg" || method == "pkg_add") {
+		name, args, _ := pkgmgr.BSDCommand(runtime.GOOS, "delete", packages)
+		return []runner.Spec{{Op: op, Name: name, Args: args, Root: true}}, nil
+	}
+	switch strings.ToLower(language) {
+	case "go":
+		goroot := filepath.Join(prefix, "go")
+		return []runner.Spec{{Op: op, Name: "rm", Args: []string{"-rf", goroot, versionsDir(goroot)}, Root: !writable(prefix)}}, nil
+	case "java":
+		return []runner.Spec{{Op: op, Name: "rm", Args: []string{"-rf", javaRoot()}, Root: !writable(prefix)}}, nil
+	case "rust":
+		return []runner.Spec{{Op: op, Name: lookPath("rustup"), Args: []string{"self", "uninstall", "-y"}}}, nil
+	case "python":
+		if method == "brew" {
+			return []runner.Spec{{Op: op, Name: "brew", Args: []string{"uninstall", pythonFormula()}}}, nil
+		}
+		return nil, fmt.Errorf("python3 is part of the system and other packages need it, so decor leaves it installed")
+	case "homebrew":
+		return nil, fmt.Errorf("removing Homebrew removes everything installed with it; run Homebrew's uninstall script if you're sure")
+	case "c++":
+		if method == "xcode-select" {
+			return nil, fmt.Errorf("decor can't remove the Command Line Tools; delete /Library/Developer/CommandLineTools as root if you're sure")
+		}
+		return nil, fmt.Errorf("the compilers are system packages other software builds with, so decor leaves them installed")
+	}
+
+	item, ok := catalog.Find(language)
+	if !ok {
+		return nil, fmt.Errorf("unsupported language: %s", language)
+	}
+	switch method {
+	case "brew":
+		args := []string{"uninstall"}
+		if item.BrewCask {
+			args = append(args, "--cask")
+		}
+		return []runner.Spec{{Op: op, Name: "brew", Args: append(args, item.Brew...)}}, nil
+	case "apt":
+		return []runner.Spec{{Op: op, Name: "apt-get", Args: append([]string{"remove", "-y"}, item.Apt...), Root: true}}, nil
+	case "pipx":
+		return []runner.Spec{{Op: op, Name: lookPath("pipx"), Args: []string{"uninstall", item.Pipx[0]}}}, nil
+	case "winget":
+		return []runner.Spec{{Op: op, Name: lookPath("winget.exe"), Args: []string{"uninstall", "--exact", "--id", item.Winget, "--silent"}}}, nil
+	case "npm":
+		return []runner.Spec{{Op: op, Name: lookPath("npm"), Args: append([]string{"uninstall", "-g"}, item.Npm...), Env: npmEnv()}}, nil
+	case "cargo":
+		return []runner.Spec{{Op: op, Name: lookPath("cargo"), Args: []string{"uninstall", item.Cargo[0]}}}, nil
+	case "binary", "release":
+		return []runner.Spec{{Op: op, Name: "rm", Args: []string{"-f", filepath.Join(config.ExpandHome("~/.local/bin"), item.Version[0])}}}, nil
+	case "archive":
+		dir := archiveDir(item.Archive)
+		specs := []runner.Spec{{Op: op, Name: "rm", Args: []string{"-rf", dir, versionsDir(dir)}, Root: !writable(filepath.Dir(dir))}}
+		for _, program := range item.Archive.Links {
+			specs = append(specs, runner.Spec{Op: op, Name: "rm", Args: []string{"-f", archiveLink(program)}})
+		}
+		return specs, nil
+	case "go":
+		return []runner.Spec{{Op: op, Name: "rm", Args: []string{"-f", filepath.Join(goBin(), item.Version[0])}}}, nil
+	}
+	return nil, fmt.Errorf("decor can't undo what %s's installer did; remove it the way its documentation says", item.Name)
+}
+
+// removalMethod is how language was installed: as decor recorded it, or as decor would install it now
+func removalMethod(language string) string {
+	if record, ok := installed.Get(language); ok && record.Method != "" {
+		return record.Method
+	}
+	return installMethod(language)
+}
+
+// removeLanguageWithProgress uninstalls something decor installed. What decor didn't install belongs
+// to whoever did, and is left alone.
+func removeLanguageWithProgress(ctx context.Context, language string, progress *LanguageProgress) error {
+	if err := checkPlatform(language); err != nil {
+		return err
+	}
+	if _, ok := installed.Get(language); !ok {
+		return fmt.Errorf("decor didn't install %s, so it leaves removing it to you", language)
+	}
+	specs, err := removal(language, removalMethod(language))
+	if err != nil {
+		return err
+	}
+
+	progress.SetPhase(PhaseInstalling)
+	progress.Set(0.3, fmt.Sprintf("Removing %s...", language))
+	for _, spec := range specs {
+		switch spec.Name {
+		case "brew", "apt-get":
+			err = runPackageManager(ctx, progress, spec.Name, spec.Args...)
+		default:
+			err = serialize(ctx, progress, func(ctx context.Context) error {
+				return runCommand(ctx, spec)
+			})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
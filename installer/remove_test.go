@@ -0,0 +1,76 @@
+package installer
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"decor/config"
+	"decor/installed"
+)
+
+func tempState(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_STATE_HOME", dir)
+	t.Setenv("LOCALAPPDATA", dir)
+}
+
+func TestRemoval(t *testing.T) {
+	original := settings
+	t.Cleanup(func() { settings = original })
+	settings = config.Default()
+
+	tests := []struct {
+		language, method string
+		want             []string // the first command's name and arguments, or nothing if decor refuses
+	}{
+		{"ripgrep", "apt", []string{"apt-get", "remove", "-y", "ripgrep"}},
+		{"ripgrep", "brew", []string{"brew", "uninstall", "ripgrep"}},
+		{"poetry", "pipx", []string{"pipx", "uninstall", "poetry"}},
+		{"Rust", "rustup", []string{"rustup", "self", "uninstall", "-y"}},
+		{"uv", "script", nil},
+		{"Python", "apt", nil},
+		{"C++", "apt", nil},
+	}
+	for _, tt := range tests {
+		specs, err := removal(tt.language, tt.method)
+		if tt.want == nil {
+			if err == nil {
+				t.Errorf("removal(%q, %q) = %v, want a refusal", tt.language, tt.method, specs)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("removal(%q, %q): %v", tt.language, tt.method, err)
+			continue
+		}
+		got := append([]string{specs[0].Name}, specs[0].Args...)
+		got[0] = got[0][strings.LastIndex(got[0], "/")+1:]
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("removal(%q, %q) runs %q, want %q", tt.language, tt.method, got, tt.want)
+		}
+	}
+}
+
+func TestRemovalSteps(t *testing.T) {
+	tempState(t)
+	original := settings
+	t.Cleanup(func() { settings = original })
+	settings = config.Default()
+	settings.InstallPrefix = t.TempDir()
+
+	plan := Plan([]string{"Go"}, map[string]string{"Go": "remove"})
+	if len(plan) != 1 || !strings.HasPrefix(plan[0].Steps[0], "nothing: decor didn't install Go") {
+		t.Fatalf("removing what decor didn't install plans %+v", plan)
+	}
+
+	if err := installed.Add("Go", "tarball", "1.25.5"); err != nil {
+		t.Fatal(err)
+	}
+	plan = Plan([]string{"Go"}, map[string]string{"Go": "remove"})
+	if len(plan[0].Touches) != 1 || !strings.HasPrefix(plan[0].Touches[0], "delete ") {
+		t.Errorf("removing Go touches %q", plan[0].Touches)
+	}
+}
@@ -0,0 +1,53 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"decor/runner"
+)
+
+// replaceDir installs a fresh copy of dir with install, which must create dir. An existing dir is moved
+// aside first, since extracting over an old toolchain leaves stale files behind, and is put back if
+// install fails. privileged runs the moves as root.
+func replaceDir(ctx context.Context, dir string, privileged bool, install func() error) error {
+	backup := dir + ".decor-previous"
+	_, statErr := os.Stat(dir)
+	existed := statErr == nil
+
+	if existed {
+		// A backup left by an interrupted run is older than what's installed now
+		for _, spec := range []runner.Spec{
+			{Op: "removing " + backup, Name: "rm", Args: []string{"-rf", backup}, Root: privileged},
+			{Op: "moving the old " + filepath.Base(dir) + " aside", Name: "mv", Args: []string{dir, backup}, Root: privileged},
+		} {
+			if err := runCommand(ctx, spec); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := install(); err != nil {
+		// The rollback mustn't be cut short by the cancellation or timeout that may have caused the failure
+		rollback := context.WithoutCancel(ctx)
+		cleanup := runner.Spec{Op: "removing the partial " + filepath.Base(dir), Name: "rm", Args: []string{"-rf", dir}, Root: privileged}
+		if cleanupErr := runCommand(rollback, cleanup); cleanupErr != nil {
+			return fmt.Errorf("%w (and removing the partial install failed: %v)", err, cleanupErr)
+		}
+		if !existed {
+			return err
+		}
+		restore := runner.Spec{Op: "restoring the old " + filepath.Base(dir), Name: "mv", Args: []string{backup, dir}, Root: privileged}
+		if restoreErr := runCommand(rollback, restore); restoreErr != nil {
+			return fmt.Errorf("%w (and restoring the old install from %s failed: %v)", err, backup, restoreErr)
+		}
+		return fmt.Errorf("%w; the previous install was restored", err)
+	}
+
+	if existed {
+		return runCommand(ctx, runner.Spec{Op: "removing the old " + filepath.Base(dir), Name: "rm", Args: []string{"-rf", backup}, Root: privileged})
+	}
+	return nil
+}
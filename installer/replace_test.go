@@ -0,0 +1,89 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"decor/runner"
+)
+
+func TestReplaceDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses rm and mv")
+	}
+	original := commands
+	t.Cleanup(func() { commands = original })
+	commands = &runner.Runner{}
+
+	// install writes a fresh marker file into dir, failing afterwards if fail is set
+	install := func(dir string, fail bool) func() error {
+		return func() error {
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(filepath.Join(dir, "new"), nil, 0o644); err != nil {
+				return err
+			}
+			if fail {
+				return errors.New("extraction failed")
+			}
+			return nil
+		}
+	}
+
+	tests := []struct {
+		name     string
+		existing bool
+		fail     bool
+		want     []string // files in dir afterwards, nil if it shouldn't exist
+	}{
+		{"fresh install", false, false, []string{"new"}},
+		{"replaces the old files", true, false, []string{"new"}},
+		{"rolls back", true, true, []string{"old"}},
+		{"cleans up a failed fresh install", false, true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "go")
+			if tt.existing {
+				if err := os.MkdirAll(dir, 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(dir, "old"), nil, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := replaceDir(context.Background(), dir, false, install(dir, tt.fail))
+			if tt.fail != (err != nil) {
+				t.Fatalf("got error %v, want failure=%t", err, tt.fail)
+			}
+			if tt.fail && tt.existing && !strings.Contains(err.Error(), "restored") {
+				t.Errorf("error doesn't mention the rollback: %v", err)
+			}
+
+			entries, err := os.ReadDir(dir)
+			if tt.want == nil {
+				if !os.IsNotExist(err) {
+					t.Errorf("%s is still there: %v", dir, err)
+				}
+			} else {
+				var got []string
+				for _, entry := range entries {
+					got = append(got, entry.Name())
+				}
+				if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+					t.Errorf("%s holds %v, want %v", dir, got, tt.want)
+				}
+			}
+			if _, err := os.Stat(dir + ".decor-previous"); !os.IsNotExist(err) {
+				t.Errorf("the backup was left behind: %v", err)
+			}
+		})
+	}
+}
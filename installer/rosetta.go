@@ -0,0 +1,36 @@
+package installer
+
+import (
+	"os/exec"
+	"path/filepath"
+	"slices"
+
+	"decor/platform"
+)
+
+// nativeMethods are the ways of installing that download a build for the platform, so reinstalling
+// replaces an x86_64 build with an arm64 one
+var nativeMethods = []string{"tarball", "binary", "release", "archive"}
+
+// programArch returns the architectures of the program a version check ran, or "" for package
+// manager queries and anything that isn't a compiled program
+func programArch(name string) string {
+	switch name {
+	case "brew", "dpkg-query":
+		return ""
+	}
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return ""
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	return platform.BinaryArch(path)
+}
+
+// Reinstallable reports whether the item runs under Rosetta and decor can replace it with a native
+// build by reinstalling it
+func (s *InstallationStatus) Reinstallable() bool {
+	return s.Rosetta && s.Managed && slices.Contains(nativeMethods, s.Source)
+}
@@ -0,0 +1,22 @@
+package installer
+
+import "testing"
+
+func TestReinstallable(t *testing.T) {
+	tests := []struct {
+		status InstallationStatus
+		want   bool
+	}{
+		{InstallationStatus{Rosetta: true, Managed: true, Source: "tarball"}, true},
+		{InstallationStatus{Rosetta: true, Managed: true, Source: "binary"}, true},
+		// Homebrew reinstalls the same Intel bottle
+		{InstallationStatus{Rosetta: true, Managed: true, Source: "brew"}, false},
+		{InstallationStatus{Rosetta: true, Source: "tarball"}, false},
+		{InstallationStatus{Managed: true, Source: "tarball"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.status.Reinstallable(); got != tt.want {
+			t.Errorf("%+v.Reinstallable() = %t, want %t", tt.status, got, tt.want)
+		}
+	}
+}
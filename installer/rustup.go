@@ -0,0 +1,53 @@
+package installer
+
+import (
+	"fmt"
+
+	"decor/platform"
+)
+
+// rustupTargets are the Rust target triples rustup-init is published for, per platform "GOOS/arch", with
+// musl Linux's after a space
+var rustupTargets = map[string]string{
+	"linux/amd64":      "x86_64-unknown-linux-gnu",
+	"linux/arm64":      "aarch64-unknown-linux-gnu",
+	"linux/armv7":      "armv7-unknown-linux-gnueabihf",
+	"linux/armv6":      "arm-unknown-linux-gnueabihf",
+	"linux/amd64 musl": "x86_64-unknown-linux-musl",
+	"linux/arm64 musl": "aarch64-unknown-linux-musl",
+	"linux/386":        "i686-unknown-linux-gnu",
+	"darwin/amd64":     "x86_64-apple-darwin",
+	"darwin/arm64":     "aarch64-apple-darwin",
+	"windows/amd64":    "x86_64-pc-windows-msvc",
+	"windows/arm64":    "aarch64-pc-windows-msvc",
+	"windows/386":      "i686-pc-windows-msvc",
+	"freebsd/amd64":    "x86_64-unknown-freebsd",
+}
+
+// rustupInitArgs are the flags rustup-init runs with: no prompts, the stable toolchain and the default
+// profile, spelled out rather than left to rustup-init's defaults, and shell startup files left alone,
+// since decor's environment script puts ~/.cargo/bin on PATH
+var rustupInitArgs = []string{"-y", "--default-toolchain", "stable", "--profile", "default", "--no-modify-path"}
+
+// rustupInitURL returns where rustup-init for the platform is published; its SHA-256 is at the same URL
+// with .sha256 added
+func rustupInitURL(p platform.Platform) (string, error) {
+	key := p.OS + "/" + p.Arch
+	if p.Musl {
+		key += " musl"
+	}
+	target, ok := rustupTargets[key]
+	if !ok {
+		return "", fmt.Errorf("rustup-init isn't published for %s", p)
+	}
+	name := "rustup-init"
+	if p.OS == "windows" {
+		name += ".exe"
+	}
+	return fmt.Sprintf("https://static.rust-lang.org/rustup/dist/%s/%s", target, name), nil
+}
+
+// rustupInit is rustup-init's URL for this platform
+func rustupInit() (string, error) {
+	return rustupInitURL(platform.Current())
+}
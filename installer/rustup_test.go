@@ -0,0 +1,34 @@
+package installer
+
+import (
+	"testing"
+
+	"decor/platform"
+)
+
+func TestRustupInitURL(t *testing.T) {
+	tests := []struct {
+		platform platform.Platform
+		want     string // empty if rustup-init isn't published for the platform
+	}{
+		{platform.Platform{OS: "linux", Arch: "amd64"}, "https://static.rust-lang.org/rustup/dist/x86_64-unknown-linux-gnu/rustup-init"},
+		{platform.Platform{OS: "darwin", Arch: "arm64"}, "https://static.rust-lang.org/rustup/dist/aarch64-apple-darwin/rustup-init"},
+		{platform.Platform{OS: "windows", Arch: "amd64"}, "https://static.rust-lang.org/rustup/dist/x86_64-pc-windows-msvc/rustup-init.exe"},
+		{platform.Platform{OS: "linux", Arch: "armv6"}, "https://static.rust-lang.org/rustup/dist/arm-unknown-linux-gnueabihf/rustup-init"},
+		{platform.Platform{OS: "linux", Arch: "arm64", Musl: true}, "https://static.rust-lang.org/rustup/dist/aarch64-unknown-linux-musl/rustup-init"},
+		{platform.Platform{OS: "linux", Arch: "armv7", Musl: true}, ""},
+		{platform.Platform{OS: "plan9", Arch: "amd64"}, ""},
+	}
+	for _, tt := range tests {
+		got, err := rustupInitURL(tt.platform)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("rustupInitURL(%s) = %s, want an error", tt.platform, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("rustupInitURL(%s) = %s, %v, want %s", tt.platform, got, err, tt.want)
+		}
+	}
+}
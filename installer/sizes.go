@@ -0,0 +1,131 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"decor/doctor"
+	"decor/download"
+	"decor/runner"
+)
+
+// unpackedRatio is roughly how much bigger a compressed archive gets once it's extracted
+const unpackedRatio = 3
+
+// compressed reports whether a download is an archive that's extracted after downloading
+func compressed(url string) bool {
+	for _, ext := range []string{".tar.gz", ".tgz", ".tar.xz", ".zip", ".deb"} {
+		if strings.HasSuffix(url, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// EstimateSizes fills in how much each action will download and take up on disk, asking the servers for
+// the size of each download and apt for its packages'. Actions whose installers fetch what they need
+// themselves, like rustup, are left unsized.
+func EstimateSizes(ctx context.Context, plan []Action) {
+	var wg sync.WaitGroup
+	for i := range plan {
+		wg.Add(1)
+		go func(a *Action) {
+			defer wg.Done()
+			estimate(ctx, a)
+		}(&plan[i])
+	}
+	wg.Wait()
+}
+
+// estimate sizes one action
+func estimate(ctx context.Context, a *Action) {
+	if len(a.URLs) == 0 && len(a.Packages) == 0 {
+		return
+	}
+	a.Sized = true
+	for _, url := range a.URLs {
+		size, err := download.Size(ctx, url)
+		if err != nil {
+			a.Sized = false
+			continue
+		}
+		a.Download += size
+		if compressed(url) {
+			size *= unpackedRatio
+		}
+		a.Disk += size
+	}
+	if len(a.Packages) > 0 {
+		output, err := commands.Run(ctx, runner.Spec{
+			Op:       "checking package sizes",
+			Name:     "apt-cache",
+			Args:     append([]string{"show", "--no-all-versions"}, a.Packages...),
+			Timeout:  settings.DetectTimeout,
+			ReadOnly: true,
+		})
+		downloadSize, disk, found := parseAptSizes(string(output))
+		if err != nil || found < len(a.Packages) {
+			a.Sized = false
+		}
+		a.Download += downloadSize
+		a.Disk += disk
+	}
+}
+
+// parseAptSizes sums the Size (bytes) and Installed-Size (KiB) fields of apt-cache show's records,
+// returning how many records it found
+func parseAptSizes(output string) (downloadSize, disk int64, records int) {
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			continue
+		}
+		switch key {
+		case "Size":
+			downloadSize += n
+			records++
+		case "Installed-Size":
+			disk += n << 10
+		}
+	}
+	return downloadSize, disk, records
+}
+
+// PlanSize totals what the plan downloads and takes up on disk, listing the actions that couldn't be sized
+func PlanSize(plan []Action) (downloadSize, disk int64, unsized []string) {
+	for _, a := range plan {
+		downloadSize += a.Download
+		disk += a.Disk
+		if !a.Sized && len(a.Steps) > 0 && !strings.HasPrefix(a.Steps[0], "nothing:") {
+			unsized = append(unsized, a.Language)
+		}
+	}
+	return downloadSize, disk, unsized
+}
+
+// SizeWarnings warns when the plan needs more space than is free in the home directory or install
+// prefix, or downloads more than the download limit setting allows
+func SizeWarnings(plan []Action) []string {
+	downloadSize, disk, _ := PlanSize(plan)
+	var warnings []string
+	home, _ := os.UserHomeDir()
+	for _, dir := range []string{home, settings.Prefix()} {
+		free, err := doctor.FreeSpace(dir)
+		if err == nil && uint64(disk) > free {
+			warnings = append(warnings, fmt.Sprintf("about %s is needed but only %s is free in %s", doctor.FormatBytes(uint64(disk)), doctor.FormatBytes(free), dir))
+			break
+		}
+	}
+	if limit := settings.DownloadLimit << 20; limit > 0 && downloadSize > limit {
+		warnings = append(warnings, fmt.Sprintf("this downloads %s, more than your download limit of %d MB", doctor.FormatBytes(uint64(downloadSize)), settings.DownloadLimit))
+	}
+	return warnings
+}
@@ -0,0 +1,67 @@
+package installer
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"decor/pkgmgr"
+	"decor/runner"
+)
+
+// brewPrefixes are where Homebrew keeps what it installs on Apple silicon, Intel Macs and Linux, besides
+// a prefix set with HOMEBREW_PREFIX. On Intel Macs that's only part of /usr/local, which is shared.
+var brewPrefixes = []string{"/opt/homebrew/", "/usr/local/Cellar/", "/usr/local/Caskroom/", "/home/linuxbrew/.linuxbrew/"}
+
+// source works out what put an item on the system from the command its version was read with: brew,
+// apt, or manual for anything else, like a tarball unpacked by hand or an installer script. What decor
+// installed is reported the way decor recorded it instead.
+func source(ctx context.Context, name string) string {
+	switch name {
+	case "brew":
+		return "brew"
+	case "dpkg-query":
+		return "apt"
+	}
+	if filepath.Base(name) == "winget.exe" {
+		return "winget"
+	}
+
+	path := name
+	if !filepath.IsAbs(path) {
+		found, err := exec.LookPath(name)
+		if err != nil {
+			return ""
+		}
+		path = found
+	}
+	paths := []string{path}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil && resolved != path {
+		paths = append(paths, resolved)
+	}
+
+	prefixes := brewPrefixes
+	if prefix := pkgmgr.BrewPrefix(); prefix != "" && prefix != "/usr/local" {
+		prefixes = append([]string{prefix + "/"}, prefixes...)
+	}
+	for _, p := range paths {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(p, prefix) {
+				return "brew"
+			}
+		}
+	}
+	if runtime.GOOS == "linux" {
+		if _, err := exec.LookPath("dpkg-query"); err == nil {
+			for _, p := range paths {
+				spec := runner.Spec{Op: "finding the package of " + p, Name: "dpkg-query", Args: []string{"-S", p}, Timeout: settings.DetectTimeout, ReadOnly: true}
+				if _, err := commands.Run(ctx, spec); err == nil {
+					return "apt"
+				}
+			}
+		}
+	}
+	return "manual"
+}
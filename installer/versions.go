@@ -0,0 +1,221 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"decor/catalog"
+	"decor/download"
+	"decor/installed"
+	"decor/runner"
+)
+
+// keepsVersions reports whether archives are unpacked to a directory per version and switched between
+// with links. Windows only lets administrators and Developer Mode make symlinks, so archives are unpacked
+// in place there.
+func keepsVersions() bool {
+	return runtime.GOOS != "windows"
+}
+
+// versionsDir is where each version of an archive unpacked to dir is kept, side by side beside it: Go's
+// under /usr/local/.decor/tools/go. Its current link points at the version in use, dir links to current,
+// and previous points at the version in use before, for decor rollback.
+func versionsDir(dir string) string {
+	return filepath.Join(filepath.Dir(dir), ".decor", "tools", filepath.Base(dir))
+}
+
+// versionName names the directory a version is unpacked to: the version, or for an archive with none,
+// the start of the download's SHA-256
+func versionName(version, file string) string {
+	if version != "" {
+		return version
+	}
+	if sum, err := download.SHA256(file); err == nil {
+		return sum[:12]
+	}
+	return "unversioned"
+}
+
+// linkedVersion returns what link in a versions directory points at, or "" when there's no link
+func linkedVersion(root, link string) string {
+	target, err := os.Readlink(filepath.Join(root, link))
+	if err != nil {
+		return ""
+	}
+	return target
+}
+
+// swapLink points link at target by renaming a new link over it, so there's never a moment with no link
+// or one half-written
+func swapLink(ctx context.Context, target, link string, privileged bool) error {
+	// mv must replace the link rather than move into the directory it points at: -T on Linux, -h elsewhere
+	noFollow := "-h"
+	if runtime.GOOS == "linux" {
+		noFollow = "-T"
+	}
+	tmp := link + ".decor-new"
+	for _, spec := range []runner.Spec{
+		{Op: "linking " + filepath.Base(link), Name: "ln", Args: []string{"-sfn", target, tmp}, Root: privileged},
+		{Op: "switching " + filepath.Base(link) + " to " + filepath.Base(target), Name: "mv", Args: []string{"-f", noFollow, tmp, link}, Root: privileged},
+	} {
+		if err := runCommand(ctx, spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// installVersion unpacks an archive into its own version directory with unpackTo and switches dir to it
+// by swapping the current link, leaving the version before whole beside it: a failed check switches
+// straight back, and decor rollback can later. A dir from before versions were kept is moved aside for
+// the link, and put back if the install fails.
+func installVersion(ctx context.Context, dir, name string, privileged bool, progress *LanguageProgress, unpackTo func(dir string) error, check func(ctx context.Context) error) error {
+	root := versionsDir(dir)
+	target := filepath.Join(root, name)
+	current := filepath.Join(root, "current")
+	previous := linkedVersion(root, "current")
+
+	err := replaceDir(ctx, target, privileged, func() error {
+		return unpackTo(target)
+	})
+	if err != nil {
+		return err
+	}
+	if err := swapLink(ctx, name, current, privileged); err != nil {
+		return err
+	}
+	backup, err := linkDir(ctx, dir, current, privileged)
+	if err == nil && check != nil && !dryRun {
+		progress.SetPhase(PhaseVerifying)
+		err = check(ctx)
+	}
+	if err != nil {
+		return switchBack(ctx, dir, root, name, previous, backup, privileged, err)
+	}
+
+	if backup != "" {
+		if err := runCommand(ctx, runner.Spec{Op: "removing the old " + filepath.Base(dir), Name: "rm", Args: []string{"-rf", backup}, Root: privileged}); err != nil {
+			return err
+		}
+	}
+	if previous != "" && previous != name {
+		return swapLink(ctx, previous, filepath.Join(root, "previous"), privileged)
+	}
+	return nil
+}
+
+// linkDir makes dir a link to current, moving a directory already there aside and returning where to,
+// so it can be removed once the new version works or put back if it doesn't
+func linkDir(ctx context.Context, dir, current string, privileged bool) (string, error) {
+	info, err := os.Lstat(dir)
+	if err == nil && info.Mode()&os.ModeSymlink != 0 {
+		return "", nil
+	}
+	var backup string
+	if err == nil {
+		backup = dir + ".decor-previous"
+		for _, spec := range []runner.Spec{
+			{Op: "removing " + backup, Name: "rm", Args: []string{"-rf", backup}, Root: privileged},
+			{Op: "moving the old " + filepath.Base(dir) + " aside", Name: "mv", Args: []string{dir, backup}, Root: privileged},
+		} {
+			if err := runCommand(ctx, spec); err != nil {
+				return "", err
+			}
+		}
+	}
+	target, err := filepath.Rel(filepath.Dir(dir), current)
+	if err != nil {
+		target = current
+	}
+	link := runner.Spec{Op: "linking " + filepath.Base(dir), Name: "ln", Args: []string{"-sfn", target, dir}, Root: privileged}
+	return backup, runCommand(ctx, link)
+}
+
+// switchBack undoes a version install that failed after its directory was unpacked: current goes back to
+// the version before, or a directory moved aside goes back in place of the link, and the failed version
+// is deleted
+func switchBack(ctx context.Context, dir, root, name, previous, backup string, privileged bool, err error) error {
+	// The rollback mustn't be cut short by the cancellation or timeout that may have caused the failure
+	rollback := context.WithoutCancel(ctx)
+	var specs []runner.Spec
+	switch {
+	case backup != "":
+		specs = append(specs,
+			runner.Spec{Op: "removing the link to " + name, Name: "rm", Args: []string{"-f", dir}, Root: privileged},
+			runner.Spec{Op: "restoring the old " + filepath.Base(dir), Name: "mv", Args: []string{backup, dir}, Root: privileged},
+			runner.Spec{Op: "removing the current link", Name: "rm", Args: []string{"-f", filepath.Join(root, "current")}, Root: privileged},
+		)
+	case previous == "":
+		specs = append(specs, runner.Spec{Op: "removing the link to " + name, Name: "rm", Args: []string{"-f", dir, filepath.Join(root, "current")}, Root: privileged})
+	}
+	if previous != name {
+		specs = append(specs, runner.Spec{Op: "removing the failed " + name, Name: "rm", Args: []string{"-rf", filepath.Join(root, name)}, Root: privileged})
+	}
+
+	if previous != "" && backup == "" {
+		if swapErr := swapLink(rollback, previous, filepath.Join(root, "current"), privileged); swapErr != nil {
+			return fmt.Errorf("%w (and switching back to %s failed: %v)", err, previous, swapErr)
+		}
+	}
+	for _, spec := range specs {
+		if cleanupErr := runCommand(rollback, spec); cleanupErr != nil {
+			return fmt.Errorf("%w (and undoing the install failed: %v)", err, cleanupErr)
+		}
+	}
+	if backup != "" || previous != "" && previous != name {
+		return fmt.Errorf("%w; the previous install was restored", err)
+	}
+	return err
+}
+
+// versionsRoot returns the directory language's versions are kept in, or "" when decor doesn't keep them
+func versionsRoot(language string) string {
+	switch strings.ToLower(language) {
+	case "go":
+		return versionsDir(archiveDir(goArchive))
+	case "java":
+		return javaRoot()
+	}
+	if item, ok := catalog.Find(language); ok && item.Archive.URL != "" {
+		return versionsDir(archiveDir(item.Archive))
+	}
+	return ""
+}
+
+// Rollback switches language back to the version in use before it was last installed or updated, which
+// is kept beside it, and returns the versions it switched from and to. Rolling back again switches
+// forward.
+func Rollback(ctx context.Context, language string) (string, string, error) {
+	if _, ok := installed.Get(language); !ok {
+		return "", "", fmt.Errorf("decor didn't install %s", language)
+	}
+	root := versionsRoot(language)
+	if root == "" || !keepsVersions() {
+		return "", "", fmt.Errorf("decor doesn't keep earlier versions of %s to roll back to", language)
+	}
+	current, previous := linkedVersion(root, "current"), linkedVersion(root, "previous")
+	if previous == "" || !exists(filepath.Join(root, filepath.Base(previous))) {
+		return "", "", fmt.Errorf("there's no earlier version of %s in %s to roll back to", language, root)
+	}
+	privileged := !writable(root)
+	if err := swapLink(ctx, previous, filepath.Join(root, "current"), privileged); err != nil {
+		return "", "", err
+	}
+	if err := swapLink(ctx, current, filepath.Join(root, "previous"), privileged); err != nil {
+		return "", "", err
+	}
+
+	if dryRun {
+		return filepath.Base(current), filepath.Base(previous), nil
+	}
+	record, _ := installed.Get(language)
+	if err := installed.Add(language, record.Method, checkLanguageInstallation(language).Version); err != nil {
+		return "", "", err
+	}
+	// The downloads recorded were the version rolled back from's
+	return filepath.Base(current), filepath.Base(previous), installed.SetOrigin(language, record.Packages, nil)
+}
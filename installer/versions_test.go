@@ -0,0 +1,83 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"decor/runner"
+)
+
+func TestInstallVersion(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses ln, mv and rm")
+	}
+	original := commands
+	t.Cleanup(func() { commands = original })
+	commands = &runner.Runner{}
+
+	// unpackTo writes a file named for the version into the directory it's given
+	unpackTo := func(version string) func(string) error {
+		return func(dir string) error {
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return err
+			}
+			return os.WriteFile(filepath.Join(dir, version), nil, 0o644)
+		}
+	}
+	failing := func(context.Context) error { return errors.New("go version failed") }
+	// in reports which version's file dir shows
+	in := func(dir string) string {
+		entries, _ := os.ReadDir(dir)
+		if len(entries) != 1 {
+			return ""
+		}
+		return entries[0].Name()
+	}
+
+	prefix := t.TempDir()
+	dir := filepath.Join(prefix, "go")
+	root := versionsDir(dir)
+	progress := &LanguageProgress{Language: "Go"}
+
+	// A directory from an install before versions were kept is replaced with a link
+	os.MkdirAll(dir, 0o755)
+	os.WriteFile(filepath.Join(dir, "legacy"), nil, 0o644)
+	if err := installVersion(context.Background(), dir, "1.24.0", false, progress, unpackTo("1.24.0"), failing); err == nil {
+		t.Fatal("a failing check didn't fail the install")
+	}
+	if got := in(dir); got != "legacy" {
+		t.Fatalf("after a failed first install, %s shows %q, want the old install back", dir, got)
+	}
+	if err := installVersion(context.Background(), dir, "1.24.0", false, progress, unpackTo("1.24.0"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Lstat(dir); err != nil || info.Mode()&os.ModeSymlink == 0 || in(dir) != "1.24.0" {
+		t.Fatalf("%s isn't a link to 1.24.0: %v, %v", dir, info, err)
+	}
+
+	// The version before stays beside the new one, and a failed check switches back to it
+	if err := installVersion(context.Background(), dir, "1.25.0", false, progress, unpackTo("1.25.0"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := in(dir); got != "1.25.0" || linkedVersion(root, "previous") != "1.24.0" {
+		t.Errorf("after updating, %s shows %q with previous %q", dir, got, linkedVersion(root, "previous"))
+	}
+	if err := installVersion(context.Background(), dir, "1.26.0", false, progress, unpackTo("1.26.0"), failing); err == nil {
+		t.Fatal("a failing check didn't fail the update")
+	}
+	if got := in(dir); got != "1.25.0" || exists(filepath.Join(root, "1.26.0")) {
+		t.Errorf("after a failed update, %s shows %q, want 1.25.0 and 1.26.0 deleted", dir, got)
+	}
+	for _, version := range []string{"1.24.0", "1.25.0"} {
+		if !exists(filepath.Join(root, version)) {
+			t.Errorf("%s is gone", version)
+		}
+	}
+	if exists(dir + ".decor-previous") {
+		t.Errorf("the legacy install was left behind")
+	}
+}
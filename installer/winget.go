@@ -0,0 +1,13 @@
+package installer
+
+import "decor/catalog"
+
+// wingetArgs returns winget's arguments for installing or upgrading the item on the Windows side, without
+// prompts: WSL can run Windows programs, but GUI tools belong on Windows rather than in the Linux VM
+func wingetArgs(item catalog.Item, update bool) []string {
+	command := "install"
+	if update {
+		command = "upgrade"
+	}
+	return []string{command, "--exact", "--id", item.Winget, "--silent", "--accept-package-agreements", "--accept-source-agreements"}
+}
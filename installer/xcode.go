@@ -0,0 +1,63 @@
+package installer
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"decor/errs"
+	"decor/runner"
+)
+
+// cltPollInterval is how often decor checks whether the Command Line Tools have finished installing, and
+// cltWait how long it waits for the user to get through Apple's installer; tests shorten both
+var (
+	cltPollInterval = 5 * time.Second
+	cltWait         = 30 * time.Minute
+)
+
+// cltAlreadyInstalled matches what xcode-select --install says, failing, when there's nothing to install
+var cltAlreadyInstalled = regexp.MustCompile(`(?i)already installed`)
+
+// cltInstalled reports whether the Command Line Tools are installed: xcode-select -p only succeeds, printing
+// their directory, once they are
+func cltInstalled(ctx context.Context) bool {
+	_, err := commands.Run(ctx, runner.Spec{
+		Op:       "checking for the Command Line Tools",
+		Name:     "xcode-select",
+		Args:     []string{"-p"},
+		Timeout:  settings.DetectTimeout,
+		ReadOnly: true,
+	})
+	return err == nil
+}
+
+// installCLT installs the Command Line Tools. xcode-select --install only opens Apple's installer dialog
+// and returns, so decor then polls until the tools are there, the user gives up or cltWait passes.
+func installCLT(ctx context.Context, progress *LanguageProgress) error {
+	op := "installing Command Line Tools"
+	if cltInstalled(ctx) {
+		progress.AddNote("the Command Line Tools were already installed")
+		return nil
+	}
+	output, err := commands.Run(ctx, runner.Spec{Op: op, Name: "xcode-select", Args: []string{"--install"}})
+	if err != nil && !cltAlreadyInstalled.Match(output) {
+		return err
+	}
+	if dryRun {
+		return nil
+	}
+
+	progress.Set(0.5, "Waiting for the Command Line Tools; finish Apple's installer dialog...")
+	deadline := time.Now().Add(cltWait)
+	for !cltInstalled(ctx) {
+		if time.Now().After(deadline) {
+			return errs.New(errs.ErrTimedOut, op, fmt.Errorf("not installed within %s; finish Apple's installer dialog and run decor again", cltWait))
+		}
+		if err := pause(ctx, cltPollInterval); err != nil {
+			return err
+		}
+	}
+	return nil
+}
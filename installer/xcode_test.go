@@ -0,0 +1,68 @@
+package installer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"decor/config"
+	"decor/errs"
+)
+
+// fakeXcodeSelect puts an xcode-select on PATH whose -p succeeds once polls checks have failed, or
+// never if polls is negative, and whose --install prints installOutput and exits with installStatus
+func fakeXcodeSelect(t *testing.T, polls int, installOutput string, installStatus int) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake xcode-select is a shell script")
+	}
+	dir := t.TempDir()
+	script := `#!/bin/sh
+count=$(cat "$0.count" 2>/dev/null || echo 0)
+if [ "$1" = -p ]; then
+	echo $((count + 1)) > "$0.count"
+	if [ ` + fmt.Sprint(polls) + ` -ge 0 ] && [ "$count" -ge ` + fmt.Sprint(polls) + ` ]; then echo /Library/Developer/CommandLineTools; exit 0; fi
+	exit 2
+fi
+echo "` + installOutput + `"
+exit ` + fmt.Sprint(installStatus) + `
+`
+	if err := os.WriteFile(filepath.Join(dir, "xcode-select"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestInstallCLT(t *testing.T) {
+	original, interval, wait := settings, cltPollInterval, cltWait
+	t.Cleanup(func() { settings, cltPollInterval, cltWait = original, interval, wait })
+	settings = config.Default()
+	cltPollInterval, cltWait = time.Millisecond, 50*time.Millisecond
+
+	tests := []struct {
+		name          string
+		polls         int
+		installOutput string
+		installStatus int
+		want          error // nil for success
+	}{
+		{"already installed", 0, "", 1, nil},
+		{"finishes after the dialog", 3, "xcode-select: note: install requested for command line developer tools", 0, nil},
+		{"says it's already installed", 2, "xcode-select: error: command line tools are already installed", 1, nil},
+		{"never finishes", -1, "xcode-select: note: install requested for command line developer tools", 0, errs.ErrTimedOut},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeXcodeSelect(t, tt.polls, tt.installOutput, tt.installStatus)
+			err := installCLT(context.Background(), NewProgress("C++"))
+			if tt.want == nil && err != nil || tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("installCLT = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
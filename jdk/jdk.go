@@ -0,0 +1,175 @@
+package jdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"decor/download"
+	"decor/errs"
+	"decor/platform"
+)
+
+// Vendors are the JDK distributions decor can install, in the order the settings offer them
+var Vendors = []string{"temurin", "zulu", "corretto", "graalvm"}
+
+// API endpoints, replaced in tests
+var (
+	adoptiumAPI = "https://api.adoptium.net/v3"
+	azulAPI     = "https://api.azul.com/metadata/v1/zulu/packages"
+)
+
+// graalvmMinimum is the oldest Java version GraalVM publishes a JDK for
+const graalvmMinimum = 17
+
+// Release is a JDK archive to download, with the digest or checksum URL to verify it against
+type Release struct {
+	Vendor      string
+	Version     int // feature release, e.g. 21
+	URL         string
+	Checksum    string // SHA-256 digest, when the vendor's API returns it directly
+	ChecksumURL string // where the SHA-256 digest is published otherwise
+}
+
+// LatestVersion asks the Adoptium API for the newest feature release, e.g. 23
+func LatestVersion(ctx context.Context) (int, error) {
+	body, _, err := download.Metadata(ctx, adoptiumAPI+"/info/available_releases")
+	if err != nil {
+		return 0, err
+	}
+	var releases struct {
+		MostRecent int `json:"most_recent_feature_release"`
+	}
+	if err := json.Unmarshal([]byte(body), &releases); err != nil || releases.MostRecent == 0 {
+		return 0, fmt.Errorf("unexpected answer from the Adoptium API: %.100s", body)
+	}
+	return releases.MostRecent, nil
+}
+
+// ParseVersion turns the java_version setting into a feature release, asking the Adoptium API when it's
+// "latest"
+func ParseVersion(ctx context.Context, version string) (int, error) {
+	if version == "latest" {
+		return LatestVersion(ctx)
+	}
+	n, err := strconv.Atoi(version)
+	if err != nil || n < 8 {
+		return 0, fmt.Errorf("unknown Java version %q, expected a feature release like 21, or latest", version)
+	}
+	return n, nil
+}
+
+// Resolve finds the vendor's newest JDK archive for the feature release on the platform. Only
+// Temurin builds for 32-bit ARM, and GraalVM has no musl builds.
+func Resolve(ctx context.Context, vendor string, version int, p platform.Platform) (Release, error) {
+	release := Release{Vendor: vendor, Version: version}
+	unsupported := errs.New(errs.ErrUnsupportedPlatform, "finding a "+vendor+" JDK", fmt.Errorf("no %s %d JDK for %s", vendor, version, p))
+	arch, ok := map[string]string{"amd64": "x64", "arm64": "aarch64"}[p.Arch]
+	if vendor == "temurin" && p.Arch == "armv7" {
+		arch, ok = "arm", true
+	}
+	if !ok {
+		return release, unsupported
+	}
+	goos := p.OS
+	if p.Musl {
+		goos = "musl"
+	}
+
+	switch vendor {
+	case "temurin":
+		platform, ok := map[string]string{"linux": "linux", "darwin": "mac", "musl": "alpine-linux"}[goos]
+		if !ok {
+			return release, unsupported
+		}
+		url := fmt.Sprintf("%s/assets/latest/%d/hotspot?architecture=%s&image_type=jdk&os=%s&vendor=eclipse", adoptiumAPI, version, arch, platform)
+		var assets []struct {
+			Binary struct {
+				Package struct {
+					Link     string `json:"link"`
+					Checksum string `json:"checksum"`
+				} `json:"package"`
+			} `json:"binary"`
+		}
+		if err := getJSON(ctx, url, &assets); err != nil {
+			return release, err
+		}
+		if len(assets) == 0 {
+			return release, unsupported
+		}
+		release.URL, release.Checksum = assets[0].Binary.Package.Link, assets[0].Binary.Package.Checksum
+	case "zulu":
+		platform, ok := map[string]string{"linux": "linux", "darwin": "macos", "musl": "linux_musl"}[goos]
+		if !ok {
+			return release, unsupported
+		}
+		url := fmt.Sprintf("%s/?java_version=%d&os=%s&arch=%s&archive_type=tar.gz&java_package_type=jdk&javafx_bundled=false&latest=true&release_status=ga&availability_types=CA",
+			azulAPI, version, platform, arch)
+		var packages []struct {
+			UUID        string `json:"package_uuid"`
+			DownloadURL string `json:"download_url"`
+		}
+		if err := getJSON(ctx, url, &packages); err != nil {
+			return release, err
+		}
+		if len(packages) == 0 {
+			return release, unsupported
+		}
+		var details struct {
+			SHA256 string `json:"sha256_hash"`
+		}
+		if err := getJSON(ctx, azulAPI+"/"+packages[0].UUID, &details); err != nil {
+			return release, err
+		}
+		release.URL, release.Checksum = packages[0].DownloadURL, details.SHA256
+	case "corretto":
+		platform, ok := map[string]string{"linux": "linux", "darwin": "macos", "musl": "alpine"}[goos]
+		if !ok {
+			return release, unsupported
+		}
+		file := fmt.Sprintf("amazon-corretto-%d-%s-%s-jdk.tar.gz", version, arch, platform)
+		release.URL = "https://corretto.aws/downloads/latest/" + file
+		release.ChecksumURL = "https://corretto.aws/downloads/latest_sha256/" + file
+	case "graalvm":
+		platform, ok := map[string]string{"linux": "linux", "darwin": "macos"}[goos]
+		if !ok || version < graalvmMinimum {
+			return release, unsupported
+		}
+		release.URL = fmt.Sprintf("https://download.oracle.com/graalvm/%d/latest/graalvm-jdk-%d_%s-%s_bin.tar.gz", version, version, platform, arch)
+		release.ChecksumURL = release.URL + ".sha256"
+	default:
+		return release, fmt.Errorf("unknown JDK vendor %q, expected one of %s", vendor, strings.Join(Vendors, ", "))
+	}
+	return release, nil
+}
+
+// getJSON fetches url and decodes its JSON body into v
+func getJSON(ctx context.Context, url string, v any) error {
+	body, err := download.Text(ctx, url)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(body), v); err != nil {
+		return fmt.Errorf("decoding %s: %w", url, err)
+	}
+	return nil
+}
+
+// Dir returns where a vendor's JDK is extracted under root, e.g. root/temurin-21, so versions and
+// vendors sit side by side
+func Dir(root, vendor string, version int) string {
+	return filepath.Join(root, fmt.Sprintf("%s-%d", vendor, version))
+}
+
+// Home returns JAVA_HOME for a JDK extracted to dir; most macOS archives keep it inside a bundle
+func Home(dir string) string {
+	bundled := filepath.Join(dir, "Contents", "Home")
+	if info, err := os.Stat(bundled); err == nil && info.IsDir() {
+		return bundled
+	}
+	return dir
+}
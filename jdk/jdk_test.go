@@ -0,0 +1,140 @@
+package jdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"decor/errs"
+	"decor/platform"
+)
+
+// fakeAPIs serves canned Adoptium and Azul answers and points the package at them
+func fakeAPIs(t *testing.T) {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	mux := http.NewServeMux()
+	mux.HandleFunc("/adoptium/info/available_releases", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"available_lts_releases":[8,11,17,21],"most_recent_feature_release":23}`))
+	})
+	mux.HandleFunc("/adoptium/assets/latest/21/hotspot", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("os") != "linux" || r.URL.Query().Get("architecture") != "x64" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`[{"binary":{"package":{"link":"https://github.com/temurin21.tar.gz","checksum":"abc123"}}}]`))
+	})
+	mux.HandleFunc("/azul/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/azul/uuid-1" {
+			w.Write([]byte(`{"sha256_hash":"def456"}`))
+			return
+		}
+		w.Write([]byte(`[{"package_uuid":"uuid-1","download_url":"https://cdn.azul.com/zulu21.tar.gz"}]`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	originalAdoptium, originalAzul := adoptiumAPI, azulAPI
+	t.Cleanup(func() { adoptiumAPI, azulAPI = originalAdoptium, originalAzul })
+	adoptiumAPI, azulAPI = server.URL+"/adoptium", server.URL+"/azul"
+}
+
+func TestParseVersion(t *testing.T) {
+	fakeAPIs(t)
+	tests := []struct {
+		setting string
+		want    int
+		ok      bool
+	}{
+		{"21", 21, true},
+		{"11", 11, true},
+		{"latest", 23, true},
+		{"7", 0, false},
+		{"twenty-one", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.setting, func(t *testing.T) {
+			got, err := ParseVersion(context.Background(), tt.setting)
+			if (err == nil) != tt.ok || got != tt.want {
+				t.Errorf("got %d, %v, want %d, ok=%t", got, err, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	fakeAPIs(t)
+	tests := []struct {
+		name     string
+		vendor   string
+		version  int
+		platform platform.Platform
+		want     Release
+		wantErr  error // nil for success
+	}{
+		{"temurin", "temurin", 21, platform.Platform{OS: "linux", Arch: "amd64"},
+			Release{Vendor: "temurin", Version: 21, URL: "https://github.com/temurin21.tar.gz", Checksum: "abc123"}, nil},
+		{"temurin without a build", "temurin", 21, platform.Platform{OS: "darwin", Arch: "arm64"}, Release{}, errs.ErrUnsupportedPlatform},
+		{"zulu", "zulu", 21, platform.Platform{OS: "linux", Arch: "amd64"},
+			Release{Vendor: "zulu", Version: 21, URL: "https://cdn.azul.com/zulu21.tar.gz", Checksum: "def456"}, nil},
+		{"corretto", "corretto", 17, platform.Platform{OS: "darwin", Arch: "arm64"}, Release{
+			Vendor: "corretto", Version: 17,
+			URL:         "https://corretto.aws/downloads/latest/amazon-corretto-17-aarch64-macos-jdk.tar.gz",
+			ChecksumURL: "https://corretto.aws/downloads/latest_sha256/amazon-corretto-17-aarch64-macos-jdk.tar.gz",
+		}, nil},
+		{"graalvm", "graalvm", 21, platform.Platform{OS: "linux", Arch: "arm64"}, Release{
+			Vendor: "graalvm", Version: 21,
+			URL:         "https://download.oracle.com/graalvm/21/latest/graalvm-jdk-21_linux-aarch64_bin.tar.gz",
+			ChecksumURL: "https://download.oracle.com/graalvm/21/latest/graalvm-jdk-21_linux-aarch64_bin.tar.gz.sha256",
+		}, nil},
+		{"graalvm too old", "graalvm", 11, platform.Platform{OS: "linux", Arch: "amd64"}, Release{}, errs.ErrUnsupportedPlatform},
+		{"windows", "corretto", 21, platform.Platform{OS: "windows", Arch: "amd64"}, Release{}, errs.ErrUnsupportedPlatform},
+		{"corretto on musl", "corretto", 21, platform.Platform{OS: "linux", Arch: "amd64", Musl: true}, Release{
+			Vendor: "corretto", Version: 21,
+			URL:         "https://corretto.aws/downloads/latest/amazon-corretto-21-x64-alpine-jdk.tar.gz",
+			ChecksumURL: "https://corretto.aws/downloads/latest_sha256/amazon-corretto-21-x64-alpine-jdk.tar.gz",
+		}, nil},
+		{"graalvm on musl", "graalvm", 21, platform.Platform{OS: "linux", Arch: "amd64", Musl: true}, Release{}, errs.ErrUnsupportedPlatform},
+		{"armv7", "corretto", 21, platform.Platform{OS: "linux", Arch: "armv7"}, Release{}, errs.ErrUnsupportedPlatform},
+		{"32-bit", "corretto", 21, platform.Platform{OS: "linux", Arch: "386"}, Release{}, errs.ErrUnsupportedPlatform},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resolve(context.Background(), tt.vendor, tt.version, tt.platform)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("got error %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Resolve(context.Background(), "openj9", 21, platform.Platform{OS: "linux", Arch: "amd64"}); err == nil {
+		t.Error("an unknown vendor resolved")
+	}
+}
+
+func TestHome(t *testing.T) {
+	flat := t.TempDir()
+	bundle := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(bundle, "Contents", "Home"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if got := Home(flat); got != flat {
+		t.Errorf("Home(flat) = %s", got)
+	}
+	if got, want := Home(bundle), filepath.Join(bundle, "Contents", "Home"); got != want {
+		t.Errorf("Home(bundle) = %s, want %s", got, want)
+	}
+}
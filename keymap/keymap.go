@@ -0,0 +1,161 @@
+package keymap
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Binding is a set of keys that do the same thing, shaped like a bubbles/key binding
+type Binding struct {
+	keys []string
+}
+
+// NewBinding creates a binding for keys, named the way tea.KeyMsg.String names them, e.g. "ctrl+n"
+func NewBinding(keys ...string) Binding {
+	return Binding{keys: keys}
+}
+
+// Keys returns the keys that trigger the binding
+func (b Binding) Keys() []string {
+	return b.keys
+}
+
+// Help names the binding's keys for help text, e.g. "up/k"
+func (b Binding) Help() string {
+	names := make([]string, len(b.keys))
+	for i, k := range b.keys {
+		if k == " " {
+			k = "space"
+		}
+		names[i] = k
+	}
+	return strings.Join(names, "/")
+}
+
+// Matches reports whether msg is one of the keys of any of bindings
+func Matches(msg tea.KeyMsg, bindings ...Binding) bool {
+	key := msg.String()
+	for _, b := range bindings {
+		if slices.Contains(b.keys, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyMap holds the navigation, confirm and cancel keys every screen shares. Keys only one screen uses,
+// like n to continue, stay with that screen.
+type KeyMap struct {
+	Up       Binding
+	Down     Binding
+	Left     Binding
+	Right    Binding
+	PageUp   Binding
+	PageDown Binding
+	Toggle   Binding // picks or unpicks the item under the cursor
+	Confirm  Binding
+	Back     Binding // closes a screen, or cancels what it's doing
+	Refresh  Binding // asks again for what was looked up online, rather than using the cached answer
+	Quit     Binding
+}
+
+// Styles lists the presets a key map can start from; the first is the default
+var Styles = []string{"vim", "emacs", "arrows"}
+
+// Default returns the key map decor uses unless configured otherwise
+func Default() KeyMap {
+	keyMap, _ := Preset(Styles[0])
+	return keyMap
+}
+
+// Preset returns the key map for style: vim adds hjkl to the arrow keys, emacs adds ctrl+p, ctrl+n, ctrl+b
+// and ctrl+f, and arrows only uses the arrow keys
+func Preset(style string) (KeyMap, error) {
+	keyMap := KeyMap{
+		Up:       NewBinding("up"),
+		Down:     NewBinding("down"),
+		Left:     NewBinding("left"),
+		Right:    NewBinding("right"),
+		PageUp:   NewBinding("pgup"),
+		PageDown: NewBinding("pgdown"),
+		Toggle:   NewBinding(" "),
+		Confirm:  NewBinding("enter"),
+		Back:     NewBinding("esc"),
+		Refresh:  NewBinding("f"),
+		Quit:     NewBinding("ctrl+c", "q"),
+	}
+	switch style {
+	case "vim":
+		keyMap.Up = NewBinding("up", "k")
+		keyMap.Down = NewBinding("down", "j")
+		keyMap.Left = NewBinding("left", "h")
+		keyMap.Right = NewBinding("right", "l")
+		keyMap.PageUp = NewBinding("pgup", "ctrl+u")
+		keyMap.PageDown = NewBinding("pgdown", "ctrl+d")
+	case "emacs":
+		keyMap.Up = NewBinding("up", "ctrl+p")
+		keyMap.Down = NewBinding("down", "ctrl+n")
+		keyMap.Left = NewBinding("left", "ctrl+b")
+		keyMap.Right = NewBinding("right", "ctrl+f")
+		keyMap.PageUp = NewBinding("pgup", "alt+v")
+		keyMap.PageDown = NewBinding("pgdown", "ctrl+v")
+		keyMap.Back = NewBinding("esc", "ctrl+g")
+	case "arrows":
+	default:
+		return KeyMap{}, fmt.Errorf("unknown key style %q, expected one of %s", style, strings.Join(Styles, ", "))
+	}
+	return keyMap, nil
+}
+
+// bindings names each binding of keyMap the way the config file does
+func (k *KeyMap) bindings() map[string]*Binding {
+	return map[string]*Binding{
+		"up":        &k.Up,
+		"down":      &k.Down,
+		"left":      &k.Left,
+		"right":     &k.Right,
+		"page_up":   &k.PageUp,
+		"page_down": &k.PageDown,
+		"toggle":    &k.Toggle,
+		"confirm":   &k.Confirm,
+		"back":      &k.Back,
+		"refresh":   &k.Refresh,
+		"quit":      &k.Quit,
+	}
+}
+
+// New returns the preset for style with the bindings in overrides, keyed by action like "up" or
+// "page_down", replaced by the keys given
+func New(style string, overrides map[string][]string) (KeyMap, error) {
+	keyMap, err := Preset(style)
+	if err != nil {
+		return Default(), err
+	}
+	bindings := keyMap.bindings()
+	for action, keys := range overrides {
+		binding, ok := bindings[action]
+		if !ok {
+			return Default(), fmt.Errorf("unknown key action %q, expected one of %s", action, strings.Join(Actions(), ", "))
+		}
+		if len(keys) == 0 {
+			return Default(), fmt.Errorf("no keys given for %s", action)
+		}
+		*binding = NewBinding(keys...)
+	}
+	return keyMap, nil
+}
+
+// Actions lists the names of the bindings that can be remapped
+func Actions() []string {
+	var keyMap KeyMap
+	var actions []string
+	for action := range keyMap.bindings() {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	return actions
+}
@@ -0,0 +1,69 @@
+package keymap
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMatches(t *testing.T) {
+	up := NewBinding("up", "k")
+	tests := []struct {
+		msg  tea.KeyMsg
+		want bool
+	}{
+		{tea.KeyMsg{Type: tea.KeyUp}, true},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'k'}}, true},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}}, false},
+		{tea.KeyMsg{Type: tea.KeyCtrlP}, false},
+	}
+	for _, tt := range tests {
+		if got := Matches(tt.msg, up); got != tt.want {
+			t.Errorf("Matches(%q) = %t, want %t", tt.msg.String(), got, tt.want)
+		}
+	}
+	if !Matches(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}, up, NewBinding(" ")) {
+		t.Error("space didn't match the second binding")
+	}
+}
+
+func TestNew(t *testing.T) {
+	emacs, err := New("emacs", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !Matches(tea.KeyMsg{Type: tea.KeyCtrlN}, emacs.Down) || Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}}, emacs.Down) {
+		t.Errorf("emacs moves down with %s", emacs.Down.Help())
+	}
+
+	custom, err := New("vim", map[string][]string{"quit": {"ctrl+q"}, "page_down": {"pgdown", " "}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := custom.Quit.Help(); got != "ctrl+q" {
+		t.Errorf("quit is %s, want ctrl+q", got)
+	}
+	if got := custom.PageDown.Help(); got != "pgdown/space" {
+		t.Errorf("page down is %s, want pgdown/space", got)
+	}
+	if got := custom.Up.Help(); got != "up/k" {
+		t.Errorf("an override changed up to %s", got)
+	}
+
+	for _, tt := range []struct {
+		style     string
+		overrides map[string][]string
+	}{
+		{"nano", nil},
+		{"vim", map[string][]string{"jump": {"g"}}},
+		{"vim", map[string][]string{"up": {}}},
+	} {
+		keyMap, err := New(tt.style, tt.overrides)
+		if err == nil {
+			t.Errorf("New(%q, %v) didn't fail", tt.style, tt.overrides)
+		}
+		if keyMap.Up.Help() != Default().Up.Help() {
+			t.Errorf("New(%q, %v) didn't fall back to the default", tt.style, tt.overrides)
+		}
+	}
+}
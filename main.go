@@ -1,73 +1,653 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
 
+	"decor/config"
+	"decor/crash"
+	"decor/daemon"
+	"decor/goversions"
+	"decor/i18n"
+	"decor/installer"
+	"decor/metrics"
 	"decor/models"
+	"decor/paths"
+	"decor/platform"
+	"decor/precommit"
+	"decor/project"
+	"decor/runner"
+	"decor/sbom"
+	"decor/scaffold"
+	"decor/sshkey"
+	"decor/symbols"
+	"decor/web"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// MainModel is the TUI's top model: a router that starts on the item selection screen
 type MainModel struct {
-	activeModel     tea.Model
-	models          []tea.Model
-	currentModelIdx int
+	router models.Router
 }
 
-func (m MainModel) InitialModel() MainModel {
+func (m MainModel) InitialModel(cfg config.Config, firstRun bool, projectFile string) MainModel {
+	root := models.Screen{Name: i18n.T("screen.select"), Model: models.LanguageModel{}.InitialModel()}
 
-	m = MainModel{
-		currentModelIdx: 0,
-		models:          []tea.Model{models.LanguageModel{}.InitialModel()},
+	// Walk new users through the settings before they pick anything
+	if firstRun {
+		return MainModel{router: models.NewRouter(root, models.Screen{Name: i18n.T("screen.setup"), Model: models.NewSettingsModel(cfg, true)})}
 	}
-
-	m.activeModel = m.models[0]
-	return m
+	// In a project with a .decor file, its toolchain comes first
+	if projectFile != "" {
+		return MainModel{router: models.NewRouter(root, models.Screen{Name: i18n.T("screen.project"), Model: models.NewProjectModel(projectFile)})}
+	}
+	return MainModel{router: models.NewRouter(root)}
 }
 
 func (m MainModel) Init() tea.Cmd {
-	return m.activeModel.Init()
+	return m.router.Init()
 }
 
 func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var cmd tea.Cmd
-
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
-			return m, tea.Quit
-		case "n":
-			if m.currentModelIdx+1 > len(m.models)-1 {
-				newModel := models.NewDownloadInstallModel(m.activeModel.(models.Decor).Selections())
-				m.models = append(m.models, newModel)
-				m.activeModel = newModel
-				m.currentModelIdx = len(m.models) - 1
-				return m, newModel.Init()
+	updated, cmd := m.router.Update(msg)
+	m.router = updated.(models.Router)
+	return m, cmd
+}
+
+func (m MainModel) View() string {
+	return m.router.View()
+}
+
+// runStats prints the local install metrics, and how to turn them on when they're off
+func runStats(args []string) error {
+	stats, err := metrics.Load()
+	if err != nil {
+		return err
+	}
+	fmt.Print(metrics.Format(stats))
+	if cfg, _, _ := config.Load(); !cfg.LocalMetrics {
+		fmt.Println("\nLocal metrics are off. Turn them on in the settings (press s) or with local_metrics = true in config.toml; they're kept on this machine and never sent anywhere.")
+	}
+	return nil
+}
+
+// runDaemon serves the engine API on the daemon socket until interrupted
+func runDaemon(args []string) error {
+	configure()
+
+	listener, err := daemon.Listen()
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("Decor daemon listening on %s\n", listener.Addr())
+	return daemon.NewServer().Serve(ctx, listener)
+}
+
+// runServe hosts the web dashboard on the address in args, or the default one, until interrupted
+func runServe(args []string) error {
+	addr := web.DefaultAddr
+	if len(args) > 0 {
+		addr = args[0]
+	}
+	configure()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// The browser can't answer a password prompt, so ask once here and keep the credentials fresh
+	privileged := installer.Privileged()
+	if privileged.NeedsElevation() {
+		fmt.Printf("Some installs need root, asking %s for your password...\n", privileged.Elevator)
+		auth := privileged.AuthCommand()
+		auth.Stdin, auth.Stdout, auth.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := runner.CheckAuth(auth.Run()); err != nil {
+			fmt.Println("Continuing without root, installs that need it will fail.")
+		} else {
+			go privileged.KeepAlive(ctx)
+		}
+	}
+
+	fmt.Printf("Decor dashboard running on http://%s (press ctrl+c to stop)\n", addr)
+	return web.Serve(ctx, addr, daemon.NewServer())
+}
+
+// runSSH creates an ed25519 key if there isn't one, loads it into the agent, points ~/.ssh/config at it,
+// and optionally copies or uploads the public key
+func runSSH(args []string) error {
+	path, err := sshkey.KeyPath()
+	if err != nil {
+		return err
+	}
+	var token string
+	if *sshUpload != "" {
+		// Fail before changing anything if the upload can't happen
+		if token, err = sshkey.Token(*sshUpload); err != nil {
+			return err
+		}
+	}
+	if *dryRun {
+		fmt.Printf("Dry run: would create %s if it's missing, add it to the agent and ~/.ssh/config\n", path)
+		return nil
+	}
+	commands := installer.Privileged()
+
+	if sshkey.Exists(path) {
+		fmt.Printf("Using the existing key %s\n", path)
+	} else {
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return err
+		}
+		hostname, _ := os.Hostname()
+		fmt.Printf("Creating %s (pick a passphrase, or press enter for none)\n", path)
+		if err := sshkey.Generate(commands, path, hostname); err != nil {
+			return err
+		}
+	}
+
+	if err := sshkey.AddToAgent(commands, path); errors.Is(err, sshkey.ErrNoAgent) {
+		fmt.Printf("%s Skipping the agent: %v\n", symbols.Warning, err)
+	} else if err != nil {
+		fmt.Printf("%s %v\n", symbols.Warning, err)
+	}
+
+	if changed, err := sshkey.Configure(path); err != nil {
+		return fmt.Errorf("updating ~/.ssh/config: %w", err)
+	} else if changed {
+		fmt.Println("Added the key to ~/.ssh/config")
+	}
+
+	publicKey, err := sshkey.PublicKey(path)
+	if err != nil {
+		return err
+	}
+	if *sshCopy {
+		if err := sshkey.CopyToClipboard(context.Background(), commands, publicKey); err != nil {
+			fmt.Printf("%s Couldn't copy the public key: %v\n", symbols.Warning, err)
+		} else {
+			fmt.Println("Copied the public key to the clipboard")
+		}
+	}
+	if *sshUpload != "" {
+		hostname, _ := os.Hostname()
+		if err := sshkey.Upload(context.Background(), *sshUpload, token, "decor@"+hostname, publicKey); err != nil {
+			return err
+		}
+		fmt.Printf("Added the public key to your %s account\n", *sshUpload)
+	}
+	fmt.Printf("\nYour public key:\n%s\n", publicKey)
+	return nil
+}
+
+// runPrecommit writes a starter .pre-commit-config.yaml for the languages in the repository at dir and,
+// if pre-commit is installed, installs its Git hook
+func runPrecommit(args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		names, err := precommit.Detect(dir)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Dry run: would write %s:\n\n%s", filepath.Join(dir, precommit.ConfigName), precommit.Render(names))
+		return nil
+	}
+
+	path, names, err := precommit.Write(dir)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		fmt.Printf("Wrote %s with general hooks only; no Go, Python, C/C++ or Kotlin sources found\n", path)
+	} else {
+		fmt.Printf("Wrote %s with hooks for %s\n", path, strings.Join(names, ", "))
+	}
+
+	if _, err := exec.LookPath("pre-commit"); err != nil {
+		fmt.Println("Install pre-commit from the Linting category, then run pre-commit install in the repository")
+		return nil
+	}
+	output, err := installer.Privileged().Run(context.Background(), runner.Spec{
+		Op:   "installing the pre-commit hook",
+		Name: "pre-commit",
+		Args: []string{"install"},
+		Dir:  dir,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(output))
+	return nil
+}
+
+// runNew scaffolds a hello-world project for language at dir, or in the project directory setting, and
+// builds it to prove the toolchain works end to end
+func runNew(args []string) error {
+	if len(args) < 1 {
+		usageError("new needs a language: %s", strings.Join(scaffold.Languages(), ", "))
+	}
+	language, dir := args[0], ""
+	if len(args) > 1 {
+		dir = args[1]
+	}
+	cfg := configure()
+	language, _ = scaffold.Lookup(language)
+	if dir == "" {
+		dir = scaffold.DefaultDir(config.ExpandHome(cfg.ProjectDir), language)
+	}
+
+	fmt.Printf("Creating a %s project in %s...\n", language, dir)
+	output, err := scaffold.Create(context.Background(), installer.Privileged(), language, dir)
+	fmt.Print(string(output))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %s works: the project in %s built and ran\n", symbols.OK, language, dir)
+	return nil
+}
+
+// runGoVersions lists, installs or switches between Go versions downloaded with golang.org/dl wrappers
+func runGoVersions(args []string) error {
+	action := "list"
+	if len(args) > 0 {
+		action = args[0]
+	}
+	if action != "list" && len(args) < 2 {
+		usageError("goversions %s needs a version, like 1.22.5", action)
+	}
+	commands := installer.Privileged()
+
+	switch action {
+	case "list":
+		versions, err := goversions.List()
+		if err != nil {
+			return err
+		}
+		if len(versions) == 0 {
+			fmt.Println("No extra Go versions yet, add one with decor goversions install 1.22.5")
+			return nil
+		}
+		for _, version := range versions {
+			marker := " "
+			if version.Default {
+				marker = "*"
 			}
-			m.currentModelIdx++
-			m.activeModel = m.models[m.currentModelIdx]
+			fmt.Printf("%s %s  %s\n", marker, version.Name, version.Dir)
 		}
+		return nil
+	case "install":
+		fmt.Printf("Installing Go %s next to your other versions...\n", args[1])
+		if err := goversions.Install(context.Background(), commands, args[1]); err != nil {
+			return err
+		}
+		name, _ := goversions.Normalize(args[1])
+		fmt.Printf("Installed %s; run it as %s, or make it the default with decor goversions use %s\n", name, name, args[1])
+		return nil
+	case "use":
+		if err := goversions.Use(commands, args[1]); err != nil {
+			return err
+		}
+		bin, _ := goversions.BinDir()
+		if args[1] == "default" {
+			fmt.Printf("Removed decor's go and gofmt links from %s\n", bin)
+			return nil
+		}
+		fmt.Printf("Linked go and gofmt in %s to Go %s\n", bin, strings.TrimPrefix(args[1], "go"))
+		if path, err := exec.LookPath("go"); err != nil || filepath.Dir(path) != bin {
+			fmt.Printf("%s go on PATH is %s; put %s before it in PATH to use the new default\n", symbols.Warning, path, bin)
+		}
+		return nil
 	}
+	usageError("Unknown goversions action: %s", action)
+	return nil
+}
 
-	updatedModel, cmd := m.activeModel.Update(msg)
-	m.activeModel = updatedModel
-	m.models[m.currentModelIdx] = updatedModel
-	return m, cmd
+// runOutdated lists the tools decor installed that have a newer release and offers to update them all
+func runOutdated(args []string) error {
+	configure()
+	outdated, err := installer.Outdated()
+	if len(outdated) == 0 {
+		if err != nil {
+			return err
+		}
+		fmt.Println("Everything decor installed is up to date")
+		return nil
+	}
+	languages := make([]string, len(outdated))
+	for i, status := range outdated {
+		languages[i] = status.Language
+		if status.Version == status.LatestVersion && status.Reinstallable() {
+			fmt.Printf("  %s %s: %s under Rosetta %s native %s\n", symbols.Update, status.Language, status.Arch, symbols.Arrow, platform.Current().Arch)
+			continue
+		}
+		fmt.Printf("  %s %s: %s %s %s\n", symbols.Update, status.Language, status.Version, symbols.Arrow, status.LatestVersion)
+	}
+
+	choices := make(map[string]string)
+	for _, lang := range languages {
+		choices[lang] = "update"
+	}
+	if !*outdatedYes {
+		touches := installer.Touches(installer.Plan(languages, choices))
+		if len(touches) > 0 {
+			fmt.Println("This changes things outside decor's own files:")
+			for _, lang := range languages {
+				for _, touch := range touches[lang] {
+					fmt.Printf("  %s %s: %s\n", symbols.Warning, lang, touch)
+				}
+			}
+		}
+		fmt.Print("Update them all? [y/N] ")
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if !strings.EqualFold(strings.TrimSpace(answer), "y") {
+			return nil
+		}
+	}
+
+	// What the update touches was listed with the question
+	return apply(languages, choices, true)
 }
 
-func (m MainModel) View() string {
-	return m.activeModel.View()
+// command is one of decor's subcommands
+type command struct {
+	name    string
+	args    string // its arguments, for the usage, e.g. "<item>..."
+	summary string // what it does, in a line
+	maxArgs int    // how many positional arguments it takes; -1 for any number
+	json    bool   // prints JSON instead of text with --json
+	flags   *flag.FlagSet
+	run     func(args []string) error
+}
+
+// decor's own flags, accepted anywhere on the command line and by every subcommand
+var (
+	jsonOutput = flag.Bool("json", false, "emit newline-delimited JSON events instead of the TUI (for headless runs)")
+	dryRun     = flag.Bool("dry-run", false, "log the commands that would change the system instead of running them")
+	ascii      = flag.Bool("ascii", false, "plain ASCII output with no color or emoji, for dumb terminals, screen readers and CI logs")
+	noColor    = flag.Bool("no-color", false, "turn off color, as NO_COLOR does")
+	yes        = flag.Bool("yes", false, "go ahead with deleting or editing files outside decor's own without asking")
+	override   = flag.Bool("override-policy", false, "go ahead with installs your organization's policy forbids, logging each one")
+)
+
+// Flags of single subcommands
+var (
+	sshFlags  = flag.NewFlagSet("ssh", flag.ExitOnError)
+	sshCopy   = sshFlags.Bool("copy", false, "copy the public key to the clipboard")
+	sshUpload = sshFlags.String("upload", "", "add the public key to your github or gitlab account, using GITHUB_TOKEN or GITLAB_TOKEN")
+
+	statusFlags   = flag.NewFlagSet("status", flag.ExitOnError)
+	statusSort    = statusFlags.String("sort", "item", "the column to sort by: "+strings.Join(statusColumns, ", "))
+	statusReverse = statusFlags.Bool("reverse", false, "sort largest first")
+
+	updateFlags = flag.NewFlagSet("update", flag.ExitOnError)
+	updateAll   = updateFlags.Bool("all", false, "update everything decor installed that has a newer release")
+
+	outdatedFlags = flag.NewFlagSet("outdated", flag.ExitOnError)
+	outdatedYes   = outdatedFlags.Bool("y", false, "update everything outdated without asking")
+
+	projectFlags = flag.NewFlagSet("project", flag.ExitOnError)
+	projectEnvrc = projectFlags.Bool("envrc", false, "write an .envrc so direnv switches the project's versions on when you enter it, installing direnv if need be")
+
+	gcFlags = flag.NewFlagSet("gc", flag.ExitOnError)
+	gcKeep  = gcFlags.Int("keep", 0, "versions of each item to keep, the one in use included; keep_versions from config.toml if not given")
+
+	sbomFlags  = flag.NewFlagSet("sbom", flag.ExitOnError)
+	sbomFormat = sbomFlags.String("format", "cyclonedx", "the SBOM format: "+strings.Join(sbom.Formats, ", "))
+)
+
+// commands lists decor's subcommands in the order the usage shows them
+func commands() []command {
+	return []command{
+		{name: "list", summary: "list every item and preset decor can install", run: runList},
+		{name: "status", args: "[--sort column] [--reverse] [item...]", summary: "show every item's installed and latest version and what installed it", maxArgs: -1, flags: statusFlags, run: runStatus},
+		{name: "install", args: "<item>...", summary: "install items or presets and what they need", maxArgs: -1, run: runInstall},
+		{name: "update", args: "--all | <item>...", summary: "update the items given, or everything decor installed that's outdated", maxArgs: -1, flags: updateFlags, run: runUpdate},
+		{name: "remove", args: "<item>...", summary: "uninstall items decor installed", maxArgs: -1, run: runRemove},
+		{name: "rollback", args: "<item>...", summary: "switch items back to the version in use before their last install or update", maxArgs: -1, run: runRollback},
+		{name: "gc", args: "[--keep N]", summary: "delete old versions kept beside archive installs, keeping the most recent and pinned ones", flags: gcFlags, run: runGC},
+		{name: "snapshot", args: "[file]", summary: "save the tools installed here as JSON, for decor diff on another machine", maxArgs: 1, run: runSnapshot},
+		{name: "diff", args: "<snapshot|manifest|host>", summary: "compare the tools here with a snapshot, a manifest or another machine over SSH", maxArgs: 1, json: true, run: runDiff},
+		{name: "check", args: "[manifest]", summary: "check the tools here against a required manifest, installing nothing", maxArgs: 1, json: true, run: runCheck},
+		{name: "ci-check", args: "[manifest]", summary: "check a CI runner has exactly the toolchain a manifest asks for, printing a JSON report and failing otherwise", maxArgs: 1, run: runCICheck},
+		{name: "export-gha", args: "[manifest]", summary: "print GitHub Actions steps setting up a manifest's Go, Python, Java and Node.js with the setup-* actions", maxArgs: 1, run: runExportGHA},
+		{name: "project", args: "[--envrc]", summary: "check the toolchain the project's .decor file asks for, and install what's missing into the project with mise or asdf", flags: projectFlags, run: runProject},
+		{name: "sbom", args: "[--format cyclonedx|spdx] [file]", summary: "write a CycloneDX or SPDX SBOM of what decor installed, with versions, download URLs and checksums", maxArgs: 1, flags: sbomFlags, run: runSBOM},
+		{name: "pins", args: "[item...]", summary: "print [[pins]] for config.toml pinning the exact files decor downloaded for what it installed", maxArgs: -1, run: runPins},
+		{name: "outdated", args: "[-y]", summary: "list what decor installed that has updates, and offer to update it", flags: outdatedFlags, run: runOutdated},
+		{name: "doctor", summary: "diagnose PATH problems, conflicting toolchains, missing compilers, proxies and disk space", run: runDoctor},
+		{name: "verify", summary: "compile and run a tiny program with each installed language, catching broken installs", run: runVerify},
+		{name: "env", args: "[sh|fish|pwsh]", summary: "print the line that loads the variables and PATH decor's installs need, for your shell's startup file", maxArgs: 1, run: runEnv},
+		{name: "clean", summary: "purge downloads left in decor's cache", run: runClean},
+		{name: "stats", summary: "show install counts, failures and durations from the local metrics, flakiest first", run: runStats},
+		{name: "daemon", summary: "run the install engine in the background, taking JSON requests on a Unix socket", run: runDaemon},
+		{name: "serve", args: "[address]", summary: "serve the web dashboard, on " + web.DefaultAddr + " unless given an address", maxArgs: 1, run: runServe},
+		{name: "ssh", args: "[-copy] [-upload github|gitlab]", summary: "create an SSH key, add it to the agent and ~/.ssh/config", flags: sshFlags, run: runSSH},
+		{name: "precommit", args: "[repository]", summary: "set up pre-commit with linters in a repository", maxArgs: 1, run: runPrecommit},
+		{name: "new", args: "<language> [directory]", summary: "create, build and run a hello-world project", maxArgs: 2, run: runNew},
+		{name: "goversions", args: "[list|install <version>|use <version>|use default]", summary: "install extra Go versions side by side and pick the default", maxArgs: 2, run: runGoVersions},
+		{name: "completion", args: "bash|zsh|fish|man", summary: "print a bash, zsh or fish completion script, or the man page", maxArgs: 1, run: runCompletion},
+		{name: "help", summary: "show this help", run: runHelp},
+	}
+}
+
+// synopsis lists decor's command lines
+func synopsis() []string {
+	lines := []string{
+		"decor [--dry-run] [--ascii] [--no-color]",
+		"decor [--dry-run] [--yes] --json <item>...",
+	}
+	for _, c := range commands() {
+		lines = append(lines, strings.TrimSpace("decor "+c.name+" "+c.args))
+	}
+	return lines
+}
+
+// printUsage writes how to run decor, its commands and its flags to w
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: %s\n       %s\n       decor <command> [arguments]\n\nCommands:\n", synopsis()[0], synopsis()[1])
+	for _, c := range commands() {
+		fmt.Fprintf(w, "  %-11s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w, "\nFlags:")
+	flag.CommandLine.SetOutput(w)
+	flag.PrintDefaults()
+}
+
+// runHelp prints the usage
+func runHelp(args []string) error {
+	printUsage(os.Stdout)
+	return nil
+}
+
+// errReported fails a command that has already said why, like doctor finding problems
+var errReported = errors.New("failed")
+
+// usageError prints a problem with the command line and the usage, then exits
+func usageError(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	printUsage(os.Stderr)
+	os.Exit(2)
+}
+
+// parseArgs parses decor's flags wherever they appear among the items, so decor go --json works like
+// decor --json go. It returns the subcommand, if there is one, and the positional arguments; a
+// subcommand's own flags are parsed the same way, along with decor's.
+func parseArgs() (*command, []string) {
+	flag.Parse()
+	flags, args := flag.CommandLine, flag.Args()
+
+	var cmd *command
+	if len(args) > 0 {
+		for _, c := range commands() {
+			if c.name == args[0] {
+				cmd = &c
+				break
+			}
+		}
+	}
+	if cmd != nil {
+		flags = cmd.flags
+		if flags == nil {
+			flags = flag.NewFlagSet(cmd.name, flag.ExitOnError)
+		}
+		flag.VisitAll(func(f *flag.Flag) {
+			if flags.Lookup(f.Name) == nil {
+				flags.Var(f.Value, f.Name, f.Usage)
+			}
+		})
+		flags.Parse(args[1:])
+		args = flags.Args()
+	}
+
+	var positional []string
+	for len(args) > 0 {
+		positional = append(positional, args[0])
+		flags.Parse(args[1:])
+		args = flags.Args()
+	}
+	return cmd, positional
+}
+
+// forcedTheme returns the theme the flags or the terminal ask for over the settings, or nothing
+func forcedTheme(ascii, noColor bool) string {
+	switch {
+	case ascii || os.Getenv("TERM") == "dumb":
+		return "ascii"
+	case noColor || os.Getenv("NO_COLOR") != "":
+		return "mono"
+	}
+	return ""
+}
+
+// openCommandLog opens decor.log in the log directory for appending
+func openCommandLog() (*os.File, error) {
+	dir, err := paths.LogDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(filepath.Join(dir, "decor.log"), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
 }
 
 func main() {
-	fmt.Printf("Welcome to Decor! This tool will help you install ('decorate') your environment with what you need.\n\n")
+	flag.Usage = func() { printUsage(os.Stderr) }
+	cmd, args := parseArgs()
+
+	// The theme and language in the settings apply to every command; flags or the terminal can ask for
+	// another theme
+	models.ForcedTheme = forcedTheme(*ascii, *noColor)
+	prefs, _, _ := config.Load()
+	models.ApplyTheme(prefs.Theme)
+	i18n.SetLocale(i18n.Detect(prefs.Language))
+
+	var commandLog io.Writer = io.Discard
+	var logPath string
+	if logFile, err := openCommandLog(); err == nil {
+		commandLog, logPath = logFile, logFile.Name()
+	}
+	// Headless dry runs are usually read by a person, so show what would have run
+	if *dryRun && *jsonOutput {
+		commandLog = io.MultiWriter(commandLog, os.Stderr)
+	}
+	runner.SetLogOutput(commandLog)
+	installer.SetDryRun(*dryRun)
 
-	MainModel := MainModel{}.InitialModel()
-	p := tea.NewProgram(MainModel)
+	// A policy that can't be read is refused rather than ignored, since it's there to stop things
+	policy, err := config.LoadPolicy()
+	switch {
+	case err != nil && *override:
+		runner.Logf("overriding a policy that can't be read: %v", err)
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "decor: can't read the policy: %v\nAsk whoever manages it, or run decor with --override-policy, which is logged.\n", err)
+		os.Exit(1)
+	}
+	installer.SetPolicy(policy, *override)
 
-	if _, err := p.Run(); err != nil {
+	if cmd != nil {
+		if *jsonOutput && !cmd.json {
+			usageError("--json only applies to installs, run decor %s without it", cmd.name)
+		}
+		if cmd.maxArgs >= 0 && len(args) > cmd.maxArgs {
+			usageError("too many arguments for %s: %s", cmd.name, strings.Join(args[cmd.maxArgs:], " "))
+		}
+		if err := cmd.run(args); err != nil {
+			if !errors.Is(err, errReported) {
+				fmt.Fprintf(os.Stderr, "decor %s: %v\n", cmd.name, err)
+			}
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *jsonOutput {
+		configure()
+		// A dry run changes nothing, so there's nothing to confirm
+		os.Exit(runHeadless(args, *yes || *dryRun))
+	}
+	if len(args) > 0 {
+		usageError("Unknown command: %s", args[0])
+	}
+	runTUI(logPath)
+}
+
+// runTUI runs the terminal UI until the user quits
+func runTUI(logPath string) {
+	fmt.Printf("%s\n\n", i18n.T("welcome"))
+
+	cfg, exists, err := config.Load()
+	if err != nil {
+		fmt.Printf("Could not read settings, using defaults: %v\n\n", err)
+	}
+	if err := models.ApplyConfig(cfg); err != nil {
+		fmt.Printf("Some settings can't be used, so their defaults apply: %v\n\n", err)
+	}
+
+	// The guard saves a report of any panic and quits cleanly, so the terminal isn't left in raw mode
+	var projectFile string
+	if dir, err := os.Getwd(); err == nil {
+		projectFile = project.Find(dir)
+	}
+	guard := crash.NewGuard(MainModel{}.InitialModel(cfg, !exists, projectFile), logPath)
+	options := []tea.ProgramOption{tea.WithoutCatchPanics()}
+	if cfg.Mouse {
+		// Mouse positions are counted from the top of the screen, so the view has to fill it
+		options = append(options, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	}
+	p := tea.NewProgram(guard, options...)
+	guard.SetSend(p.Send)
+
+	_, err = p.Run()
+	if c, crashed := guard.Crashed(); crashed {
+		fmt.Printf("decor crashed: %v\n", c.Value)
+		if c.Err != nil {
+			fmt.Printf("Could not save a crash report (%v), so here's the stack trace:\n\n%s\n", c.Err, c.Stack)
+		} else {
+			fmt.Printf("A crash report was saved to %s, please attach it when you report the problem.\n", c.Report)
+		}
+		os.Exit(1)
+	}
+	if err != nil {
 		fmt.Printf("Alas, there's been an error: %v", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,58 @@
+// Package manifest reads lists of the tools a machine or project needs, one per line with an optional
+// version, like .tool-versions. A version matches as far as it goes, so 1.22 accepts 1.22.3, and >=
+// makes it a minimum:
+//
+//	# the toolchain CI builds with
+//	go 1.22
+//	rust >=1.80
+//	ripgrep
+package manifest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Entry is a tool a manifest asks for, at a version or, with Version empty, any version
+type Entry struct {
+	Name    string
+	Version string
+}
+
+// Parse reads a manifest. Blank lines and everything after a # are ignored.
+func Parse(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(text)
+		switch len(fields) {
+		case 0:
+			continue
+		case 1:
+			entries = append(entries, Entry{Name: fields[0]})
+		case 2:
+			entries = append(entries, Entry{Name: fields[0], Version: fields[1]})
+		default:
+			return nil, fmt.Errorf("line %d: want a tool and maybe a version, got %q", line, strings.TrimSpace(text))
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// Load reads the manifest at path
+func Load(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	entries, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,22 @@
+package manifest
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	entries, err := Parse(strings.NewReader("# the toolchain\ngo 1.22\n\n  ripgrep   # any version\nNode.js 20\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Entry{{"go", "1.22"}, {"ripgrep", ""}, {"Node.js", "20"}}
+	if !slices.Equal(entries, want) {
+		t.Errorf("Parse = %+v, want %+v", entries, want)
+	}
+
+	if _, err := Parse(strings.NewReader("go 1.22\ngo 1.22 extra\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("a line with three fields gave %v", err)
+	}
+}
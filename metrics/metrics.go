@@ -0,0 +1,171 @@
+package metrics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"decor/paths"
+)
+
+// FileName is the metrics file in decor's state directory. It's only ever read by decor stats;
+// nothing in it is sent anywhere.
+const FileName = "metrics.json"
+
+// Counts are how often something was installed or updated, how often that failed, and the time it took
+type Counts struct {
+	Runs     int           `json:"runs"`
+	Failures int           `json:"failures"`
+	Total    time.Duration `json:"total"`
+}
+
+// FailureRate is the share of runs that failed, from 0 to 1
+func (c Counts) FailureRate() float64 {
+	if c.Runs == 0 {
+		return 0
+	}
+	return float64(c.Failures) / float64(c.Runs)
+}
+
+// Average is the mean time a run took
+func (c Counts) Average() time.Duration {
+	if c.Runs == 0 {
+		return 0
+	}
+	return c.Total / time.Duration(c.Runs)
+}
+
+// add counts one run
+func (c *Counts) add(elapsed time.Duration, failed bool) {
+	c.Runs++
+	c.Total += elapsed
+	if failed {
+		c.Failures++
+	}
+}
+
+// Stats are the metrics collected so far, by item and by installer (e.g. "apt", "script")
+type Stats struct {
+	Version    int               `json:"version"`
+	Since      time.Time         `json:"since"` // when the first run was counted
+	Items      map[string]Counts `json:"items"`
+	Installers map[string]Counts `json:"installers"`
+}
+
+// mu serializes read-modify-write cycles, since items install concurrently
+var mu sync.Mutex
+
+// Path returns the metrics file's location
+func Path() (string, error) {
+	dir, err := paths.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// load reads the metrics, returning empty ones if there aren't any yet; the caller holds mu
+func load() (Stats, error) {
+	stats := Stats{Version: 1, Items: make(map[string]Counts), Installers: make(map[string]Counts)}
+	path, err := Path()
+	if err != nil {
+		return stats, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return stats, nil
+	}
+	if err != nil {
+		return stats, err
+	}
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return stats, err
+	}
+	if stats.Items == nil {
+		stats.Items = make(map[string]Counts)
+	}
+	if stats.Installers == nil {
+		stats.Installers = make(map[string]Counts)
+	}
+	return stats, nil
+}
+
+// Load returns the metrics collected so far
+func Load() (Stats, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	return load()
+}
+
+// Record counts an install or update of item with installer that took elapsed
+func Record(item, installer string, elapsed time.Duration, failed bool) error {
+	mu.Lock()
+	defer mu.Unlock()
+	stats, err := load()
+	if err != nil {
+		return err
+	}
+	if stats.Since.IsZero() {
+		stats.Since = time.Now().UTC().Truncate(time.Second)
+	}
+	counts := stats.Items[item]
+	counts.add(elapsed, failed)
+	stats.Items[item] = counts
+	if installer != "" {
+		counts := stats.Installers[installer]
+		counts.add(elapsed, failed)
+		stats.Installers[installer] = counts
+	}
+
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Format renders the metrics as two tables, by installer and by item, flakiest first
+func Format(stats Stats) string {
+	if len(stats.Items) == 0 {
+		return "No installs counted yet.\n"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Since %s\n", stats.Since.Local().Format("2 Jan 2006"))
+	table(&b, "Installer", stats.Installers)
+	table(&b, "Item", stats.Items)
+	return b.String()
+}
+
+// table writes one table of counts, sorted by failure rate and then by name
+func table(b *strings.Builder, heading string, counts map[string]Counts) {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, c := counts[names[i]].FailureRate(), counts[names[j]].FailureRate()
+		if a != c {
+			return a > c
+		}
+		return names[i] < names[j]
+	})
+	fmt.Fprintf(b, "\n%-20s %6s %8s %8s %10s\n", heading, "Runs", "Failed", "Rate", "Average")
+	for _, name := range names {
+		c := counts[name]
+		fmt.Fprintf(b, "%-20s %6d %8d %7.0f%% %10s\n", name, c.Runs, c.Failures, 100*c.FailureRate(), c.Average().Round(time.Second))
+	}
+}
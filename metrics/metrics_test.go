@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// tempState points the state directory at a fresh temporary directory
+func tempState(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_STATE_HOME", dir)
+	t.Setenv("LOCALAPPDATA", dir)
+}
+
+func TestRecord(t *testing.T) {
+	tempState(t)
+
+	runs := []struct {
+		item, installer string
+		elapsed         time.Duration
+		failed          bool
+	}{
+		{"Go", "tarball", 30 * time.Second, false},
+		{"Go", "tarball", 50 * time.Second, true},
+		{"ripgrep", "apt", 10 * time.Second, false},
+		{"Java", "tarball", 60 * time.Second, false},
+	}
+	for _, run := range runs {
+		if err := Record(run.item, run.installer, run.elapsed, run.failed); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Since.IsZero() {
+		t.Error("the first run's time wasn't kept")
+	}
+	if got := stats.Items["Go"]; got.Runs != 2 || got.Failures != 1 || got.Average() != 40*time.Second {
+		t.Errorf("Go counts %+v", got)
+	}
+	if got := stats.Installers["tarball"]; got.Runs != 3 || got.Failures != 1 || got.Total != 140*time.Second {
+		t.Errorf("tarball counts %+v", got)
+	}
+
+	out := Format(stats)
+	// The flakiest installer and item come first
+	if strings.Index(out, "tarball") > strings.Index(out, "apt") || strings.Index(out, "Go ") > strings.Index(out, "Java") {
+		t.Errorf("tables aren't sorted by failure rate:\n%s", out)
+	}
+	if !strings.Contains(out, "50%") {
+		t.Errorf("Go's failure rate is missing:\n%s", out)
+	}
+}
+
+func TestFormatEmpty(t *testing.T) {
+	tempState(t)
+	stats, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := Format(stats); got != "No installs counted yet.\n" {
+		t.Errorf("Format of no metrics = %q", got)
+	}
+}
@@ -1,12 +1,17 @@
 package models
 
 import (
+	"decor/catalog"
+	"decor/installer"
+
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 type Decor struct {
 	tea.Model
+	presets  []catalog.Preset // listed above the items; the cursor covers both
 	choices  []string
 	cursor   int
-	Selected map[int]struct{}
+	Selected map[int]struct{}                // indexes into choices
+	outdated []*installer.InstallationStatus // decor-managed items with a newer release, from the update check
 }
@@ -1,161 +1,663 @@
 package models
 
 import (
-	"crypto/tls"
+	"context"
 	"fmt"
-	"net/http"
-	"os/exec"
-	"runtime"
+	"slices"
 	"strings"
-	"sync"
 	"time"
 
+	"decor/catalog"
+	"decor/config"
+	"decor/daemon"
+	"decor/doctor"
+	"decor/download"
+	"decor/i18n"
+	"decor/installer"
+	"decor/keymap"
+	"decor/releasenotes"
+	"decor/runner"
+	"decor/scaffold"
+	"decor/symbols"
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
-// InstallationStatus represents the status of a language installation
-type InstallationStatus struct {
-	Language      string
-	Installed     bool
-	Version       string
-	LatestVersion string
-	Error         string
-}
-
-// LanguageProgress tracks download/install progress for a language
-type LanguageProgress struct {
-	Language       string
-	Progress       float64 // 0.0 to 1.0
-	CurrentStep    string  // "downloading", "installing", "complete", "error"
-	TotalSteps     int
-	CurrentStepNum int
-	ErrorMessage   string
-	mu             sync.Mutex
-}
-
-// ProgressUpdateMsg is sent when progress changes
-type ProgressUpdateMsg struct {
-	Language string
-	Progress float64
-	Step     string
-}
-
 // DownloadInstallModel manages the installation flow
 type DownloadInstallModel struct {
 	Decor
 	selectedLanguages  []string
-	installationStatus map[string]*InstallationStatus
+	installationStatus map[string]*installer.InstallationStatus
 	currentIndex       int
-	state              string            // "checking", "prompting", "installing", "complete"
-	userChoices        map[string]string // "skip" or "install" or "update"
-	languageProgress   map[string]*LanguageProgress
+	state              string                                // "checking", "prompting", "conflicts", "plan", "confirming", "preflight", "authenticating", "installing", "complete"
+	userChoices        map[string]string                     // "skip" or "install" or "update"
+	progress           map[string]installer.ProgressSnapshot // the latest snapshot of each item, from ProgressMsg or the daemon
+	results            map[string]string                     // each item's outcome once the run is complete: "installed", "skipped", "error: ..."
+	preflightResults   []doctor.Result
+	spinnerFrame       int
+	authError          error
+	client             *daemon.Client // set when a decor daemon is running, which then does the work
+	runError           error
+	followUps          []string // items offered once the install is complete, e.g. language servers
+	summaryCursor      int      // the row under the cursor on the summary: the failed items, then the follow-ups
+	followUpSelected   map[string]bool
+	components         map[string][]string // optional components picked for each item, shown as a checklist while prompting
+	componentCursor    int
+	logins             map[string]string    // login state of installed items that have one: "checking", "logged in", ...
+	projects           map[string]string    // hello-world projects for installed languages: "offered", "creating", "created" or "failed"
+	projectNotes       map[string]string    // where each project was created, or why it failed
+	projectDir         string               // the project directory setting, as the user wrote it
+	presetChoices      map[string]string    // choices made before checking, e.g. by update all, which skip the prompts
+	releaseNotes       map[string]string    // release notes for languages that can be updated, shown while prompting
+	notesFetched       map[string]time.Time // when each language's releases were fetched, a while ago if they were cached
+	notesScroll        int                  // first line of the release notes pane
+	addedDeps          map[string]string    // prerequisites added to the selection, and the item that needs each
+	conflicts          []installer.Conflict // clashes among the choices still to be resolved, the first one shown
+	plan               []installer.Action   // what applying will do, shown for confirmation
+	planSized          bool                 // the plan's download and disk sizes have been estimated
+	collapsed          map[string]bool      // categories folded into one line while installing, or unfolded, once toggled
+	progressCursor     int                  // the row of the install progress under the cursor, from progressRows
+	queued             []string             // items yet to start installing, in the order they will
+	paused             bool                 // the queue's paused, so nothing new starts installing
+	queueError         error                // why the last change to the queue couldn't be made
+	started            time.Time            // when installing began
+	elapsed            time.Duration        // wall-clock time of the whole run, once complete
 }
 
 // NewDownloadInstallModel creates a new download/install model
 func NewDownloadInstallModel(selectedLanguages []string) DownloadInstallModel {
+	client, _ := daemon.Dial()
+	// Prerequisites that are already installed default to skip once they're checked
+	selectedLanguages, added := installer.WithDependencies(selectedLanguages)
 	return DownloadInstallModel{
 		selectedLanguages:  selectedLanguages,
-		installationStatus: make(map[string]*InstallationStatus),
+		addedDeps:          added,
+		installationStatus: make(map[string]*installer.InstallationStatus),
 		userChoices:        make(map[string]string),
-		languageProgress:   make(map[string]*LanguageProgress),
+		progress:           make(map[string]installer.ProgressSnapshot),
+		followUpSelected:   make(map[string]bool),
+		components:         make(map[string][]string),
+		logins:             make(map[string]string),
+		projects:           make(map[string]string),
+		projectNotes:       make(map[string]string),
+		releaseNotes:       make(map[string]string),
+		notesFetched:       make(map[string]time.Time),
+		collapsed:          make(map[string]bool),
 		state:              "checking",
+		client:             client,
 	}
 }
 
+// NewUpdateAllModel creates a download/install model that updates every given language without prompting
+func NewUpdateAllModel(languages []string) DownloadInstallModel {
+	m := NewDownloadInstallModel(languages)
+	m.presetChoices = make(map[string]string)
+	for _, lang := range languages {
+		m.presetChoices[lang] = "update"
+	}
+	return m
+}
+
 func (m DownloadInstallModel) Init() tea.Cmd {
 	return tea.Batch(
-		checkInstalledLanguages(m.selectedLanguages),
+		checkInstalledLanguages(m.client, m.selectedLanguages),
+		checkSpinnerTicker(),
 	)
 }
 
+// paneBorder is the border drawn around scrolling panes, in plain ASCII if symbols are
+func paneBorder() lipgloss.Border {
+	if symbols.ASCII() {
+		return lipgloss.Border{Top: "-", Bottom: "-", Left: "|", Right: "|", TopLeft: "+", TopRight: "+", BottomLeft: "+", BottomRight: "+"}
+	}
+	return lipgloss.RoundedBorder()
+}
+
+// move moves the cursor through the components of the item being prompted, or else scrolls its notes, or
+// moves the cursor through the failed items and follow-ups on the summary, by delta lines
+func (m *DownloadInstallModel) move(delta int) {
+	switch {
+	case m.state == "complete" && len(m.failedItems())+len(m.followUps) > 0:
+		m.summaryCursor = max(0, min(m.summaryCursor+delta, len(m.failedItems())+len(m.followUps)-1))
+	case m.state == "installing":
+		m.progressCursor = max(0, min(m.progressCursor+delta, len(m.progressRows())-1))
+	case m.state == "prompting" && m.currentIndex < len(m.selectedLanguages) && len(installer.Components(m.selectedLanguages[m.currentIndex])) > 0:
+		components := installer.Components(m.selectedLanguages[m.currentIndex])
+		m.componentCursor = max(0, min(m.componentCursor+delta, len(components)-1))
+	case m.state == "prompting" && m.currentIndex < len(m.selectedLanguages):
+		lines := m.notesLines(m.selectedLanguages[m.currentIndex])
+		m.notesScroll = max(0, min(m.notesScroll+delta, len(lines)-notesHeight))
+	}
+}
+
+// Keys only the install screen uses, on top of the shared ones
+var (
+	yesKey     = keymap.NewBinding("y")
+	skipKey    = keymap.NewBinding("n", "s")
+	installKey = keymap.NewBinding("i", "r")
+	updateKey  = keymap.NewBinding("u")
+	resolveKey = keymap.NewBinding("1", "2", "b")
+	applyKey   = keymap.NewBinding("a")
+	loginKey   = keymap.NewBinding("l")
+	projectKey = keymap.NewBinding("p")
+	earlierKey = keymap.NewBinding("+", "=")
+	laterKey   = keymap.NewBinding("-")
+	pauseKey   = keymap.NewBinding("p")
+)
+
 func (m DownloadInstallModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
+		switch {
+		case keymap.Matches(msg, Keys.Quit):
 			return m, tea.Quit
-		case "y", "enter":
+		case keymap.Matches(msg, Keys.Back):
+			// Back to the screen this was opened from, e.g. to pick more items
+			if m.state == "complete" {
+				return m, Pop(nil)
+			}
+			if m.state == "confirming" {
+				m.state = "plan"
+			}
+		case keymap.Matches(msg, Keys.Up):
+			m.move(-1)
+		case keymap.Matches(msg, Keys.Down):
+			m.move(1)
+		case m.state == "installing" && m.queueKey(msg):
+			return m.changeQueue(msg)
+		case keymap.Matches(msg, loginKey):
+			if m.state == "complete" {
+				return m, m.startLogin()
+			}
+		case keymap.Matches(msg, projectKey):
+			if m.state == "complete" {
+				return m, m.createProjects()
+			}
+		case keymap.Matches(msg, Keys.Toggle):
+			if i := m.summaryCursor - len(m.failedItems()); m.state == "complete" && i >= 0 && i < len(m.followUps) {
+				name := m.followUps[i]
+				m.followUpSelected[name] = !m.followUpSelected[name]
+			}
 			if m.state == "prompting" {
-				m.userChoices[m.selectedLanguages[m.currentIndex]] = getDefaultChoice(m.installationStatus[m.selectedLanguages[m.currentIndex]])
-				m.currentIndex++
-				if m.currentIndex >= len(m.selectedLanguages) {
-					m.state = "installing"
-					return m, installSelectedLanguagesWithProgress(m.selectedLanguages, m.userChoices, m.installationStatus)
-				}
+				m.toggleComponent()
+			}
+		case keymap.Matches(msg, Keys.Confirm, yesKey):
+			if failed := m.failedItems(); m.state == "complete" && m.summaryCursor < len(failed) {
+				lang := failed[m.summaryCursor]
+				return m, Push(i18n.T("screen.log"), NewLogViewModel(lang, m.progress[lang]))
+			}
+			if m.state == "complete" {
+				return m.installFollowUps()
+			}
+			if m.state == "preflight" && m.preflightResults != nil {
+				return m.startInstallation()
+			}
+			// Only y goes ahead with what the confirmation lists, so a stray enter doesn't
+			if m.state == "confirming" && keymap.Matches(msg, yesKey) {
+				m.state = "preflight"
+				return m, runPreflight(m.selectedLanguages, m.userChoices)
 			}
-		case "n":
 			if m.state == "prompting" {
-				m.userChoices[m.selectedLanguages[m.currentIndex]] = "skip"
-				m.currentIndex++
-				if m.currentIndex >= len(m.selectedLanguages) {
-					m.state = "installing"
-					return m, installSelectedLanguagesWithProgress(m.selectedLanguages, m.userChoices, m.installationStatus)
-				}
+				return m.choose(installer.DefaultChoice(m.installationStatus[m.selectedLanguages[m.currentIndex]]))
 			}
-		case "u":
+		case keymap.Matches(msg, skipKey):
 			if m.state == "prompting" {
-				m.userChoices[m.selectedLanguages[m.currentIndex]] = "update"
-				m.currentIndex++
-				if m.currentIndex >= len(m.selectedLanguages) {
-					m.state = "installing"
-					return m, installSelectedLanguagesWithProgress(m.selectedLanguages, m.userChoices, m.installationStatus)
-				}
+				return m.choose("skip")
+			}
+		case keymap.Matches(msg, resolveKey):
+			if m.state == "conflicts" {
+				return m.resolve(msg.String())
+			}
+		case keymap.Matches(msg, applyKey):
+			// Nothing changes until the plan is applied, and what it deletes or edits is confirmed
+			if m.state == "plan" && len(installer.Touches(m.plan)) > 0 {
+				m.state = "confirming"
+				return m, nil
+			}
+			if m.state == "plan" {
+				m.state = "preflight"
+				return m, runPreflight(m.selectedLanguages, m.userChoices)
+			}
+		case keymap.Matches(msg, installKey):
+			// Reinstalling runs the same steps as a fresh install
+			if m.state == "prompting" {
+				return m.choose("install")
+			}
+		case keymap.Matches(msg, updateKey):
+			if m.state == "prompting" {
+				return m.choose("update")
+			}
+		case keymap.Matches(msg, Keys.Refresh):
+			if m.state == "prompting" {
+				return m.refresh()
 			}
 		}
+	case tea.MouseMsg:
+		m.move(wheel(msg))
+	case checkTickMsg:
+		if m.state == "checking" {
+			m.spinnerFrame++
+			return m, checkSpinnerTicker()
+		}
+	case CheckResultMsg:
+		m.installationStatus[msg.Status.Language] = msg.Status
+		return m, waitForCheck(msg.results)
+	case checksDoneMsg:
+		return m.checked()
 	case InstallationStatusMsg:
 		m.installationStatus = msg.Status
-		m.state = "prompting"
+		return m.checked()
+	case PreflightMsg:
+		m.preflightResults = msg.Results
+		if doctor.AllOK(msg.Results) {
+			return m.startInstallation()
+		}
+	case AuthResultMsg:
+		if msg.Err != nil {
+			m.authError = msg.Err
+			return m, nil
+		}
+		m.state = "installing"
+		m.started = time.Now()
+		return m, installSelectedLanguagesWithProgress(m.selectedLanguages, m.userChoices, m.installationStatus)
 	case InitProgressMsg:
-		m.languageProgress = msg.Trackers
-		return m, progressUpdateTicker()
-	case ProgressTickMsg:
-		// Check if any language is still installing
-		allComplete := true
-		for _, prog := range m.languageProgress {
-			prog.mu.Lock()
-			if prog.Progress < 1.0 && prog.CurrentStep != "error" {
-				allComplete = false
-			}
-			prog.mu.Unlock()
+		if msg.updates == nil {
+			return m, progressUpdateTicker(m.client)
+		}
+		m.progress = msg.Progress
+		return m, tea.Batch(waitForProgress(msg.updates, msg.results), progressUpdateTicker(nil))
+	case ProgressMsg:
+		m.progress[msg.Snapshot.Language] = msg.Snapshot
+		return m, waitForProgress(msg.updates, msg.results)
+	case DaemonErrorMsg:
+		m.runError = msg.Err
+		return m, m.finish()
+	case QueueChangedMsg:
+		m.queueError = msg.Err
+		if msg.Err == nil {
+			m.queued, m.paused = msg.Queued, msg.Paused
 		}
-		if allComplete {
-			m.state = "complete"
+	case PlanSizedMsg:
+		if m.state == "plan" {
+			m.plan, m.planSized = msg.Plan, true
+		}
+	case LatestRefreshedMsg:
+		if msg.Status != nil {
+			m.installationStatus[msg.Status.Language] = msg.Status
+		}
+	case ReleaseNotesMsg:
+		m.releaseNotes[msg.Language] = msg.Text
+		if !msg.Fetched.IsZero() {
+			m.notesFetched[msg.Language] = msg.Fetched
+		}
+	case LoginStatusMsg:
+		if msg.LoggedIn {
+			m.logins[msg.Item] = "logged in"
+		} else if m.logins[msg.Item] != "login failed" {
+			m.logins[msg.Item] = "not logged in"
+		}
+	case ProjectDoneMsg:
+		if msg.Err != nil {
+			m.projects[msg.Language] = "failed"
+			m.projectNotes[msg.Language] = msg.Err.Error()
+		} else {
+			m.projects[msg.Language] = "created"
+			m.projectNotes[msg.Language] = msg.Dir
+		}
+	case LoginDoneMsg:
+		if msg.Err != nil {
+			m.logins[msg.Item] = "login failed"
+		}
+		return m, checkLogin(msg.Item)
+	case ProgressTickMsg:
+		// The ticker stops once the install is over
+		if m.state != "installing" {
 			return m, nil
 		}
-		return m, progressUpdateTicker()
-	case ProgressUpdateMsg:
-		if progress, exists := m.languageProgress[msg.Language]; exists {
-			progress.mu.Lock()
-			progress.Progress = msg.Progress
-			progress.CurrentStep = msg.Step
-			progress.mu.Unlock()
+		m.spinnerFrame++
+		m.queued, m.paused = msg.Queued, msg.Paused
+		if m.client != nil {
+			m.progress = msg.Progress
+			if msg.Done {
+				m.results = msg.Results
+				return m, m.finish()
+			}
 		}
-		return m, progressUpdateTicker()
+		return m, progressUpdateTicker(m.client)
 	case InstallCompleteMsg:
-		m.state = "complete"
-		return m, nil
+		if m.state != "installing" {
+			return m, nil
+		}
+		m.results = msg.Results
+		return m, m.finish()
 	case InstallErrorMsg:
 		return m, nil
 	}
 	return m, nil
 }
 
+// checked moves on from checking: to the prompts, or straight to the pre-flight checks when the choices
+// were made up front
+func (m DownloadInstallModel) checked() (tea.Model, tea.Cmd) {
+	for _, lang := range m.selectedLanguages {
+		m.components[lang] = installer.PickedComponents(lang)
+	}
+	if m.presetChoices == nil {
+		m.state = "prompting"
+		return m, m.fetchReleaseNotes(false)
+	}
+	for _, lang := range m.selectedLanguages {
+		choice, ok := m.presetChoices[lang]
+		if !ok {
+			choice = installer.DefaultChoice(m.installationStatus[lang])
+		}
+		m.userChoices[lang] = choice
+	}
+	m.currentIndex = len(m.selectedLanguages)
+	return m.review()
+}
+
+// choose records the choice for the language being prompted and moves on to the next one, or to the
+// pre-flight checks after the last
+func (m DownloadInstallModel) choose(choice string) (tea.Model, tea.Cmd) {
+	m.userChoices[m.selectedLanguages[m.currentIndex]] = choice
+	m.currentIndex++
+	m.notesScroll = 0
+	m.componentCursor = 0
+	if m.currentIndex >= len(m.selectedLanguages) {
+		return m.review()
+	}
+	return m, m.fetchReleaseNotes(false)
+}
+
+// review moves on once every choice is made: to resolving any conflicts among them, then to the plan
+func (m DownloadInstallModel) review() (tea.Model, tea.Cmd) {
+	if err := m.saveComponents(); err != nil {
+		m.runError = fmt.Errorf("couldn't save the picked components: %w", err)
+	}
+	m.conflicts = installer.Conflicts(m.selectedLanguages, m.userChoices)
+	if len(m.conflicts) > 0 {
+		m.state = "conflicts"
+		return m, nil
+	}
+	return m.showPlan()
+}
+
+// PlanSizedMsg carries the plan with its download and disk sizes estimated
+type PlanSizedMsg struct {
+	Plan []installer.Action
+}
+
+// sizeEstimateTimeout bounds estimating the plan's sizes, which is only informative
+const sizeEstimateTimeout = 15 * time.Second
+
+// showPlan moves to the plan screen and starts estimating its sizes
+func (m DownloadInstallModel) showPlan() (tea.Model, tea.Cmd) {
+	m.state = "plan"
+	m.plan = installer.Plan(m.selectedLanguages, m.userChoices)
+	m.planSized = false
+	plan := slices.Clone(m.plan)
+	return m, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), sizeEstimateTimeout)
+		defer cancel()
+		installer.EstimateSizes(ctx, plan)
+		return PlanSizedMsg{Plan: plan}
+	}
+}
+
+// resolve settles the conflict being shown by skipping one side ("1" keeps the first item, "2" the second)
+// or keeping both ("b"), dropping later conflicts a skip settled too
+func (m DownloadInstallModel) resolve(key string) (tea.Model, tea.Cmd) {
+	conflict := m.conflicts[0]
+	switch key {
+	case "1":
+		m.userChoices[conflict.Second] = "skip"
+	case "2":
+		m.userChoices[conflict.First] = "skip"
+	}
+
+	var remaining []installer.Conflict
+	for _, c := range m.conflicts[1:] {
+		if m.userChoices[c.First] != "skip" && m.userChoices[c.Second] != "skip" {
+			remaining = append(remaining, c)
+		}
+	}
+	m.conflicts = remaining
+	if len(m.conflicts) == 0 {
+		return m.showPlan()
+	}
+	return m, nil
+}
+
+// ReleaseNotesMsg carries the release notes for a language that can be updated
+type ReleaseNotesMsg struct {
+	Language string
+	Text     string
+	Fetched  time.Time // when the releases were fetched, or zero when they couldn't be
+}
+
+// releaseNotesTimeout bounds fetching release notes, which mustn't hold up the prompt for long
+const releaseNotesTimeout = 10 * time.Second
+
+// fetchReleaseNotes fetches the release notes for the language being prompted, if it can be updated and
+// they haven't been fetched yet. Releases fetched lately come from the cache unless refresh is set.
+func (m DownloadInstallModel) fetchReleaseNotes(refresh bool) tea.Cmd {
+	lang := m.selectedLanguages[m.currentIndex]
+	status := m.installationStatus[lang]
+	item, ok := catalog.Find(lang)
+	if _, fetched := m.releaseNotes[lang]; fetched || !ok || status == nil || !offersUpdate(status) {
+		return nil
+	}
+	if item.Repo == "" {
+		if item.Notes != "" {
+			m.releaseNotes[lang] = i18n.T("install.release_notes_link", item.Notes)
+		}
+		return nil
+	}
+
+	m.releaseNotes[lang] = i18n.T("install.release_notes_fetching")
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), releaseNotesTimeout)
+		defer cancel()
+		if refresh {
+			ctx = download.Refresh(ctx)
+		}
+		releases, fetched, err := releasenotes.Newer(ctx, item.Repo, status.Version)
+		switch {
+		case err != nil:
+			return ReleaseNotesMsg{Language: lang, Text: i18n.T("install.release_notes_failed", err, item.Repo)}
+		case len(releases) == 0:
+			return ReleaseNotesMsg{Language: lang, Text: i18n.T("install.release_notes_none", item.Repo), Fetched: fetched}
+		}
+		return ReleaseNotesMsg{Language: lang, Text: releasenotes.Format(releases), Fetched: fetched}
+	}
+}
+
+// refresh looks up the latest version of the language being prompted and its release notes again,
+// asking upstream rather than the cache, for whichever were looked up online
+func (m DownloadInstallModel) refresh() (tea.Model, tea.Cmd) {
+	if m.currentIndex >= len(m.selectedLanguages) {
+		return m, nil
+	}
+	lang := m.selectedLanguages[m.currentIndex]
+	var cmds []tea.Cmd
+	if status := m.installationStatus[lang]; status != nil && !status.LatestFetched.IsZero() {
+		cmds = append(cmds, refreshLatest(lang))
+	}
+	if _, ok := m.notesFetched[lang]; ok {
+		delete(m.releaseNotes, lang)
+		delete(m.notesFetched, lang)
+		m.notesScroll = 0
+		cmds = append(cmds, m.fetchReleaseNotes(true))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// LatestRefreshedMsg carries a language's status checked again once its latest version was looked up
+type LatestRefreshedMsg struct {
+	Status *installer.InstallationStatus
+}
+
+// refreshLatest looks up the latest version of lang upstream and checks it again against that
+func refreshLatest(lang string) tea.Cmd {
+	return func() tea.Msg {
+		installer.RefreshLatest(context.Background(), lang)
+		return LatestRefreshedMsg{Status: <-installer.CheckStream([]string{lang})}
+	}
+}
+
+// roughAge is how old something fetched d ago is, to the largest whole unit: "45m", "2h" or "3d"
+func roughAge(d time.Duration) string {
+	switch {
+	case d >= 48*time.Hour:
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	}
+	return fmt.Sprintf("%dm", int(d/time.Minute))
+}
+
+// offersUpdate reports whether the prompt for status offers an update
+func offersUpdate(status *installer.InstallationStatus) bool {
+	return status.Installed && !status.TimedOut && status.Version != status.LatestVersion
+}
+
+// notesHeight is how many lines of release notes the pane shows at once
+const notesHeight = 12
+
+// notesLines returns the language's release notes wrapped to fit the pane, or nil if there are none
+func (m DownloadInstallModel) notesLines(lang string) []string {
+	text, ok := m.releaseNotes[lang]
+	if !ok {
+		return nil
+	}
+	return strings.Split(strings.TrimRight(lipgloss.NewStyle().Width(76).Render(text), "\n"), "\n")
+}
+
+// toggleComponent picks or unpicks the component under the cursor for the item being prompted
+func (m *DownloadInstallModel) toggleComponent() {
+	if m.currentIndex >= len(m.selectedLanguages) {
+		return
+	}
+	lang := m.selectedLanguages[m.currentIndex]
+	components := installer.Components(lang)
+	if m.componentCursor >= len(components) {
+		return
+	}
+	// The picks stay in the checklist's order, so unchanged picks compare equal to the settings'
+	toggled := components[m.componentCursor].Name
+	var picked []string
+	for _, c := range components {
+		if slices.Contains(m.components[lang], c.Name) != (c.Name == toggled) {
+			picked = append(picked, c.Name)
+		}
+	}
+	m.components[lang] = picked
+}
+
+// renderComponents shows the item's optional components as a checklist, or nothing if it has none
+func (m DownloadInstallModel) renderComponents(lang string) string {
+	components := installer.Components(lang)
+	if len(components) == 0 {
+		return ""
+	}
+	descriptionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")) // Gray
+
+	output := "\n" + i18n.T("install.components_title") + "\n"
+	for i, c := range components {
+		cursor := " "
+		if m.componentCursor == i {
+			cursor = ">"
+		}
+		checked := " "
+		if slices.Contains(m.components[lang], c.Name) {
+			checked = "x"
+		}
+		output += fmt.Sprintf("%s [%s] %s %s\n", cursor, checked, c.Name, descriptionStyle.Render(c.Description))
+	}
+	return output + i18n.T("install.components_help", Keys.Toggle.Help(), Keys.Up.Help(), Keys.Down.Help()) + "\n"
+}
+
+// saveComponents keeps the components picked for what's being installed or updated in the settings, so
+// the installers, and a daemon doing the work, use them, and the next run starts from them. Nothing is
+// written if the picks didn't change.
+func (m DownloadInstallModel) saveComponents() error {
+	cfg, _, err := config.Load()
+	if err != nil {
+		return err
+	}
+	changed := false
+	for _, lang := range m.selectedLanguages {
+		choice := m.userChoices[lang]
+		picked, ok := m.components[lang]
+		if !ok || choice == "skip" || choice == "remove" || len(installer.Components(lang)) == 0 {
+			continue
+		}
+		if slices.Equal(picked, installer.PickedComponents(lang)) {
+			continue
+		}
+		if cfg.Components == nil {
+			cfg.Components = make(map[string][]string)
+		}
+		cfg.Components[strings.ToLower(lang)] = append([]string{}, picked...)
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	if err := config.Save(cfg); err != nil {
+		return err
+	}
+	ApplyConfig(cfg)
+	return nil
+}
+
+// renderReleaseNotes shows the language's release notes in a scrollable pane, or nothing if there are none
+func (m DownloadInstallModel) renderReleaseNotes(lang string) string {
+	lines := m.notesLines(lang)
+	if lines == nil {
+		return ""
+	}
+	first := min(m.notesScroll, max(len(lines)-notesHeight, 0))
+	last := min(first+notesHeight, len(lines))
+
+	pane := lipgloss.NewStyle().Border(paneBorder()).Padding(0, 1).Render(strings.Join(lines[first:last], "\n"))
+	output := "\n" + pane + "\n"
+	if len(lines) > notesHeight {
+		output += i18n.T("install.notes_lines", first+1, last, len(lines), Keys.Up.Help(), Keys.Down.Help()) + "\n"
+	}
+	// Releases from the cache say how old they are, as GitHub may have published another since
+	if fetched, ok := m.notesFetched[lang]; ok && time.Since(fetched) >= time.Minute {
+		output += i18n.T("install.release_notes_cached", roughAge(time.Since(fetched)), Keys.Refresh.Help()) + "\n"
+	}
+	return output
+}
+
 func (m DownloadInstallModel) View() string {
 	switch m.state {
 	case "checking":
-		return "Checking installed languages...\n"
+		output := "\n" + i18n.T("install.checking", len(m.installationStatus), len(m.selectedLanguages)) + "\n"
+		for _, lang := range m.selectedLanguages {
+			if status := m.installationStatus[lang]; status != nil {
+				output += formatStatusLine(m.label(lang), status)
+				continue
+			}
+			output += fmt.Sprintf("  %s %s\n", symbols.Spinner(m.spinnerFrame), i18n.T("install.checking_item", m.label(lang)))
+		}
+		return output
 	case "prompting":
 		var output string
 
 		// Show all checked languages and their status
-		output += "\n=== Installation Status ===\n"
+		output += "\n" + i18n.T("install.status_title") + "\n"
 		for _, lang := range m.selectedLanguages {
 			status := m.installationStatus[lang]
 			if status == nil {
 				continue
 			}
-			output += formatStatusLine(lang, status)
+			output += formatStatusLine(m.label(lang), status)
 		}
 
 		output += "\n"
@@ -166,23 +668,551 @@ func (m DownloadInstallModel) View() string {
 		}
 		lang := m.selectedLanguages[m.currentIndex]
 		status := m.installationStatus[lang]
-		output += formatPrompt(lang, status)
+		output += formatPrompt(m.label(lang), status)
+		// A latest version from the cache says how old it is, as another may have been released since
+		if status.Installed && !status.LatestFetched.IsZero() && time.Since(status.LatestFetched) >= time.Minute {
+			output += i18n.T("install.latest_cached", roughAge(time.Since(status.LatestFetched)), Keys.Refresh.Help()) + "\n"
+		}
+		output += m.renderComponents(lang)
+		output += m.renderReleaseNotes(lang)
+		return output
+	case "conflicts":
+		c := m.conflicts[0]
+		return "\n" + i18n.T("install.conflict", symbols.Warning, c.First, c.Second, c.Reason, c.First, c.Second, c.Second, c.First) + "\n"
+	case "plan":
+		return renderPlan(m.plan, m.planSized)
+	case "confirming":
+		return renderConfirmation(m.plan)
+	case "preflight":
+		if m.preflightResults == nil {
+			return i18n.T("install.preflight_running") + "\n"
+		}
+		output := "\n" + doctor.Format(i18n.T("install.preflight_title"), m.preflightResults) + "\n"
+		if doctor.HasFailures(m.preflightResults) {
+			output += i18n.T("install.preflight_failed") + "\n"
+		}
+		output += i18n.T("install.preflight_help", Keys.Confirm.Help(), Keys.Quit.Help()) + "\n"
 		return output
+	case "authenticating":
+		if m.authError != nil {
+			return "\n" + i18n.T("install.auth_failed", m.authError, Keys.Quit.Help()) + "\n"
+		}
+		return i18n.T("install.auth", installer.Privileged().Elevator) + "\n"
 	case "installing":
 		return m.renderInstallationProgress()
 	case "complete":
 		var output string
-		output += "\n=== Installation Complete ===\n"
-		for lang, result := range m.userChoices {
-			output += fmt.Sprintf("%s: %s\n", lang, result)
+		output += "\n" + i18n.T("install.complete_title") + "\n"
+		if m.runError != nil {
+			output += fmt.Sprintf("%s %v\n", symbols.Failed, m.runError)
+		}
+		failed := m.failedItems()
+		for _, lang := range m.selectedLanguages {
+			// Failed items can be picked to see their output, so the results get a cursor column
+			if len(failed) > 0 {
+				cursor := " "
+				if i := slices.Index(failed, lang); i >= 0 && i == m.summaryCursor {
+					cursor = ">"
+				}
+				output += cursor + " "
+			}
+			output += fmt.Sprintf("%s %s: %s\n", resultIcon(m.result(lang)), lang, i18n.Word("result", m.result(lang)))
+			snapshot, exists := m.progress[lang]
+			if !exists {
+				continue
+			}
+			for _, note := range snapshot.Notes {
+				output += fmt.Sprintf("  %s %s\n", symbols.Info, note)
+			}
+			if snapshot.ErrorMessage == "" {
+				continue
+			}
+			output += fmt.Sprintf("  %s %s\n", symbols.Failed, snapshot.ErrorMessage)
+			if snapshot.Hint != "" {
+				output += fmt.Sprintf("  %s %s\n", symbols.Arrow, snapshot.Hint)
+			}
+		}
+		if len(failed) > 0 {
+			output += "\n" + i18n.T("install.failed_help", Keys.Up.Help(), Keys.Down.Help(), Keys.Confirm.Help()) + "\n"
 		}
+		output += m.renderTimings()
+		output += m.renderLogins()
+		output += m.renderProjects()
+		output += m.renderFollowUps()
 		return output
 	default:
 		return ""
 	}
 }
 
-// renderInstallationProgress renders styled progress bars for all languages
+// result is a language's outcome in words, e.g. "installed" or "failed"; the error itself is shown below it
+func (m DownloadInstallModel) result(lang string) string {
+	result, ok := m.results[lang]
+	switch {
+	case !ok && m.userChoices[lang] == "skip":
+		return "skipped"
+	case !ok:
+		return "not finished"
+	case strings.HasPrefix(result, "error"):
+		return "failed"
+	}
+	return result
+}
+
+// failedItems lists the items that failed, which the summary's cursor can pick to see their output
+func (m DownloadInstallModel) failedItems() []string {
+	var failed []string
+	for _, lang := range m.selectedLanguages {
+		if m.progress[lang].ErrorMessage != "" {
+			failed = append(failed, lang)
+		}
+	}
+	return failed
+}
+
+// resultIcon marks an outcome on the summary
+func resultIcon(result string) string {
+	switch result {
+	case "installed", "updated", "removed":
+		return symbols.OK.String()
+	case "skipped":
+		return symbols.Skipped.String()
+	}
+	return symbols.Failed.String()
+}
+
+// renderTimings summarizes how long each item spent downloading, installing and verifying, what decor
+// downloaded for it, and the run's total wall-clock time
+func (m DownloadInstallModel) renderTimings() string {
+	seconds := func(d time.Duration) string {
+		if d == 0 {
+			return symbols.Dash.String()
+		}
+		return d.Round(100 * time.Millisecond).String()
+	}
+	var rows []string
+	for _, lang := range m.selectedLanguages {
+		snapshot, ok := m.progress[lang]
+		if !ok || len(snapshot.Timings) == 0 {
+			continue
+		}
+		size := symbols.Dash.String()
+		if snapshot.Downloaded > 0 {
+			size = doctor.FormatBytes(uint64(snapshot.Downloaded))
+		}
+		rows = append(rows, fmt.Sprintf("%-15s %10s %10s %10s %10s %10s\n", lang,
+			seconds(snapshot.Timings[installer.PhaseDownloading]),
+			seconds(snapshot.Timings[installer.PhaseInstalling]),
+			seconds(snapshot.Timings[installer.PhaseVerifying]),
+			size,
+			seconds(snapshot.Elapsed())))
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+	output := fmt.Sprintf("\n%-15s %10s %10s %10s %10s %10s\n", "", i18n.T("install.col_download"), i18n.T("install.col_install"), i18n.T("install.col_verify"), i18n.T("install.col_size"), i18n.T("install.col_total"))
+	output += strings.Join(rows, "")
+	output += i18n.T("install.finished_in", m.elapsed.Round(100*time.Millisecond)) + "\n"
+	return output
+}
+
+// finish moves to the complete screen, works out which follow-up items to offer, and checks the
+// login state of installed items that need one
+func (m *DownloadInstallModel) finish() tea.Cmd {
+	m.state = "complete"
+	m.elapsed = time.Since(m.started)
+	if m.runError != nil {
+		return nil
+	}
+
+	var checks []tea.Cmd
+	for _, lang := range m.selectedLanguages {
+		item, ok := catalog.Find(lang)
+		if !ok || len(item.LoginStatus) == 0 || !m.installedNow(lang) {
+			continue
+		}
+		m.logins[lang] = "checking"
+		checks = append(checks, checkLogin(lang))
+	}
+
+	cfg, _, _ := config.Load()
+	m.projectDir = cfg.ProjectDir
+	for _, lang := range m.selectedLanguages {
+		if _, ok := scaffold.Lookup(lang); ok && m.installedNow(lang) {
+			m.projects[lang] = "offered"
+		}
+	}
+
+	selected := make(map[string]bool)
+	for _, lang := range m.selectedLanguages {
+		selected[lang] = true
+	}
+	for _, lang := range m.selectedLanguages {
+		if !m.installedNow(lang) {
+			continue
+		}
+		item, ok := catalog.Find(lang)
+		if !ok {
+			continue
+		}
+		for _, name := range item.FollowUps {
+			name = installer.Alternative(name)
+			if !selected[name] {
+				selected[name] = true
+				m.followUps = append(m.followUps, name)
+			}
+		}
+	}
+	return tea.Batch(checks...)
+}
+
+// installedNow reports whether lang is installed once the run is over: it was installed already, or this
+// run installed or updated it without errors
+func (m DownloadInstallModel) installedNow(lang string) bool {
+	switch m.userChoices[lang] {
+	case "install", "update":
+		result := m.results[lang]
+		return result == "installed" || result == "updated"
+	}
+	status := m.installationStatus[lang]
+	return status != nil && status.Installed
+}
+
+// LoginStatusMsg reports whether the user is logged in to an item's service
+type LoginStatusMsg struct {
+	Item     string
+	LoggedIn bool
+}
+
+// LoginDoneMsg is sent when an interactive login command exits
+type LoginDoneMsg struct {
+	Item string
+	Err  error
+}
+
+// checkLogin runs the item's login status command in the background
+func checkLogin(item string) tea.Cmd {
+	return func() tea.Msg {
+		return LoginStatusMsg{Item: item, LoggedIn: installer.LoggedIn(item)}
+	}
+}
+
+// startLogin hands the terminal to the login command of the first item that isn't logged in
+func (m DownloadInstallModel) startLogin() tea.Cmd {
+	for _, lang := range m.selectedLanguages {
+		state, ok := m.logins[lang]
+		if !ok || state == "logged in" || state == "checking" {
+			continue
+		}
+		cmd := installer.LoginCommand(lang)
+		if cmd == nil {
+			continue
+		}
+		return tea.Exec(cmd, func(err error) tea.Msg {
+			return LoginDoneMsg{Item: lang, Err: err}
+		})
+	}
+	return nil
+}
+
+// renderLogins lists the login state of installed items that have one
+func (m DownloadInstallModel) renderLogins() string {
+	if len(m.logins) == 0 {
+		return ""
+	}
+	output := "\n" + i18n.T("install.accounts_title") + "\n"
+	pending := false
+	for _, lang := range m.selectedLanguages {
+		state, ok := m.logins[lang]
+		if !ok {
+			continue
+		}
+		icon := symbols.Failed.String()
+		switch state {
+		case "logged in":
+			icon = symbols.OK.String()
+		case "checking":
+			icon = symbols.Spinner(m.spinnerFrame)
+		default:
+			pending = true
+		}
+		output += fmt.Sprintf("  %s %s: %s\n", icon, lang, i18n.Word("login", state))
+	}
+	if pending {
+		output += i18n.T("install.login_help") + "\n"
+	}
+	return output
+}
+
+// ProjectDoneMsg is sent when a hello-world project has been scaffolded and built, or failed to
+type ProjectDoneMsg struct {
+	Language string
+	Dir      string
+	Err      error
+}
+
+// createProjects scaffolds a hello-world project in the project directory for each offered language,
+// building and running it to prove the toolchain works end to end
+func (m DownloadInstallModel) createProjects() tea.Cmd {
+	base := config.ExpandHome(m.projectDir)
+
+	var cmds []tea.Cmd
+	for _, lang := range m.selectedLanguages {
+		if m.projects[lang] != "offered" {
+			continue
+		}
+		m.projects[lang] = "creating"
+		dir := scaffold.DefaultDir(base, lang)
+		cmds = append(cmds, func() tea.Msg {
+			output, err := scaffold.Create(context.Background(), installer.Privileged(), lang, dir)
+			if err != nil {
+				if last := lastLine(output); last != "" {
+					err = fmt.Errorf("%w: %s", err, last)
+				}
+			}
+			return ProjectDoneMsg{Language: lang, Dir: dir, Err: err}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// lastLine returns the last non-empty line of a command's output, which usually says what went wrong
+func lastLine(output []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// renderProjects lists the hello-world projects that were offered or created
+func (m DownloadInstallModel) renderProjects() string {
+	if len(m.projects) == 0 {
+		return ""
+	}
+	output := "\n" + i18n.T("install.projects_title") + "\n"
+	var offered []string
+	for _, lang := range m.selectedLanguages {
+		switch m.projects[lang] {
+		case "offered":
+			offered = append(offered, lang)
+		case "creating":
+			output += fmt.Sprintf("  %s %s\n", symbols.Pending, i18n.T("install.project_creating", lang))
+		case "created":
+			output += fmt.Sprintf("  %s %s\n", symbols.OK, i18n.T("install.project_created", lang, m.projectNotes[lang]))
+		case "failed":
+			output += fmt.Sprintf("  %s %s: %s\n", symbols.Failed, lang, m.projectNotes[lang])
+		}
+	}
+	if len(offered) > 0 {
+		output += i18n.T("install.project_help", strings.Join(offered, ", "), m.projectDir) + "\n"
+	}
+	return output
+}
+
+// installFollowUps starts a new install flow for the follow-up items the user picked
+func (m DownloadInstallModel) installFollowUps() (tea.Model, tea.Cmd) {
+	var picked []string
+	for _, name := range m.followUps {
+		if m.followUpSelected[name] {
+			picked = append(picked, name)
+		}
+	}
+	if len(picked) == 0 {
+		return m, nil
+	}
+	return m, Push(i18n.T("screen.tooling"), NewDownloadInstallModel(picked))
+}
+
+// renderFollowUps lists the follow-up items with checkboxes, or nothing if there are none
+func (m DownloadInstallModel) renderFollowUps() string {
+	if len(m.followUps) == 0 {
+		return ""
+	}
+	descriptionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")) // Gray
+
+	output := "\n" + i18n.T("install.tooling_title") + "\n"
+	for i, name := range m.followUps {
+		cursor := " "
+		if m.summaryCursor-len(m.failedItems()) == i {
+			cursor = ">"
+		}
+		checked := " "
+		if m.followUpSelected[name] {
+			checked = "x"
+		}
+		output += fmt.Sprintf("%s [%s] %s", cursor, checked, name)
+		if item, ok := catalog.Find(name); ok && item.Description != "" {
+			output += " " + descriptionStyle.Render(item.Description)
+		}
+		output += "\n"
+	}
+	output += "\n" + i18n.T("install.tooling_help", Keys.Toggle.Help(), Keys.Confirm.Help(), Keys.Quit.Help()) + "\n"
+	return output
+}
+
+// groupAfter is how many items an install can have before its progress is grouped by category
+const groupAfter = 8
+
+// progressGroup is the items of one category being installed
+type progressGroup struct {
+	category string
+	items    []string
+}
+
+// grouped reports whether the install has enough items to show its progress by category
+func (m DownloadInstallModel) grouped() bool {
+	return len(m.selectedLanguages) > groupAfter
+}
+
+// groups returns the items being installed by category, in the order the categories first come up
+func (m DownloadInstallModel) groups() []progressGroup {
+	var groups []progressGroup
+	for _, lang := range m.selectedLanguages {
+		category := i18n.T("install.other")
+		if item, ok := catalog.Find(lang); ok && item.Category != "" {
+			category = item.Category
+		}
+		i := slices.IndexFunc(groups, func(g progressGroup) bool { return g.category == category })
+		if i < 0 {
+			groups = append(groups, progressGroup{category: category})
+			i = len(groups) - 1
+		}
+		groups[i].items = append(groups[i].items, lang)
+	}
+	return groups
+}
+
+// snapshot returns the latest progress of lang, or how an item that hasn't started yet stands
+func (m DownloadInstallModel) snapshot(lang string) installer.ProgressSnapshot {
+	if snapshot, ok := m.progress[lang]; ok {
+		return snapshot
+	}
+	return installer.NewProgress(lang).Snapshot()
+}
+
+// tally returns how far along items are as a whole, how many of them have finished and how many failed,
+// leaving out skipped ones
+func (m DownloadInstallModel) tally(items []string) (progress float64, finished, failed, total int) {
+	for _, lang := range items {
+		snapshot := m.snapshot(lang)
+		if m.userChoices[lang] == "skip" || snapshot.Phase == installer.PhaseSkipped {
+			continue
+		}
+		total++
+		progress += snapshot.Progress
+		switch snapshot.Phase {
+		case installer.PhaseDone:
+			finished++
+		case installer.PhaseFailed:
+			finished++
+			failed++
+		}
+	}
+	if total > 0 {
+		progress /= float64(total)
+	}
+	return progress, finished, failed, total
+}
+
+// progressRow is a line of the install progress the cursor can be on: a category's, or an item's
+type progressRow struct {
+	category string
+	item     string // empty on the category's own line
+}
+
+// progressRows lists the lines of the install progress the cursor moves through: each item, or when
+// they're grouped, each category and the items of those that aren't folded
+func (m DownloadInstallModel) progressRows() []progressRow {
+	var rows []progressRow
+	if !m.grouped() {
+		for _, lang := range m.selectedLanguages {
+			rows = append(rows, progressRow{item: lang})
+		}
+		return rows
+	}
+	for _, g := range m.groups() {
+		rows = append(rows, progressRow{category: g.category})
+		if m.folded(g) {
+			continue
+		}
+		for _, lang := range g.items {
+			rows = append(rows, progressRow{category: g.category, item: lang})
+		}
+	}
+	return rows
+}
+
+// queueKey reports whether msg changes the queue or the progress view, rather than doing what it does
+// on the other screens
+func (m DownloadInstallModel) queueKey(msg tea.KeyMsg) bool {
+	return keymap.Matches(msg, skipKey, earlierKey, laterKey, pauseKey, Keys.Toggle, Keys.Left, Keys.Right)
+}
+
+// changeQueue folds or unfolds the category under the cursor, or skips or moves the item under it, or
+// pauses or resumes the queue
+func (m DownloadInstallModel) changeQueue(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	rows := m.progressRows()
+	if len(rows) == 0 {
+		return m, nil
+	}
+	row := rows[min(m.progressCursor, len(rows)-1)]
+	switch {
+	case keymap.Matches(msg, pauseKey):
+		if m.paused {
+			return m, queueChange(m.client, "resume", "")
+		}
+		return m, queueChange(m.client, "pause", "")
+	case row.item == "":
+		g := m.groups()[slices.IndexFunc(m.groups(), func(g progressGroup) bool { return g.category == row.category })]
+		switch {
+		case keymap.Matches(msg, Keys.Toggle):
+			m.collapsed[g.category] = !m.folded(g)
+		case keymap.Matches(msg, Keys.Left, Keys.Right):
+			m.collapsed[g.category] = keymap.Matches(msg, Keys.Left)
+		}
+	case keymap.Matches(msg, skipKey):
+		return m, queueChange(m.client, "skip", row.item)
+	case keymap.Matches(msg, earlierKey):
+		return m, queueChange(m.client, "earlier", row.item)
+	case keymap.Matches(msg, laterKey):
+		return m, queueChange(m.client, "later", row.item)
+	}
+	return m, nil
+}
+
+// QueueChangedMsg carries the queue after a change to it, or why the change couldn't be made
+type QueueChangedMsg struct {
+	Queued []string
+	Paused bool
+	Err    error
+}
+
+// queueChange makes a change to the running install's queue, through the daemon if it's the one running it
+func queueChange(client *daemon.Client, change, language string) tea.Cmd {
+	return func() tea.Msg {
+		if client == nil {
+			err := installer.ChangeQueue(change, language)
+			queued, paused := installer.Queued()
+			return QueueChangedMsg{Queued: queued, Paused: paused, Err: err}
+		}
+		if err := client.Queue(change, language); err != nil {
+			return QueueChangedMsg{Err: err}
+		}
+		status, err := client.Status()
+		return QueueChangedMsg{Queued: status.Queued, Paused: status.Paused, Err: err}
+	}
+}
+
+// folded reports whether g shows as one line: as toggled, or else once all its items have installed
+func (m DownloadInstallModel) folded(g progressGroup) bool {
+	if collapsed, ok := m.collapsed[g.category]; ok {
+		return collapsed
+	}
+	_, finished, failed, total := m.tally(g.items)
+	return finished == total && failed == 0
+}
+
+// renderInstallationProgress renders styled progress bars for all languages, under a bar for the whole
+// install, and by category when there are many of them
 func (m DownloadInstallModel) renderInstallationProgress() string {
 	// Define lipgloss styles
 	titleStyle := lipgloss.NewStyle().
@@ -190,6 +1220,74 @@ func (m DownloadInstallModel) renderInstallationProgress() string {
 		Foreground(lipgloss.Color("11")). // Yellow
 		MarginBottom(1)
 
+	groupStyle := lipgloss.NewStyle().
+		Bold(true).
+		Width(28)
+
+	progressBarStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("10")). // Green
+		MarginLeft(1)
+
+	statusStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")). // Gray
+		MarginLeft(1)
+
+	var output string
+	output += titleStyle.Render(i18n.T("install.progress_title")) + "\n"
+
+	progress, finished, failed, total := m.tally(m.selectedLanguages)
+	output += lipgloss.JoinHorizontal(
+		lipgloss.Left,
+		groupStyle.Render(i18n.T("install.overall")),
+		progressBarStyle.Render(renderProgressBar(progress, 30)),
+		statusStyle.Render(i18n.T("install.finished_count", finished, total, failed)),
+	) + "\n\n"
+
+	if m.paused {
+		output += i18n.T("install.queue_paused", symbols.Warning, pauseKey.Help()) + "\n\n"
+	}
+
+	rows := m.progressRows()
+	cursor := min(m.progressCursor, len(rows)-1)
+	for i, row := range rows {
+		pointer := " "
+		if i == cursor {
+			pointer = ">"
+		}
+		if row.item != "" {
+			indent := ""
+			if m.grouped() {
+				indent = "  "
+			}
+			output += pointer + indent + m.renderProgressRow(row.item) + "\n"
+			continue
+		}
+		g := m.groups()[slices.IndexFunc(m.groups(), func(g progressGroup) bool { return g.category == row.category })]
+		marker := symbols.Expanded
+		if m.folded(g) {
+			marker = symbols.Collapsed
+		}
+		progress, finished, failed, total := m.tally(g.items)
+		output += lipgloss.JoinHorizontal(
+			lipgloss.Left,
+			groupStyle.Render(fmt.Sprintf("%s %s %s", pointer, marker, g.category)),
+			progressBarStyle.Render(renderProgressBar(progress, 30)),
+			statusStyle.Render(i18n.T("install.finished_count", finished, total, failed)),
+		) + "\n"
+	}
+
+	if m.queueError != nil {
+		output += "\n" + fmt.Sprintf("%s %v", symbols.Failed, m.queueError) + "\n"
+	}
+	output += "\n" + statusStyle.Render(i18n.T("install.queue_help", Keys.Up.Help(), Keys.Down.Help(), skipKey.Help(), earlierKey.Help(), laterKey.Help(), pauseKey.Help())) + "\n"
+	if m.grouped() {
+		output += statusStyle.Render(i18n.T("install.groups_help", Keys.Toggle.Help(), Keys.Left.Help(), Keys.Right.Help())) + "\n"
+	}
+	return output
+}
+
+// renderProgressRow renders one language's phase, progress bar and step
+func (m DownloadInstallModel) renderProgressRow(lang string) string {
 	progressContainerStyle := lipgloss.NewStyle().
 		MarginBottom(1).
 		PaddingLeft(2)
@@ -207,52 +1305,51 @@ func (m DownloadInstallModel) renderInstallationProgress() string {
 		Foreground(lipgloss.Color("8")). // Gray
 		MarginLeft(1)
 
-	var output string
-	output += titleStyle.Render("Installing Languages...") + "\n"
-
-	for _, lang := range m.selectedLanguages {
-		choice := m.userChoices[lang]
-		if choice == "skip" {
-			output += progressContainerStyle.Render(
-				lipgloss.JoinHorizontal(
-					lipgloss.Left,
-					langNameStyle.Render(lang),
-					statusStyle.Render("⊘ Skipped"),
-				),
-			) + "\n"
-			continue
-		}
-
-		// Initialize progress if not exists
-		if _, exists := m.languageProgress[lang]; !exists {
-			m.languageProgress[lang] = &LanguageProgress{
-				Language:    lang,
-				Progress:    0.0,
-				CurrentStep: "starting",
-				TotalSteps:  3,
-			}
-		}
+	phaseStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("13")). // Magenta
+		Width(12)
 
-		prog := m.languageProgress[lang]
-		prog.mu.Lock()
-		progress := prog.Progress
-		step := prog.CurrentStep
-		prog.mu.Unlock()
-
-		// Create a progress modal
-		progressBar := renderProgressBar(progress, 30)
+	// Grouped rows go without the blank line between them, so a category of many items fits
+	if m.grouped() {
+		progressContainerStyle = progressContainerStyle.UnsetMarginBottom()
+	}
 
-		output += progressContainerStyle.Render(
+	snapshot := m.snapshot(lang)
+	if m.userChoices[lang] == "skip" || snapshot.Phase == installer.PhaseSkipped {
+		return progressContainerStyle.Render(
 			lipgloss.JoinHorizontal(
 				lipgloss.Left,
 				langNameStyle.Render(lang),
-				progressBarStyle.Render(progressBar),
-				statusStyle.Render(fmt.Sprintf("(%s)", step)),
+				statusStyle.Render(i18n.T("install.skipped", symbols.Skipped)),
 			),
-		) + "\n"
+		)
 	}
 
-	return output
+	phase := phaseStyle.Render(i18n.Word("phase", snapshot.Phase))
+	step := fmt.Sprintf("(%s)", snapshot.CurrentStep)
+	if place := slices.Index(m.queued, lang); place >= 0 && snapshot.Phase == installer.PhaseQueued {
+		step = i18n.T("install.queue_place", place+1)
+	}
+	if snapshot.Waiting {
+		return progressContainerStyle.Render(
+			lipgloss.JoinHorizontal(
+				lipgloss.Left,
+				langNameStyle.Render(lang),
+				phase,
+				progressBarStyle.Render(symbols.Spinner(m.spinnerFrame)),
+				statusStyle.Render(snapshot.CurrentStep),
+			),
+		)
+	}
+	return progressContainerStyle.Render(
+		lipgloss.JoinHorizontal(
+			lipgloss.Left,
+			langNameStyle.Render(lang),
+			phase,
+			progressBarStyle.Render(renderProgressBar(snapshot.Progress, 30)),
+			statusStyle.Render(step),
+		),
+	)
 }
 
 // renderProgressBar creates a visual progress bar with percentage
@@ -274,9 +1371,10 @@ func renderProgressBar(progress float64, width int) string {
 
 // Message types for async operations
 type InstallationStatusMsg struct {
-	Status map[string]*InstallationStatus
+	Status map[string]*installer.InstallationStatus
 }
 
+// InstallCompleteMsg is sent when every install has finished, with each item's outcome from installer.Run
 type InstallCompleteMsg struct {
 	Results map[string]string
 }
@@ -286,530 +1384,299 @@ type InstallErrorMsg struct {
 	Error    string
 }
 
-// progressUpdateTicker sends periodic progress updates
-func progressUpdateTicker() tea.Cmd {
-	return tea.Tick(100*time.Millisecond, func(time.Time) tea.Msg {
-		return ProgressTickMsg{}
-	})
+// PreflightMsg carries the results of the pre-install checks
+type PreflightMsg struct {
+	Results []doctor.Result
+}
+
+// AuthResultMsg is sent when the password prompt for privileged commands finishes
+type AuthResultMsg struct {
+	Err error
 }
 
-type ProgressTickMsg struct{}
+// startInstallation prompts for the password when any pending step needs root, then begins installing
+func (m DownloadInstallModel) startInstallation() (tea.Model, tea.Cmd) {
+	// The daemon elevates on its own side
+	if m.client != nil {
+		m.state = "installing"
+		m.started = time.Now()
+		return m, applyWithDaemon(m.client, m.selectedLanguages, m.userChoices)
+	}
+
+	privileged := installer.Privileged()
+	if privileged.NeedsElevation() && installer.NeedsRoot(m.selectedLanguages, m.userChoices) {
+		m.state = "authenticating"
+		return m, tea.ExecProcess(privileged.AuthCommand(), func(err error) tea.Msg {
+			return AuthResultMsg{Err: runner.CheckAuth(err)}
+		})
+	}
+	m.state = "installing"
+	m.started = time.Now()
+	return m, installSelectedLanguagesWithProgress(m.selectedLanguages, m.userChoices, m.installationStatus)
+}
 
-// checkInstalledLanguages checks which languages are installed
-func checkInstalledLanguages(languages []string) tea.Cmd {
+// runPreflight checks disk space, base tools and network for the languages that will be installed
+func runPreflight(languages []string, choices map[string]string) tea.Cmd {
 	return func() tea.Msg {
-		status := make(map[string]*InstallationStatus)
+		var pending []string
 		for _, lang := range languages {
-			installed, version, latest := checkLanguageInstallation(lang)
-			status[lang] = &InstallationStatus{
-				Language:      lang,
-				Installed:     installed,
-				Version:       version,
-				LatestVersion: latest,
+			if choices[lang] != "skip" {
+				pending = append(pending, lang)
 			}
 		}
-		return InstallationStatusMsg{Status: status}
+		return PreflightMsg{Results: doctor.Preflight(pending)}
 	}
 }
 
-// checkLanguageInstallation checks if a language is installed and gets its version
-func checkLanguageInstallation(language string) (bool, string, string) {
-	var cmd *exec.Cmd
-	switch strings.ToLower(language) {
-	case "go":
-		cmd = exec.Command("go", "version")
-	case "python":
-		cmd = exec.Command("python3", "--version")
-	case "rust":
-		cmd = exec.Command("rustc", "--version")
-	case "c++":
-		if runtime.GOOS == "darwin" {
-			cmd = exec.Command("clang", "--version")
-		} else {
-			cmd = exec.Command("g++", "--version")
+// progressUpdateTicker animates the spinners while installing, and polls the daemon for progress when
+// there is one. Local installs send their progress and completion as messages instead.
+func progressUpdateTicker(client *daemon.Client) tea.Cmd {
+	return tea.Tick(100*time.Millisecond, func(time.Time) tea.Msg {
+		if client == nil {
+			queued, paused := installer.Queued()
+			return ProgressTickMsg{Queued: queued, Paused: paused}
 		}
-	case "java":
-		cmd = exec.Command("java", "-version")
-	default:
-		return false, "", ""
-	}
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return false, "", ""
-	}
-
-	version := parseVersion(string(output), language)
-	latest := getLatestVersion(language)
-
-	return true, version, latest
+		status, err := client.Status()
+		if err != nil {
+			return DaemonErrorMsg{Err: err}
+		}
+		return ProgressTickMsg{Progress: status.Progress, Done: status.State == "done", Results: status.Results, Queued: status.Queued, Paused: status.Paused}
+	})
 }
 
-// parseVersion extracts version from command output
-func parseVersion(output, language string) string {
-	lines := strings.Split(output, "\n")
-	if len(lines) > 0 {
-		return strings.TrimSpace(lines[0])
-	}
-	return "unknown"
+// ProgressTickMsg carries the daemon's progress, and its results once its run has finished, and the
+// queue of the run, the daemon's or decor's own
+type ProgressTickMsg struct {
+	Progress map[string]installer.ProgressSnapshot
+	Done     bool
+	Results  map[string]string
+	Queued   []string
+	Paused   bool
 }
 
-func createSecureClient() *http.Client {
-	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			MinVersion: tls.VersionTLS12, // Minimum TLS 1.2
-		},
-		DisableCompression: false,
-		MaxIdleConns:       100,
-		IdleConnTimeout:    90 * time.Second,
-	}
-
-	return &http.Client{
-		Transport: transport,
-		Timeout:   5 * time.Second,
-	}
+// DaemonErrorMsg is sent when a call to the daemon fails
+type DaemonErrorMsg struct {
+	Err error
 }
 
-// getLatestVersion gets the latest version of a language (simplified)
-func getLatestVersion(language string) string {
-
-	latestVersions := map[string]string{
-		"go":     "1.25.5",
-		"python": "3.13.0",
-		"rust":   "1.81.0",
-		"c++":    "14",
-		"java":   "21",
-	}
-	return latestVersions[strings.ToLower(language)]
+// checkSpinnerTicker animates the pending rows while languages are being checked
+func checkSpinnerTicker() tea.Cmd {
+	return tea.Tick(100*time.Millisecond, func(time.Time) tea.Msg {
+		return checkTickMsg{}
+	})
 }
 
-// formatStatusLine formats the installation status for display
-func formatStatusLine(language string, status *InstallationStatus) string {
-	if !status.Installed {
-		return fmt.Sprintf("  ❌ %s: NOT INSTALLED\n", language)
-	}
-
-	if status.Version == status.LatestVersion {
-		return fmt.Sprintf("  ✅ %s: %s (latest)\n", language, status.Version)
-	}
+type checkTickMsg struct{}
 
-	return fmt.Sprintf("  ⚠️  %s: %s (latest: %s)\n", language, status.Version, status.LatestVersion)
+// CheckResultMsg is sent as each language's detection finishes
+type CheckResultMsg struct {
+	Status  *installer.InstallationStatus
+	results <-chan *installer.InstallationStatus
 }
 
-// formatPrompt formats the installation prompt for the user
-func formatPrompt(language string, status *InstallationStatus) string {
-	if !status.Installed {
-		return fmt.Sprintf(
-			"%s is not installed.\n(i) Install\n(s) Skip\n",
-			language,
-		)
-	}
+// checksDoneMsg is sent once every language has been checked
+type checksDoneMsg struct{}
 
-	if status.Version == status.LatestVersion {
-		return fmt.Sprintf(
-			"%s is installed (version: %s).\n(s) Skip\n(r) Reinstall\n",
-			language,
-			status.Version,
-		)
+// waitForCheck delivers the next detection result from results
+func waitForCheck(results <-chan *installer.InstallationStatus) tea.Cmd {
+	return func() tea.Msg {
+		status, ok := <-results
+		if !ok {
+			return checksDoneMsg{}
+		}
+		return CheckResultMsg{Status: status, results: results}
 	}
-
-	return fmt.Sprintf(
-		"%s is installed (current: %s, latest: %s).\n(u) Update\n(s) Skip\n",
-		language,
-		status.Version,
-		status.LatestVersion,
-	)
 }
 
-// getDefaultChoice returns the default choice based on installation status
-func getDefaultChoice(status *InstallationStatus) string {
-	if !status.Installed {
-		return "install"
+// checkInstalledLanguages checks which languages are installed, streaming results as they arrive
+func checkInstalledLanguages(client *daemon.Client, languages []string) tea.Cmd {
+	if client == nil {
+		return func() tea.Msg {
+			return waitForCheck(installer.CheckStream(languages))()
+		}
 	}
-	if status.Version != status.LatestVersion {
-		return "update"
+	return func() tea.Msg {
+		plan, err := client.Plan(languages)
+		if err != nil {
+			return DaemonErrorMsg{Err: err}
+		}
+		status := make(map[string]*installer.InstallationStatus)
+		for _, item := range plan {
+			status[item.Language] = &item.InstallationStatus
+		}
+		return InstallationStatusMsg{Status: status}
 	}
-	return "skip"
 }
 
-// installSelectedLanguagesWithProgress installs languages with progress tracking
-func installSelectedLanguagesWithProgress(languages []string, choices map[string]string, status map[string]*InstallationStatus) tea.Cmd {
-	return tea.Batch(
-		func() tea.Msg {
-			// Create and initialize progress trackers for all non-skipped languages
-			progressTrackers := make(map[string]*LanguageProgress)
-			for _, lang := range languages {
-				choice := choices[lang]
-				if choice != "skip" {
-					progressTrackers[lang] = &LanguageProgress{
-						Language:    lang,
-						Progress:    0.0,
-						CurrentStep: "starting",
-						TotalSteps:  3,
-					}
-				}
-			}
-
-			// Start installation in background
-			go func() {
-				results := make(map[string]string)
-				var wg sync.WaitGroup
-
-				for _, lang := range languages {
-					choice := choices[lang]
-					if choice == "skip" {
-						results[lang] = "skipped"
-						continue
-					}
-
-					progress := progressTrackers[lang]
-
-					wg.Add(1)
-					go func(language, choiceType string, prog *LanguageProgress) {
-						defer wg.Done()
-						var err error
-
-						switch choiceType {
-						case "install":
-							err = installLanguageWithProgress(language, prog)
-							if err != nil {
-								results[language] = fmt.Sprintf("error: %v", err)
-								prog.CurrentStep = "error"
-								prog.ErrorMessage = err.Error()
-							} else {
-								results[language] = "installed"
-								prog.CurrentStep = "complete"
-								prog.Progress = 1.0
-							}
-						case "update":
-							err = updateLanguageWithProgress(language, prog)
-							if err != nil {
-								results[language] = fmt.Sprintf("error: %v", err)
-								prog.CurrentStep = "error"
-								prog.ErrorMessage = err.Error()
-							} else {
-								results[language] = "updated"
-								prog.CurrentStep = "complete"
-								prog.Progress = 1.0
-							}
-						}
-					}(lang, choice, progress)
-				}
-
-				wg.Wait()
-				// Send completion message (handled by completion ticker)
-			}()
-
-			// Store trackers in a shared location
-			return InitProgressMsg{Trackers: progressTrackers}
-		},
-		progressUpdateTicker(),
-	)
-}
-
-// InitProgressMsg initializes progress trackers
-type InitProgressMsg struct {
-	Trackers map[string]*LanguageProgress
-}
-
-// installLanguageWithProgress downloads and installs a language with progress tracking
-func installLanguageWithProgress(language string, progress *LanguageProgress) error {
-	switch strings.ToLower(language) {
-	case "go":
-		return installGoWithProgress(progress)
-	case "python":
-		return installPythonWithProgress(progress)
-	case "rust":
-		return installRustWithProgress(progress)
-	case "c++":
-		return installCppWithProgress(progress)
-	case "java":
-		return installJavaWithProgress(progress)
-	default:
-		return fmt.Errorf("unsupported language: %s", language)
-	}
-}
-
-// updateLanguageWithProgress updates an existing language installation with progress
-func updateLanguageWithProgress(language string, progress *LanguageProgress) error {
-	switch strings.ToLower(language) {
-	case "go":
-		return updateGoWithProgress(progress)
-	case "python":
-		return updatePythonWithProgress(progress)
-	case "rust":
-		return updateRustWithProgress(progress)
-	case "c++":
-		return updateCppWithProgress(progress)
-	case "java":
-		return updateJavaWithProgress(progress)
-	default:
-		return fmt.Errorf("unsupported language: %s", language)
+// applyWithDaemon asks the daemon to install the languages, then polls it for progress
+func applyWithDaemon(client *daemon.Client, languages []string, choices map[string]string) tea.Cmd {
+	return func() tea.Msg {
+		if err := client.Apply(languages, choices); err != nil {
+			return DaemonErrorMsg{Err: err}
+		}
+		return InitProgressMsg{}
 	}
 }
 
-// Language-specific install functions with progress tracking
-func installGoWithProgress(progress *LanguageProgress) error {
-	steps := []string{
-		"Downloading Go...",
-		"Extracting files...",
-		"Verifying installation...",
-	}
-
-	for i, step := range steps {
-		progress.mu.Lock()
-		progress.Progress = float64(i) / float64(len(steps))
-		progress.CurrentStep = step
-		progress.mu.Unlock()
-		time.Sleep(500 * time.Millisecond) // Simulate work
+// label names a language for display, saying which item needs it if it was added as a prerequisite
+func (m DownloadInstallModel) label(lang string) string {
+	if neededBy, ok := m.addedDeps[lang]; ok {
+		return i18n.T("install.needed_by", lang, neededBy)
 	}
-
-	progress.mu.Lock()
-	progress.Progress = 1.0
-	progress.CurrentStep = "Verifying installation..."
-	progress.mu.Unlock()
-
-	cmd := exec.Command("bash", "-c", "curl -L https://go.dev/dl/go1.25.5.darwin-arm64.tar.gz -o go1.25.5.tar.gz && tar -C /usr/local -xzf go1.25.5.tar.gz")
-	return cmd.Run()
+	return lang
 }
 
-func installPythonWithProgress(progress *LanguageProgress) error {
-	steps := []string{
-		"Preparing installation...",
-		"Installing Python...",
-		"Verifying installation...",
+// renderPlan lists every step the install will take and what it downloads, for confirming before anything changes
+func renderPlan(plan []installer.Action, sized bool) string {
+	output := "\n" + i18n.T("plan.title") + "\n"
+	if len(plan) == 0 {
+		output += "\n" + i18n.T("plan.empty") + "\n"
 	}
-
-	for i, step := range steps {
-		progress.mu.Lock()
-		progress.Progress = float64(i) / float64(len(steps))
-		progress.CurrentStep = step
-		progress.mu.Unlock()
-		time.Sleep(500 * time.Millisecond) // Simulate work
+	for _, action := range plan {
+		heading := i18n.T("plan."+action.Choice, action.Language)
+		output += "\n" + heading + "\n"
+		for _, step := range action.Steps {
+			output += fmt.Sprintf("  %s %s\n", symbols.Bullet, step)
+		}
 	}
-
-	progress.mu.Lock()
-	progress.Progress = 1.0
-	progress.CurrentStep = "Verifying installation..."
-	progress.mu.Unlock()
-
-	if runtime.GOOS == "darwin" {
-		fmt.Println("Installing Python using Homebrew...")
-		cmd := exec.Command("brew", "install", "python@3.13")
-		return cmd.Run()
+	if len(plan) > 0 {
+		output += "\n" + renderPlanSize(plan, sized)
 	}
-	cmd := exec.Command("apt-get", "install", "-y", "python3")
-	return cmd.Run()
+	return output + "\n" + i18n.T("plan.help", Keys.Quit.Help()) + "\n"
 }
 
-func installRustWithProgress(progress *LanguageProgress) error {
-	steps := []string{
-		"Downloading Rust installer...",
-		"Running installation script...",
-		"Configuring environment...",
-	}
-
-	for i, step := range steps {
-		progress.mu.Lock()
-		progress.Progress = float64(i) / float64(len(steps))
-		progress.CurrentStep = step
-		progress.mu.Unlock()
-		time.Sleep(500 * time.Millisecond) // Simulate work
+// renderConfirmation lists what the plan deletes, replaces or edits outside decor's own files, in red,
+// for confirming before anything runs
+func renderConfirmation(plan []installer.Action) string {
+	var lines []string
+	for _, action := range plan {
+		for _, touch := range action.Touches {
+			lines = append(lines, fmt.Sprintf("%s %s: %s", symbols.Warning, action.Language, touch))
+		}
 	}
-
-	progress.mu.Lock()
-	progress.Progress = 1.0
-	progress.CurrentStep = "Configuring environment..."
-	progress.mu.Unlock()
-
-	cmd := exec.Command("bash", "-c", "curl --proto '=https' --tlsv1.2 -sSf https://sh.rustup.rs | sh -s -- -y")
-	return cmd.Run()
+	box := lipgloss.NewStyle().
+		Border(paneBorder()).
+		BorderForeground(lipgloss.Color("9")). // Red
+		Foreground(lipgloss.Color("9")).
+		Padding(0, 1).
+		Render(lipgloss.NewStyle().Bold(true).Render(i18n.T("confirm.title")) + "\n\n" + strings.Join(lines, "\n"))
+	return "\n" + box + "\n\n" + i18n.T("confirm.help", yesKey.Help(), Keys.Back.Help(), Keys.Quit.Help()) + "\n"
 }
 
-func installCppWithProgress(progress *LanguageProgress) error {
-	steps := []string{
-		"Preparing installation...",
-		"Installing C++ compiler...",
-		"Setting up environment...",
+// renderPlanSize totals the plan's estimated download and disk usage, with any warnings about them
+func renderPlanSize(plan []installer.Action, sized bool) string {
+	if !sized {
+		return i18n.T("plan.estimating") + "\n"
 	}
-
-	for i, step := range steps {
-		progress.mu.Lock()
-		progress.Progress = float64(i) / float64(len(steps))
-		progress.CurrentStep = step
-		progress.mu.Unlock()
-		time.Sleep(500 * time.Millisecond) // Simulate work
+	downloadSize, disk, unsized := installer.PlanSize(plan)
+	output := i18n.T("plan.size", doctor.FormatBytes(uint64(downloadSize)), doctor.FormatBytes(uint64(disk)))
+	if len(unsized) > 0 {
+		output += i18n.T("plan.unsized", strings.Join(unsized, ", "))
 	}
-
-	progress.mu.Lock()
-	progress.Progress = 1.0
-	progress.CurrentStep = "Setting up environment..."
-	progress.mu.Unlock()
-
-	if runtime.GOOS == "darwin" {
-		cmd := exec.Command("xcode-select", "--install")
-		return cmd.Run()
+	output += "\n"
+	for _, warning := range installer.SizeWarnings(plan) {
+		output += fmt.Sprintf("%s %s\n", symbols.Warning, warning)
 	}
-	cmd := exec.Command("apt-get", "install", "-y", "build-essential")
-	return cmd.Run()
+	return output
 }
 
-func installJavaWithProgress(progress *LanguageProgress) error {
-	steps := []string{
-		"Preparing installation...",
-		"Installing OpenJDK...",
-		"Setting up environment...",
-	}
-
-	for i, step := range steps {
-		progress.mu.Lock()
-		progress.Progress = float64(i) / float64(len(steps))
-		progress.CurrentStep = step
-		progress.mu.Unlock()
-		time.Sleep(500 * time.Millisecond) // Simulate work
+// formatStatusLine formats the installation status for display
+func formatStatusLine(language string, status *installer.InstallationStatus) string {
+	if status.TimedOut {
+		return fmt.Sprintf("  %s %s\n", symbols.TimedOut, i18n.T("status.timed_out", language, status.Error))
 	}
-
-	progress.mu.Lock()
-	progress.Progress = 1.0
-	progress.CurrentStep = "Setting up environment..."
-	progress.mu.Unlock()
-
-	if runtime.GOOS == "darwin" {
-		cmd := exec.Command("brew", "install", "openjdk@21")
-		return cmd.Run()
+	if !status.Installed {
+		return fmt.Sprintf("  %s %s\n", symbols.Failed, i18n.T("status.not_installed", language))
 	}
-	cmd := exec.Command("apt-get", "install", "-y", "openjdk-21-jdk")
-	return cmd.Run()
-}
 
-// Language-specific update functions with progress tracking
-func updateGoWithProgress(progress *LanguageProgress) error {
-	steps := []string{
-		"Checking latest version...",
-		"Downloading Go...",
-		"Installing update...",
+	service := origin(status)
+	if status.Service != "" {
+		service += i18n.T("status.service", status.Service)
 	}
-
-	for i, step := range steps {
-		progress.mu.Lock()
-		progress.Progress = float64(i) / float64(len(steps))
-		progress.CurrentStep = step
-		progress.mu.Unlock()
-		time.Sleep(500 * time.Millisecond) // Simulate work
+	if status.Version == status.LatestVersion {
+		return fmt.Sprintf("  %s %s\n", symbols.OK, i18n.T("status.latest", language, status.Version, service))
 	}
 
-	progress.mu.Lock()
-	progress.Progress = 1.0
-	progress.CurrentStep = "Installing update..."
-	progress.mu.Unlock()
-
-	return installGoWithProgress(progress)
+	return fmt.Sprintf("  %s %s\n", symbols.Warning, i18n.T("status.outdated", language, status.Version, status.LatestVersion, service))
 }
 
-func updatePythonWithProgress(progress *LanguageProgress) error {
-	steps := []string{
-		"Fetching available updates...",
-		"Upgrading Python...",
-		"Verifying update...",
-	}
-
-	for i, step := range steps {
-		progress.mu.Lock()
-		progress.Progress = float64(i) / float64(len(steps))
-		progress.CurrentStep = step
-		progress.mu.Unlock()
-		time.Sleep(500 * time.Millisecond) // Simulate work
+// formatPrompt formats the installation prompt for the user
+func formatPrompt(language string, status *installer.InstallationStatus) string {
+	if status.TimedOut {
+		return i18n.T("prompt.timed_out", language) + "\n"
 	}
-
-	progress.mu.Lock()
-	progress.Progress = 1.0
-	progress.CurrentStep = "Verifying update..."
-	progress.mu.Unlock()
-
-	if runtime.GOOS == "darwin" {
-		cmd := exec.Command("brew", "upgrade", "python@3.13")
-		return cmd.Run()
+	if !status.Installed {
+		return i18n.T("prompt.not_installed", language) + "\n"
 	}
-	cmd := exec.Command("apt-get", "upgrade", "-y", "python3")
-	return cmd.Run()
-}
 
-func updateRustWithProgress(progress *LanguageProgress) error {
-	steps := []string{
-		"Checking for updates...",
-		"Updating Rust...",
-		"Verifying update...",
+	if status.Version == status.LatestVersion {
+		return i18n.T("prompt.installed", language, status.Version) + "\n"
 	}
 
-	for i, step := range steps {
-		progress.mu.Lock()
-		progress.Progress = float64(i) / float64(len(steps))
-		progress.CurrentStep = step
-		progress.mu.Unlock()
-		time.Sleep(500 * time.Millisecond) // Simulate work
+	if !status.Managed {
+		return i18n.T("prompt.unmanaged", language, status.Version, status.LatestVersion) + "\n"
 	}
 
-	progress.mu.Lock()
-	progress.Progress = 1.0
-	progress.CurrentStep = "Verifying update..."
-	progress.mu.Unlock()
-
-	cmd := exec.Command("rustup", "update")
-	return cmd.Run()
+	return i18n.T("prompt.outdated", language, status.Version, status.LatestVersion) + "\n"
 }
 
-func updateCppWithProgress(progress *LanguageProgress) error {
-	steps := []string{
-		"Checking for system updates...",
-		"Installing updates...",
-		"Verifying...",
+// origin says whether decor installed something or found it already on the system
+func origin(status *installer.InstallationStatus) string {
+	if status.Managed {
+		return i18n.T("status.managed")
 	}
+	return i18n.T("status.found")
+}
 
-	for i, step := range steps {
-		progress.mu.Lock()
-		progress.Progress = float64(i) / float64(len(steps))
-		progress.CurrentStep = step
-		progress.mu.Unlock()
-		time.Sleep(500 * time.Millisecond) // Simulate work
-	}
+// progressBuffer is how many progress snapshots can queue up before the installers wait for the TUI
+const progressBuffer = 64
 
-	progress.mu.Lock()
-	progress.Progress = 1.0
-	progress.CurrentStep = "Verifying..."
-	progress.mu.Unlock()
+// installSelectedLanguagesWithProgress starts installing the languages in the background. The trackers
+// stay with the installer: each change is sent to the model as a snapshot in a ProgressMsg.
+func installSelectedLanguagesWithProgress(languages []string, choices map[string]string, status map[string]*installer.InstallationStatus) tea.Cmd {
+	return func() tea.Msg {
+		trackers := installer.NewTrackers(languages, choices)
+		updates := make(chan installer.ProgressSnapshot, progressBuffer)
+		results := make(chan map[string]string, 1)
+		initial := make(map[string]installer.ProgressSnapshot)
+		for lang, tracker := range trackers {
+			initial[lang] = tracker.Snapshot()
+			tracker.OnChange = func(snapshot installer.ProgressSnapshot) {
+				updates <- snapshot
+			}
+		}
 
-	if runtime.GOOS == "darwin" {
-		cmd := exec.Command("softwareupdate", "-i", "-a")
-		return cmd.Run()
+		go func() {
+			results <- installer.Run(context.Background(), languages, choices, trackers)
+			close(updates)
+		}()
+		return InitProgressMsg{Progress: initial, updates: updates, results: results}
 	}
-	cmd := exec.Command("apt-get", "upgrade", "-y")
-	return cmd.Run()
 }
 
-func updateJavaWithProgress(progress *LanguageProgress) error {
-	steps := []string{
-		"Fetching available updates...",
-		"Upgrading OpenJDK...",
-		"Verifying update...",
-	}
-
-	for i, step := range steps {
-		progress.mu.Lock()
-		progress.Progress = float64(i) / float64(len(steps))
-		progress.CurrentStep = step
-		progress.mu.Unlock()
-		time.Sleep(500 * time.Millisecond) // Simulate work
-	}
+// InitProgressMsg is sent when installing starts, with each item's starting progress and the channel
+// its changes arrive on. Installs run by the daemon have neither; its progress is polled instead.
+type InitProgressMsg struct {
+	Progress map[string]installer.ProgressSnapshot
+	updates  <-chan installer.ProgressSnapshot
+	results  <-chan map[string]string
+}
 
-	progress.mu.Lock()
-	progress.Progress = 1.0
-	progress.CurrentStep = "Verifying update..."
-	progress.mu.Unlock()
+// ProgressMsg carries a snapshot of one item's progress after it changed
+type ProgressMsg struct {
+	Snapshot installer.ProgressSnapshot
+	updates  <-chan installer.ProgressSnapshot
+	results  <-chan map[string]string
+}
 
-	if runtime.GOOS == "darwin" {
-		cmd := exec.Command("brew", "upgrade", "openjdk@21")
-		return cmd.Run()
+// waitForProgress delivers the next progress change, then InstallCompleteMsg with the run's results once
+// the installers are done and close updates
+func waitForProgress(updates <-chan installer.ProgressSnapshot, results <-chan map[string]string) tea.Cmd {
+	return func() tea.Msg {
+		snapshot, ok := <-updates
+		if !ok {
+			return InstallCompleteMsg{Results: <-results}
+		}
+		return ProgressMsg{Snapshot: snapshot, updates: updates, results: results}
 	}
-	cmd := exec.Command("apt-get", "upgrade", "-y", "openjdk-21-jdk")
-	return cmd.Run()
 }
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"slices"
+	"testing"
+
+	"decor/installer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestProgressGroups(t *testing.T) {
+	m := NewDownloadInstallModel(nil)
+	m.selectedLanguages = []string{"Go", "jq", "Python", "ripgrep", "Rust", "fd", "bat", "fzf", "Node.js"}
+	m.state = "installing"
+	m.userChoices["Rust"] = "skip"
+	for _, lang := range []string{"jq", "ripgrep", "fd", "bat", "fzf"} {
+		m.progress[lang] = installer.ProgressSnapshot{Language: lang, Progress: 1, Phase: installer.PhaseDone}
+	}
+	m.progress["Go"] = installer.ProgressSnapshot{Language: "Go", Progress: 0.5, Phase: installer.PhaseDownloading}
+
+	groups := m.groups()
+	if len(groups) != 3 || groups[0].category != "Languages" || !slices.Equal(groups[1].items, []string{"jq", "ripgrep", "fd", "bat", "fzf"}) {
+		t.Fatalf("groups = %+v", groups)
+	}
+	if progress, finished, failed, total := m.tally(groups[0].items); progress != 0.25 || finished != 0 || failed != 0 || total != 2 {
+		t.Errorf("tally of the languages = %v, %d, %d, %d, want 0.25 with none of the 2 not skipped finished", progress, finished, failed, total)
+	}
+	// A category that's all installed folds on its own, until it's toggled
+	if m.folded(groups[0]) || !m.folded(groups[1]) {
+		t.Error("only the finished category should be folded")
+	}
+	// The cursor goes through the unfolded languages' items to the tools' category
+	down := tea.KeyMsg{Type: tea.KeyDown}
+	for _, key := range []tea.KeyMsg{down, down, down, down, {Type: tea.KeySpace, Runes: []rune(" ")}} {
+		updated, _ := m.Update(key)
+		m = updated.(DownloadInstallModel)
+	}
+	if m.folded(groups[1]) {
+		t.Error("toggling the finished category didn't unfold it")
+	}
+}
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"decor/doctor"
+	"decor/i18n"
+	"decor/installer"
+	"decor/keymap"
+	"decor/runner"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// OldVersionsMsg carries the old versions garbage collection would delete
+type OldVersionsMsg struct {
+	Versions []installer.OldVersion
+	Err      error
+}
+
+// PrunedMsg is sent when the old versions have been deleted
+type PrunedMsg struct {
+	Freed int64
+	Err   error
+}
+
+// GCModel lists the old versions kept beside archive installs and deletes them once confirmed, showing
+// the space reclaimed
+type GCModel struct {
+	keep     int
+	versions []installer.OldVersion
+	state    string // "listing", "authenticating", "pruning" or "done"
+	loaded   bool
+	freed    int64
+	err      error
+}
+
+// NewGCModel creates the garbage collection screen keeping keep versions of each item; the versions are
+// listed by Init
+func NewGCModel(keep int) GCModel {
+	return GCModel{keep: keep, state: "listing"}
+}
+
+func (m GCModel) Init() tea.Cmd {
+	keep := m.keep
+	return func() tea.Msg {
+		versions, err := installer.OldVersions(keep)
+		return OldVersionsMsg{Versions: versions, Err: err}
+	}
+}
+
+func (m GCModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case OldVersionsMsg:
+		m.versions, m.err, m.loaded = msg.Versions, msg.Err, true
+	case AuthResultMsg:
+		if msg.Err != nil {
+			m.state, m.err = "done", msg.Err
+			return m, nil
+		}
+		m.state = "pruning"
+		return m, prune(m.versions)
+	case PrunedMsg:
+		m.state, m.freed, m.err = "done", msg.Freed, msg.Err
+	case tea.KeyMsg:
+		switch {
+		case keymap.Matches(msg, Keys.Quit):
+			return m, tea.Quit
+		case keymap.Matches(msg, Keys.Back):
+			if m.state == "listing" || m.state == "done" {
+				return m, Pop(nil)
+			}
+		case keymap.Matches(msg, Keys.Confirm):
+			if m.state == "listing" && m.loaded && len(m.versions) > 0 {
+				return m.start()
+			}
+		}
+	}
+	return m, nil
+}
+
+// start prompts for the password when deleting any version needs root, then deletes them
+func (m GCModel) start() (tea.Model, tea.Cmd) {
+	var languages []string
+	choices := make(map[string]string)
+	for _, v := range m.versions {
+		if choices[v.Item] == "" {
+			languages = append(languages, v.Item)
+			choices[v.Item] = "gc"
+		}
+	}
+	privileged := installer.Privileged()
+	if privileged.NeedsElevation() && installer.NeedsRoot(languages, choices) {
+		m.state = "authenticating"
+		return m, tea.ExecProcess(privileged.AuthCommand(), func(err error) tea.Msg {
+			return AuthResultMsg{Err: runner.CheckAuth(err)}
+		})
+	}
+	m.state = "pruning"
+	return m, prune(m.versions)
+}
+
+// prune deletes versions in the background
+func prune(versions []installer.OldVersion) tea.Cmd {
+	return func() tea.Msg {
+		freed, err := installer.Prune(context.Background(), versions)
+		return PrunedMsg{Freed: freed, Err: err}
+	}
+}
+
+// total adds up the sizes of the versions listed
+func (m GCModel) total() int64 {
+	var total int64
+	for _, v := range m.versions {
+		total += v.Size
+	}
+	return total
+}
+
+func (m GCModel) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("11")). // Yellow
+		MarginBottom(1)
+
+	descriptionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")) // Gray
+
+	var s strings.Builder
+	s.WriteString(titleStyle.Render(i18n.T("gc.title")) + "\n")
+	switch {
+	case !m.loaded:
+		s.WriteString(i18n.T("gc.reading") + "\n")
+	case m.state == "listing" && m.err != nil:
+		s.WriteString(i18n.T("gc.read_failed", m.err, Keys.Back.Help()) + "\n")
+	case m.state == "listing" && len(m.versions) == 0:
+		s.WriteString(i18n.T("gc.empty", m.keep, Keys.Back.Help()) + "\n")
+	case m.state == "listing":
+		for _, v := range m.versions {
+			fmt.Fprintf(&s, "  %-20s %-16s %10s %s\n", v.Item, v.Version, doctor.FormatBytes(uint64(v.Size)), descriptionStyle.Render(v.Dir))
+		}
+		s.WriteString("\n" + i18n.T("gc.help", doctor.FormatBytes(uint64(m.total())), Keys.Confirm.Help(), Keys.Back.Help()) + "\n")
+	case m.state == "authenticating", m.state == "pruning":
+		s.WriteString(i18n.T("gc.pruning") + "\n")
+	case m.err != nil:
+		s.WriteString(i18n.T("gc.failed", m.err, doctor.FormatBytes(uint64(m.freed)), Keys.Back.Help()) + "\n")
+	default:
+		s.WriteString(i18n.T("gc.done", doctor.FormatBytes(uint64(m.freed)), Keys.Back.Help()) + "\n")
+	}
+	return s.String()
+}
@@ -0,0 +1,187 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"decor/doctor"
+	"decor/history"
+	"decor/i18n"
+	"decor/installer"
+	"decor/keymap"
+	"decor/symbols"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// HistoryLoadedMsg carries the saved runs, newest first
+type HistoryLoadedMsg struct {
+	Runs []history.Run
+	Err  error
+}
+
+// historyHeight is how many lines of a run's report are shown at once
+const historyHeight = 20
+
+// HistoryModel lists previous runs and shows the full report of the one picked
+type HistoryModel struct {
+	runs    []history.Run
+	loaded  bool
+	err     error
+	cursor  int
+	viewing bool // showing the report of the run under the cursor
+	scroll  int  // first line of the report shown
+}
+
+// NewHistoryModel creates the history screen; the runs are read by Init
+func NewHistoryModel() HistoryModel {
+	return HistoryModel{}
+}
+
+func (m HistoryModel) Init() tea.Cmd {
+	return func() tea.Msg {
+		runs, err := history.List()
+		return HistoryLoadedMsg{Runs: runs, Err: err}
+	}
+}
+
+func (m HistoryModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case HistoryLoadedMsg:
+		m.runs, m.err, m.loaded = msg.Runs, msg.Err, true
+	case tea.MouseMsg:
+		m.move(wheel(msg))
+	case tea.KeyMsg:
+		switch {
+		case keymap.Matches(msg, Keys.Back):
+			if m.viewing {
+				m.viewing = false
+				return m, nil
+			}
+			return m, Pop(nil)
+		case keymap.Matches(msg, Keys.Quit):
+			return m, tea.Quit
+		case keymap.Matches(msg, Keys.Up):
+			m.move(-1)
+		case keymap.Matches(msg, Keys.Down):
+			m.move(1)
+		case keymap.Matches(msg, Keys.PageDown, Keys.Toggle):
+			if m.viewing {
+				m.scroll = max(0, min(m.scroll+historyHeight, len(m.report())-historyHeight))
+			}
+		case keymap.Matches(msg, Keys.PageUp):
+			if m.viewing {
+				m.scroll = max(0, m.scroll-historyHeight)
+			}
+		case keymap.Matches(msg, Keys.Confirm):
+			if !m.viewing && len(m.runs) > 0 {
+				m.viewing, m.scroll = true, 0
+			}
+		}
+	}
+	return m, nil
+}
+
+// move scrolls the report, or moves the cursor through the list, by delta lines
+func (m *HistoryModel) move(delta int) {
+	if m.viewing {
+		m.scroll = max(0, min(m.scroll+delta, len(m.report())-historyHeight))
+	} else if len(m.runs) > 0 {
+		m.cursor = max(0, min(m.cursor+delta, len(m.runs)-1))
+	}
+}
+
+// outcome sums up the run in the current language, e.g. "2 installed, 1 failed"
+func outcome(run history.Run) string {
+	counts := run.Counts()
+	var parts []string
+	for _, result := range history.Outcomes {
+		if counts[result] > 0 {
+			parts = append(parts, i18n.T("outcome."+result, counts[result]))
+		}
+	}
+	if len(parts) == 0 {
+		return i18n.T("history.nothing")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// report renders the selected run's full report: each item's outcome and timings, then the commands run
+func (m HistoryModel) report() []string {
+	run := m.runs[m.cursor]
+	lines := []string{
+		i18n.T("history.run", run.Started.Local().Format(i18n.T("history.date")), run.Elapsed.Round(time.Second), outcome(run)),
+		"",
+	}
+	for _, item := range run.Items {
+		lines = append(lines, fmt.Sprintf("%s (%s): %s", item.Name, item.Choice, i18n.Word("result", item.Result)))
+		if item.Hint != "" {
+			lines = append(lines, fmt.Sprintf("  %s %s", symbols.Arrow, item.Hint))
+		}
+		for _, note := range item.Notes {
+			lines = append(lines, fmt.Sprintf("  %s %s", symbols.Info, note))
+		}
+		var timings []string
+		for _, phase := range []string{installer.PhaseDownloading, installer.PhaseInstalling, installer.PhaseVerifying} {
+			if d := item.Timings[phase]; d > 0 {
+				timings = append(timings, fmt.Sprintf("%s %s", i18n.Word("phase", phase), d.Round(100*time.Millisecond)))
+			}
+		}
+		if item.Downloaded > 0 {
+			timings = append(timings, i18n.T("history.downloaded", doctor.FormatBytes(uint64(item.Downloaded))))
+		}
+		if len(timings) > 0 {
+			lines = append(lines, "  "+strings.Join(timings, ", "))
+		}
+	}
+	if run.Log != "" {
+		lines = append(lines, "", i18n.T("history.commands"))
+		lines = append(lines, strings.Split(strings.TrimRight(run.Log, "\n"), "\n")...)
+	}
+	return lines
+}
+
+func (m HistoryModel) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("11")). // Yellow
+		MarginBottom(1)
+
+	descriptionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")) // Gray
+
+	var s strings.Builder
+	s.WriteString(titleStyle.Render(i18n.T("history.title")) + "\n")
+	switch {
+	case !m.loaded:
+		s.WriteString(i18n.T("history.reading") + "\n")
+	case m.err != nil:
+		s.WriteString(i18n.T("history.read_failed", m.err, Keys.Back.Help()) + "\n")
+	case len(m.runs) == 0:
+		s.WriteString(i18n.T("history.empty", Keys.Back.Help()) + "\n")
+	case m.viewing:
+		lines := m.report()
+		end := min(m.scroll+historyHeight, len(lines))
+		s.WriteString(strings.Join(lines[m.scroll:end], "\n") + "\n")
+		if len(lines) > historyHeight {
+			s.WriteString(descriptionStyle.Render(i18n.T("history.lines", m.scroll+1, end, len(lines))) + "\n")
+		}
+		s.WriteString("\n" + i18n.T("history.report_help", Keys.Up.Help(), Keys.Down.Help(), Keys.PageUp.Help(), Keys.PageDown.Help(), Keys.Back.Help()) + "\n")
+	default:
+		for i, run := range m.runs {
+			cursor := " "
+			if m.cursor == i {
+				cursor = ">"
+			}
+			names := make([]string, len(run.Items))
+			for j, item := range run.Items {
+				names[j] = item.Name
+			}
+			fmt.Fprintf(&s, "%s %s  %-28s %s\n", cursor, run.Started.Local().Format("2006-01-02 15:04"), outcome(run), descriptionStyle.Render(strings.Join(names, ", ")))
+		}
+		s.WriteString("\n" + i18n.T("history.list_help", Keys.Confirm.Help(), Keys.Back.Help()) + "\n")
+	}
+	return s.String()
+}
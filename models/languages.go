@@ -2,9 +2,18 @@ package models
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
+	"decor/catalog"
+	"decor/config"
+	"decor/i18n"
+	"decor/installer"
+	"decor/keymap"
+	"decor/symbols"
+
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 type LanguageModel struct {
@@ -12,10 +21,24 @@ type LanguageModel struct {
 }
 
 func (m Decor) Init() tea.Cmd {
-	// Just return `nil`, which means "no I/O right now, please."
+	// Look for updates to what decor installed in the background, as often as the settings ask
+	if installer.UpdateCheckDue() {
+		return checkOutdated
+	}
 	return nil
 }
 
+// OutdatedMsg carries the decor-managed items the update check found a newer release for
+type OutdatedMsg struct {
+	Status []*installer.InstallationStatus
+}
+
+// checkOutdated runs the update check; a failed check just shows nothing
+func checkOutdated() tea.Msg {
+	outdated, _ := installer.Outdated()
+	return OutdatedMsg{Status: outdated}
+}
+
 func (m Decor) Selections() []string {
 	var selectedLanguages []string
 	for index := range m.Selected {
@@ -26,51 +49,107 @@ func (m Decor) Selections() []string {
 
 func (m Decor) InitialModel() Decor {
 	return Decor{
-		choices:  []string{"Go", "Python", "Rust", "C++", "Java"},
+		presets:  installer.OfferedPresets(),
+		choices:  installer.Offered(),
 		Selected: make(map[int]struct{}),
 	}
 }
 
+// togglePreset selects every item in the preset, or deselects them if they're all selected already
+func (m Decor) togglePreset(preset catalog.Preset) {
+	var indexes []int
+	allSelected := true
+	for _, name := range installer.PresetItems(preset) {
+		for i, choice := range m.choices {
+			if choice == name {
+				indexes = append(indexes, i)
+				if _, ok := m.Selected[i]; !ok {
+					allSelected = false
+				}
+			}
+		}
+	}
+	for _, i := range indexes {
+		if allSelected {
+			delete(m.Selected, i)
+		} else {
+			m.Selected[i] = struct{}{}
+		}
+	}
+}
+
+// Keys only the selection screen uses
+var (
+	continueKey  = keymap.NewBinding("n")
+	settingsKey  = keymap.NewBinding("s")
+	historyKey   = keymap.NewBinding("h")
+	statusKey    = keymap.NewBinding("t")
+	updateAllKey = keymap.NewBinding("U")
+	gcKey        = keymap.NewBinding("g")
+)
+
 func (m Decor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case OutdatedMsg:
+		m.outdated = msg.Status
 
 	// Is it a key press?
 	case tea.KeyMsg:
 
-		// Cool, what was the actual key pressed?
-		switch msg.String() {
+		switch {
 
 		// These keys should exit the program.
-		case "ctrl+c", "q":
+		case keymap.Matches(msg, Keys.Quit):
 			return m, tea.Quit
 
-		// The "up" and "k" keys move the cursor up
-		case "up", "k":
+		// The up keys move the cursor up
+		case keymap.Matches(msg, Keys.Up):
 			if m.cursor > 0 {
 				m.cursor--
 			}
 
-		// The "down" and "j" keys move the cursor down
-		case "down", "j":
-			if m.cursor < len(m.choices)-1 {
+		// The down keys move the cursor down
+		case keymap.Matches(msg, Keys.Down):
+			if m.cursor < len(m.presets)+len(m.choices)-1 {
 				m.cursor++
 			}
-		case "n":
-			// This is where we would transition to the next model, passing the selected languages.
-			selectedLanguages := m.Selections()
-			fmt.Printf("Selected languages: %s\n", strings.Join(selectedLanguages, ", "))
-			return NewDownloadInstallModel(m.Selections()), nil // This is just a placeholder. You would return the new model here.
-
-		// The "enter" key and the spacebar (a literal space) toggle
-		// the selected state for the item that the cursor is pointing at.
-		case "enter", " ":
-			_, ok := m.Selected[m.cursor]
-			if ok {
-				delete(m.Selected, m.cursor)
-			} else {
-				m.Selected[m.cursor] = struct{}{}
+		case keymap.Matches(msg, updateAllKey):
+			if len(m.outdated) > 0 {
+				languages := make([]string, len(m.outdated))
+				for i, status := range m.outdated {
+					languages[i] = status.Language
+				}
+				return m, Push(i18n.T("screen.update_all"), NewUpdateAllModel(languages))
 			}
+		case keymap.Matches(msg, continueKey):
+			return m, Push(i18n.T("screen.install"), NewDownloadInstallModel(m.Selections()))
+		case keymap.Matches(msg, settingsKey):
+			cfg, _, _ := config.Load()
+			return m, Push(i18n.T("screen.settings"), NewSettingsModel(cfg, false))
+		case keymap.Matches(msg, historyKey):
+			return m, Push(i18n.T("screen.history"), NewHistoryModel())
+		case keymap.Matches(msg, statusKey):
+			return m, Push(i18n.T("screen.status"), NewStatusModel(installer.Offered()))
+		case keymap.Matches(msg, gcKey):
+			cfg, _, _ := config.Load()
+			return m, Push(i18n.T("screen.gc"), NewGCModel(cfg.KeepVersions))
+
+		// The toggle and confirm keys toggle the selected state for the
+		// item that the cursor is pointing at.
+		case keymap.Matches(msg, Keys.Toggle, Keys.Confirm):
+			m.toggle()
 		}
+
+	// Clicking a row toggles it, and the wheel moves the cursor
+	case tea.MouseMsg:
+		if clicked(msg) {
+			_, rows := m.render()
+			if i := slices.Index(rows, msg.Y); i >= 0 {
+				m.cursor = i
+				m.toggle()
+			}
+		}
+		m.cursor = max(0, min(m.cursor+wheel(msg), len(m.presets)+len(m.choices)-1))
 	}
 
 	// Return the updated model to the Bubble Tea runtime for processing.
@@ -78,17 +157,74 @@ func (m Decor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// toggle selects or deselects the preset or item under the cursor
+func (m Decor) toggle() {
+	if m.cursor < len(m.presets) {
+		m.togglePreset(m.presets[m.cursor])
+		return
+	}
+	index := m.cursor - len(m.presets)
+	if _, ok := m.Selected[index]; ok {
+		delete(m.Selected, index)
+	} else {
+		m.Selected[index] = struct{}{}
+	}
+}
+
 func (m Decor) View() string {
-	// The header
+	view, _ := m.render()
+	return view
+}
+
+// render draws the selection screen, returning the line each row the cursor can be on is drawn at
+func (m Decor) render() (string, []int) {
 	var s strings.Builder
-	s.WriteString("What programming language(s) do you want to install?\n\n")
+	var rows []int
+	lines, counted := 0, 0
+	line := func() int {
+		lines += strings.Count(s.String()[counted:], "\n")
+		counted = s.Len()
+		return lines
+	}
 
-	// Iterate over our choices
+	// The header
+	if len(m.outdated) > 0 {
+		updates := make([]string, len(m.outdated))
+		for i, status := range m.outdated {
+			updates[i] = fmt.Sprintf("%s %s %s %s", status.Language, status.Version, symbols.Arrow, status.LatestVersion)
+		}
+		s.WriteString(i18n.T("select.updates", symbols.Update, strings.Join(updates, ", ")) + "\n\n")
+	}
+	s.WriteString(i18n.T("select.title") + "\n")
+
+	descriptionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")) // Gray
+
+	// Presets select a bundle of the items below
+	if len(m.presets) > 0 {
+		s.WriteString("\n" + i18n.T("select.presets") + "\n")
+	}
+	for i, preset := range m.presets {
+		cursor := " "
+		if m.cursor == i {
+			cursor = ">"
+		}
+		rows = append(rows, line())
+		fmt.Fprintf(&s, "%s     %s %s\n", cursor, preset.Name, descriptionStyle.Render("- "+preset.Description))
+	}
+
+	// Iterate over our choices, starting a heading whenever the category changes
+	category := ""
 	for i, choice := range m.choices {
+		item, _ := catalog.Find(choice)
+		if item.Category != category {
+			category = item.Category
+			fmt.Fprintf(&s, "\n%s\n", category)
+		}
 
 		// Is the cursor pointing at this choice?
 		cursor := " " // no cursor
-		if m.cursor == i {
+		if m.cursor == len(m.presets)+i {
 			cursor = ">" // cursor!
 		}
 
@@ -99,10 +235,11 @@ func (m Decor) View() string {
 		}
 
 		// Render the row
-		fmt.Fprintf(&s, "%s [%s] %s\n", cursor, checked, choice)
+		rows = append(rows, line())
+		fmt.Fprintf(&s, "%s [%s] %s %s\n", cursor, checked, choice, descriptionStyle.Render("- "+item.Description))
 	}
 
 	// Send the UI for rendering
-	fmt.Fprintln(&s, "\nPress space or enter to select.\nPress up/down or k/j to navigate. \nPress n to continue. \nPress q or ctrl+c to quit.")
-	return s.String()
+	s.WriteString("\n" + i18n.T("select.help", Keys.Toggle.Help(), Keys.Confirm.Help(), Keys.Up.Help(), Keys.Down.Help(), Keys.Quit.Help()) + "\n")
+	return s.String(), rows
 }
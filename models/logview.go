@@ -0,0 +1,201 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"decor/i18n"
+	"decor/installer"
+	"decor/keymap"
+	"decor/symbols"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Keys only the log viewer uses, on top of the shared ones
+var (
+	searchKey    = keymap.NewBinding("/")
+	nextMatchKey = keymap.NewBinding("n")
+	prevMatchKey = keymap.NewBinding("N")
+)
+
+// logChrome is how many lines the viewer's breadcrumb, title, status and help take from the terminal
+const logChrome = 7
+
+// LogViewModel pages through what a failed item's commands printed, taking the whole terminal, with / to
+// search it
+type LogViewModel struct {
+	title     string
+	lines     []string
+	height    int // lines of output shown at once
+	scroll    int // first line shown
+	searching bool
+	query     string // what's being searched for, or was last
+	matches   []int  // lines the query matches
+	match     int    // which of them was last jumped to
+}
+
+// NewLogViewModel creates a viewer of a failed item's error, hint and command output
+func NewLogViewModel(item string, snapshot installer.ProgressSnapshot) LogViewModel {
+	lines := []string{fmt.Sprintf("%s %s", symbols.Failed, snapshot.ErrorMessage)}
+	if snapshot.Hint != "" {
+		lines = append(lines, fmt.Sprintf("%s %s", symbols.Arrow, snapshot.Hint))
+	}
+	lines = append(lines, "")
+	if output := strings.TrimRight(snapshot.Output, "\n"); output != "" {
+		lines = append(lines, strings.Split(output, "\n")...)
+	} else {
+		lines = append(lines, i18n.T("logview.no_output"))
+	}
+	return LogViewModel{title: i18n.T("logview.title", item), lines: lines, height: historyHeight}
+}
+
+func (m LogViewModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m LogViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = max(5, msg.Height-logChrome)
+		m.scrollTo(m.scroll)
+	case tea.MouseMsg:
+		m.scrollTo(m.scroll + wheel(msg))
+	case tea.KeyMsg:
+		if m.searching {
+			return m.typeQuery(msg), nil
+		}
+		switch {
+		case keymap.Matches(msg, Keys.Back):
+			return m, Pop(nil)
+		case keymap.Matches(msg, Keys.Quit):
+			return m, tea.Quit
+		case keymap.Matches(msg, searchKey):
+			m.searching, m.query = true, ""
+		case keymap.Matches(msg, nextMatchKey):
+			m.jump(1)
+		case keymap.Matches(msg, prevMatchKey):
+			m.jump(-1)
+		case keymap.Matches(msg, Keys.Up):
+			m.scrollTo(m.scroll - 1)
+		case keymap.Matches(msg, Keys.Down):
+			m.scrollTo(m.scroll + 1)
+		case keymap.Matches(msg, Keys.PageUp):
+			m.scrollTo(m.scroll - m.height)
+		case keymap.Matches(msg, Keys.PageDown, Keys.Toggle):
+			m.scrollTo(m.scroll + m.height)
+		}
+	}
+	return m, nil
+}
+
+// typeQuery edits the search being typed; enter runs it and esc drops it
+func (m LogViewModel) typeQuery(msg tea.KeyMsg) LogViewModel {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.searching = false
+		m.search()
+	case tea.KeyEsc:
+		m.searching, m.query = false, ""
+		m.matches = nil
+	case tea.KeyBackspace:
+		if runes := []rune(m.query); len(runes) > 0 {
+			m.query = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.query += string(msg.Runes)
+	}
+	return m
+}
+
+// search finds the lines matching the query, ignoring case, and shows the first one from the top of the
+// page on
+func (m *LogViewModel) search() {
+	m.matches = nil
+	if m.query == "" {
+		return
+	}
+	query := strings.ToLower(m.query)
+	for i, line := range m.lines {
+		if strings.Contains(strings.ToLower(line), query) {
+			m.matches = append(m.matches, i)
+		}
+	}
+	m.match = 0
+	for i, line := range m.matches {
+		if line >= m.scroll {
+			m.match = i
+			break
+		}
+	}
+	if len(m.matches) > 0 {
+		m.scrollTo(m.matches[m.match])
+	}
+}
+
+// jump shows the next match, or the previous one when delta is -1, wrapping around
+func (m *LogViewModel) jump(delta int) {
+	if len(m.matches) == 0 {
+		return
+	}
+	m.match = (m.match + delta + len(m.matches)) % len(m.matches)
+	m.scrollTo(m.matches[m.match])
+}
+
+// scrollTo shows the page starting at line, kept within the output
+func (m *LogViewModel) scrollTo(line int) {
+	m.scroll = max(0, min(line, len(m.lines)-m.height))
+}
+
+// highlight marks where the query matches line
+func (m LogViewModel) highlight(line string) string {
+	lower := strings.ToLower(line)
+	if m.query == "" || m.matches == nil || len(lower) != len(line) {
+		return line
+	}
+	style := lipgloss.NewStyle().Reverse(true)
+	query := strings.ToLower(m.query)
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, query)
+		if i < 0 {
+			break
+		}
+		b.WriteString(line[:i] + style.Render(line[i:i+len(query)]))
+		line, lower = line[i+len(query):], lower[i+len(query):]
+	}
+	return b.String() + line
+}
+
+func (m LogViewModel) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("11")). // Yellow
+		MarginBottom(1)
+
+	descriptionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")) // Gray
+
+	var s strings.Builder
+	s.WriteString(titleStyle.Render(m.title) + "\n")
+	end := min(m.scroll+m.height, len(m.lines))
+	for _, line := range m.lines[m.scroll:end] {
+		s.WriteString(m.highlight(line) + "\n")
+	}
+	// Short output still fills the screen, so the status and help stay at the bottom
+	s.WriteString(strings.Repeat("\n", m.height-(end-m.scroll)))
+
+	status := i18n.T("history.lines", m.scroll+1, end, len(m.lines))
+	switch {
+	case m.searching:
+		status = "/" + m.query + "_"
+	case m.query != "" && len(m.matches) == 0:
+		status += ", " + i18n.T("logview.no_matches", m.query)
+	case m.query != "":
+		status += ", " + i18n.T("logview.match", m.match+1, len(m.matches), m.query)
+	}
+	s.WriteString(descriptionStyle.Render(status) + "\n")
+	s.WriteString(i18n.T("logview.help", Keys.Up.Help(), Keys.Down.Help(), searchKey.Help(), nextMatchKey.Help(), prevMatchKey.Help(), Keys.Back.Help()) + "\n")
+	return s.String()
+}
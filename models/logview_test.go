@@ -0,0 +1,65 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"decor/installer"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// keys sends each of keys to the viewer as if typed
+func keys(m LogViewModel, keys ...tea.KeyMsg) LogViewModel {
+	for _, key := range keys {
+		updated, _ := m.Update(key)
+		m = updated.(LogViewModel)
+	}
+	return m
+}
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestLogViewSearch(t *testing.T) {
+	var output strings.Builder
+	for i := range 100 {
+		fmt.Fprintf(&output, "line %d\n", i)
+	}
+	output.WriteString("E: Unable to locate package python3\n")
+	m := NewLogViewModel("Python", installer.ProgressSnapshot{ErrorMessage: "installing Python: exit status 100", Output: output.String()})
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 17})
+	m = updated.(LogViewModel)
+	if m.height != 10 {
+		t.Fatalf("height = %d on a 17-line terminal, want 10", m.height)
+	}
+
+	m = keys(m, runes("/"), runes("unable"), tea.KeyMsg{Type: tea.KeyEnter})
+	if len(m.matches) != 1 || m.scroll != len(m.lines)-m.height {
+		t.Errorf("searching for unable matched %v and scrolled to %d", m.matches, m.scroll)
+	}
+	if view := m.View(); !strings.Contains(view, "match 1 of 1") {
+		t.Errorf("the view doesn't say what matched:\n%s", view)
+	}
+
+	m = keys(m, runes("/"), runes("line 5"), tea.KeyMsg{Type: tea.KeyEnter})
+	// line 5 and line 50 to 59, from the top of the page on, wrapping around
+	if len(m.matches) != 11 || m.matches[m.match] != 2+5 {
+		t.Errorf("searching for line 5 matched %v, showing %d", m.matches, m.match)
+	}
+	m = keys(m, runes("n"))
+	if m.scroll != 2+50 {
+		t.Errorf("the next match scrolled to %d, want line 50's", m.scroll)
+	}
+	m = keys(m, runes("N"), runes("N"))
+	if m.matches[m.match] != 2+59 {
+		t.Errorf("going back past the first match showed %d, want the last", m.matches[m.match])
+	}
+
+	m = keys(m, runes("/"), tea.KeyMsg{Type: tea.KeyEsc})
+	if m.searching || m.query != "" || m.matches != nil {
+		t.Errorf("esc left the search %q with %v", m.query, m.matches)
+	}
+}
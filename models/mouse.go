@@ -0,0 +1,22 @@
+package models
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// wheel returns -1 when msg scrolls up and 1 when it scrolls down, and 0 for any other mouse event
+func wheel(msg tea.MouseMsg) int {
+	if msg.Action != tea.MouseActionPress {
+		return 0
+	}
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		return -1
+	case tea.MouseButtonWheelDown:
+		return 1
+	}
+	return 0
+}
+
+// clicked reports whether msg is a press of the left button
+func clicked(msg tea.MouseMsg) bool {
+	return msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft
+}
@@ -0,0 +1,213 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"decor/i18n"
+	"decor/installer"
+	"decor/keymap"
+	"decor/manifest"
+	"decor/project"
+	"decor/symbols"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ProjectCheckedMsg carries where the project's tools stand, and the version manager that checked them
+type ProjectCheckedMsg struct {
+	Manager  string
+	Statuses []project.Status
+	Envrc    bool // the project's .envrc loads the manager's versions
+	Err      error
+}
+
+// ProjectInstalledMsg carries how installing the project's missing tools went, an error or the version
+// installed for each
+type ProjectInstalledMsg struct {
+	Results []string
+}
+
+// EnvrcWrittenMsg is sent when the project's .envrc has been written and allowed
+type EnvrcWrittenMsg struct {
+	Err error
+}
+
+// ProjectModel shows the tools a repository's .decor file asks for and installs the missing ones into the
+// project with mise or asdf. It opens on top of the selection screen when decor starts in a project.
+type ProjectModel struct {
+	path     string
+	manager  string
+	statuses []project.Status
+	results  []string // what installing each missing tool did, in order
+	envrc    bool     // the project's .envrc loads the manager's versions
+	state    string   // "checking", "listing" or "installing"
+	err      error
+}
+
+// Keys only the project screen uses
+var (
+	recheckKey = keymap.NewBinding("r")
+	envrcKey   = keymap.NewBinding("e")
+)
+
+// NewProjectModel creates the project screen for the .decor file at path; Init checks its tools
+func NewProjectModel(path string) ProjectModel {
+	return ProjectModel{path: path, state: "checking"}
+}
+
+func (m ProjectModel) Init() tea.Cmd {
+	path := m.path
+	return func() tea.Msg {
+		entries, err := manifest.Load(path)
+		if err != nil {
+			return ProjectCheckedMsg{Err: err}
+		}
+		// With no manager nothing's checked, and the screen offers to install mise
+		manager := project.Manager()
+		statuses := project.Check(context.Background(), installer.Privileged(), manager, entries)
+		return ProjectCheckedMsg{Manager: manager, Statuses: statuses, Envrc: project.EnvrcActivates(filepath.Dir(path), manager)}
+	}
+}
+
+// missing returns the tools no installed version of matches
+func (m ProjectModel) missing() []project.Status {
+	var missing []project.Status
+	for _, s := range m.statuses {
+		if s.Installed == "" {
+			missing = append(missing, s)
+		}
+	}
+	return missing
+}
+
+func (m ProjectModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case ProjectCheckedMsg:
+		m.manager, m.statuses, m.envrc, m.err, m.state = msg.Manager, msg.Statuses, msg.Envrc, msg.Err, "listing"
+	case EnvrcWrittenMsg:
+		if msg.Err != nil {
+			m.results = append(m.results, fmt.Sprintf("%s %s: %v", symbols.Failed, project.Envrc, msg.Err))
+		} else {
+			m.results = append(m.results, fmt.Sprintf("%s %s", symbols.OK, i18n.T("project.envrc_written", filepath.Join(filepath.Dir(m.path), project.Envrc))))
+			m.envrc = true
+		}
+	case ProjectInstalledMsg:
+		m.results, m.state = msg.Results, "checking"
+		return m, m.Init()
+	case tea.KeyMsg:
+		if m.state != "listing" {
+			if keymap.Matches(msg, Keys.Quit) {
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+		switch {
+		case keymap.Matches(msg, Keys.Quit):
+			return m, tea.Quit
+		case keymap.Matches(msg, Keys.Back):
+			return m, Pop(nil)
+		case keymap.Matches(msg, recheckKey):
+			m.state, m.results = "checking", nil
+			return m, m.Init()
+		case keymap.Matches(msg, envrcKey):
+			if m.err != nil || m.manager == "" || m.envrc {
+				return m, nil
+			}
+			if !project.HasDirenv() {
+				return m, Push(i18n.T("screen.install"), NewDownloadInstallModel([]string{"direnv"}))
+			}
+			return m, writeEnvrc(filepath.Dir(m.path), m.manager)
+		case keymap.Matches(msg, Keys.Confirm):
+			if m.err != nil {
+				return m, nil
+			}
+			if m.manager == "" {
+				return m, Push(i18n.T("screen.install"), NewDownloadInstallModel([]string{"mise"}))
+			}
+			if missing := m.missing(); len(missing) > 0 {
+				m.state = "installing"
+				return m, installProject(m.manager, filepath.Dir(m.path), missing)
+			}
+		}
+	}
+	return m, nil
+}
+
+// installProject installs the missing tools into the project in dir, one after another
+func installProject(manager, dir string, missing []project.Status) tea.Cmd {
+	return func() tea.Msg {
+		results := make([]string, len(missing))
+		for i, s := range missing {
+			version, err := project.Install(context.Background(), installer.Privileged(), manager, dir, s)
+			if err != nil {
+				results[i] = fmt.Sprintf("%s %s: %v", symbols.Failed, s.Name, err)
+			} else {
+				results[i] = fmt.Sprintf("%s %s", symbols.OK, i18n.T("project.installed", s.Name, version))
+			}
+		}
+		return ProjectInstalledMsg{Results: results}
+	}
+}
+
+// writeEnvrc writes and allows the .envrc in dir that loads manager's versions
+func writeEnvrc(dir, manager string) tea.Cmd {
+	return func() tea.Msg {
+		return EnvrcWrittenMsg{Err: project.WriteEnvrc(context.Background(), installer.Privileged(), dir, manager)}
+	}
+}
+
+func (m ProjectModel) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("11")). // Yellow
+		MarginBottom(1)
+
+	descriptionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")) // Gray
+
+	var s strings.Builder
+	s.WriteString(titleStyle.Render(i18n.T("project.title", m.path)) + "\n")
+	if m.err != nil {
+		s.WriteString(i18n.T("project.read_failed", m.err, Keys.Back.Help()) + "\n")
+		return s.String()
+	}
+	for _, st := range m.statuses {
+		want := st.Version
+		if want == "" {
+			want = i18n.T("project.any")
+		}
+		switch {
+		case st.Installed != "":
+			fmt.Fprintf(&s, "  %s %-16s %s\n", symbols.OK, st.Name, st.Installed)
+		case m.manager == "":
+			fmt.Fprintf(&s, "  %s %-16s %s\n", symbols.Info, st.Name, descriptionStyle.Render(want))
+		default:
+			fmt.Fprintf(&s, "  %s %-16s %s\n", symbols.Failed, st.Name, descriptionStyle.Render(i18n.T("project.missing", want)))
+		}
+	}
+	if len(m.results) > 0 {
+		s.WriteString("\n" + strings.Join(m.results, "\n") + "\n")
+	}
+
+	s.WriteString("\n")
+	switch {
+	case m.state == "checking":
+		s.WriteString(i18n.T("project.checking") + "\n")
+	case m.state == "installing":
+		s.WriteString(i18n.T("project.installing", m.manager) + "\n")
+	case m.manager == "":
+		s.WriteString(i18n.T("project.no_manager", Keys.Confirm.Help(), recheckKey.Help(), Keys.Back.Help()) + "\n")
+	case len(m.missing()) > 0:
+		s.WriteString(i18n.T("project.help", Keys.Confirm.Help(), m.manager, recheckKey.Help(), Keys.Back.Help()) + "\n")
+	default:
+		s.WriteString(i18n.T("project.ready", Keys.Back.Help()) + "\n")
+	}
+	if m.state == "listing" && m.manager != "" && !m.envrc {
+		s.WriteString(descriptionStyle.Render(i18n.T("project.envrc_help", envrcKey.Help())) + "\n")
+	}
+	return s.String()
+}
@@ -0,0 +1,140 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"decor/symbols"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Screen is a model the router shows under a name, used in the breadcrumb
+type Screen struct {
+	Name  string
+	Model tea.Model
+}
+
+// PushMsg asks the router to show a screen on top of the current one
+type PushMsg struct {
+	Screen Screen
+}
+
+// PopMsg asks the router to go back to the previous screen, which is sent Payload if it isn't nil, e.g.
+// to say what the closed screen did
+type PopMsg struct {
+	Payload tea.Msg
+}
+
+// Push returns a command that opens model as a new screen called name. Screens pass what the next one
+// needs, like the selected items, to its constructor.
+func Push(name string, model tea.Model) tea.Cmd {
+	return func() tea.Msg {
+		return PushMsg{Screen: Screen{Name: name, Model: model}}
+	}
+}
+
+// Pop returns a command that closes the current screen, handing payload to the one below
+func Pop(payload tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return PopMsg{Payload: payload}
+	}
+}
+
+// Router shows a stack of screens: messages go to the top one, which can push new screens or pop back
+type Router struct {
+	stack  []Screen
+	width  int // the terminal's, for screens opened later
+	height int // the terminal's, to work out which line of the top screen the mouse is on
+}
+
+// NewRouter creates a router showing root, with any screens given opened on top of it
+func NewRouter(root Screen, screens ...Screen) Router {
+	return Router{stack: append([]Screen{root}, screens...)}
+}
+
+// Top returns the screen being shown
+func (r Router) Top() Screen {
+	return r.stack[len(r.stack)-1]
+}
+
+// Depth is how many screens are open
+func (r Router) Depth() int {
+	return len(r.stack)
+}
+
+func (r Router) Init() tea.Cmd {
+	return r.Top().Model.Init()
+}
+
+func (r Router) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		r.width, r.height = msg.Width, msg.Height
+	case PushMsg:
+		r.stack = append(slices.Clone(r.stack), msg.Screen)
+		cmd := msg.Screen.Model.Init()
+		if r.height > 0 {
+			// The terminal's size only arrives when it changes, so a new screen is told it now
+			updated, sizeCmd := msg.Screen.Model.Update(tea.WindowSizeMsg{Width: r.width, Height: r.height})
+			r.stack[len(r.stack)-1].Model = updated
+			cmd = tea.Batch(cmd, sizeCmd)
+		}
+		return r, cmd
+	case PopMsg:
+		// The root screen stays
+		if len(r.stack) == 1 {
+			return r, nil
+		}
+		r.stack = r.stack[:len(r.stack)-1]
+		if msg.Payload == nil {
+			return r, nil
+		}
+	}
+
+	if pop, ok := msg.(PopMsg); ok {
+		msg = pop.Payload
+	}
+	if mouse, ok := msg.(tea.MouseMsg); ok {
+		mouse.Y = r.screenLine(mouse.Y)
+		msg = mouse
+	}
+	// Copies of the router share the stack, so it's copied before the top screen is replaced
+	top := len(r.stack) - 1
+	updated, cmd := r.stack[top].Model.Update(msg)
+	r.stack = slices.Clone(r.stack)
+	r.stack[top].Model = updated
+	return r, cmd
+}
+
+// screenLine converts a row of the terminal to a line of the top screen's view. A view taller than the
+// terminal has its top cut off, and the breadcrumb sits above the view.
+func (r Router) screenLine(row int) int {
+	if lines := strings.Count(r.View(), "\n") + 1; r.height > 0 && lines > r.height {
+		row += lines - r.height
+	}
+	if len(r.stack) > 1 {
+		row -= 2
+	}
+	return row
+}
+
+// Breadcrumb names the open screens, e.g. "Select › Install"
+func (r Router) Breadcrumb() string {
+	names := make([]string, len(r.stack))
+	for i, screen := range r.stack {
+		names[i] = screen.Name
+	}
+	return strings.Join(names, " "+symbols.Separator.String()+" ")
+}
+
+func (r Router) View() string {
+	if len(r.stack) == 1 {
+		return r.Top().Model.View()
+	}
+	breadcrumbStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")) // Gray
+	return breadcrumbStyle.Render(fmt.Sprintf("decor %s %s", symbols.Separator, r.Breadcrumb())) + "\n\n" + r.Top().Model.View()
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// screen records the messages it gets, and pushes or pops when sent "push" or "pop"
+type screen struct {
+	name string
+	got  []tea.Msg
+}
+
+func (s screen) Init() tea.Cmd { return nil }
+
+func (s screen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	s.got = append(s.got, msg)
+	switch msg {
+	case "push":
+		return s, Push("Child", screen{name: "child"})
+	case "pop":
+		return s, Pop("closed")
+	}
+	return s, nil
+}
+
+func (s screen) View() string { return s.name }
+
+// send delivers msg to the router and then any message its command returns
+func send(r Router, msg tea.Msg) Router {
+	updated, cmd := r.Update(msg)
+	r = updated.(Router)
+	if cmd != nil {
+		r = send(r, cmd())
+	}
+	return r
+}
+
+func TestRouter(t *testing.T) {
+	r := NewRouter(Screen{Name: "Select", Model: screen{name: "root"}})
+	if r.View() != "root" {
+		t.Errorf("the root screen has a breadcrumb: %q", r.View())
+	}
+
+	r = send(r, "push")
+	if r.Depth() != 2 || r.Top().Name != "Child" {
+		t.Fatalf("after a push the top is %q at depth %d", r.Top().Name, r.Depth())
+	}
+	if view := r.View(); !strings.Contains(view, "decor › Select › Child") || !strings.HasSuffix(view, "child") {
+		t.Errorf("the child's view is %q", view)
+	}
+
+	r = send(r, "hello")
+	if got := r.Top().Model.(screen).got; len(got) != 1 || got[0] != "hello" {
+		t.Errorf("the child got %v", got)
+	}
+
+	r = send(r, "pop")
+	if r.Depth() != 1 {
+		t.Fatalf("after a pop the depth is %d", r.Depth())
+	}
+	// The root saw the push and then the child's payload
+	if got := r.Top().Model.(screen).got; len(got) != 2 || got[1] != "closed" {
+		t.Errorf("the root got %v", got)
+	}
+
+	r = send(r, PopMsg{})
+	if r.Depth() != 1 {
+		t.Error("popped the root screen")
+	}
+}
+
+func TestRouterMouse(t *testing.T) {
+	r := NewRouter(Screen{Name: "Select", Model: screen{name: "root"}})
+	r = send(r, "push")
+	// The child's view is under the breadcrumb and a blank line
+	r = send(r, tea.WindowSizeMsg{Width: 80, Height: 24})
+	r = send(r, tea.MouseMsg{Y: 2, Button: tea.MouseButtonLeft})
+	// The shorter terminal cuts off the breadcrumb's line
+	r = send(r, tea.WindowSizeMsg{Width: 80, Height: 2})
+	r = send(r, tea.MouseMsg{Y: 1, Button: tea.MouseButtonLeft})
+
+	got := r.Top().Model.(screen).got
+	if len(got) != 4 {
+		t.Fatalf("the child got %v", got)
+	}
+	for _, i := range []int{1, 3} {
+		if mouse := got[i].(tea.MouseMsg); mouse.Y != 0 {
+			t.Errorf("a click on the child's first line arrived at line %d", mouse.Y)
+		}
+	}
+}
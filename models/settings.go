@@ -0,0 +1,354 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"decor/config"
+	"decor/i18n"
+	"decor/installer"
+	"decor/keymap"
+	"decor/symbols"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Keys are the navigation, confirm and cancel keys every screen uses, set from the [keys] table by
+// ApplyConfig
+var Keys = keymap.Default()
+
+// ApplyConfig makes the installers and views respect the given preferences. A bad [keys] table, a
+// language decor doesn't speak or a custom tool clashing with decor's leaves the defaults in place and
+// is returned as an error.
+func ApplyConfig(cfg config.Config) error {
+	toolsErr := installer.Configure(cfg)
+	ApplyTheme(cfg.Theme)
+	var keysErr error
+	Keys, keysErr = keymap.New(cfg.KeyStyle, cfg.Keys)
+	return errors.Join(toolsErr, keysErr, i18n.SetLocale(i18n.Detect(cfg.Language)))
+}
+
+// ForcedTheme overrides the theme in the settings when set, from --ascii or --no-color
+var ForcedTheme string
+
+// ApplyTheme sets the colors and symbols of every view and command for theme, or ForcedTheme if it's set
+func ApplyTheme(theme string) {
+	if ForcedTheme != "" {
+		theme = ForcedTheme
+	}
+	symbols.SetASCII(theme == "ascii")
+	switch theme {
+	case "mono", "ascii":
+		lipgloss.SetColorProfile(termenv.Ascii)
+	default:
+		lipgloss.SetColorProfile(termenv.EnvColorProfile())
+	}
+}
+
+// mouseMode turns mouse clicks and scrolling, and the full-screen view they need to line up with what's
+// drawn, on or off
+func mouseMode(on bool) tea.Cmd {
+	if on {
+		return tea.Batch(tea.EnterAltScreen, tea.EnableMouseCellMotion)
+	}
+	return tea.Batch(tea.DisableMouse, tea.ExitAltScreen)
+}
+
+// settingField is one editable preference and the values it cycles through
+type settingField struct {
+	name    string // the key in config.toml, and the field's message IDs: settings.<name> and settings.<name>.help
+	options []string
+	get     func(config.Config) string
+	set     func(*config.Config, string)
+}
+
+var settingFields = []settingField{
+	{
+		name:    "package_manager",
+		options: []string{"auto", "brew", "apt"},
+		get:     func(c config.Config) string { return c.PackageManager },
+		set:     func(c *config.Config, v string) { c.PackageManager = v },
+	},
+	{
+		name:    "install_prefix",
+		options: []string{"/usr/local", "~/.local", "/opt"},
+		get:     func(c config.Config) string { return c.InstallPrefix },
+		set:     func(c *config.Config, v string) { c.InstallPrefix = v },
+	},
+	{
+		name:    "sudo_policy",
+		options: []string{"auto", "sudo", "doas", "none"},
+		get:     func(c config.Config) string { return c.SudoPolicy },
+		set:     func(c *config.Config, v string) { c.SudoPolicy = v },
+	},
+	{
+		name:    "theme",
+		options: []string{"default", "mono", "ascii"},
+		get:     func(c config.Config) string { return c.Theme },
+		set:     func(c *config.Config, v string) { c.Theme = v },
+	},
+	{
+		name:    "telemetry",
+		options: []string{"off", "on"},
+		get: func(c config.Config) string {
+			if c.Telemetry {
+				return "on"
+			}
+			return "off"
+		},
+		set: func(c *config.Config, v string) { c.Telemetry = v == "on" },
+	},
+	{
+		name:    "local_metrics",
+		options: []string{"off", "on"},
+		get: func(c config.Config) string {
+			if c.LocalMetrics {
+				return "on"
+			}
+			return "off"
+		},
+		set: func(c *config.Config, v string) { c.LocalMetrics = v == "on" },
+	},
+	{
+		name:    "channel",
+		options: []string{"stable", "lts"},
+		get:     func(c config.Config) string { return c.Channel },
+		set:     func(c *config.Config, v string) { c.Channel = v },
+	},
+	{
+		name:    "update_check",
+		options: []string{"daily", "weekly", "never"},
+		get:     func(c config.Config) string { return c.UpdateCheck },
+		set:     func(c *config.Config, v string) { c.UpdateCheck = v },
+	},
+	{
+		name:    "python_manager",
+		options: []string{"uv", "conda"},
+		get:     func(c config.Config) string { return c.PythonManager },
+		set:     func(c *config.Config, v string) { c.PythonManager = v },
+	},
+	{
+		name:    "local_cluster",
+		options: []string{"kind", "minikube"},
+		get:     func(c config.Config) string { return c.LocalCluster },
+		set:     func(c *config.Config, v string) { c.LocalCluster = v },
+	},
+	{
+		name:    "cpp_compiler",
+		options: []string{"gcc", "gcc-13", "gcc-14", "clang", "clang-18", "clang-19"},
+		get:     func(c config.Config) string { return c.CppCompiler },
+		set:     func(c *config.Config, v string) { c.CppCompiler = v },
+	},
+	{
+		name:    "cpp_package_manager",
+		options: []string{"vcpkg", "conan"},
+		get:     func(c config.Config) string { return c.CppPackages },
+		set:     func(c *config.Config, v string) { c.CppPackages = v },
+	},
+	{
+		name:    "java_vendor",
+		options: []string{"temurin", "zulu", "corretto", "graalvm"},
+		get:     func(c config.Config) string { return c.JavaVendor },
+		set:     func(c *config.Config, v string) { c.JavaVendor = v },
+	},
+	{
+		name:    "java_version",
+		options: []string{"21", "17", "11", "latest"},
+		get:     func(c config.Config) string { return c.JavaVersion },
+		set:     func(c *config.Config, v string) { c.JavaVersion = v },
+	},
+	{
+		name:    "starter_configs",
+		options: []string{"off", "on"},
+		get: func(c config.Config) string {
+			if c.StarterConfigs {
+				return "on"
+			}
+			return "off"
+		},
+		set: func(c *config.Config, v string) { c.StarterConfigs = v == "on" },
+	},
+	{
+		name:    "wsl_winget",
+		options: []string{"off", "on"},
+		get: func(c config.Config) string {
+			if c.WSLWinget {
+				return "on"
+			}
+			return "off"
+		},
+		set: func(c *config.Config, v string) { c.WSLWinget = v == "on" },
+	},
+	{
+		name:    "mouse",
+		options: []string{"off", "on"},
+		get: func(c config.Config) string {
+			if c.Mouse {
+				return "on"
+			}
+			return "off"
+		},
+		set: func(c *config.Config, v string) { c.Mouse = v == "on" },
+	},
+	{
+		name:    "keys",
+		options: keymap.Styles,
+		get:     func(c config.Config) string { return c.KeyStyle },
+		set:     func(c *config.Config, v string) { c.KeyStyle = v },
+	},
+	{
+		name:    "language",
+		options: append([]string{"auto"}, i18n.Locales()...),
+		get:     func(c config.Config) string { return c.Language },
+		set:     func(c *config.Config, v string) { c.Language = v },
+	},
+	{
+		name:    "project_dir",
+		options: []string{"~/projects", "~/src", "~/code"},
+		get:     func(c config.Config) string { return c.ProjectDir },
+		set:     func(c *config.Config, v string) { c.ProjectDir = v },
+	},
+}
+
+// nextField moves to the next field as well as the down keys
+var nextField = keymap.NewBinding("tab")
+
+// SettingsClosedMsg is handed to the previous screen when the settings screen is saved or dismissed
+type SettingsClosedMsg struct {
+	Saved bool
+}
+
+// SettingsModel lets the user change their preferences, and doubles as the first-run wizard
+type SettingsModel struct {
+	config   config.Config
+	cursor   int
+	firstRun bool
+	err      error
+}
+
+// NewSettingsModel creates a settings screen editing cfg
+func NewSettingsModel(cfg config.Config, firstRun bool) SettingsModel {
+	return SettingsModel{config: cfg, firstRun: firstRun}
+}
+
+func (m SettingsModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		m.cursor = max(0, min(m.cursor+wheel(msg), len(settingFields)-1))
+	case tea.KeyMsg:
+		switch {
+		case keymap.Matches(msg, Keys.Quit):
+			return m, tea.Quit
+		case keymap.Matches(msg, Keys.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case keymap.Matches(msg, Keys.Down, nextField):
+			if m.cursor < len(settingFields)-1 {
+				m.cursor++
+			}
+		case keymap.Matches(msg, Keys.Right, Keys.Toggle):
+			m.cycle(1)
+		case keymap.Matches(msg, Keys.Left):
+			m.cycle(-1)
+		case keymap.Matches(msg, Keys.Confirm):
+			// The wizard walks through every field before saving
+			if m.firstRun && m.cursor < len(settingFields)-1 {
+				m.cursor++
+				return m, nil
+			}
+			if err := config.Save(m.config); err != nil {
+				m.err = err
+				return m, nil
+			}
+			ApplyConfig(m.config)
+			return m, tea.Batch(mouseMode(m.config.Mouse), Pop(SettingsClosedMsg{Saved: true}))
+		case keymap.Matches(msg, Keys.Back):
+			if !m.firstRun {
+				return m, Pop(SettingsClosedMsg{Saved: false})
+			}
+		}
+	}
+	return m, nil
+}
+
+// cycle moves the selected field to its next or previous option
+func (m *SettingsModel) cycle(delta int) {
+	field := settingFields[m.cursor]
+	options := fieldOptions(field, m.config)
+	current := field.get(m.config)
+
+	index := 0
+	for i, option := range options {
+		if option == current {
+			index = i
+			break
+		}
+	}
+	index = (index + delta + len(options)) % len(options)
+	field.set(&m.config, options[index])
+}
+
+// fieldOptions returns the field's options, including a custom value set in the config file
+func fieldOptions(field settingField, cfg config.Config) []string {
+	current := field.get(cfg)
+	for _, option := range field.options {
+		if option == current {
+			return field.options
+		}
+	}
+	return append([]string{current}, field.options...)
+}
+
+func (m SettingsModel) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("11")). // Yellow
+		MarginBottom(1)
+
+	labelStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("6")). // Cyan
+		Width(24)
+
+	descriptionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")) // Gray
+
+	var s strings.Builder
+	if m.firstRun {
+		s.WriteString(titleStyle.Render(i18n.T("settings.welcome")) + "\n")
+	} else {
+		s.WriteString(titleStyle.Render(i18n.T("settings.title")) + "\n")
+	}
+
+	for i, field := range settingFields {
+		cursor := " "
+		if m.cursor == i {
+			cursor = ">"
+		}
+		value := fmt.Sprintf("< %s >", field.get(m.config))
+		fmt.Fprintf(&s, "%s %s %s\n", cursor, labelStyle.Render(i18n.T("settings."+field.name)), value)
+		if m.cursor == i {
+			s.WriteString("    " + descriptionStyle.Render(i18n.T("settings."+field.name+".help")) + "\n")
+		}
+	}
+
+	if m.err != nil {
+		s.WriteString("\n" + i18n.T("settings.save_failed", m.err) + "\n")
+	}
+
+	path, _ := config.Path()
+	if m.firstRun {
+		s.WriteString("\n" + i18n.T("settings.wizard_help", Keys.Left.Help(), Keys.Right.Help(), Keys.Confirm.Help(), path) + "\n")
+	} else {
+		s.WriteString("\n" + i18n.T("settings.help", Keys.Left.Help(), Keys.Right.Help(), Keys.Up.Help(), Keys.Down.Help(), Keys.Confirm.Help(), path, Keys.Back.Help()) + "\n")
+	}
+	return s.String()
+}
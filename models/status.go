@@ -0,0 +1,168 @@
+package models
+
+import (
+	"strings"
+
+	"decor/i18n"
+	"decor/installer"
+	"decor/keymap"
+	"decor/symbols"
+	"decor/table"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// statusHeight is how many rows of the status table are shown at once
+const statusHeight = 20
+
+// StatusTable lays out what's known about each item: its installed and latest versions, what installed
+// it, and whether decor did, sorted by name
+func StatusTable(statuses []*installer.InstallationStatus) *table.Table {
+	t := table.New(i18n.T("overview.item"), i18n.T("overview.installed"), i18n.T("overview.latest"), i18n.T("overview.source"), i18n.T("overview.managed"), i18n.T("overview.arch"))
+	for _, s := range statuses {
+		version, latest, source, managed, arch := s.Version, "", "", "", s.Arch
+		if s.TimedOut {
+			version = i18n.T("overview.timed_out")
+		}
+		if s.Installed {
+			latest, source = s.LatestVersion, i18n.Word("source", s.Source)
+		}
+		if s.Managed {
+			managed = symbols.OK.String()
+		}
+		if s.Rosetta {
+			arch = symbols.Warning.String() + " " + i18n.T("overview.rosetta", arch)
+		}
+		t.Add(s.Language, version, latest, source, managed, arch)
+	}
+	t.Sort()
+	return t
+}
+
+// StatusModel shows every item decor supports in a table that can be sorted by any column
+type StatusModel struct {
+	languages []string
+	statuses  []*installer.InstallationStatus
+	table     *table.Table
+	done      bool // every item has been checked
+	cursor    int
+	scroll    int // first row shown
+}
+
+// NewStatusModel creates the status screen for languages, which Init starts checking
+func NewStatusModel(languages []string) StatusModel {
+	return StatusModel{languages: languages, table: StatusTable(nil)}
+}
+
+func (m StatusModel) Init() tea.Cmd {
+	languages := m.languages
+	return func() tea.Msg {
+		return waitForCheck(installer.CheckStream(languages))()
+	}
+}
+
+func (m StatusModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case CheckResultMsg:
+		m.add(msg.Status)
+		return m, waitForCheck(msg.results)
+	case checksDoneMsg:
+		m.done = true
+	case tea.MouseMsg:
+		m.move(wheel(msg))
+	case tea.KeyMsg:
+		switch {
+		case keymap.Matches(msg, Keys.Back):
+			return m, Pop(nil)
+		case keymap.Matches(msg, Keys.Quit):
+			return m, tea.Quit
+		case keymap.Matches(msg, Keys.Up):
+			m.move(-1)
+		case keymap.Matches(msg, Keys.Down):
+			m.move(1)
+		case keymap.Matches(msg, Keys.PageUp):
+			m.move(-statusHeight)
+		case keymap.Matches(msg, Keys.PageDown):
+			m.move(statusHeight)
+		case keymap.Matches(msg, Keys.Left):
+			m.sortOn((m.table.SortBy + len(m.table.Columns) - 1) % len(m.table.Columns))
+		case keymap.Matches(msg, Keys.Right):
+			m.sortOn((m.table.SortBy + 1) % len(m.table.Columns))
+		case keymap.Matches(msg, Keys.Toggle, Keys.Confirm):
+			m.sortOn(m.table.SortBy)
+		}
+	}
+	return m, nil
+}
+
+// add puts a newly checked item in the table, keeping its order and the row under the cursor
+func (m *StatusModel) add(status *installer.InstallationStatus) {
+	m.statuses = append(m.statuses, status)
+	m.rebuild((*table.Table).Sort)
+}
+
+// sortOn sorts by column, or reverses the order if the table is sorted by it already
+func (m *StatusModel) sortOn(column int) {
+	m.rebuild(func(t *table.Table) {
+		t.SortOn(column)
+	})
+}
+
+// rebuild lays the table out again and reorders it with sort, keeping the cursor on the same item
+func (m *StatusModel) rebuild(sort func(*table.Table)) {
+	selected := ""
+	if m.cursor < len(m.table.Rows) {
+		selected = m.table.Rows[m.cursor][0]
+	}
+	sortBy, reverse := m.table.SortBy, m.table.Reverse
+	m.table = StatusTable(m.statuses)
+	m.table.SortBy, m.table.Reverse = sortBy, reverse
+	sort(m.table)
+	for i, row := range m.table.Rows {
+		if row[0] == selected {
+			m.cursor = i
+		}
+	}
+	m.move(0)
+}
+
+// move moves the cursor by delta rows, scrolling to keep it in view
+func (m *StatusModel) move(delta int) {
+	m.cursor = max(0, min(m.cursor+delta, len(m.table.Rows)-1))
+	if m.cursor < m.scroll {
+		m.scroll = m.cursor
+	} else if m.cursor >= m.scroll+statusHeight {
+		m.scroll = m.cursor - statusHeight + 1
+	}
+}
+
+func (m StatusModel) View() string {
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("11")). // Yellow
+		MarginBottom(1)
+
+	headerStyle := lipgloss.NewStyle().Bold(true)
+	descriptionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("8")) // Gray
+
+	var s strings.Builder
+	s.WriteString(titleStyle.Render(i18n.T("overview.title")) + "\n")
+	s.WriteString(headerStyle.Render("  "+m.table.Header()) + "\n")
+	end := min(m.scroll+statusHeight, len(m.table.Rows))
+	for i := m.scroll; i < end; i++ {
+		cursor := " "
+		if m.cursor == i {
+			cursor = ">"
+		}
+		s.WriteString(cursor + " " + m.table.Line(m.table.Rows[i]) + "\n")
+	}
+	if !m.done {
+		s.WriteString(descriptionStyle.Render(i18n.T("overview.checking", len(m.statuses), len(m.languages))) + "\n")
+	} else if len(m.table.Rows) > statusHeight {
+		s.WriteString(descriptionStyle.Render(i18n.T("overview.rows", m.scroll+1, end, len(m.table.Rows))) + "\n")
+	}
+	s.WriteString("\n" + i18n.T("overview.help", Keys.Up.Help(), Keys.Down.Help(), Keys.Left.Help(), Keys.Right.Help(), Keys.Toggle.Help(), Keys.Back.Help()) + "\n")
+	return s.String()
+}
@@ -0,0 +1,109 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// appName is the directory name decor uses inside each base directory. The functions below only
+// resolve paths; callers create the directories when they first write there.
+const appName = "decor"
+
+// ConfigDir returns the directory for user-edited configuration.
+// $XDG_CONFIG_HOME/decor on Linux, ~/Library/Application Support/decor on macOS, %AppData%\decor on Windows.
+func ConfigDir() (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, appName), nil
+}
+
+// CacheDir returns the directory for downloads and other data that can be safely deleted.
+// $XDG_CACHE_HOME/decor on Linux, ~/Library/Caches/decor on macOS, %LocalAppData%\decor\cache on Windows.
+func CacheDir() (string, error) {
+	if runtime.GOOS == "windows" {
+		return localAppData("cache")
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, appName), nil
+}
+
+// StateDir returns the directory for records of what decor has done.
+// $XDG_STATE_HOME/decor on Linux, ~/Library/Application Support/decor/state on macOS, %LocalAppData%\decor\state on Windows.
+func StateDir() (string, error) {
+	switch runtime.GOOS {
+	case "windows":
+		return localAppData("state")
+	case "darwin":
+		return darwinSupportDir("state")
+	}
+	return xdgDir("XDG_STATE_HOME", filepath.Join(".local", "state"))
+}
+
+// LogDir returns the directory for log files.
+// $XDG_STATE_HOME/decor/logs on Linux, ~/Library/Logs/decor on macOS, %LocalAppData%\decor\logs on Windows.
+func LogDir() (string, error) {
+	switch runtime.GOOS {
+	case "windows":
+		return localAppData("logs")
+	case "darwin":
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, "Library", "Logs", appName), nil
+	}
+	state, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(state, "logs"), nil
+}
+
+// LegacyDir returns ~/.decor, where older versions kept their files
+func LegacyDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".decor"), nil
+}
+
+// xdgDir resolves an XDG base directory variable, falling back to a path under the home directory
+func xdgDir(env, fallback string) (string, error) {
+	base := os.Getenv(env)
+	if base == "" || !filepath.IsAbs(base) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		base = filepath.Join(home, fallback)
+	}
+	return filepath.Join(base, appName), nil
+}
+
+// darwinSupportDir returns a directory under ~/Library/Application Support/decor
+func darwinSupportDir(sub string) (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, appName, sub), nil
+}
+
+// localAppData returns a directory under %LocalAppData%\decor
+func localAppData(sub string) (string, error) {
+	base := os.Getenv("LocalAppData")
+	if base == "" {
+		var err error
+		if base, err = os.UserCacheDir(); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(base, appName, sub), nil
+}
@@ -0,0 +1,117 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestLinuxDirs(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("XDG directories are only used on Linux")
+	}
+	home := t.TempDir()
+
+	tests := []struct {
+		name string
+		env  map[string]string
+		dir  func() (string, error)
+		want string
+	}{
+		{"config from XDG", map[string]string{"XDG_CONFIG_HOME": "/xdg/config"}, ConfigDir, "/xdg/config/decor"},
+		{"config fallback", map[string]string{"XDG_CONFIG_HOME": ""}, ConfigDir, filepath.Join(home, ".config", "decor")},
+		{"cache from XDG", map[string]string{"XDG_CACHE_HOME": "/xdg/cache"}, CacheDir, "/xdg/cache/decor"},
+		{"cache fallback", map[string]string{"XDG_CACHE_HOME": ""}, CacheDir, filepath.Join(home, ".cache", "decor")},
+		{"state from XDG", map[string]string{"XDG_STATE_HOME": "/xdg/state"}, StateDir, "/xdg/state/decor"},
+		{"state fallback", map[string]string{"XDG_STATE_HOME": ""}, StateDir, filepath.Join(home, ".local", "state", "decor")},
+		{"relative state ignored", map[string]string{"XDG_STATE_HOME": "relative/state"}, StateDir, filepath.Join(home, ".local", "state", "decor")},
+		{"logs under state", map[string]string{"XDG_STATE_HOME": "/xdg/state"}, LogDir, "/xdg/state/decor/logs"},
+		{"legacy", nil, LegacyDir, filepath.Join(home, ".decor")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", home)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			got, err := tt.dir()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDarwinDirs(t *testing.T) {
+	if runtime.GOOS != "darwin" {
+		t.Skip("Library directories are only used on macOS")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	support := filepath.Join(home, "Library", "Application Support", "decor")
+
+	tests := []struct {
+		name string
+		dir  func() (string, error)
+		want string
+	}{
+		{"config", ConfigDir, support},
+		{"cache", CacheDir, filepath.Join(home, "Library", "Caches", "decor")},
+		{"state", StateDir, filepath.Join(support, "state")},
+		{"logs", LogDir, filepath.Join(home, "Library", "Logs", "decor")},
+	}
+	for _, tt := range tests {
+		if got, err := tt.dir(); err != nil || got != tt.want {
+			t.Errorf("%s: got %s, %v; want %s", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestWindowsDirs(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		t.Skip("AppData directories are only used on Windows")
+	}
+	base := t.TempDir()
+	t.Setenv("AppData", filepath.Join(base, "Roaming"))
+	t.Setenv("LocalAppData", filepath.Join(base, "Local"))
+
+	tests := []struct {
+		name string
+		dir  func() (string, error)
+		want string
+	}{
+		{"config", ConfigDir, filepath.Join(base, "Roaming", "decor")},
+		{"cache", CacheDir, filepath.Join(base, "Local", "decor", "cache")},
+		{"state", StateDir, filepath.Join(base, "Local", "decor", "state")},
+		{"logs", LogDir, filepath.Join(base, "Local", "decor", "logs")},
+	}
+	for _, tt := range tests {
+		if got, err := tt.dir(); err != nil || got != tt.want {
+			t.Errorf("%s: got %s, %v; want %s", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestDirsAreNotCreated(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_CACHE_HOME", "")
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("AppData", filepath.Join(home, "Roaming"))
+	t.Setenv("LocalAppData", filepath.Join(home, "Local"))
+
+	for _, dir := range []func() (string, error){ConfigDir, CacheDir, StateDir, LogDir} {
+		path, err := dir()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s exists after resolving it, want it left for the first write", path)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package pkgmgr
+
+import "regexp"
+
+// AptEnv keeps apt-get and the package scripts it runs from prompting: debconf takes every default and
+// needrestart restarts services without asking
+var AptEnv = []string{"DEBIAN_FRONTEND=noninteractive", "NEEDRESTART_MODE=a"}
+
+// AptOptions keep dpkg from asking what to do about config files the user changed: it keeps theirs
+var AptOptions = []string{"-o", "Dpkg::Options::=--force-confdef", "-o", "Dpkg::Options::=--force-confold"}
+
+// aptErrors turn apt-get's errors into what the user can do about them. $1 is the first submatch.
+var aptErrors = []struct {
+	pattern *regexp.Regexp
+	message string
+}{
+	{regexp.MustCompile(`Unable to locate package (\S+)`), "apt doesn't know a package called $1; it may be in a newer release of your distribution or a repository that isn't set up"},
+	{regexp.MustCompile(`Package '?([^' ]+)'? has no installation candidate`), "apt knows $1 but has nothing to install for it; it may have been replaced, or come from a repository that isn't set up"},
+	{regexp.MustCompile(`dpkg was interrupted`), "an earlier install was interrupted; run sudo dpkg --configure -a, then try again"},
+	{regexp.MustCompile(`(Unmet dependencies|held broken packages)`), "the packages' dependencies conflict with what's installed; run sudo apt-get -f install to see why"},
+	{regexp.MustCompile(`NO_PUBKEY ([0-9A-F]+)`), "a repository's signing key $1 is missing, so apt won't trust it; re-add the repository with its key"},
+	{regexp.MustCompile(`is not valid yet|is no longer valid`), "apt rejected a repository's release file as out of date; check the system clock"},
+	{regexp.MustCompile(`You don't have enough free space`), "there isn't enough free disk space; run sudo apt-get clean or free some up"},
+}
+
+// ExplainApt returns what apt-get's output says went wrong, in words a user can act on, or "" if it's
+// nothing decor recognizes
+func ExplainApt(output []byte) string {
+	for _, e := range aptErrors {
+		if match := e.pattern.FindSubmatchIndex(output); match != nil {
+			return string(e.pattern.Expand(nil, []byte(e.message), output, match))
+		}
+	}
+	return ""
+}
+
+// NeedsIndexes reports whether an apt-get command installs packages, so apt's package lists must be fresh
+func NeedsIndexes(args []string) bool {
+	return len(args) > 0 && (args[0] == "install" || args[0] == "upgrade" || args[0] == "dist-upgrade")
+}
@@ -0,0 +1,60 @@
+package pkgmgr
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+)
+
+// aptLocked looks the apt and dpkg lock files up in /proc/locks, which lists every lock held on the
+// system and, unlike the lock files themselves, is readable without root
+func aptLocked() (bool, string) {
+	procLocks, err := os.ReadFile("/proc/locks")
+	if err != nil {
+		return false, ""
+	}
+	held := heldLocks(string(procLocks))
+
+	for _, path := range aptLockFiles {
+		var stat syscall.Stat_t
+		if err := syscall.Stat(path, &stat); err != nil {
+			continue
+		}
+		if pid, ok := held[lockKey(stat.Dev, stat.Ino)]; ok {
+			return true, describeHolder(pid)
+		}
+	}
+	return false, ""
+}
+
+// heldLocks maps the "major:minor:inode" of each locked file in /proc/locks to the pid holding it.
+// Lines marked "->" are processes waiting for a lock, not holding one.
+func heldLocks(procLocks string) map[string]string {
+	held := make(map[string]string)
+	for _, line := range strings.Split(procLocks, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 6 || fields[1] == "->" {
+			continue
+		}
+		held[fields[5]] = fields[4]
+	}
+	return held
+}
+
+// lockKey formats a file's device and inode the way /proc/locks does
+func lockKey(dev, ino uint64) string {
+	major := (dev>>8)&0xfff | (dev>>32)&^0xfff
+	minor := dev&0xff | (dev>>12)&0xffffff00
+	return fmt.Sprintf("%02x:%02x:%d", major, minor, ino)
+}
+
+// describeHolder names the process holding a lock, e.g. "unattended-upgr, pid 812". Open file
+// description locks have no owning process and show a pid of -1.
+func describeHolder(pid string) string {
+	comm, err := os.ReadFile("/proc/" + pid + "/comm")
+	if pid == "-1" || err != nil {
+		return "another process"
+	}
+	return fmt.Sprintf("%s, pid %s", strings.TrimSpace(string(comm)), pid)
+}
@@ -0,0 +1,47 @@
+package pkgmgr
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHeldLocks(t *testing.T) {
+	procLocks := `1: POSIX  ADVISORY  WRITE 812 08:01:1310723 0 EOF
+2: -> POSIX  ADVISORY  WRITE 4417 08:01:1310723 0 EOF
+3: FLOCK  ADVISORY  WRITE 901 00:1a:42 0 EOF
+4: OFDLCK ADVISORY  READ  -1 fd:00:9617516 0 EOF
+
+`
+	want := map[string]string{
+		"08:01:1310723": "812",
+		"00:1a:42":      "901",
+		"fd:00:9617516": "-1",
+	}
+	if got := heldLocks(procLocks); !reflect.DeepEqual(got, want) {
+		t.Errorf("heldLocks() = %v, want %v", got, want)
+	}
+}
+
+func TestLockKey(t *testing.T) {
+	tests := []struct {
+		dev, ino uint64
+		want     string
+	}{
+		{0x801, 1310723, "08:01:1310723"},
+		{0xfe00, 9617516, "fe:00:9617516"},
+		{0x1a, 42, "00:1a:42"},
+		// Majors and minors too big for the old 8-bit encoding
+		{0xa10301, 7, "103:a01:7"},
+	}
+	for _, tt := range tests {
+		if got := lockKey(tt.dev, tt.ino); got != tt.want {
+			t.Errorf("lockKey(%#x, %d) = %q, want %q", tt.dev, tt.ino, got, tt.want)
+		}
+	}
+}
+
+func TestDescribeHolder(t *testing.T) {
+	if got := describeHolder("-1"); got != "another process" {
+		t.Errorf("describeHolder(-1) = %q, want another process", got)
+	}
+}
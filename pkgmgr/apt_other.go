@@ -0,0 +1,8 @@
+//go:build !linux
+
+package pkgmgr
+
+// aptLocked always reports free, apt only runs on Linux
+func aptLocked() (bool, string) {
+	return false, ""
+}
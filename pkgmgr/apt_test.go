@@ -0,0 +1,40 @@
+package pkgmgr
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExplainApt(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string // a fragment of the explanation, or empty for none
+	}{
+		{"Reading package lists...\nE: Unable to locate package ripgrepp\n", "called ripgrepp"},
+		{"E: Package 'python' has no installation candidate\n", "knows python"},
+		{"E: dpkg was interrupted, you must manually run 'sudo dpkg --configure -a' to correct the problem.\n", "dpkg --configure -a"},
+		{"W: GPG error: https://example.com stable InRelease: NO_PUBKEY 23F3D4EA75716059\n", "key 23F3D4EA75716059"},
+		{"E: Release file for http://deb.debian.org/debian/dists/bookworm-updates/InRelease is not valid yet\n", "system clock"},
+		{"Setting up ripgrep (14.1.0-1) ...\n", ""},
+	}
+	for _, tt := range tests {
+		got := ExplainApt([]byte(tt.output))
+		if tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+			t.Errorf("ExplainApt(%q) = %q, want it to mention %q", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestNeedsIndexes(t *testing.T) {
+	for args, want := range map[string]bool{
+		"install -y ripgrep": true,
+		"upgrade -y python3": true,
+		"remove -y ripgrep":  false,
+		"install -y ./x.deb": true,
+		"":                   false,
+	} {
+		if got := NeedsIndexes(strings.Fields(args)); got != want {
+			t.Errorf("NeedsIndexes(%q) = %t, want %t", args, got, want)
+		}
+	}
+}
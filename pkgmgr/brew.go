@@ -0,0 +1,21 @@
+package pkgmgr
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// BrewPrefix returns where Homebrew is installed: HOMEBREW_PREFIX, or the first standard prefix holding
+// bin/brew, /opt/homebrew on Apple silicon, /usr/local on Intel Macs and /home/linuxbrew/.linuxbrew on
+// Linux. It returns "" when Homebrew isn't installed.
+func BrewPrefix() string {
+	if prefix := os.Getenv("HOMEBREW_PREFIX"); prefix != "" {
+		return prefix
+	}
+	for _, prefix := range brewPrefixes {
+		if info, err := os.Stat(filepath.Join(prefix, "bin", "brew")); err == nil && !info.IsDir() {
+			return prefix
+		}
+	}
+	return ""
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin && !freebsd
+
+package pkgmgr
+
+// brewLocked always reports free, Homebrew doesn't run on this platform
+func brewLocked() (bool, string) {
+	return false, ""
+}
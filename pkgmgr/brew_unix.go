@@ -0,0 +1,34 @@
+//go:build linux || darwin || freebsd
+
+package pkgmgr
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// brewLocked tries a non-blocking flock on each of Homebrew's lock files, the lock brew itself takes
+// while it updates or installs
+func brewLocked() (bool, string) {
+	for _, dir := range brewLockDirs() {
+		locks, _ := filepath.Glob(filepath.Join(dir, "*.lock"))
+		for _, path := range locks {
+			file, err := os.Open(path)
+			if err != nil {
+				continue
+			}
+			err = syscall.Flock(int(file.Fd()), syscall.LOCK_SH|syscall.LOCK_NB)
+			if err == nil {
+				syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+			}
+			file.Close()
+			if errors.Is(err, syscall.EWOULDBLOCK) {
+				return true, "brew, " + strings.TrimSuffix(filepath.Base(path), ".lock")
+			}
+		}
+	}
+	return false, ""
+}
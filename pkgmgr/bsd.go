@@ -0,0 +1,25 @@
+package pkgmgr
+
+// PkgEnv keeps FreeBSD's pkg from asking anything, including whether to bootstrap itself on a fresh
+// install
+var PkgEnv = []string{"ASSUME_ALWAYS_YES=yes"}
+
+// BSDCommand returns the command that runs verb, "install", "upgrade" or "delete", on packages with
+// the package tools of goos: pkg on FreeBSD, pkg_add and pkg_delete on OpenBSD. ok is false on other
+// systems.
+func BSDCommand(goos, verb string, packages []string) (name string, args []string, ok bool) {
+	switch goos {
+	case "freebsd":
+		return "pkg", append([]string{verb, "-y"}, packages...), true
+	case "openbsd":
+		switch verb {
+		case "install":
+			return "pkg_add", append([]string{"-I"}, packages...), true
+		case "upgrade":
+			return "pkg_add", append([]string{"-u", "-I"}, packages...), true
+		case "delete":
+			return "pkg_delete", append([]string{"-I"}, packages...), true
+		}
+	}
+	return "", nil, false
+}
@@ -0,0 +1,32 @@
+package pkgmgr
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestBSDCommand(t *testing.T) {
+	tests := []struct {
+		goos, verb string
+		want       []string // the command and its arguments, or nil for none
+	}{
+		{"freebsd", "install", []string{"pkg", "install", "-y", "go"}},
+		{"freebsd", "delete", []string{"pkg", "delete", "-y", "go"}},
+		{"openbsd", "install", []string{"pkg_add", "-I", "go"}},
+		{"openbsd", "upgrade", []string{"pkg_add", "-u", "-I", "go"}},
+		{"openbsd", "delete", []string{"pkg_delete", "-I", "go"}},
+		{"linux", "install", nil},
+	}
+	for _, tt := range tests {
+		name, args, ok := BSDCommand(tt.goos, tt.verb, []string{"go"})
+		if tt.want == nil {
+			if ok {
+				t.Errorf("BSDCommand(%s, %s) = %s %v, want none", tt.goos, tt.verb, name, args)
+			}
+			continue
+		}
+		if got := append([]string{name}, args...); !ok || !slices.Equal(got, tt.want) {
+			t.Errorf("BSDCommand(%s, %s) = %v, want %v", tt.goos, tt.verb, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package pkgmgr
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"decor/errs"
+)
+
+// Manager identifies a system package manager
+type Manager string
+
+const (
+	Apt    Manager = "apt"
+	Brew   Manager = "brew"
+	Pkg    Manager = "pkg"     // FreeBSD
+	PkgAdd Manager = "pkg_add" // OpenBSD, which waits for its own lock
+)
+
+// Backoff between lock checks and retries; vars so tests can shorten them
+var (
+	initialBackoff = 1 * time.Second
+	maxBackoff     = 30 * time.Second
+)
+
+// maxAttempts is how many times Run tries a command that keeps losing the race for the lock
+const maxAttempts = 5
+
+// aptLockFiles are the files apt and dpkg hold fcntl locks on while they run
+var aptLockFiles = []string{
+	"/var/lib/dpkg/lock-frontend",
+	"/var/lib/dpkg/lock",
+	"/var/lib/apt/lists/lock",
+	"/var/cache/apt/archives/lock",
+}
+
+// brewPrefixes are where Homebrew is installed when HOMEBREW_PREFIX isn't set
+var brewPrefixes = []string{"/opt/homebrew", "/usr/local", "/home/linuxbrew/.linuxbrew"}
+
+// lockMessages are output fragments that mean the command failed because the lock was held
+var lockMessages = []string{
+	"Could not get lock",
+	"Unable to acquire the dpkg frontend lock",
+	"Unable to lock directory",
+	"another active Homebrew",
+	"Another active Homebrew",
+	"has already locked",
+	"Cannot get an exclusive lock",
+}
+
+// ForCommand returns the package manager a command name belongs to
+func ForCommand(name string) (Manager, bool) {
+	switch name {
+	case "apt", "apt-get", "dpkg":
+		return Apt, true
+	case "brew":
+		return Brew, true
+	case "pkg":
+		return Pkg, true
+	case "pkg_add", "pkg_delete":
+		return PkgAdd, true
+	}
+	return "", false
+}
+
+// Locked reports whether another process currently holds the manager's lock, and describes the holder
+func Locked(manager Manager) (bool, string) {
+	switch manager {
+	case Apt:
+		return aptLocked()
+	case Brew:
+		return brewLocked()
+	}
+	return false, ""
+}
+
+// brewLockDirs returns the directories Homebrew keeps its lock files in
+func brewLockDirs() []string {
+	prefixes := brewPrefixes
+	if prefix := os.Getenv("HOMEBREW_PREFIX"); prefix != "" {
+		prefixes = []string{prefix}
+	}
+	var dirs []string
+	for _, prefix := range prefixes {
+		dirs = append(dirs, filepath.Join(prefix, "var", "homebrew", "locks"))
+	}
+	return dirs
+}
+
+// WaitUnlocked blocks until the manager's lock is free, backing off between checks
+func WaitUnlocked(ctx context.Context, manager Manager, onWait func(holder string)) error {
+	backoff := initialBackoff
+	for {
+		locked, holder := Locked(manager)
+		if !locked {
+			return nil
+		}
+		if onWait != nil {
+			onWait(holder)
+		}
+		if err := sleep(ctx, backoff); err != nil {
+			return waitError(err, manager, holder)
+		}
+		backoff = nextBackoff(backoff)
+	}
+}
+
+// Run waits for the manager's lock, runs the command and retries with backoff if it still lost the race for the lock.
+// run executes one attempt and returns the command's output. Each wait for the lock, and each backoff
+// after losing the race, may take up to maxWait; the command itself is only limited by ctx.
+func Run(ctx context.Context, manager Manager, maxWait time.Duration, run func() ([]byte, error), onWait func(holder string)) error {
+	backoff := initialBackoff
+	for attempt := 1; ; attempt++ {
+		waitCtx, cancel := context.WithTimeout(ctx, maxWait)
+		err := WaitUnlocked(waitCtx, manager, onWait)
+		cancel()
+		if err != nil {
+			return err
+		}
+
+		output, err := run()
+		if err == nil {
+			return nil
+		}
+		if !isLockError(output) || attempt >= maxAttempts {
+			return err
+		}
+
+		if onWait != nil {
+			onWait(string(manager))
+		}
+		waitCtx, cancel = context.WithTimeout(ctx, maxWait)
+		err = sleep(waitCtx, backoff)
+		cancel()
+		if err != nil {
+			return waitError(err, manager, string(manager))
+		}
+		backoff = nextBackoff(backoff)
+	}
+}
+
+// sleep waits for d, returning early with ctx's error if it's done first
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// waitError describes giving up on a lock: a timeout if the wait ran out, otherwise the cancellation
+func waitError(err error, manager Manager, holder string) error {
+	op := fmt.Sprintf("waiting for the %s lock", manager)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return errs.New(errs.ErrTimedOut, op, fmt.Errorf("still held by %s", holder))
+	}
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+// isLockError reports whether command output indicates a held package manager lock
+func isLockError(output []byte) bool {
+	for _, msg := range lockMessages {
+		if bytes.Contains(output, []byte(msg)) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextBackoff doubles the backoff up to maxBackoff
+func nextBackoff(backoff time.Duration) time.Duration {
+	backoff *= 2
+	if backoff > maxBackoff {
+		return maxBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,145 @@
+package pkgmgr
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"decor/errs"
+)
+
+// unlocked is a manager Locked never reports as held, so tests only exercise the retry logic
+const unlocked Manager = "test"
+
+func TestIsLockError(t *testing.T) {
+	tests := []struct {
+		output string
+		want   bool
+	}{
+		{"E: Could not get lock /var/lib/dpkg/lock-frontend. It is held by process 812 (unattended-upgr)", true},
+		{"E: Unable to acquire the dpkg frontend lock (/var/lib/dpkg/lock-frontend), is another process using it?", true},
+		{"E: Unable to lock directory /var/lib/apt/lists/", true},
+		{"Error: Another active Homebrew update process is already in progress.", true},
+		{"Error: A `brew install` process has already locked /opt/homebrew/Cellar/go.", true},
+		{"Waiting for another active Homebrew process to finish", true},
+		{"pkg: Cannot get an exclusive lock on a database, it is locked by another process", true},
+		{"E: Unable to locate package nosuchpackage", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isLockError([]byte(tt.output)); got != tt.want {
+			t.Errorf("isLockError(%q) = %t, want %t", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestNextBackoff(t *testing.T) {
+	want := []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 30 * time.Second, 30 * time.Second}
+	backoff := time.Second
+	for i, w := range want {
+		backoff = nextBackoff(backoff)
+		if backoff != w {
+			t.Fatalf("step %d: nextBackoff = %v, want %v", i+1, backoff, w)
+		}
+	}
+}
+
+func TestForCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		want Manager
+		ok   bool
+	}{
+		{"apt-get", Apt, true},
+		{"apt", Apt, true},
+		{"dpkg", Apt, true},
+		{"brew", Brew, true},
+		{"pkg", Pkg, true},
+		{"pkg_delete", PkgAdd, true},
+		{"pipx", "", false},
+	}
+	for _, tt := range tests {
+		if got, ok := ForCommand(tt.name); got != tt.want || ok != tt.ok {
+			t.Errorf("ForCommand(%q) = %q, %t; want %q, %t", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	initialBackoff, maxBackoff = time.Millisecond, 4*time.Millisecond
+	t.Cleanup(func() { initialBackoff, maxBackoff = time.Second, 30*time.Second })
+
+	lockOutput := []byte("E: Could not get lock /var/lib/dpkg/lock-frontend")
+	otherOutput := []byte("E: Unable to locate package nosuchpackage")
+	failed := errors.New("exit status 100")
+
+	tests := []struct {
+		name         string
+		outputs      [][]byte // output of each attempt; the last one succeeds if its entry is nil
+		wantAttempts int
+		wantWaits    int
+		wantErr      bool
+	}{
+		{"succeeds first time", [][]byte{nil}, 1, 0, false},
+		{"retries after losing the lock", [][]byte{lockOutput, lockOutput, nil}, 3, 2, false},
+		{"other failures aren't retried", [][]byte{otherOutput}, 1, 0, true},
+		{"gives up after max attempts", [][]byte{lockOutput, lockOutput, lockOutput, lockOutput, lockOutput, lockOutput}, maxAttempts, maxAttempts - 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts, waits := 0, 0
+			err := Run(context.Background(), unlocked, time.Second, func() ([]byte, error) {
+				output := tt.outputs[attempts]
+				attempts++
+				if output == nil {
+					return nil, nil
+				}
+				return output, failed
+			}, func(string) { waits++ })
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Run() = %v, want error %t", err, tt.wantErr)
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("Run() made %d attempts, want %d", attempts, tt.wantAttempts)
+			}
+			if waits != tt.wantWaits {
+				t.Errorf("Run() reported %d waits, want %d", waits, tt.wantWaits)
+			}
+		})
+	}
+}
+
+func TestRunWaitTimeout(t *testing.T) {
+	initialBackoff, maxBackoff = 50*time.Millisecond, 50*time.Millisecond
+	t.Cleanup(func() { initialBackoff, maxBackoff = time.Second, 30*time.Second })
+
+	lockOutput := []byte("E: Could not get lock /var/lib/dpkg/lock-frontend")
+	err := Run(context.Background(), unlocked, time.Millisecond, func() ([]byte, error) {
+		return lockOutput, errors.New("exit status 100")
+	}, nil)
+	if !errors.Is(err, errs.ErrTimedOut) {
+		t.Errorf("Run() = %v, want a timeout once the wait runs out", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = Run(ctx, unlocked, time.Minute, func() ([]byte, error) {
+		return lockOutput, errors.New("exit status 100")
+	}, nil)
+	if !errors.Is(err, context.Canceled) || errors.Is(err, errs.ErrTimedOut) {
+		t.Errorf("Run() = %v, want a cancellation, not a timeout", err)
+	}
+}
+
+func TestRunDoesNotLimitCommand(t *testing.T) {
+	// The command runs longer than maxWait; only the lock wait is bounded by it
+	err := Run(context.Background(), unlocked, time.Millisecond, func() ([]byte, error) {
+		time.Sleep(20 * time.Millisecond)
+		return nil, nil
+	}, nil)
+	if err != nil {
+		t.Errorf("Run() = %v, want the slow command to finish", err)
+	}
+}
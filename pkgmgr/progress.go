@@ -0,0 +1,113 @@
+package pkgmgr
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// AptStatusOptions have apt-get write machine-readable progress to its standard output, as dlstatus lines
+// while it downloads and pmstatus lines while dpkg unpacks and sets packages up
+var AptStatusOptions = []string{"-o", "APT::Status-Fd=1"}
+
+// AptStatus reports whether line is one of apt-get's status lines, which are for decor rather than people
+func AptStatus(line string) bool {
+	return strings.HasPrefix(line, "dlstatus:") || strings.HasPrefix(line, "pmstatus:")
+}
+
+// brewPercent is the percentage at the end of one of brew's download progress bars
+var brewPercent = regexp.MustCompile(`(\d+(?:\.\d+)?)%$`)
+
+// Tracker follows a package manager's output and works out how far along it is. Downloading is the first
+// part of the way and installing the rest.
+type Tracker struct {
+	Fraction float64 // 0.0 to 1.0, and never going back
+	Step     string  // what the package manager is doing, e.g. "Pouring python@3.12"
+
+	manager  Manager
+	total    int     // formulae brew installs, counting dependencies
+	fetched  int     // formulae brew has started fetching
+	poured   int     // formulae brew has started pouring
+	download float64 // how much of the formula being fetched brew has, 0.0 to 1.0
+}
+
+// NewTracker creates a Tracker for manager's output
+func NewTracker(manager Manager) *Tracker {
+	return &Tracker{manager: manager, total: 1}
+}
+
+// Line reads a line of the package manager's output, reporting whether it moved the progress on
+func (t *Tracker) Line(line string) bool {
+	var fraction float64
+	var ok bool
+	switch t.manager {
+	case Apt:
+		fraction, ok = t.apt(line)
+	case Brew:
+		fraction, ok = t.brew(strings.TrimSpace(line))
+	}
+	if !ok {
+		return false
+	}
+	t.Fraction = max(t.Fraction, min(fraction, 1))
+	return true
+}
+
+// apt reads a status line, dlstatus:id:percent:description or pmstatus:package:percent:description.
+// Downloading is the first half and dpkg's work the second.
+func (t *Tracker) apt(line string) (float64, bool) {
+	fields := strings.SplitN(line, ":", 4)
+	if len(fields) < 4 || !AptStatus(line) {
+		return 0, false
+	}
+	percent, err := strconv.ParseFloat(fields[2], 64)
+	if err != nil {
+		return 0, false
+	}
+	t.Step = fields[3]
+	fraction := percent / 200
+	if fields[0] == "pmstatus" {
+		fraction += 0.5
+	}
+	return fraction, true
+}
+
+// brew reads the headings brew prints for each formula it fetches and pours, and its download progress
+// bars. Fetching is the first 60% of the way and pouring the rest.
+func (t *Tracker) brew(line string) (float64, bool) {
+	switch {
+	case strings.HasPrefix(line, "==> Fetching downloads for: "):
+		// Newer brews list everything they fetch up front, the formula itself included
+		t.total = len(strings.Split(strings.TrimPrefix(line, "==> Fetching downloads for: "), ","))
+	case strings.HasPrefix(line, "==> Installing dependencies for "), strings.HasPrefix(line, "==> Fetching dependencies for "):
+		_, deps, found := strings.Cut(line, ": ")
+		if !found {
+			return 0, false
+		}
+		t.total = len(strings.Split(deps, ",")) + 1
+	case strings.HasPrefix(line, "==> Fetching "):
+		t.fetched++
+		t.download = 0
+		t.Step = strings.TrimPrefix(line, "==> ")
+	case strings.HasPrefix(line, "==> Pouring "):
+		t.poured++
+		t.Step = strings.TrimPrefix(line, "==> ")
+	case strings.HasPrefix(line, "#"):
+		match := brewPercent.FindStringSubmatch(line)
+		if match == nil {
+			return 0, false
+		}
+		t.download, _ = strconv.ParseFloat(match[1], 64)
+		t.download /= 100
+	default:
+		return 0, false
+	}
+	total := float64(max(t.total, t.fetched, t.poured))
+	fetching := (float64(max(t.fetched-1, 0)) + t.download) / total
+	pouring := 0.0
+	if t.poured > 0 {
+		fetching = 1
+		pouring = (float64(t.poured) - 0.5) / total
+	}
+	return 0.6*fetching + 0.4*pouring, true
+}
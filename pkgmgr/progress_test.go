@@ -0,0 +1,49 @@
+package pkgmgr
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTracker(t *testing.T) {
+	tests := []struct {
+		manager Manager
+		lines   []string
+		want    float64
+		step    string
+	}{
+		{Apt, []string{"dlstatus:1:20:Retrieving file 1 of 3"}, 0.1, "Retrieving file 1 of 3"},
+		{Apt, []string{"dlstatus:1:100:Retrieving file 3 of 3", "pmstatus:ripgrep:50:Unpacking ripgrep (amd64)"}, 0.75, "Unpacking ripgrep (amd64)"},
+		{Apt, []string{"pmstatus:ripgrep:80:Configuring ripgrep", "pmstatus:ripgrep:20:Installing ripgrep"}, 0.9, "Installing ripgrep"},
+		{Apt, []string{"Reading package lists...", "dlstatus:oops"}, 0, ""},
+		{Brew, []string{"==> Fetching python@3.12", "######## 50.0%"}, 0.3, "Fetching python@3.12"},
+		{Brew, []string{
+			"==> Fetching dependencies for python@3.12: mpdecimal, xz, sqlite",
+			"==> Fetching mpdecimal",
+			"##################### 100.0%",
+		}, 0.15, "Fetching mpdecimal"},
+		{Brew, []string{"==> Fetching ripgrep", "######## 100.0%", "==> Pouring ripgrep--14.1.0.arm64_sonoma.bottle.tar.gz"}, 0.8, "Pouring ripgrep--14.1.0.arm64_sonoma.bottle.tar.gz"},
+		{Brew, []string{"Warning: ripgrep 14.1.0 is already installed"}, 0, ""},
+	}
+	for _, tt := range tests {
+		tracker := NewTracker(tt.manager)
+		for _, line := range tt.lines {
+			tracker.Line(line)
+		}
+		if math.Abs(tracker.Fraction-tt.want) > 1e-9 || tracker.Step != tt.step {
+			t.Errorf("%s after %q: %v %q, want %v %q", tt.manager, tt.lines, tracker.Fraction, tracker.Step, tt.want, tt.step)
+		}
+	}
+}
+
+func TestAptStatus(t *testing.T) {
+	for line, want := range map[string]bool{
+		"dlstatus:1:20:Retrieving file 1 of 3": true,
+		"pmstatus:ripgrep:50:Unpacking":        true,
+		"Setting up ripgrep (14.1.0-1) ...":    false,
+	} {
+		if got := AptStatus(line); got != want {
+			t.Errorf("AptStatus(%q) = %t, want %t", line, got, want)
+		}
+	}
+}
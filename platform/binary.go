@@ -0,0 +1,52 @@
+package platform
+
+import (
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"slices"
+	"strings"
+)
+
+// machoArches, elfArches and peArches name executable formats' CPU types as GOARCH does
+var (
+	machoArches = map[macho.Cpu]string{macho.CpuAmd64: "amd64", macho.CpuArm64: "arm64", macho.Cpu386: "386", macho.CpuArm: "arm"}
+	elfArches   = map[elf.Machine]string{elf.EM_X86_64: "amd64", elf.EM_AARCH64: "arm64", elf.EM_386: "386", elf.EM_ARM: "arm", elf.EM_RISCV: "riscv64"}
+	peArches    = map[uint16]string{pe.IMAGE_FILE_MACHINE_AMD64: "amd64", pe.IMAGE_FILE_MACHINE_ARM64: "arm64", pe.IMAGE_FILE_MACHINE_I386: "386"}
+)
+
+// BinaryArch returns the architectures the program at path is built for, as GOARCH names: "arm64", or
+// "amd64+arm64" for a universal macOS binary. It's "" for scripts and anything else it can't read.
+func BinaryArch(path string) string {
+	if fat, err := macho.OpenFat(path); err == nil {
+		defer fat.Close()
+		var arches []string
+		for _, arch := range fat.Arches {
+			if name, ok := machoArches[arch.Cpu]; ok && !slices.Contains(arches, name) {
+				arches = append(arches, name)
+			}
+		}
+		slices.Sort(arches)
+		return strings.Join(arches, "+")
+	}
+	if f, err := macho.Open(path); err == nil {
+		defer f.Close()
+		return machoArches[f.Cpu]
+	}
+	if f, err := elf.Open(path); err == nil {
+		defer f.Close()
+		return elfArches[f.Machine]
+	}
+	if f, err := pe.Open(path); err == nil {
+		defer f.Close()
+		return peArches[f.Machine]
+	}
+	return ""
+}
+
+// Translated reports whether a program built for arch runs under Rosetta on this machine: it's
+// x86_64 only, on Apple silicon
+func Translated(arch string) bool {
+	p := Current()
+	return p.OS == "darwin" && p.Arch == "arm64" && arch == "amd64"
+}
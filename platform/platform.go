@@ -0,0 +1,90 @@
+// Package platform describes the machine in the terms downloads are named by: beyond GOOS and GOARCH,
+// which ARM version a 32-bit ARM board implements, like a Raspberry Pi's, and whether Linux runs on musl
+// libc, like Alpine, rather than glibc
+package platform
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// Platform is an OS, architecture and C library combination
+type Platform struct {
+	OS      string // GOOS
+	Arch    string // GOARCH, except 32-bit ARM, which is "armv6" or "armv7"
+	Musl    bool   // Linux with musl libc rather than glibc
+	Rosetta bool   // decor itself runs as x86_64 under Rosetta on Apple silicon, whose Arch is still arm64
+}
+
+// String describes the platform, like linux/armv7 or linux/amd64 (musl)
+func (p Platform) String() string {
+	s := p.OS + "/" + p.Arch
+	if p.Musl {
+		s += " (musl)"
+	}
+	return s
+}
+
+// GOARCH returns the Go architecture, folding the ARM versions into arm
+func (p Platform) GOARCH() string {
+	if p.Arch == "armv6" || p.Arch == "armv7" {
+		return "arm"
+	}
+	return p.Arch
+}
+
+// Current is the machine decor runs on, detected once
+var Current = sync.OnceValue(func() Platform {
+	p := Platform{OS: runtime.GOOS, Arch: runtime.GOARCH}
+	if p.Arch == "arm" {
+		cpuinfo, _ := os.ReadFile("/proc/cpuinfo")
+		p.Arch = armVersion(string(cpuinfo))
+	}
+	if p.OS == "linux" {
+		p.Musl = musl()
+	}
+	// An x86_64 decor under Rosetta still installs native builds
+	if p.OS == "darwin" && p.Arch == "amd64" && appleSilicon() {
+		p.Arch, p.Rosetta = "arm64", true
+	}
+	return p
+})
+
+var (
+	// armModel is the ARMv6 or ARMv7 in /proc/cpuinfo's model name, which the Raspberry Pi 1 and Zero
+	// report correctly even though their CPU architecture line says 7
+	armModel = regexp.MustCompile(`(?m)^model name\s*:.*\(v([67])l\)`)
+	// armArchitecture is /proc/cpuinfo's CPU architecture line
+	armArchitecture = regexp.MustCompile(`(?m)^CPU architecture\s*:\s*(\d+)`)
+)
+
+// armVersion reads the ARM version from /proc/cpuinfo's contents. Without a clue it's armv6, whose
+// builds run on every 32-bit ARM board.
+func armVersion(cpuinfo string) string {
+	if m := armModel.FindStringSubmatch(cpuinfo); m != nil {
+		return "armv" + m[1]
+	}
+	if m := armArchitecture.FindStringSubmatch(cpuinfo); m != nil {
+		if n, _ := strconv.Atoi(m[1]); n >= 7 {
+			return "armv7"
+		}
+	}
+	return "armv6"
+}
+
+// muslLoaders match musl's dynamic loader, which glibc systems don't have
+var muslLoaders = []string{"/lib/ld-musl-*.so.1", "/usr/lib/ld-musl-*.so.1"}
+
+// musl reports whether the system's C library is musl
+func musl() bool {
+	for _, pattern := range muslLoaders {
+		if matches, _ := filepath.Glob(pattern); len(matches) > 0 {
+			return true
+		}
+	}
+	return false
+}
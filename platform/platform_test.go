@@ -0,0 +1,56 @@
+package platform
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestArmVersion(t *testing.T) {
+	tests := []struct {
+		cpuinfo string
+		want    string
+	}{
+		{"processor\t: 0\nmodel name\t: ARMv6-compatible processor rev 7 (v6l)\nCPU architecture: 7\n", "armv6"},
+		{"processor\t: 0\nmodel name\t: ARMv7 Processor rev 4 (v7l)\nCPU architecture: 7\n", "armv7"},
+		{"processor\t: 0\nCPU architecture: 8\n", "armv7"},
+		{"", "armv6"},
+	}
+	for _, tt := range tests {
+		if got := armVersion(tt.cpuinfo); got != tt.want {
+			t.Errorf("armVersion(%q) = %s, want %s", tt.cpuinfo, got, tt.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	for p, want := range map[Platform]string{
+		{OS: "linux", Arch: "armv7"}:             "linux/armv7",
+		{OS: "linux", Arch: "amd64", Musl: true}: "linux/amd64 (musl)",
+	} {
+		if got := p.String(); got != want {
+			t.Errorf("%#v.String() = %s, want %s", p, got, want)
+		}
+		if p.Arch == "armv7" && p.GOARCH() != "arm" {
+			t.Errorf("%s.GOARCH() = %s, want arm", p, p.GOARCH())
+		}
+	}
+}
+
+func TestBinaryArch(t *testing.T) {
+	self, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := BinaryArch(self); got != runtime.GOARCH {
+		t.Errorf("BinaryArch(the test binary) = %q, want %s", got, runtime.GOARCH)
+	}
+	script := filepath.Join(t.TempDir(), "script")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho hi\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if got := BinaryArch(script); got != "" {
+		t.Errorf("BinaryArch(a script) = %q, want none", got)
+	}
+}
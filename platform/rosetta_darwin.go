@@ -0,0 +1,12 @@
+//go:build darwin
+
+package platform
+
+import "syscall"
+
+// appleSilicon reports whether the Mac has an Apple silicon CPU, even when decor runs as x86_64 under
+// Rosetta
+func appleSilicon() bool {
+	arm64, err := syscall.SysctlUint32("hw.optional.arm64")
+	return err == nil && arm64 == 1
+}
@@ -0,0 +1,8 @@
+//go:build !darwin
+
+package platform
+
+// appleSilicon is always false off macOS
+func appleSilicon() bool {
+	return false
+}
@@ -0,0 +1,152 @@
+package precommit
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ConfigName is the file pre-commit reads from the root of a repository
+const ConfigName = ".pre-commit-config.yaml"
+
+// baseHooks run in every repository
+const baseHooks = `  - repo: https://github.com/pre-commit/pre-commit-hooks
+    rev: v5.0.0
+    hooks:
+      - id: trailing-whitespace
+      - id: end-of-file-fixer
+      - id: check-yaml
+      - id: check-added-large-files
+`
+
+// language pairs the file extensions that identify a language with the hooks that lint and format it
+type language struct {
+	name       string
+	extensions []string
+	hooks      string
+}
+
+var languages = []language{
+	{
+		name:       "Go",
+		extensions: []string{".go"},
+		hooks: `  - repo: https://github.com/golangci/golangci-lint
+    rev: v1.61.0
+    hooks:
+      - id: golangci-lint
+`,
+	},
+	{
+		name:       "Python",
+		extensions: []string{".py"},
+		hooks: `  - repo: https://github.com/astral-sh/ruff-pre-commit
+    rev: v0.6.9
+    hooks:
+      - id: ruff
+        args: [--fix]
+      - id: ruff-format
+`,
+	},
+	{
+		name:       "C/C++",
+		extensions: []string{".c", ".h", ".cc", ".cpp", ".cxx", ".hpp"},
+		hooks: `  - repo: https://github.com/pre-commit/mirrors-clang-format
+    rev: v19.1.1
+    hooks:
+      - id: clang-format
+        types_or: [c, c++]
+`,
+	},
+	{
+		name:       "Kotlin",
+		extensions: []string{".kt", ".kts"},
+		hooks: `  - repo: https://github.com/macisamuele/language-formatters-pre-commit-hooks
+    rev: v2.14.0
+    hooks:
+      - id: pretty-format-kotlin
+        args: [--autofix]
+`,
+	},
+}
+
+// skipDirs are never searched for source files
+var skipDirs = map[string]bool{".git": true, "node_modules": true, "vendor": true, ".venv": true, "venv": true, "target": true, "build": true}
+
+// maxDepth keeps detection quick in large repositories
+const maxDepth = 4
+
+// Detect returns the names of the languages with hooks that appear in the repository at dir
+func Detect(dir string) ([]string, error) {
+	found := make(map[string]bool)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			rel, _ := filepath.Rel(dir, path)
+			if skipDirs[d.Name()] || strings.Count(rel, string(filepath.Separator)) >= maxDepth {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ext := filepath.Ext(d.Name())
+		for _, lang := range languages {
+			for _, e := range lang.extensions {
+				if ext == e {
+					found[lang.name] = true
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, lang := range languages {
+		if found[lang.name] {
+			names = append(names, lang.name)
+		}
+	}
+	return names, nil
+}
+
+// Render builds a pre-commit config with the base hooks and those for the named languages
+func Render(names []string) string {
+	var b strings.Builder
+	b.WriteString("# Starter pre-commit config from decor. Run `pre-commit autoupdate` to pick up newer hook versions.\n")
+	b.WriteString("repos:\n")
+	b.WriteString(baseHooks)
+	for _, lang := range languages {
+		for _, name := range names {
+			if name == lang.name {
+				b.WriteString(lang.hooks)
+			}
+		}
+	}
+	return b.String()
+}
+
+// Write detects the repository's languages and writes a starter config to its root, refusing to
+// replace an existing one. It returns the config's path and the languages it covers.
+func Write(dir string) (string, []string, error) {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		return "", nil, fmt.Errorf("%s isn't the root of a git repository", dir)
+	}
+	path := filepath.Join(dir, ConfigName)
+	if _, err := os.Stat(path); err == nil {
+		return path, nil, fmt.Errorf("%s already exists", path)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return path, nil, err
+	}
+
+	names, err := Detect(dir)
+	if err != nil {
+		return path, nil, err
+	}
+	return path, names, os.WriteFile(path, []byte(Render(names)), 0o644)
+}
@@ -0,0 +1,68 @@
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"decor/runner"
+)
+
+// Envrc is direnv's file, which decor can write so entering the project switches its tools on
+const Envrc = ".envrc"
+
+// envrcLine is what loads manager's versions for the project when direnv enters it: mise's environment
+// for the directory, or asdf's shims, which pick versions from .tool-versions
+func envrcLine(manager string) string {
+	switch manager {
+	case "mise":
+		return `eval "$(mise env -s bash)"`
+	case "asdf":
+		return `PATH_add "${ASDF_DATA_DIR:-$HOME/.asdf}/shims"`
+	}
+	return ""
+}
+
+// HasDirenv reports whether direnv is installed
+func HasDirenv() bool {
+	_, err := lookPath("direnv")
+	return err == nil
+}
+
+// EnvrcActivates reports whether the .envrc in dir already loads manager's versions
+func EnvrcActivates(dir, manager string) bool {
+	data, err := os.ReadFile(filepath.Join(dir, Envrc))
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == envrcLine(manager) {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteEnvrc adds the line loading manager's versions to the .envrc in dir, creating it or keeping what's
+// there, and allows it, so direnv loads it from the next prompt on
+func WriteEnvrc(ctx context.Context, commands *runner.Runner, dir, manager string) error {
+	path := filepath.Join(dir, Envrc)
+	if commands.DryRun {
+		runner.Logf("dry run: adding %s to %s", envrcLine(manager), path)
+	} else if !EnvrcActivates(dir, manager) {
+		data, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if len(data) > 0 && !strings.HasSuffix(string(data), "\n") {
+			data = append(data, '\n')
+		}
+		data = append(data, "# Added by decor: switches on the versions the project's .decor file asks for\n"+envrcLine(manager)+"\n"...)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return err
+		}
+	}
+	_, err := commands.Run(ctx, runner.Spec{Op: "allowing " + path, Name: "direnv", Args: []string{"allow", dir}})
+	return err
+}
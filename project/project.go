@@ -0,0 +1,247 @@
+// Package project reads a repository's .decor file, the toolchain the project builds with, and installs
+// it into the project with mise or asdf, so each project gets the versions it asks for without changing
+// the machine's own. The file is a manifest, like .tool-versions but with ranges:
+//
+//	go 1.22
+//	node 20
+//	terraform 1.7
+package project
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"decor/config"
+	"decor/manifest"
+	"decor/runner"
+)
+
+// File is the name of a project's toolchain file, looked for in the directory decor runs in and the
+// directories above it
+const File = ".decor"
+
+// Managers are the version managers that install a project's tools, in order of preference
+var Managers = []string{"mise", "asdf"}
+
+// lookPath finds the version managers on PATH; tests replace it
+var lookPath = exec.LookPath
+
+// Find returns the path of the .decor file in dir or the nearest directory above it, or "" when there's
+// none. ~/.decor, where older versions kept the settings, is a directory and doesn't count.
+func Find(dir string) string {
+	for {
+		path := filepath.Join(dir, File)
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// Manager returns the first of Managers that's installed, or "" when neither is
+func Manager() string {
+	for _, manager := range Managers {
+		if _, err := lookPath(manager); err == nil {
+			return manager
+		}
+	}
+	return ""
+}
+
+// plugins are each manager's names for tools, by the lowercased names a .decor file may use, where they
+// differ from those
+var plugins = map[string]map[string]string{
+	"mise": {"golang": "go", "node.js": "node", "nodejs": "node"},
+	"asdf": {"go": "golang", "node": "nodejs", "node.js": "nodejs"},
+}
+
+// Plugin returns manager's name for the tool a .decor file calls name
+func Plugin(manager, name string) string {
+	name = strings.ToLower(name)
+	if plugin, ok := plugins[manager][name]; ok {
+		return plugin
+	}
+	return name
+}
+
+// Status is where a tool the project asks for stands
+type Status struct {
+	manifest.Entry
+	Plugin    string // the manager's name for it
+	Installed string // the newest installed version the entry accepts, or "" when there's none
+}
+
+// Check looks up which versions of each entry's tool manager has installed
+func Check(ctx context.Context, commands *runner.Runner, manager string, entries []manifest.Entry) []Status {
+	statuses := make([]Status, len(entries))
+	for i, e := range entries {
+		statuses[i] = Status{Entry: e, Plugin: Plugin(manager, e.Name)}
+		for _, version := range installedVersions(ctx, commands, manager, statuses[i].Plugin) {
+			if Accepts(e.Version, version) {
+				statuses[i].Installed = version
+			}
+		}
+	}
+	return statuses
+}
+
+// Accepts reports whether version meets want, a version or range from a .decor file; an empty want
+// accepts any version
+func Accepts(want, version string) bool {
+	if want == "" {
+		return true
+	}
+	ok, _ := config.VersionAllowed(want, version)
+	return ok
+}
+
+// installedVersions lists the versions of plugin manager has installed, oldest first. A tool the manager
+// doesn't know has none.
+func installedVersions(ctx context.Context, commands *runner.Runner, manager, plugin string) []string {
+	var versions []string
+	switch manager {
+	case "mise":
+		output, err := commands.Run(ctx, runner.Spec{Op: "listing " + plugin + " versions", Name: "mise", Args: []string{"ls", "--installed", "--json", plugin}, ReadOnly: true})
+		if err != nil {
+			return nil
+		}
+		var installed []struct {
+			Version string `json:"version"`
+		}
+		json.Unmarshal(output, &installed)
+		for _, v := range installed {
+			versions = append(versions, v.Version)
+		}
+	case "asdf":
+		output, err := commands.Run(ctx, runner.Spec{Op: "listing " + plugin + " versions", Name: "asdf", Args: []string{"list", plugin}, ReadOnly: true})
+		if err != nil {
+			return nil
+		}
+		// One version a line, the one in use marked with a *
+		for _, line := range strings.Split(string(output), "\n") {
+			if version := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "*")); version != "" && !strings.Contains(version, " ") {
+				versions = append(versions, version)
+			}
+		}
+	}
+	return versions
+}
+
+// request turns a version from a .decor file into one the managers install: the newest meets any
+// minimum, and a version like 1.22 installs the newest 1.22 release
+func request(want string) (string, error) {
+	switch {
+	case strings.ContainsAny(want, "<,"):
+		return "", fmt.Errorf("%q is a range mise and asdf can't install from; give a version or a minimum", want)
+	case want == "", strings.HasPrefix(want, ">"):
+		return "latest", nil
+	}
+	return strings.TrimLeft(want, "="), nil
+}
+
+// Install installs the tool s is for with manager and pins it in dir, the project's directory: mise use
+// writes it to mise.toml there, and for asdf it's written to .tool-versions. It returns the version
+// installed.
+func Install(ctx context.Context, commands *runner.Runner, manager, dir string, s Status) (string, error) {
+	version, err := request(s.Version)
+	if err != nil {
+		return "", err
+	}
+	switch manager {
+	case "mise":
+		spec := runner.Spec{Op: "installing " + s.Name + " with mise", Name: "mise", Args: []string{"use", s.Plugin + "@" + version}, Dir: dir, Env: []string{"MISE_YES=1"}}
+		if _, err := commands.Run(ctx, spec); err != nil {
+			return "", err
+		}
+	case "asdf":
+		if version, err = installWithAsdf(ctx, commands, dir, s.Plugin, version); err != nil {
+			return "", err
+		}
+	default:
+		return "", fmt.Errorf("installing %s into the project needs mise or asdf", s.Name)
+	}
+	if commands.DryRun {
+		return version, nil
+	}
+	installed := Check(ctx, commands, manager, []manifest.Entry{s.Entry})[0].Installed
+	if installed == "" {
+		return "", fmt.Errorf("%s installed %s, but no version of it matches %s", manager, s.Plugin, s.Version)
+	}
+	return installed, nil
+}
+
+// installWithAsdf adds plugin if asdf doesn't have it yet, installs the newest release matching version
+// and writes it to dir's .tool-versions, returning the release
+func installWithAsdf(ctx context.Context, commands *runner.Runner, dir, plugin, version string) (string, error) {
+	added, err := commands.Run(ctx, runner.Spec{Op: "listing asdf plugins", Name: "asdf", Args: []string{"plugin", "list"}, ReadOnly: true})
+	if err != nil || !containsLine(added, plugin) {
+		if _, err := commands.Run(ctx, runner.Spec{Op: "adding the asdf " + plugin + " plugin", Name: "asdf", Args: []string{"plugin", "add", plugin}}); err != nil {
+			return "", err
+		}
+	}
+	latest := []string{"latest", plugin}
+	if version != "latest" {
+		latest = append(latest, version)
+	}
+	output, err := commands.Run(ctx, runner.Spec{Op: "finding the newest " + plugin + " " + version, Name: "asdf", Args: latest, ReadOnly: true})
+	if err != nil {
+		return "", err
+	}
+	release := strings.TrimSpace(string(output))
+	if release == "" || strings.ContainsAny(release, " \n") {
+		return "", fmt.Errorf("asdf has no %s release matching %s", plugin, version)
+	}
+	if _, err := commands.Run(ctx, runner.Spec{Op: "installing " + plugin + " " + release + " with asdf", Name: "asdf", Args: []string{"install", plugin, release}, Dir: dir}); err != nil {
+		return "", err
+	}
+	if commands.DryRun {
+		runner.Logf("dry run: pinning %s %s in %s", plugin, release, filepath.Join(dir, ".tool-versions"))
+		return release, nil
+	}
+	return release, pinToolVersion(filepath.Join(dir, ".tool-versions"), plugin, release)
+}
+
+// containsLine reports whether output has a line that's just s
+func containsLine(output []byte, s string) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == s {
+			return true
+		}
+	}
+	return false
+}
+
+// pinToolVersion sets plugin's version in a .tool-versions file, replacing its line if it has one and
+// keeping the others
+func pinToolVersion(path, plugin, version string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	var lines []string
+	pinned := false
+	for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 && fields[0] == plugin {
+			line, pinned = plugin+" "+version, true
+		}
+		if line != "" || len(lines) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	if !pinned {
+		lines = append(lines, plugin+" "+version)
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644)
+}
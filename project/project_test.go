@@ -0,0 +1,133 @@
+package project
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"decor/runner"
+)
+
+func TestFind(t *testing.T) {
+	home := t.TempDir()
+	// ~/.decor, where older versions kept the settings, is a directory and isn't a project
+	os.Mkdir(filepath.Join(home, File), 0o755)
+	repo := filepath.Join(home, "src", "app")
+	nested := filepath.Join(repo, "cmd", "server")
+	os.MkdirAll(nested, 0o755)
+
+	if got := Find(nested); got != "" {
+		t.Errorf("with no .decor file, Find = %q", got)
+	}
+	os.WriteFile(filepath.Join(repo, File), []byte("go 1.22\n"), 0o644)
+	for _, dir := range []string{repo, nested} {
+		if got, want := Find(dir), filepath.Join(repo, File); got != want {
+			t.Errorf("Find(%s) = %q, want %q", dir, got, want)
+		}
+	}
+}
+
+func TestRequest(t *testing.T) {
+	tests := []struct {
+		want, version string
+		ok            bool
+	}{
+		{"", "latest", true},
+		{"1.22", "1.22", true},
+		{"=20", "20", true},
+		{">=1.80", "latest", true},
+		{">=1.22, <1.24", "", false},
+	}
+	for _, tt := range tests {
+		version, err := request(tt.want)
+		if version != tt.version || (err == nil) != tt.ok {
+			t.Errorf("request(%q) = %q, %v, want %q", tt.want, version, err, tt.version)
+		}
+	}
+}
+
+func TestPlugin(t *testing.T) {
+	tests := []struct {
+		manager, name, want string
+	}{
+		{"mise", "Node.js", "node"},
+		{"asdf", "node", "nodejs"},
+		{"asdf", "go", "golang"},
+		{"mise", "go", "go"},
+		{"asdf", "terraform", "terraform"},
+	}
+	for _, tt := range tests {
+		if got := Plugin(tt.manager, tt.name); got != tt.want {
+			t.Errorf("Plugin(%q, %q) = %q, want %q", tt.manager, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPinToolVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".tool-versions")
+	os.WriteFile(path, []byte("golang 1.21.0\nnodejs 20.11.1\n"), 0o644)
+
+	if err := pinToolVersion(path, "golang", "1.22.5"); err != nil {
+		t.Fatal(err)
+	}
+	if err := pinToolVersion(path, "terraform", "1.7.5"); err != nil {
+		t.Fatal(err)
+	}
+	want := "golang 1.22.5\nnodejs 20.11.1\nterraform 1.7.5\n"
+	if got, _ := os.ReadFile(path); string(got) != want {
+		t.Errorf(".tool-versions is\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestManager(t *testing.T) {
+	original := lookPath
+	t.Cleanup(func() { lookPath = original })
+	tests := []struct {
+		installed []string
+		want      string
+	}{
+		{[]string{"asdf", "mise"}, "mise"},
+		{[]string{"asdf"}, "asdf"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		lookPath = func(name string) (string, error) {
+			if slices.Contains(tt.installed, name) {
+				return "/usr/bin/" + name, nil
+			}
+			return "", exec.ErrNotFound
+		}
+		if got := Manager(); got != tt.want {
+			t.Errorf("with %v installed, Manager = %q, want %q", tt.installed, got, tt.want)
+		}
+	}
+}
+
+func TestWriteEnvrc(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fakes direnv with a shell script")
+	}
+	bin := t.TempDir()
+	os.WriteFile(filepath.Join(bin, "direnv"), []byte("#!/bin/sh\n"), 0o755)
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, Envrc), []byte("export APP_ENV=dev"), 0o644)
+	for range 2 {
+		if err := WriteEnvrc(context.Background(), &runner.Runner{}, dir, "mise"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, _ := os.ReadFile(filepath.Join(dir, Envrc))
+	if !strings.HasPrefix(string(got), "export APP_ENV=dev\n") || strings.Count(string(got), envrcLine("mise")) != 1 {
+		t.Errorf(".envrc is\n%s\nwant what was there and mise's line once", got)
+	}
+	if !EnvrcActivates(dir, "mise") || EnvrcActivates(dir, "asdf") {
+		t.Error("EnvrcActivates doesn't see which manager's line is there")
+	}
+}
@@ -0,0 +1,244 @@
+// Package release installs programs published as GitHub release assets: it finds the latest release,
+// picks the asset built for this platform, looks up its published checksum and pulls the program out
+package release
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"decor/download"
+	"decor/extract"
+	"decor/platform"
+)
+
+// githubAPI is the GitHub REST API, replaced in tests
+var githubAPI = "https://api.github.com"
+
+// Release is a GitHub release and the files attached to it
+type Release struct {
+	Tag    string  `json:"tag_name"`
+	Assets []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name   string `json:"name"`
+	URL    string `json:"browser_download_url"`
+	Digest string `json:"digest"` // "sha256:<hex>", which GitHub computes for assets uploaded since mid-2025
+}
+
+// Latest fetches the newest release of the GitHub repository owner/name, skipping drafts and
+// prereleases
+func Latest(ctx context.Context, repo string) (Release, error) {
+	var r Release
+	body, _, err := download.Metadata(ctx, fmt.Sprintf("%s/repos/%s/releases/latest", githubAPI, repo))
+	if err != nil {
+		return r, err
+	}
+	if err := json.Unmarshal([]byte(body), &r); err != nil || r.Tag == "" {
+		return r, fmt.Errorf("unexpected answer from the GitHub API: %.100s", body)
+	}
+	return r, nil
+}
+
+// osNames and archNames are what release assets call each GOOS and platform architecture. A bare "arm"
+// is usually an ARMv6 build, which ARMv7 boards run too.
+var (
+	osNames = map[string][]string{
+		"linux":   {"linux"},
+		"darwin":  {"darwin", "macos", "apple", "mac", "osx"},
+		"windows": {"windows", "win64", "win"},
+		"freebsd": {"freebsd"},
+	}
+	archNames = map[string][]string{
+		"amd64": {"amd64", "x86_64", "x64", "64bit"},
+		"arm64": {"arm64", "aarch64"},
+		"386":   {"386", "i386", "i686", "32bit"},
+		"armv7": {"armv7", "armv7l", "armhf", "arm"},
+		"armv6": {"armv6", "armv6l", "arm"},
+	}
+)
+
+// notPrograms matches assets that are never the program: checksums, signatures, packages and SBOMs
+var notPrograms = regexp.MustCompile(`(?i)(\.(sha\d*|sha\d+sum|md5|asc|sig|pem|sbom|json|txt|deb|rpm|apk|msi|pkg|dmg)$|checksums?|sha\d+sums)`)
+
+// Pick chooses the asset for the platform. pattern is a glob like "tool_*_{os}_{arch}.tar.gz", with
+// {os} and {arch} matching any of the names assets use for the platform; "*" or "" picks the one asset
+// whose name mentions both. On musl, builds linked against glibc are passed over and musl builds
+// preferred; elsewhere it's the other way round.
+func Pick(assets []Asset, pattern string, p platform.Platform) (Asset, error) {
+	var matches []Asset
+	for _, asset := range assets {
+		name := strings.ToLower(asset.Name)
+		if p.Musl && mentions(name, []string{"gnu", "glibc"}) {
+			continue
+		}
+		if pattern != "" && pattern != "*" {
+			if globMatches(strings.ToLower(pattern), name, p.OS, p.Arch) {
+				matches = append(matches, asset)
+			}
+			continue
+		}
+		if !notPrograms.MatchString(name) && mentions(name, osNames[p.OS]) && mentions(name, archNames[p.Arch]) {
+			matches = append(matches, asset)
+		}
+	}
+
+	// Several assets can fit, like a .tar.gz and a .zip of the same build; the first archive type decor
+	// reads wins, built for the platform's C library
+	if len(matches) > 1 {
+		for _, kind := range extract.Types {
+			for _, m := range matches {
+				name := strings.ToLower(m.Name)
+				if extract.Type(name) == kind && strings.Contains(name, "musl") == p.Musl {
+					return m, nil
+				}
+			}
+		}
+	}
+	if len(matches) == 0 {
+		names := make([]string, len(assets))
+		for i, asset := range assets {
+			names[i] = asset.Name
+		}
+		return Asset{}, fmt.Errorf("no release asset for %s among %s", p, strings.Join(names, ", "))
+	}
+	return matches[0], nil
+}
+
+// globMatches reports whether name matches pattern with {os} and {arch} standing for any name of the
+// platform's
+func globMatches(pattern, name, goos, arch string) bool {
+	for _, o := range osNames[goos] {
+		for _, a := range archNames[arch] {
+			expanded := strings.NewReplacer("{os}", o, "{arch}", a).Replace(pattern)
+			if ok, _ := path.Match(expanded, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mentions reports whether name contains any of words, each standing alone between separators
+func mentions(name string, words []string) bool {
+	for _, word := range words {
+		if regexp.MustCompile(`(^|[^a-z0-9])` + regexp.QuoteMeta(word) + `($|[^a-z0-9])`).MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Checksum finds the SHA-256 the release publishes for asset, in an asset.sha256 file or a checksums
+// file listing every asset, or else the digest GitHub computed for it. It returns "" when there's none.
+func Checksum(ctx context.Context, r Release, asset Asset) (string, error) {
+	for _, a := range r.Assets {
+		name := strings.ToLower(a.Name)
+		single := name == strings.ToLower(asset.Name)+".sha256"
+		if !single && !strings.Contains(name, "checksums") && !strings.Contains(name, "sha256sums") {
+			continue
+		}
+		text, err := download.Text(ctx, a.URL)
+		if err != nil {
+			return "", err
+		}
+		if sum := findSum(text, asset.Name, single); sum != "" {
+			return sum, nil
+		}
+	}
+	if sum, ok := strings.CutPrefix(asset.Digest, "sha256:"); ok && sha256Pattern.MatchString(sum) {
+		return strings.ToLower(sum), nil
+	}
+	return "", nil
+}
+
+// sha256Pattern matches a hex SHA-256
+var sha256Pattern = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
+// findSum looks name up in a checksums file's "hash  name" lines. A file for a single asset may hold
+// only the hash.
+func findSum(text, name string, single bool) string {
+	for _, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || !sha256Pattern.MatchString(fields[0]) {
+			continue
+		}
+		if single && len(fields) == 1 || len(fields) > 1 && path.Base(strings.TrimPrefix(fields[1], "*")) == name {
+			return strings.ToLower(fields[0])
+		}
+	}
+	return ""
+}
+
+// Extract writes the program called name from the downloaded asset file to dest, executable. Archives
+// (.tar.gz, .tar.xz, .tar.zst, .zip) are searched for it; any other asset is the program itself.
+func Extract(file, assetName, name, dest string) error {
+	kind := extract.Type(assetName)
+	if kind == "" {
+		src, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		return write(src, dest)
+	}
+	var writeErr error
+	err := extract.Find(file, kind, func(entry string) bool {
+		return isProgram(entry, name)
+	}, func(src io.Reader) error {
+		writeErr = write(src, dest)
+		return writeErr
+	})
+	switch {
+	case writeErr != nil:
+		return writeErr
+	case errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("%s isn't in the release archive", name)
+	case err != nil:
+		return fmt.Errorf("reading %s: %w", file, err)
+	}
+	return nil
+}
+
+// isProgram reports whether an archive entry is the program called name
+func isProgram(entry, name string) bool {
+	base := path.Base(entry)
+	return base == name || base == name+".exe"
+}
+
+// write saves the program to dest through a temporary file, so a running copy is replaced rather than
+// overwritten
+func write(src io.Reader, dest string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(dest), ".decor-*")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, src); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o755); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package release
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"decor/platform"
+)
+
+var lazygitAssets = []Asset{
+	{Name: "checksums.txt"},
+	{Name: "lazygit_0.44.1_Darwin_arm64.tar.gz"},
+	{Name: "lazygit_0.44.1_Darwin_x86_64.tar.gz"},
+	{Name: "lazygit_0.44.1_Linux_arm64.tar.gz"},
+	{Name: "lazygit_0.44.1_Linux_x86_64.tar.gz"},
+	{Name: "lazygit_0.44.1_Windows_x86_64.zip"},
+}
+
+func TestPick(t *testing.T) {
+	tests := []struct {
+		assets   []Asset
+		pattern  string
+		platform platform.Platform
+		want     string // the asset's name, or empty for none
+	}{
+		{lazygitAssets, "", platform.Platform{OS: "linux", Arch: "amd64"}, "lazygit_0.44.1_Linux_x86_64.tar.gz"},
+		{lazygitAssets, "*", platform.Platform{OS: "darwin", Arch: "arm64"}, "lazygit_0.44.1_Darwin_arm64.tar.gz"},
+		{lazygitAssets, "lazygit_*_{os}_{arch}.zip", platform.Platform{OS: "windows", Arch: "amd64"}, "lazygit_0.44.1_Windows_x86_64.zip"},
+		{lazygitAssets, "lazygit_*_{os}_{arch}.zip", platform.Platform{OS: "linux", Arch: "amd64"}, ""},
+		{lazygitAssets, "", platform.Platform{OS: "linux", Arch: "386"}, ""},
+		{[]Asset{{Name: "just-1.36.0-x86_64-unknown-linux-musl.tar.gz"}, {Name: "just-1.36.0-x86_64-unknown-linux-gnu.tar.gz"}, {Name: "just-1.36.0-x86_64-unknown-linux-gnu.tar.gz.sha256"}}, "", platform.Platform{OS: "linux", Arch: "amd64"}, "just-1.36.0-x86_64-unknown-linux-gnu.tar.gz"},
+		{[]Asset{{Name: "yq_linux_arm"}, {Name: "yq_linux_arm64"}}, "", platform.Platform{OS: "linux", Arch: "arm64"}, "yq_linux_arm64"},
+		{[]Asset{{Name: "yq_linux_arm"}, {Name: "yq_linux_arm64"}}, "", platform.Platform{OS: "linux", Arch: "armv6"}, "yq_linux_arm"},
+		{[]Asset{{Name: "tool-linux-armv6l.tar.gz"}, {Name: "tool-linux-armv7l.tar.gz"}}, "", platform.Platform{OS: "linux", Arch: "armv7"}, "tool-linux-armv7l.tar.gz"},
+		{[]Asset{{Name: "just-1.36.0-x86_64-unknown-linux-gnu.tar.gz"}, {Name: "just-1.36.0-x86_64-unknown-linux-musl.tar.gz"}}, "", platform.Platform{OS: "linux", Arch: "amd64", Musl: true}, "just-1.36.0-x86_64-unknown-linux-musl.tar.gz"},
+		{[]Asset{{Name: "tool-x86_64-unknown-linux-gnu.tar.gz"}}, "", platform.Platform{OS: "linux", Arch: "amd64", Musl: true}, ""},
+	}
+	for _, tt := range tests {
+		got, err := Pick(tt.assets, tt.pattern, tt.platform)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("Pick(%q, %s) = %s, want none", tt.pattern, tt.platform, got.Name)
+			}
+			continue
+		}
+		if err != nil || got.Name != tt.want {
+			t.Errorf("Pick(%q, %s) = %s, %v, want %s", tt.pattern, tt.platform, got.Name, err, tt.want)
+		}
+	}
+}
+
+func TestLatestAndChecksum(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	sum := strings.Repeat("ab", 32)
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/jesseduffield/lazygit/releases/latest":
+			w.Write([]byte(`{"tag_name":"v0.44.1","assets":[{"name":"checksums.txt","browser_download_url":"` + server.URL + `/checksums.txt"},` +
+				`{"name":"lazygit_0.44.1_Linux_x86_64.tar.gz","browser_download_url":"` + server.URL + `/lazygit.tar.gz"}]}`))
+		case "/checksums.txt":
+			w.Write([]byte(strings.Repeat("cd", 32) + "  lazygit_0.44.1_Darwin_arm64.tar.gz\n" + sum + "  lazygit_0.44.1_Linux_x86_64.tar.gz\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	original := githubAPI
+	defer func() { githubAPI = original }()
+	githubAPI = server.URL
+
+	r, err := Latest(context.Background(), "jesseduffield/lazygit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Tag != "v0.44.1" || len(r.Assets) != 2 {
+		t.Fatalf("Latest = %+v", r)
+	}
+	got, err := Checksum(context.Background(), r, r.Assets[1])
+	if err != nil || got != sum {
+		t.Errorf("Checksum = %q, %v, want %q", got, err, sum)
+	}
+	digest := strings.Repeat("ef", 32)
+	if got, err := Checksum(context.Background(), Release{}, Asset{Name: "Homebrew-4.4.0.pkg", Digest: "sha256:" + digest}); err != nil || got != digest {
+		t.Errorf("Checksum from the asset's digest = %q, %v, want %q", got, err, digest)
+	}
+	if _, err := Latest(context.Background(), "nobody/nothing"); err == nil {
+		t.Error("a missing repository has a latest release")
+	}
+}
+
+func TestExtract(t *testing.T) {
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	for name, body := range map[string]string{"README.md": "docs", "lazygit_0.44.1/lazygit": "#!/bin/sh\n"} {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(body)), Typeflag: tar.TypeReg})
+		tw.Write([]byte(body))
+	}
+	tw.Close()
+	gz.Close()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "download.tar.gz")
+	if err := os.WriteFile(file, archive.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	dest := filepath.Join(dir, "lazygit")
+	if err := Extract(file, "lazygit_0.44.1_Linux_x86_64.tar.gz", "lazygit", dest); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(dest)
+	if err != nil || info.Mode().Perm() != 0o755 || info.Size() != int64(len("#!/bin/sh\n")) {
+		t.Errorf("extracted %v, %v", info, err)
+	}
+	if err := Extract(file, "lazygit_0.44.1_Linux_x86_64.tar.gz", "lg", dest); err == nil {
+		t.Error("extracted a program the archive doesn't have")
+	}
+}
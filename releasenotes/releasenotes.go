@@ -0,0 +1,74 @@
+package releasenotes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"decor/download"
+	"decor/symbols"
+)
+
+// githubAPI is the GitHub REST API, replaced in tests
+var githubAPI = "https://api.github.com"
+
+// maxReleases bounds how many releases are fetched, and shown when the installed one isn't among them
+const maxReleases = 10
+
+// versionNumber finds a dotted version number in a version command's output, e.g. 1.81.0 in
+// "rustc 1.81.0 (eeb90cda1 2024-09-04)"
+var versionNumber = regexp.MustCompile(`\d+(\.\d+)+`)
+
+// Release is one GitHub release
+type Release struct {
+	Tag  string `json:"tag_name"`
+	Name string `json:"name"`
+	Body string `json:"body"`
+	URL  string `json:"html_url"`
+}
+
+// Newer fetches the releases of the GitHub repository owner/name published after the installed version,
+// newest first. When the installed version isn't among the latest releases, all of those are returned.
+// It also returns when the list was fetched, which is a while ago when it comes from the cache.
+func Newer(ctx context.Context, repo, installed string) ([]Release, time.Time, error) {
+	body, fetched, err := download.Metadata(ctx, fmt.Sprintf("%s/repos/%s/releases?per_page=%d", githubAPI, repo, maxReleases))
+	if err != nil {
+		return nil, fetched, err
+	}
+	var releases []Release
+	if err := json.Unmarshal([]byte(body), &releases); err != nil {
+		return nil, fetched, fmt.Errorf("unexpected answer from the GitHub API: %.100s", body)
+	}
+
+	current := versionNumber.FindString(installed)
+	for i, release := range releases {
+		if current != "" && versionNumber.FindString(release.Tag) == current {
+			return releases[:i], fetched, nil
+		}
+	}
+	return releases, fetched, nil
+}
+
+// Format renders releases as plain text for the terminal, a heading per release followed by its notes
+func Format(releases []Release) string {
+	var b strings.Builder
+	for i, release := range releases {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		title := release.Tag
+		if release.Name != "" && release.Name != release.Tag {
+			title += fmt.Sprintf(" %s %s", symbols.Dash, release.Name)
+		}
+		fmt.Fprintf(&b, "%s\n%s\n", title, strings.Repeat(symbols.Rule.String(), min(len([]rune(title)), 60)))
+		notes := strings.TrimSpace(strings.ReplaceAll(release.Body, "\r\n", "\n"))
+		if notes == "" {
+			notes = "(no notes) " + release.URL
+		}
+		b.WriteString(notes + "\n")
+	}
+	return b.String()
+}
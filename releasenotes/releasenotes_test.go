@@ -0,0 +1,64 @@
+package releasenotes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewer(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/BurntSushi/ripgrep/releases" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`[{"tag_name":"14.1.1","body":"fixes"},{"tag_name":"14.1.0","body":"features"},{"tag_name":"14.0.3"}]`))
+	}))
+	defer server.Close()
+	original := githubAPI
+	defer func() { githubAPI = original }()
+	githubAPI = server.URL
+
+	tests := []struct {
+		installed string
+		want      []string
+	}{
+		{"ripgrep 14.0.3\n-SIMD -AVX", []string{"14.1.1", "14.1.0"}},
+		{"ripgrep 14.1.1", nil},
+		{"ripgrep 13.0.0", []string{"14.1.1", "14.1.0", "14.0.3"}},
+		{"", []string{"14.1.1", "14.1.0", "14.0.3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.installed, func(t *testing.T) {
+			releases, _, err := Newer(context.Background(), "BurntSushi/ripgrep", tt.installed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var tags []string
+			for _, release := range releases {
+				tags = append(tags, release.Tag)
+			}
+			if strings.Join(tags, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", tags, tt.want)
+			}
+		})
+	}
+
+	if _, _, err := Newer(context.Background(), "nobody/nothing", ""); err == nil {
+		t.Error("a missing repository returned releases")
+	}
+}
+
+func TestFormat(t *testing.T) {
+	got := Format([]Release{
+		{Tag: "v2.0.0", Name: "Big one", Body: "New things\r\n"},
+		{Tag: "v1.9.0", Name: "v1.9.0", URL: "https://example.com/v1.9.0"},
+	})
+	want := "v2.0.0 — Big one\n" + strings.Repeat("─", 16) + "\nNew things\n\nv1.9.0\n──────\n(no notes) https://example.com/v1.9.0\n"
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,65 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Attached is a command that runs on the terminal, for prompts like ssh-keygen's passphrase or a browser
+// login. It's logged like Run and, unless its spec is ReadOnly, only logged on a dry run. It satisfies
+// bubbletea's ExecCommand, so the TUI can hand it the terminal with tea.Exec.
+type Attached struct {
+	runner *Runner
+	spec   Spec
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// Attach prepares spec to run on the terminal, using os.Stdin, os.Stdout and os.Stderr unless they're changed
+func (r *Runner) Attach(spec Spec) *Attached {
+	if spec.Op == "" {
+		spec.Op = "running " + spec.Name
+	}
+	return &Attached{runner: r, spec: spec, stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}
+}
+
+// SetStdin sets where the command reads its input
+func (a *Attached) SetStdin(r io.Reader) { a.stdin = r }
+
+// SetStdout sets where the command writes its output
+func (a *Attached) SetStdout(w io.Writer) { a.stdout = w }
+
+// SetStderr sets where the command writes its errors
+func (a *Attached) SetStderr(w io.Writer) { a.stderr = w }
+
+// Run runs the command until it exits, or until spec.Timeout if one is set
+func (a *Attached) Run() error {
+	ctx := context.Background()
+	if a.spec.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, a.spec.Timeout)
+		defer cancel()
+	}
+
+	cmd := a.runner.command(ctx, a.spec)
+	if a.runner.DryRun && !a.spec.ReadOnly {
+		logger.Printf("dry run: %s: %s", a.spec.Op, strings.Join(cmd.Args, " "))
+		return nil
+	}
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = a.stdin, a.stdout, a.stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		logger.Printf("%s: %s: failed after %s: %v", a.spec.Op, strings.Join(cmd.Args, " "), elapsed, err)
+		return fmt.Errorf("%s: %w", a.spec.Op, err)
+	}
+	logger.Printf("%s: %s: ok in %s", a.spec.Op, strings.Join(cmd.Args, " "), elapsed)
+	return nil
+}
@@ -0,0 +1,231 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"decor/errs"
+)
+
+// Spec describes a command for Run
+type Spec struct {
+	Op       string        // what the command does, e.g. "extracting Go", used in logs and errors
+	Name     string        // program to run
+	Args     []string      // its arguments
+	Root     bool          // needs root, so it goes through the elevator when we aren't root
+	Env      []string      // KEY=value pairs added to the environment
+	Dir      string        // working directory, the current one if empty
+	Timeout  time.Duration // kills the command after this long; zero means only ctx limits it
+	ReadOnly bool          // changes nothing, so it still runs on a dry run (e.g. version checks)
+	Input    []byte        // written to the command's standard input
+
+	// Lines is called with each line of output as it's printed, e.g. to follow a package manager's
+	// progress. Lines it returns false for are left out of the output kept and returned, for status
+	// lines meant for decor rather than people.
+	Lines func(line string) bool
+}
+
+// logger records every command decor runs; it discards everything until SetLogOutput is called
+var logger = log.New(logWriter{}, "", log.LstdFlags)
+
+var (
+	logMu     sync.Mutex
+	logOutput io.Writer = io.Discard
+	captures            = make(map[*bytes.Buffer]bool) // copies of the log being taken by Capture
+)
+
+// logWriter sends log lines to logOutput and to every capture in progress
+type logWriter struct{}
+
+func (logWriter) Write(p []byte) (int, error) {
+	logMu.Lock()
+	defer logMu.Unlock()
+	for buf := range captures {
+		buf.Write(p)
+	}
+	return logOutput.Write(p)
+}
+
+// SetLogOutput sends the command log to w
+func SetLogOutput(w io.Writer) {
+	logMu.Lock()
+	defer logMu.Unlock()
+	logOutput = w
+}
+
+// Capture starts copying the command log, e.g. to keep with a run's report. The returned function stops
+// copying and returns what was logged in between.
+func Capture() func() string {
+	buf := new(bytes.Buffer)
+	logMu.Lock()
+	captures[buf] = true
+	logMu.Unlock()
+	return func() string {
+		logMu.Lock()
+		defer logMu.Unlock()
+		delete(captures, buf)
+		return buf.String()
+	}
+}
+
+// outputKey is the context key CaptureOutput keeps its buffer under
+type outputKey struct{}
+
+// outputBuffer collects the commands run with a context and what they printed
+type outputBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// CaptureOutput returns a context whose commands are kept with everything they print, e.g. to show why
+// one item failed while others ran alongside it. The returned function returns what's been kept.
+func CaptureOutput(ctx context.Context) (context.Context, func() string) {
+	out := new(outputBuffer)
+	return context.WithValue(ctx, outputKey{}, out), func() string {
+		out.mu.Lock()
+		defer out.mu.Unlock()
+		return out.buf.String()
+	}
+}
+
+// keepOutput adds a command and its output to ctx's capture, if it has one
+func keepOutput(ctx context.Context, args []string, output []byte, err error) {
+	out, ok := ctx.Value(outputKey{}).(*outputBuffer)
+	if !ok {
+		return
+	}
+	out.mu.Lock()
+	defer out.mu.Unlock()
+	fmt.Fprintf(&out.buf, "$ %s\n", strings.Join(args, " "))
+	out.buf.Write(output)
+	if len(output) > 0 && output[len(output)-1] != '\n' {
+		out.buf.WriteByte('\n')
+	}
+	if err != nil {
+		fmt.Fprintf(&out.buf, "(%v)\n", err)
+	}
+}
+
+// Logf adds a line to the command log, for work that doesn't go through Run
+func Logf(format string, args ...any) {
+	logger.Printf(format, args...)
+}
+
+// Run runs the command described by spec and returns its combined output. Failures are classified
+// with errs, and a command killed by spec.Timeout returns errs.ErrTimedOut. On a dry run, commands
+// that aren't ReadOnly are only logged.
+func (r *Runner) Run(ctx context.Context, spec Spec) ([]byte, error) {
+	if spec.Op == "" {
+		spec.Op = "running " + spec.Name
+	}
+
+	if spec.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, spec.Timeout)
+		defer cancel()
+	}
+
+	cmd := r.command(ctx, spec)
+	if r.DryRun && !spec.ReadOnly {
+		logger.Printf("dry run: %s: %s", spec.Op, strings.Join(cmd.Args, " "))
+		return nil, nil
+	}
+
+	start := time.Now()
+	var output []byte
+	var err error
+	if spec.Lines != nil {
+		lines := &lineWriter{lines: spec.Lines}
+		cmd.Stdout, cmd.Stderr = lines, lines
+		err = cmd.Run()
+		lines.flush()
+		output = lines.kept.Bytes()
+	} else {
+		output, err = cmd.CombinedOutput()
+	}
+	elapsed := time.Since(start).Round(time.Millisecond)
+	keepOutput(ctx, cmd.Args, output, err)
+
+	if err != nil && spec.Timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		logger.Printf("%s: %s: timed out after %s", spec.Op, strings.Join(cmd.Args, " "), spec.Timeout)
+		return output, errs.New(errs.ErrTimedOut, spec.Op, fmt.Errorf("no answer within %s", spec.Timeout))
+	}
+	if err != nil {
+		logger.Printf("%s: %s: failed after %s: %v\n%s", spec.Op, strings.Join(cmd.Args, " "), elapsed, err, output)
+		return output, errs.FromOutput(spec.Op, output, err)
+	}
+	logger.Printf("%s: %s: ok in %s", spec.Op, strings.Join(cmd.Args, " "), elapsed)
+	return output, nil
+}
+
+// lineWriter hands a command's output to a Spec's Lines a line at a time, keeping the lines it wants
+// kept. Progress bars redraw with a carriage return, so that ends a line too.
+type lineWriter struct {
+	lines   func(string) bool
+	partial []byte
+	kept    bytes.Buffer
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.partial = append(w.partial, p...)
+	for {
+		end := bytes.IndexAny(w.partial, "\r\n")
+		if end < 0 {
+			return len(p), nil
+		}
+		w.line(w.partial[:end+1])
+		w.partial = w.partial[end+1:]
+	}
+}
+
+// line passes one line, with its ending, to lines and keeps it if asked to
+func (w *lineWriter) line(line []byte) {
+	if w.lines(strings.TrimRight(string(line), "\r\n")) {
+		w.kept.Write(line)
+	}
+}
+
+// flush passes on output that didn't end with a newline
+func (w *lineWriter) flush() {
+	if len(w.partial) > 0 {
+		w.line(w.partial)
+		w.partial = nil
+	}
+}
+
+// command builds the exec.Cmd for spec, prefixing the elevator when it needs root and we aren't root.
+// Elevated commands run non-interactively so they never prompt underneath the TUI; call
+// AuthCommand first to cache credentials.
+func (r *Runner) command(ctx context.Context, spec Spec) *exec.Cmd {
+	var cmd *exec.Cmd
+	if !spec.Root || !r.NeedsElevation() {
+		cmd = exec.CommandContext(ctx, spec.Name, spec.Args...)
+		if len(spec.Env) > 0 {
+			cmd.Env = append(os.Environ(), spec.Env...)
+		}
+	} else {
+		// sudo and doas reset the environment, so pass extra variables through env(1)
+		args := []string{"-n"}
+		if len(spec.Env) > 0 {
+			args = append(append(args, "env"), spec.Env...)
+		}
+		args = append(append(args, spec.Name), spec.Args...)
+		cmd = exec.CommandContext(ctx, r.Elevator, args...)
+	}
+	cmd.Dir = spec.Dir
+	if spec.Input != nil {
+		cmd.Stdin = bytes.NewReader(spec.Input)
+	}
+	// Stop waiting on children that kept the output pipes open after the command was killed
+	cmd.WaitDelay = killWaitDelay
+	return cmd
+}
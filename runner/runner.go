@@ -0,0 +1,97 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// ErrCancelled is returned when the user cancels or fails the password prompt
+var ErrCancelled = errors.New("password prompt cancelled, nothing was installed")
+
+// keepAliveInterval is how often the sudo timestamp is refreshed, well under sudo's 5 minute default
+const keepAliveInterval = 60 * time.Second
+
+// killWaitDelay bounds how long a killed command's leftover children may hold its output open
+const killWaitDelay = 5 * time.Second
+
+// Runner builds commands, elevating only those that need root
+type Runner struct {
+	Elevator string // "sudo" or "doas", empty disables elevation
+	DryRun   bool   // log commands that would change the system instead of running them
+}
+
+// New creates a runner using the given elevator, or for "auto" or "", the DECOR_ELEVATOR environment
+// variable or whichever of sudo and doas is installed. "none" disables elevation.
+func New(elevator string) *Runner {
+	if elevator == "none" {
+		return &Runner{}
+	}
+	if elevator == "" || elevator == "auto" {
+		elevator = os.Getenv("DECOR_ELEVATOR")
+	}
+	if elevator == "" {
+		for _, candidate := range []string{"sudo", "doas"} {
+			if _, err := exec.LookPath(candidate); err == nil {
+				elevator = candidate
+				break
+			}
+		}
+	}
+	return &Runner{Elevator: elevator}
+}
+
+// NeedsElevation reports whether root commands have to go through the elevator
+func (r *Runner) NeedsElevation() bool {
+	if runtime.GOOS == "windows" || r.Elevator == "" {
+		return false
+	}
+	return os.Geteuid() != 0
+}
+
+// AuthCommand returns an interactive command that prompts for the password and caches the credentials
+func (r *Runner) AuthCommand() *exec.Cmd {
+	if r.Elevator == "doas" {
+		// doas has no validate flag; running true caches credentials when "persist" is configured
+		return exec.Command("doas", "true")
+	}
+	return exec.Command(r.Elevator, "-v")
+}
+
+// Authenticated reports whether root commands can run without prompting for a password
+func (r *Runner) Authenticated() bool {
+	if !r.NeedsElevation() {
+		return true
+	}
+	return exec.Command(r.Elevator, "-n", "true").Run() == nil
+}
+
+// CheckAuth converts a failed AuthCommand into ErrCancelled
+func CheckAuth(err error) error {
+	if err != nil {
+		return ErrCancelled
+	}
+	return nil
+}
+
+// KeepAlive refreshes the cached sudo timestamp until ctx is done, so long installs
+// don't expire it halfway through
+func (r *Runner) KeepAlive(ctx context.Context) {
+	if r.Elevator != "sudo" || !r.NeedsElevation() {
+		return
+	}
+
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			exec.Command("sudo", "-n", "-v").Run()
+		}
+	}
+}
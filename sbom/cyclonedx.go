@@ -0,0 +1,92 @@
+package sbom
+
+import (
+	"time"
+
+	"decor/installed"
+)
+
+// cdxBOM is a CycloneDX 1.5 document, with the fields decor fills in
+type cdxBOM struct {
+	BOMFormat    string         `json:"bomFormat"`
+	SpecVersion  string         `json:"specVersion"`
+	SerialNumber string         `json:"serialNumber"`
+	Version      int            `json:"version"`
+	Metadata     cdxMetadata    `json:"metadata"`
+	Components   []cdxComponent `json:"components"`
+}
+
+type cdxMetadata struct {
+	Timestamp string       `json:"timestamp"`
+	Tools     cdxTools     `json:"tools"`
+	Component cdxComponent `json:"component"`
+}
+
+type cdxTools struct {
+	Components []cdxComponent `json:"components"`
+}
+
+type cdxComponent struct {
+	Type               string        `json:"type"`
+	BOMRef             string        `json:"bom-ref,omitempty"`
+	Name               string        `json:"name"`
+	Version            string        `json:"version,omitempty"`
+	Purl               string        `json:"purl,omitempty"`
+	Hashes             []cdxHash     `json:"hashes,omitempty"`
+	ExternalReferences []cdxRef      `json:"externalReferences,omitempty"`
+	Properties         []cdxProperty `json:"properties,omitempty"`
+}
+
+type cdxHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cdxRef struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// cycloneDX describes the records as a CycloneDX BOM of the machine, one application per item
+func cycloneDX(records []installed.Record, machine Machine, now time.Time) cdxBOM {
+	bom := cdxBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + uuid(),
+		Version:      1,
+		Metadata: cdxMetadata{
+			Timestamp: now.UTC().Format(time.RFC3339),
+			Tools:     cdxTools{Components: []cdxComponent{{Type: "application", Name: "decor"}}},
+			Component: cdxComponent{Type: "device", Name: machine.Host},
+		},
+		Components: []cdxComponent{},
+	}
+	for _, r := range records {
+		c := cdxComponent{Type: "application", BOMRef: ref(r.Name), Name: r.Name, Version: r.Version}
+		if list := purls(r, machine.Distro); len(list) > 0 {
+			c.Purl = list[0]
+		}
+		for _, source := range r.Sources {
+			c.ExternalReferences = append(c.ExternalReferences, cdxRef{Type: "distribution", URL: source.URL})
+			if source.SHA256 != "" {
+				c.Hashes = append(c.Hashes, cdxHash{Alg: "SHA-256", Content: source.SHA256})
+			}
+		}
+		c.Properties = append(c.Properties, cdxProperty{Name: "decor:method", Value: r.Method})
+		for _, p := range r.Packages {
+			c.Properties = append(c.Properties, cdxProperty{Name: "decor:package", Value: p})
+		}
+		installedAt := r.InstalledAt
+		if !r.UpdatedAt.IsZero() {
+			installedAt = r.UpdatedAt
+		}
+		c.Properties = append(c.Properties, cdxProperty{Name: "decor:installed_at", Value: installedAt.UTC().Format(time.RFC3339)})
+		bom.Components = append(bom.Components, c)
+	}
+	return bom
+}
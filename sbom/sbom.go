@@ -0,0 +1,123 @@
+// Package sbom writes what decor installed as a software bill of materials, in CycloneDX or SPDX JSON,
+// for security teams that keep inventories of developer machines
+package sbom
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"decor/installed"
+)
+
+// Formats are the SBOM formats decor writes
+var Formats = []string{"cyclonedx", "spdx"}
+
+// Machine describes where the SBOM was taken
+type Machine struct {
+	Host   string
+	Distro string // the ID in /etc/os-release, e.g. "ubuntu", which Debian package URLs are namespaced by
+}
+
+// Generate writes records as an SBOM in format, one of Formats
+func Generate(format string, records []installed.Record, machine Machine, now time.Time) ([]byte, error) {
+	var bom any
+	switch format {
+	case "cyclonedx":
+		bom = cycloneDX(records, machine, now)
+	case "spdx":
+		bom = spdx(records, machine, now)
+	default:
+		return nil, fmt.Errorf("no SBOM format %q; the formats are %s", format, strings.Join(Formats, ", "))
+	}
+	data, err := json.MarshalIndent(bom, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// Distro reads the distribution's ID from os-release, or "" where there's none
+func Distro() string {
+	data, err := os.ReadFile("/etc/os-release")
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if id, ok := strings.CutPrefix(line, "ID="); ok {
+			return strings.Trim(id, `"'`)
+		}
+	}
+	return ""
+}
+
+// purlTypes are the package URL types of the package managers decor installs with
+var purlTypes = map[string]string{"brew": "brew", "apt": "deb", "pipx": "pypi", "npm": "npm", "cargo": "cargo", "go": "golang", "winget": "winget"}
+
+// purl returns the package URL for a package, or for a download when name is the item's own name and
+// it came from a URL. It's "" when neither applies.
+func purl(r installed.Record, name string, distro string) string {
+	version := ""
+	if r.Version != "" {
+		version = "@" + url.PathEscape(r.Version)
+	}
+	kind, ok := purlTypes[r.Method]
+	switch {
+	case ok && kind == "deb":
+		if distro == "" {
+			distro = "debian"
+		}
+		return "pkg:deb/" + distro + "/" + name + version
+	case ok && kind == "golang":
+		// go install takes module@version; the purl carries the version separately
+		module, _, _ := strings.Cut(name, "@")
+		return "pkg:golang/" + module + version
+	case ok:
+		return "pkg:" + kind + "/" + name + version
+	case len(r.Sources) > 0:
+		purl := "pkg:generic/" + url.PathEscape(strings.ToLower(name)) + version + "?download_url=" + url.QueryEscape(r.Sources[0].URL)
+		if r.Sources[0].SHA256 != "" {
+			purl += "&checksum=sha256:" + r.Sources[0].SHA256
+		}
+		return purl
+	}
+	return ""
+}
+
+// purls returns the package URLs for a record: one per package it came in, or its download's
+func purls(r installed.Record, distro string) []string {
+	var list []string
+	for _, name := range r.Packages {
+		if p := purl(r, name, distro); p != "" {
+			list = append(list, p)
+		}
+	}
+	if len(list) == 0 {
+		if p := purl(r, r.Name, distro); p != "" {
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
+// uuid returns a random version 4 UUID
+func uuid() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// idChars are the characters SPDX identifiers and CycloneDX references can't have
+var idChars = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// ref makes an identifier from an item's name, like "GitHub-CLI"
+func ref(name string) string {
+	return idChars.ReplaceAllString(name, "-")
+}
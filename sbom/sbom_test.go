@@ -0,0 +1,74 @@
+package sbom
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"decor/installed"
+)
+
+var records = []installed.Record{
+	{Name: "Go", Version: "1.25.5", Method: "tarball", Sources: []installed.Source{{URL: "https://go.dev/dl/go1.25.5.linux-amd64.tar.gz", SHA256: "abc123"}}},
+	{Name: "GitHub CLI", Version: "2.62.0", Method: "apt", Packages: []string{"gh"}},
+	{Name: "gopls", Version: "0.16.2", Method: "go", Packages: []string{"golang.org/x/tools/gopls@latest"}},
+}
+
+func TestPurls(t *testing.T) {
+	tests := []struct {
+		record installed.Record
+		want   []string
+	}{
+		{records[0], []string{"pkg:generic/go@1.25.5?download_url=https%3A%2F%2Fgo.dev%2Fdl%2Fgo1.25.5.linux-amd64.tar.gz&checksum=sha256:abc123"}},
+		{records[1], []string{"pkg:deb/ubuntu/gh@2.62.0"}},
+		{records[2], []string{"pkg:golang/golang.org/x/tools/gopls@0.16.2"}},
+		{installed.Record{Name: "jq", Version: "1.7.1", Method: "brew", Packages: []string{"jq"}}, []string{"pkg:brew/jq@1.7.1"}},
+		{installed.Record{Name: "SDKMAN", Method: "script"}, nil},
+	}
+	for _, tt := range tests {
+		if got := purls(tt.record, "ubuntu"); !slices.Equal(got, tt.want) {
+			t.Errorf("purls(%s) = %q, want %q", tt.record.Name, got, tt.want)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	machine := Machine{Host: "dev box", Distro: "ubuntu"}
+
+	data, err := Generate("cyclonedx", records, machine, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var bom cdxBOM
+	if err := json.Unmarshal(data, &bom); err != nil {
+		t.Fatal(err)
+	}
+	if bom.BOMFormat != "CycloneDX" || len(bom.Components) != 3 || !strings.HasPrefix(bom.SerialNumber, "urn:uuid:") {
+		t.Errorf("CycloneDX BOM = %+v", bom)
+	}
+	if c := bom.Components[0]; len(c.Hashes) != 1 || c.Hashes[0].Content != "abc123" || c.ExternalReferences[0].URL != records[0].Sources[0].URL {
+		t.Errorf("Go's component is %+v", c)
+	}
+
+	data, err = Generate("spdx", records, machine, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc spdxDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Name != "decor-dev-box" || len(doc.Packages) != 3 || len(doc.Relationships) != 3 {
+		t.Errorf("SPDX document = %+v", doc)
+	}
+	if p := doc.Packages[1]; p.SPDXID != "SPDXRef-Package-GitHub-CLI" || p.DownloadLocation != noAssertion || p.ExternalRefs[0].ReferenceLocator != "pkg:deb/ubuntu/gh@2.62.0" {
+		t.Errorf("GitHub CLI's package is %+v", p)
+	}
+
+	if _, err := Generate("swid", records, machine, now); err == nil {
+		t.Error("Generate took an unknown format")
+	}
+}
@@ -0,0 +1,99 @@
+package sbom
+
+import (
+	"time"
+
+	"decor/installed"
+)
+
+// noAssertion is SPDX's way of saying a field isn't known
+const noAssertion = "NOASSERTION"
+
+// spdxDocument is an SPDX 2.3 document, with the fields decor fills in
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	Checksums        []spdxChecksum    `json:"checksums,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	CopyrightText    string            `json:"copyrightText"`
+	Comment          string            `json:"comment,omitempty"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// spdx describes the records as an SPDX document describing one package per item. Licenses aren't
+// something decor knows, so they're left unasserted.
+func spdx(records []installed.Record, machine Machine, now time.Time) spdxDocument {
+	doc := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              "decor-" + ref(machine.Host),
+		DocumentNamespace: "https://spdx.org/spdxdocs/decor-" + ref(machine.Host) + "-" + uuid(),
+		CreationInfo:      spdxCreationInfo{Created: now.UTC().Format(time.RFC3339), Creators: []string{"Tool: decor"}},
+		Packages:          []spdxPackage{},
+		Relationships:     []spdxRelationship{},
+	}
+	for _, r := range records {
+		p := spdxPackage{
+			Name:             r.Name,
+			SPDXID:           "SPDXRef-Package-" + ref(r.Name),
+			VersionInfo:      r.Version,
+			DownloadLocation: noAssertion,
+			LicenseConcluded: noAssertion,
+			LicenseDeclared:  noAssertion,
+			CopyrightText:    noAssertion,
+			Comment:          "installed by decor with " + r.Method,
+		}
+		if len(r.Sources) > 0 {
+			p.DownloadLocation = r.Sources[0].URL
+		}
+		for _, source := range r.Sources {
+			if source.SHA256 != "" {
+				p.Checksums = append(p.Checksums, spdxChecksum{Algorithm: "SHA256", ChecksumValue: source.SHA256})
+			}
+		}
+		for _, purl := range purls(r, machine.Distro) {
+			p.ExternalRefs = append(p.ExternalRefs, spdxExternalRef{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: purl})
+		}
+		doc.Packages = append(doc.Packages, p)
+		doc.Relationships = append(doc.Relationships, spdxRelationship{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: p.SPDXID})
+	}
+	return doc
+}
@@ -0,0 +1,249 @@
+package scaffold
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"decor/runner"
+)
+
+// stepTimeout bounds each scaffolding command; the first Maven or Cargo build downloads a lot
+const stepTimeout = 10 * time.Minute
+
+// Step is one command run to create or build a project
+type Step struct {
+	Args   []string
+	Parent bool // runs in the project's parent directory, for tools that create the project directory
+}
+
+// Template creates a hello-world project for a language and builds it to prove the toolchain works
+type Template struct {
+	Language   string
+	Requires   string            // program that must be on PATH for the template to be used
+	CreatesDir bool              // a step creates the project directory, so it mustn't exist beforehand
+	Files      map[string]string // written into the project directory before the steps run
+	Steps      []Step
+}
+
+// templates are tried in order, the first for a language whose program is installed wins
+var templates = []Template{
+	{
+		Language: "Go",
+		Requires: "go",
+		Files: map[string]string{
+			"main.go": "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"Hello from Go, set up by decor\")\n}\n",
+		},
+		Steps: []Step{
+			{Args: []string{"go", "mod", "init", "example.com/hello"}},
+			{Args: []string{"go", "build", "./..."}},
+			{Args: []string{"go", "run", "."}},
+		},
+	},
+	{
+		Language: "Rust",
+		Requires: "cargo",
+		Steps: []Step{
+			{Args: []string{"cargo", "init", "--name", "hello", "--vcs", "none"}},
+			{Args: []string{"cargo", "run", "--quiet"}},
+		},
+	},
+	{
+		Language: "Python",
+		Requires: "uv",
+		Files: map[string]string{
+			"hello.py": "import sys\n\nprint(f\"Hello from Python {sys.version.split()[0]}, set up by decor\")\n",
+		},
+		Steps: []Step{
+			{Args: []string{"uv", "init", "--name", "hello"}},
+			{Args: []string{"uv", "run", "hello.py"}},
+		},
+	},
+	{
+		Language: "Python",
+		Requires: "python3",
+		Files: map[string]string{
+			"hello.py": "import sys\n\nprint(f\"Hello from Python {sys.version.split()[0]}, set up by decor\")\n",
+		},
+		Steps: []Step{
+			{Args: []string{"python3", "-m", "venv", ".venv"}},
+			{Args: []string{"python3", "hello.py"}},
+		},
+	},
+	{
+		Language:   "Java",
+		Requires:   "mvn",
+		CreatesDir: true,
+		Steps: []Step{
+			// The artifact ID is filled in with the project directory's name
+			{Parent: true, Args: []string{"mvn", "-B", "archetype:generate", "-DgroupId=com.example", "-DartifactId=",
+				"-DarchetypeArtifactId=maven-archetype-quickstart", "-DarchetypeVersion=1.4", "-DinteractiveMode=false"}},
+			{Args: []string{"mvn", "-B", "-q", "package"}},
+			{Args: []string{"java", "-cp", "target/classes", "com.example.App"}},
+		},
+	},
+	{
+		Language: "Java",
+		Requires: "javac",
+		Files: map[string]string{
+			"Hello.java": "public class Hello {\n    public static void main(String[] args) {\n        System.out.println(\"Hello from Java \" + System.getProperty(\"java.version\") + \", set up by decor\");\n    }\n}\n",
+		},
+		Steps: []Step{
+			{Args: []string{"javac", "Hello.java"}},
+			{Args: []string{"java", "Hello"}},
+		},
+	},
+	{
+		Language: "C++",
+		Requires: "c++",
+		Files: map[string]string{
+			"hello.cpp": "#include <iostream>\n\nint main() {\n    std::cout << \"Hello from C++, set up by decor\" << std::endl;\n}\n",
+		},
+		Steps: []Step{
+			{Args: []string{"c++", "-std=c++17", "-o", "hello", "hello.cpp"}},
+			{Args: []string{"./hello"}},
+		},
+	},
+}
+
+// lookPath finds programs on PATH, replaced in tests
+var lookPath = exec.LookPath
+
+// Languages returns the languages a project can be scaffolded for
+func Languages() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, t := range templates {
+		if !seen[t.Language] {
+			seen[t.Language] = true
+			names = append(names, t.Language)
+		}
+	}
+	return names
+}
+
+// Lookup returns the name of language as templates spell it, e.g. C++ for c++, and whether it has a
+// template at all, installed or not
+func Lookup(language string) (string, bool) {
+	for _, t := range templates {
+		if strings.EqualFold(t.Language, language) {
+			return t.Language, true
+		}
+	}
+	return language, false
+}
+
+// Find returns the first template for language whose program is installed
+func Find(language string) (Template, error) {
+	language, _ = Lookup(language)
+	var missing []string
+	for _, t := range templates {
+		if !strings.EqualFold(t.Language, language) {
+			continue
+		}
+		if _, err := lookPath(t.Requires); err == nil {
+			return t, nil
+		}
+		missing = append(missing, t.Requires)
+	}
+	if missing == nil {
+		return Template{}, fmt.Errorf("no project template for %s, pick one of %s", language, strings.Join(Languages(), ", "))
+	}
+	return Template{}, fmt.Errorf("%s isn't installed (looked for %s), install it first", language, strings.Join(missing, " or "))
+}
+
+// DefaultDir returns where a project for language goes under base, e.g. base/hello-cpp for C++
+func DefaultDir(base, language string) string {
+	slug := strings.ToLower(strings.ReplaceAll(language, "+", "p"))
+	return filepath.Join(base, "hello-"+slug)
+}
+
+// commands returns the template's steps for a project at dir, with the project name filled in
+func (t Template) commands(dir string) []runner.Spec {
+	var specs []runner.Spec
+	for _, step := range t.Steps {
+		spec := runner.Spec{
+			Op:      fmt.Sprintf("scaffolding a %s project", t.Language),
+			Name:    step.Args[0],
+			Args:    make([]string, len(step.Args)-1),
+			Dir:     dir,
+			Timeout: stepTimeout,
+		}
+		copy(spec.Args, step.Args[1:])
+		for i, arg := range spec.Args {
+			if arg == "-DartifactId=" {
+				spec.Args[i] += filepath.Base(dir)
+			}
+		}
+		if step.Parent {
+			spec.Dir = filepath.Dir(dir)
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// checkDir makes sure dir can hold a new project: it has to be empty, or missing if the template
+// creates it
+func checkDir(dir string, createsDir bool) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if createsDir {
+		return fmt.Errorf("%s already exists, pick a new directory", dir)
+	}
+	if len(entries) > 0 {
+		return fmt.Errorf("%s isn't empty, pick a new directory", dir)
+	}
+	return nil
+}
+
+// Create scaffolds a hello-world project for language at dir and builds and runs it, returning the
+// commands' combined output. On a dry run the commands are only logged and no files are written.
+func Create(ctx context.Context, r *runner.Runner, language, dir string) ([]byte, error) {
+	t, err := Find(language)
+	if err != nil {
+		return nil, err
+	}
+	if dir, err = filepath.Abs(dir); err != nil {
+		return nil, err
+	}
+	if err := checkDir(dir, t.CreatesDir); err != nil {
+		return nil, err
+	}
+
+	if !r.DryRun {
+		parent := dir
+		if t.CreatesDir {
+			parent = filepath.Dir(dir)
+		}
+		if err := os.MkdirAll(parent, 0o755); err != nil {
+			return nil, err
+		}
+		for name, content := range t.Files {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var output []byte
+	for _, spec := range t.commands(dir) {
+		out, err := r.Run(ctx, spec)
+		output = append(output, out...)
+		if err != nil {
+			return output, err
+		}
+	}
+	return output, nil
+}
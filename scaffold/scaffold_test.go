@@ -0,0 +1,147 @@
+package scaffold
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"decor/runner"
+)
+
+// stubPath makes only the given programs look installed
+func stubPath(t *testing.T, installed ...string) {
+	t.Helper()
+	original := lookPath
+	t.Cleanup(func() { lookPath = original })
+	lookPath = func(name string) (string, error) {
+		for _, program := range installed {
+			if program == name {
+				return "/usr/bin/" + name, nil
+			}
+		}
+		return "", exec.ErrNotFound
+	}
+}
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name      string
+		language  string
+		installed []string
+		requires  string
+		err       string
+	}{
+		{"preferred tool", "Python", []string{"uv", "python3"}, "uv", ""},
+		{"fallback", "python", []string{"python3"}, "python3", ""},
+		{"maven over javac", "Java", []string{"mvn", "javac"}, "mvn", ""},
+		{"nothing installed", "Java", nil, "", "looked for mvn or javac"},
+		{"unknown language", "Cobol", []string{"cobc"}, "", "no project template for Cobol"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubPath(t, tt.installed...)
+			got, err := Find(tt.language)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("got error %v, want one containing %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Requires != tt.requires {
+				t.Errorf("got the %s template, want %s", got.Requires, tt.requires)
+			}
+		})
+	}
+}
+
+func TestDefaultDir(t *testing.T) {
+	tests := map[string]string{"Go": "hello-go", "C++": "hello-cpp", "Python": "hello-python"}
+	for language, want := range tests {
+		if got := DefaultDir("/projects", language); got != filepath.Join("/projects", want) {
+			t.Errorf("DefaultDir(%s) = %s, want %s", language, got, want)
+		}
+	}
+}
+
+func TestMavenCommands(t *testing.T) {
+	stubPath(t, "mvn")
+	tmpl, err := Find("Java")
+	if err != nil {
+		t.Fatal(err)
+	}
+	specs := tmpl.commands("/work/my-app")
+	if specs[0].Dir != "/work" {
+		t.Errorf("archetype:generate runs in %s, want the parent directory", specs[0].Dir)
+	}
+	if !strings.Contains(strings.Join(specs[0].Args, " "), "-DartifactId=my-app ") {
+		t.Errorf("artifact ID not filled in: %v", specs[0].Args)
+	}
+	if specs[1].Dir != "/work/my-app" {
+		t.Errorf("the build runs in %s, want the project directory", specs[1].Dir)
+	}
+	// Filling in the name must not change the template for the next project
+	if again := tmpl.commands("/work/other"); !strings.Contains(strings.Join(again[0].Args, " "), "-DartifactId=other ") {
+		t.Errorf("artifact ID not filled in for a second project: %v", again[0].Args)
+	}
+}
+
+func TestCheckDir(t *testing.T) {
+	empty := t.TempDir()
+	full := t.TempDir()
+	if err := os.WriteFile(filepath.Join(full, "main.go"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(empty, "new")
+
+	tests := []struct {
+		name       string
+		dir        string
+		createsDir bool
+		ok         bool
+	}{
+		{"missing", missing, false, true},
+		{"empty", empty, false, true},
+		{"not empty", full, false, false},
+		{"missing, created by a step", missing, true, true},
+		{"exists, created by a step", empty, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkDir(tt.dir, tt.createsDir)
+			if (err == nil) != tt.ok {
+				t.Errorf("got %v, want ok=%t", err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCreateDryRun(t *testing.T) {
+	stubPath(t, "go")
+	dir := filepath.Join(t.TempDir(), "hello-go")
+	if _, err := Create(context.Background(), &runner.Runner{DryRun: true}, "Go", dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("a dry run created %s", dir)
+	}
+}
+
+func TestCreateGo(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go isn't installed")
+	}
+	dir := filepath.Join(t.TempDir(), "hello-go")
+	output, err := Create(context.Background(), &runner.Runner{}, "Go", dir)
+	if err != nil {
+		t.Fatalf("%v\n%s", err, output)
+	}
+	if !strings.Contains(string(output), "Hello from Go") {
+		t.Errorf("the project didn't run, output:\n%s", output)
+	}
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"decor/pkgmgr"
+	"decor/runner"
+)
+
+// States a service can be in, as reported by the platform's service manager
+const (
+	StateRunning = "running"
+	StateStopped = "stopped"
+	StateMissing = "not installed"
+	StateUnknown = "unknown"
+)
+
+// queryTimeout bounds status and health commands, which should answer right away
+const queryTimeout = 10 * time.Second
+
+// Enable starts the service now and on every boot (or login, for launchd agents)
+func Enable(ctx context.Context, r *runner.Runner, name string) error {
+	switch runtime.GOOS {
+	case "linux":
+		return run(ctx, r, "enabling "+name, true, "systemctl", "enable", "--now", name)
+	case "darwin":
+		plist, err := agentPlist(name)
+		if err != nil {
+			return err
+		}
+		// bootstrap fails if the agent is loaded already, which is fine
+		run(ctx, r, "loading "+name, false, "launchctl", "bootstrap", guiDomain(), plist)
+		if err := run(ctx, r, "enabling "+name, false, "launchctl", "enable", guiDomain()+"/"+name); err != nil {
+			return err
+		}
+		return run(ctx, r, "starting "+name, false, "launchctl", "kickstart", guiDomain()+"/"+name)
+	case "windows":
+		if err := run(ctx, r, "enabling "+name, true, "sc.exe", "config", name, "start=", "auto"); err != nil {
+			return err
+		}
+		return Start(ctx, r, name)
+	}
+	return fmt.Errorf("enabling %s: no service manager on %s", name, runtime.GOOS)
+}
+
+// Start starts the service without changing whether it starts on boot
+func Start(ctx context.Context, r *runner.Runner, name string) error {
+	switch runtime.GOOS {
+	case "linux":
+		return run(ctx, r, "starting "+name, true, "systemctl", "start", name)
+	case "darwin":
+		return run(ctx, r, "starting "+name, false, "launchctl", "kickstart", guiDomain()+"/"+name)
+	case "windows":
+		return run(ctx, r, "starting "+name, true, "sc.exe", "start", name)
+	}
+	return fmt.Errorf("starting %s: no service manager on %s", name, runtime.GOOS)
+}
+
+// Stop stops the service without changing whether it starts on boot
+func Stop(ctx context.Context, r *runner.Runner, name string) error {
+	switch runtime.GOOS {
+	case "linux":
+		return run(ctx, r, "stopping "+name, true, "systemctl", "stop", name)
+	case "darwin":
+		return run(ctx, r, "stopping "+name, false, "launchctl", "kill", "SIGTERM", guiDomain()+"/"+name)
+	case "windows":
+		return run(ctx, r, "stopping "+name, true, "sc.exe", "stop", name)
+	}
+	return fmt.Errorf("stopping %s: no service manager on %s", name, runtime.GOOS)
+}
+
+// State asks the service manager whether the service is running
+func State(ctx context.Context, r *runner.Runner, name string) string {
+	spec := runner.Spec{Op: "checking " + name, ReadOnly: true, Timeout: queryTimeout}
+	switch runtime.GOOS {
+	case "linux":
+		spec.Name, spec.Args = "systemctl", []string{"show", "--property=LoadState,ActiveState", name}
+		output, err := r.Run(ctx, spec)
+		switch {
+		case err != nil:
+			return StateUnknown
+		case strings.Contains(string(output), "LoadState=not-found"):
+			return StateMissing
+		case strings.Contains(string(output), "ActiveState=active"):
+			return StateRunning
+		}
+		return StateStopped
+	case "darwin":
+		spec.Name, spec.Args = "launchctl", []string{"print", guiDomain() + "/" + name}
+		output, err := r.Run(ctx, spec)
+		switch {
+		case err != nil:
+			// Not loaded: stopped if there's an agent to load, otherwise missing
+			if agentPath(name) == "" && formulaPlist(name) == "" {
+				return StateMissing
+			}
+			return StateStopped
+		case strings.Contains(string(output), "state = running"):
+			return StateRunning
+		}
+		return StateStopped
+	case "windows":
+		spec.Name, spec.Args = "sc.exe", []string{"query", name}
+		output, err := r.Run(ctx, spec)
+		switch {
+		case strings.Contains(string(output), "1060"):
+			// ERROR_SERVICE_DOES_NOT_EXIST
+			return StateMissing
+		case err != nil:
+			return StateUnknown
+		case strings.Contains(string(output), "RUNNING"):
+			return StateRunning
+		}
+		return StateStopped
+	}
+	return StateUnknown
+}
+
+// Healthy runs a health check command, like pg_isready, which succeeds once the service accepts connections
+func Healthy(ctx context.Context, r *runner.Runner, command []string) error {
+	_, err := r.Run(ctx, runner.Spec{
+		Op:       "health checking " + command[0],
+		Name:     command[0],
+		Args:     command[1:],
+		ReadOnly: true,
+		Timeout:  queryTimeout,
+	})
+	return err
+}
+
+// run runs a service manager command through r
+func run(ctx context.Context, r *runner.Runner, op string, root bool, name string, args ...string) error {
+	_, err := r.Run(ctx, runner.Spec{Op: op, Name: name, Args: args, Root: root})
+	return err
+}
+
+// guiDomain is the launchd domain for the logged-in user's agents
+func guiDomain() string {
+	return fmt.Sprintf("gui/%d", os.Getuid())
+}
+
+// agentPath returns the user's launchd agent for label, or "" if there isn't one
+func agentPath(label string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	agent := filepath.Join(home, "Library", "LaunchAgents", label+".plist")
+	if _, err := os.Stat(agent); err != nil {
+		return ""
+	}
+	return agent
+}
+
+// formulaPlist returns the agent a Homebrew formula ships for label, or "" if none does
+func formulaPlist(label string) string {
+	prefixes := []string{"/opt/homebrew", "/usr/local"}
+	if prefix := pkgmgr.BrewPrefix(); prefix != "" {
+		prefixes = []string{prefix}
+	}
+	for _, prefix := range prefixes {
+		if matches, _ := filepath.Glob(filepath.Join(prefix, "opt", "*", label+".plist")); len(matches) > 0 {
+			return matches[0]
+		}
+	}
+	return ""
+}
+
+// agentPlist finds the launchd agent for label, copying a Homebrew formula's agent into
+// ~/Library/LaunchAgents the way brew services does
+func agentPlist(label string) (string, error) {
+	if agent := agentPath(label); agent != "" {
+		return agent, nil
+	}
+	source := formulaPlist(label)
+	if source == "" {
+		return "", fmt.Errorf("no launchd agent named %s", label)
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	agent := filepath.Join(home, "Library", "LaunchAgents", label+".plist")
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(agent), 0o755); err != nil {
+		return "", err
+	}
+	return agent, os.WriteFile(agent, data, 0o644)
+}
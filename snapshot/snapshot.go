@@ -0,0 +1,224 @@
+// Package snapshot records which tools a machine has, and compares two machines, or a machine and a
+// manifest, to explain why something works on one and not the other
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"decor/installer"
+	"decor/manifest"
+	"decor/runner"
+)
+
+// remoteTimeout bounds how long a remote host gets to check its tools and answer
+const remoteTimeout = 2 * time.Minute
+
+// Snapshot is the tools installed on a machine, as decor snapshot writes them
+type Snapshot struct {
+	Host    string    `json:"host"`
+	Taken   time.Time `json:"taken"`
+	Items   []Item    `json:"items"`
+	Partial bool      `json:"partial,omitempty"` // lists only some tools, like a manifest, so nothing is extra
+}
+
+// Item is one installed tool
+type Item struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"` // e.g. "1.25.5", or empty for any version in a manifest
+	Source  string `json:"source,omitempty"`  // "brew", "apt", "manual", or how decor installed it
+	Managed bool   `json:"managed,omitempty"` // decor installed it
+}
+
+// Take checks languages and records the installed ones
+func Take(languages []string) Snapshot {
+	host, _ := os.Hostname()
+	s := Snapshot{Host: host, Taken: time.Now().UTC()}
+	status := installer.Check(languages)
+	for _, lang := range languages {
+		if st := status[lang]; st != nil && st.Installed {
+			s.Items = append(s.Items, Item{Name: lang, Version: Version(st.Version), Source: st.Source, Managed: st.Managed})
+		}
+	}
+	return s
+}
+
+// FromManifest turns manifest entries into a partial snapshot of what they ask for
+func FromManifest(name string, entries []manifest.Entry) Snapshot {
+	s := Snapshot{Host: name, Partial: true}
+	for _, e := range entries {
+		s.Items = append(s.Items, Item{Name: e.Name, Version: e.Version})
+	}
+	return s
+}
+
+// Parse reads a snapshot, or failing that a manifest, from data; name says where it came from
+func Parse(name string, data []byte) (Snapshot, error) {
+	var s Snapshot
+	if json.Valid(data) {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return s, fmt.Errorf("%s: %w", name, err)
+		}
+		if s.Host == "" {
+			s.Host = name
+		}
+		return s, nil
+	}
+	entries, err := manifest.Parse(strings.NewReader(string(data)))
+	if err != nil {
+		return s, fmt.Errorf("%s is neither a snapshot nor a manifest: %w", name, err)
+	}
+	return FromManifest(name, entries), nil
+}
+
+// Load reads the snapshot or manifest at path
+func Load(path string) (Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	return Parse(path, data)
+}
+
+// Remote takes a snapshot of host, anything ssh accepts like user@host or an alias from ~/.ssh/config, by
+// running decor snapshot there. ssh runs in batch mode, so a host that needs a password fails rather
+// than waiting for one.
+func Remote(ctx context.Context, commands *runner.Runner, host string) (Snapshot, error) {
+	spec := runner.Spec{
+		Op:       "taking a snapshot of " + host,
+		Name:     "ssh",
+		Args:     []string{"-o", "BatchMode=yes", host, "decor", "snapshot"},
+		Timeout:  remoteTimeout,
+		ReadOnly: true,
+	}
+	output, err := commands.Run(ctx, spec)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("%w (is decor installed on %s?)", err, host)
+	}
+	s, err := Parse(host, output)
+	if err != nil || s.Partial {
+		return Snapshot{}, fmt.Errorf("%s didn't answer with a snapshot; is its decor older than decor snapshot?", host)
+	}
+	return s, nil
+}
+
+// versionPattern matches the first dotted version in a tool's output
+var versionPattern = regexp.MustCompile(`\d+(\.\d+)*`)
+
+// Version picks the version number out of what a tool printed, e.g. 1.25.5 from "go version go1.25.5
+// linux/amd64", returning the output as it is if there's no number in it
+func Version(output string) string {
+	if v := versionPattern.FindString(output); v != "" {
+		return v
+	}
+	return output
+}
+
+// compare compares dotted versions as far as the shorter goes, so 1.22.3 matches 1.22, returning -1, 0
+// or 1
+func compare(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		x, errX := strconv.Atoi(as[i])
+		y, errY := strconv.Atoi(bs[i])
+		if errX != nil || errY != nil {
+			return strings.Compare(as[i], bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// The ways a tool can differ between here and there
+const (
+	Missing = "missing" // there but not here
+	Extra   = "extra"   // here but not there
+	Older   = "older"   // older here than there
+	Newer   = "newer"   // newer here than there
+	Unknown = "unknown" // here, but its version couldn't be read as far as there gives it
+)
+
+// Difference is a tool that isn't the same here and there
+type Difference struct {
+	Name  string `json:"name"`
+	Kind  string `json:"kind"` // Missing, Extra, Older, Newer or Unknown
+	Here  string `json:"here,omitempty"`
+	There string `json:"there,omitempty"`
+}
+
+// Diff lists how here differs from there, by name. Tools a partial snapshot like a manifest doesn't
+// mention aren't extra, a version it leaves out matches any, and one like >=1.22 is a minimum.
+func Diff(here, there Snapshot) []Difference {
+	local := make(map[string]Item)
+	for _, item := range here.Items {
+		local[strings.ToLower(item.Name)] = item
+	}
+	remote := make(map[string]Item)
+	for _, item := range there.Items {
+		remote[strings.ToLower(item.Name)] = item
+	}
+
+	var diffs []Difference
+	for key, want := range remote {
+		have, ok := local[key]
+		switch {
+		case !ok:
+			diffs = append(diffs, Difference{Name: want.Name, Kind: Missing, There: want.Version})
+		case want.Version == "" || have.Version == "":
+		default:
+			minimum := strings.HasPrefix(want.Version, ">=")
+			switch c := compare(have.Version, strings.TrimPrefix(want.Version, ">=")); {
+			case c < 0:
+				diffs = append(diffs, Difference{Name: have.Name, Kind: Older, Here: have.Version, There: want.Version})
+			case c > 0 && !minimum:
+				diffs = append(diffs, Difference{Name: have.Name, Kind: Newer, Here: have.Version, There: want.Version})
+			}
+		}
+	}
+	if !there.Partial {
+		for key, have := range local {
+			if _, ok := remote[key]; !ok {
+				diffs = append(diffs, Difference{Name: have.Name, Kind: Extra, Here: have.Version})
+			}
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return strings.ToLower(diffs[i].Name) < strings.ToLower(diffs[j].Name)
+	})
+	return diffs
+}
+
+// Exact lists how here falls short of there like Diff, but strictly, as a CI runner is checked: a tool
+// whose version couldn't be read doesn't meet a version there asks for, and neither does one read to
+// fewer places, like 1.22 for 1.22.3, which Diff lets match.
+func Exact(here, there Snapshot) []Difference {
+	diffs := Diff(here, there)
+	for _, want := range there.Items {
+		if want.Version == "" || slices.ContainsFunc(diffs, func(d Difference) bool { return strings.EqualFold(d.Name, want.Name) }) {
+			continue
+		}
+		wanted := strings.Split(strings.TrimPrefix(want.Version, ">="), ".")
+		for _, have := range here.Items {
+			if strings.EqualFold(have.Name, want.Name) && (!versionPattern.MatchString(have.Version) || len(strings.Split(have.Version, ".")) < len(wanted)) {
+				diffs = append(diffs, Difference{Name: have.Name, Kind: Unknown, Here: have.Version, There: want.Version})
+			}
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return strings.ToLower(diffs[i].Name) < strings.ToLower(diffs[j].Name)
+	})
+	return diffs
+}
@@ -0,0 +1,64 @@
+package snapshot
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestVersion(t *testing.T) {
+	for output, want := range map[string]string{
+		"go version go1.25.5 linux/amd64":     "1.25.5",
+		"rustc 1.90.0 (1159e78c4 2025-09-14)": "1.90.0",
+		"jq-1.6":                              "1.6",
+		"unknown":                             "unknown",
+	} {
+		if got := Version(output); got != want {
+			t.Errorf("Version(%q) = %q, want %q", output, got, want)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	here := Snapshot{Items: []Item{{Name: "Go", Version: "1.22.3"}, {Name: "jq", Version: "1.6"}, {Name: "Rust", Version: "1.90.0"}}}
+	there := Snapshot{Items: []Item{{Name: "Go", Version: "1.25.5"}, {Name: "ripgrep", Version: "14.1.0"}, {Name: "Rust", Version: "1.81.0"}}}
+	want := []Difference{
+		{Name: "Go", Kind: Older, Here: "1.22.3", There: "1.25.5"},
+		{Name: "jq", Kind: Extra, Here: "1.6"},
+		{Name: "ripgrep", Kind: Missing, There: "14.1.0"},
+		{Name: "Rust", Kind: Newer, Here: "1.90.0", There: "1.81.0"},
+	}
+	if got := Diff(here, there); !slices.Equal(got, want) {
+		t.Errorf("Diff = %+v, want %+v", got, want)
+	}
+
+	// A manifest's versions match as far as they go, and what it doesn't list isn't extra
+	manifest, err := Parse(".tools", []byte("go 1.22\nrust\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := Diff(here, manifest); len(got) != 0 {
+		t.Errorf("diffing against a manifest the machine satisfies gave %+v", got)
+	}
+	manifest, _ = Parse(".tools", []byte("go >=1.22\njq >=1.7\n"))
+	want = []Difference{{Name: "jq", Kind: Older, Here: "1.6", There: ">=1.7"}}
+	if got := Diff(here, manifest); !slices.Equal(got, want) {
+		t.Errorf("diffing against minimum versions gave %+v, want %+v", got, want)
+	}
+}
+
+func TestExact(t *testing.T) {
+	here := Snapshot{Items: []Item{{Name: "Go", Version: "1.22"}, {Name: "jq", Version: "1.7.1"}, {Name: "Rust"}, {Name: "Node.js", Version: "20.11.1"}}}
+	required, err := Parse(".decor", []byte("go 1.22.3\njq 1.7\nrust 1.80\nnode.js 22\nripgrep\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Difference{
+		{Name: "Go", Kind: Unknown, Here: "1.22", There: "1.22.3"},
+		{Name: "Node.js", Kind: Older, Here: "20.11.1", There: "22"},
+		{Name: "ripgrep", Kind: Missing},
+		{Name: "Rust", Kind: Unknown, There: "1.80"},
+	}
+	if got := Exact(here, required); !slices.Equal(got, want) {
+		t.Errorf("Exact = %+v, want %+v", got, want)
+	}
+}
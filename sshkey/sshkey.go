@@ -0,0 +1,132 @@
+package sshkey
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"decor/runner"
+)
+
+// KeyPath returns where the user's ed25519 key lives, ~/.ssh/id_ed25519
+func KeyPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".ssh", "id_ed25519"), nil
+}
+
+// Exists reports whether the key pair at path is already there
+func Exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// Generate runs ssh-keygen on the terminal to create an ed25519 key pair at path, prompting for a passphrase
+func Generate(r *runner.Runner, path, comment string) error {
+	return r.Attach(runner.Spec{
+		Op:   "creating an SSH key",
+		Name: "ssh-keygen",
+		Args: []string{"-t", "ed25519", "-C", comment, "-f", path},
+	}).Run()
+}
+
+// ErrNoAgent is returned by AddToAgent when there's no ssh-agent to add the key to
+var ErrNoAgent = errors.New("no ssh-agent is running; start one with eval \"$(ssh-agent -s)\" and try again")
+
+// AddToAgent runs ssh-add on the terminal to load the key into the agent, asking for its passphrase. On
+// macOS the passphrase is also stored in the keychain.
+func AddToAgent(r *runner.Runner, path string) error {
+	if runtime.GOOS != "windows" && os.Getenv("SSH_AUTH_SOCK") == "" {
+		return ErrNoAgent
+	}
+	args := []string{path}
+	if runtime.GOOS == "darwin" {
+		args = []string{"--apple-use-keychain", path}
+	}
+	return r.Attach(runner.Spec{Op: "adding the SSH key to the agent", Name: "ssh-add", Args: args}).Run()
+}
+
+// configMarker starts the block decor appends to ~/.ssh/config
+const configMarker = "# Added by decor"
+
+// Configure appends a block to ~/.ssh/config that offers the key to every host and adds it to the agent
+// on first use. It returns false if the config already mentions the key.
+func Configure(path string) (bool, error) {
+	configPath := filepath.Join(filepath.Dir(path), "config")
+	existing, err := os.ReadFile(configPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+
+	identity := "~/.ssh/" + filepath.Base(path)
+	if bytes.Contains(existing, []byte(identity)) || bytes.Contains(existing, []byte(path)) {
+		return false, nil
+	}
+
+	var block strings.Builder
+	if len(existing) > 0 {
+		if !bytes.HasSuffix(existing, []byte("\n")) {
+			block.WriteString("\n")
+		}
+		block.WriteString("\n")
+	}
+	fmt.Fprintf(&block, "%s\nHost *\n  AddKeysToAgent yes\n", configMarker)
+	if runtime.GOOS == "darwin" {
+		block.WriteString("  UseKeychain yes\n")
+	}
+	fmt.Fprintf(&block, "  IdentityFile %s\n", identity)
+
+	file, err := os.OpenFile(configPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+	_, err = file.WriteString(block.String())
+	return err == nil, err
+}
+
+// PublicKey reads the public half of the key pair at path
+func PublicKey(path string) ([]byte, error) {
+	key, err := os.ReadFile(path + ".pub")
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimSpace(key), nil
+}
+
+// clipboardTimeout bounds a clipboard tool, which should return right away
+const clipboardTimeout = 10 * time.Second
+
+// clipboardCommands are tried in order until one is on PATH
+var clipboardCommands = map[string][][]string{
+	"darwin":  {{"pbcopy"}},
+	"linux":   {{"wl-copy"}, {"xclip", "-selection", "clipboard"}, {"xsel", "--clipboard", "--input"}, {"clip.exe"}},
+	"windows": {{"clip"}},
+}
+
+// CopyToClipboard puts text on the system clipboard
+func CopyToClipboard(ctx context.Context, r *runner.Runner, text []byte) error {
+	for _, command := range clipboardCommands[runtime.GOOS] {
+		if _, err := exec.LookPath(command[0]); err != nil {
+			continue
+		}
+		_, err := r.Run(ctx, runner.Spec{
+			Op:      "copying the public key",
+			Name:    command[0],
+			Args:    command[1:],
+			Input:   text,
+			Timeout: clipboardTimeout,
+		})
+		return err
+	}
+	return fmt.Errorf("no clipboard tool found on %s (install wl-clipboard or xclip)", runtime.GOOS)
+}
@@ -0,0 +1,92 @@
+package sshkey
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// githubAPI is the GitHub REST API, replaced in tests
+var githubAPI = "https://api.github.com"
+
+// uploadTimeout bounds a single API call to GitHub or GitLab
+const uploadTimeout = 30 * time.Second
+
+// Hosts are the services Upload can add keys to, with the variable their token is read from
+var Hosts = map[string]string{
+	"github": "GITHUB_TOKEN",
+	"gitlab": "GITLAB_TOKEN",
+}
+
+// Token reads the API token for host from the environment
+func Token(host string) (string, error) {
+	variable, ok := Hosts[host]
+	if !ok {
+		return "", fmt.Errorf("unknown host %q, expected github or gitlab", host)
+	}
+	token := os.Getenv(variable)
+	if token == "" && host == "github" {
+		token = os.Getenv("GH_TOKEN")
+	}
+	if token == "" {
+		return "", fmt.Errorf("set %s to a token that can add SSH keys", variable)
+	}
+	return token, nil
+}
+
+// Upload adds the public key to the user's GitHub or GitLab account. A key that's already there counts
+// as uploaded. GitLab requests go to GITLAB_URL, or gitlab.com if it's unset.
+func Upload(ctx context.Context, host, token, title string, publicKey []byte) error {
+	body, err := json.Marshal(map[string]string{"title": title, "key": string(publicKey)})
+	if err != nil {
+		return err
+	}
+
+	var url string
+	header := http.Header{"Content-Type": {"application/json"}}
+	switch host {
+	case "github":
+		url = githubAPI + "/user/keys"
+		header.Set("Authorization", "Bearer "+token)
+		header.Set("Accept", "application/vnd.github+json")
+	case "gitlab":
+		base := strings.TrimSuffix(os.Getenv("GITLAB_URL"), "/")
+		if base == "" {
+			base = "https://gitlab.com"
+		}
+		url = base + "/api/v4/user/keys"
+		header.Set("PRIVATE-TOKEN", token)
+	default:
+		return fmt.Errorf("unknown host %q, expected github or gitlab", host)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, uploadTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header = header
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("uploading key to %s: %w", host, err)
+	}
+	defer resp.Body.Close()
+	reply, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+
+	switch {
+	case resp.StatusCode == http.StatusCreated:
+		return nil
+	case (resp.StatusCode == http.StatusUnprocessableEntity || resp.StatusCode == http.StatusBadRequest) &&
+		bytes.Contains(reply, []byte("already")):
+		return nil
+	}
+	return fmt.Errorf("uploading key to %s: %s: %s", host, resp.Status, bytes.TrimSpace(reply))
+}
@@ -0,0 +1,84 @@
+package sshkey
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestToken(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "gh-token")
+	t.Setenv("GITLAB_TOKEN", "")
+	if token, err := Token("github"); err != nil || token != "gh-token" {
+		t.Errorf("Token(github) = %q, %v, want GH_TOKEN's", token, err)
+	}
+	t.Setenv("GITHUB_TOKEN", "github-token")
+	if token, err := Token("github"); err != nil || token != "github-token" {
+		t.Errorf("Token(github) = %q, %v, want GITHUB_TOKEN's first", token, err)
+	}
+	if _, err := Token("gitlab"); err == nil || !strings.Contains(err.Error(), "GITLAB_TOKEN") {
+		t.Errorf("Token(gitlab) with no token = %v, want it to say what to set", err)
+	}
+	if _, err := Token("bitbucket"); err == nil {
+		t.Error("Token(bitbucket) succeeded")
+	}
+}
+
+func TestUpload(t *testing.T) {
+	tests := []struct {
+		name   string
+		host   string
+		status int
+		reply  string
+		ok     bool
+	}{
+		{"github created", "github", http.StatusCreated, `{"id":1}`, true},
+		{"github already there", "github", http.StatusUnprocessableEntity, `{"message":"Validation Failed","errors":[{"message":"key is already in use"}]}`, true},
+		{"github bad token", "github", http.StatusUnauthorized, `{"message":"Bad credentials"}`, false},
+		{"github other validation", "github", http.StatusUnprocessableEntity, `{"message":"key is invalid"}`, false},
+		{"gitlab created", "gitlab", http.StatusCreated, `{"id":1}`, true},
+		{"gitlab already there", "gitlab", http.StatusBadRequest, `{"message":{"fingerprint":["has already been taken"]}}`, true},
+		{"gitlab forbidden", "gitlab", http.StatusForbidden, `{"message":"403 Forbidden"}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got struct{ path, auth, title, key string }
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body map[string]string
+				json.NewDecoder(r.Body).Decode(&body)
+				got.path, got.title, got.key = r.URL.Path, body["title"], body["key"]
+				got.auth = r.Header.Get("Authorization") + r.Header.Get("PRIVATE-TOKEN")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.reply))
+			}))
+			defer server.Close()
+			original := githubAPI
+			defer func() { githubAPI = original }()
+			githubAPI = server.URL
+			t.Setenv("GITLAB_URL", server.URL+"/")
+
+			err := Upload(context.Background(), tt.host, "secret", "laptop", []byte("ssh-ed25519 AAAA me@laptop"))
+			if (err == nil) != tt.ok {
+				t.Fatalf("Upload() = %v, want ok=%t", err, tt.ok)
+			}
+			if !tt.ok && !strings.Contains(err.Error(), tt.reply) {
+				t.Errorf("Upload() = %v, want the API's answer in it", err)
+			}
+			wantPath, wantAuth := "/user/keys", "Bearer secret"
+			if tt.host == "gitlab" {
+				wantPath, wantAuth = "/api/v4/user/keys", "secret"
+			}
+			if got.path != wantPath || got.auth != wantAuth || got.title != "laptop" || got.key != "ssh-ed25519 AAAA me@laptop" {
+				t.Errorf("request = %+v, want %s with %q", got, wantPath, wantAuth)
+			}
+		})
+	}
+
+	if err := Upload(context.Background(), "bitbucket", "secret", "laptop", nil); err == nil {
+		t.Error("Upload to bitbucket succeeded")
+	}
+}
@@ -0,0 +1,66 @@
+package symbols
+
+import "sync/atomic"
+
+// ascii is set when output should stick to plain ASCII, for dumb terminals, screen readers and CI logs
+var ascii atomic.Bool
+
+// SetASCII switches every symbol to its plain ASCII form, or back
+func SetASCII(on bool) {
+	ascii.Store(on)
+}
+
+// ASCII reports whether symbols are shown in plain ASCII
+func ASCII() bool {
+	return ascii.Load()
+}
+
+// Symbol is an icon with a plain ASCII stand-in. It formats with %s like a string.
+type Symbol struct {
+	fancy string
+	plain string
+}
+
+func (s Symbol) String() string {
+	if ascii.Load() {
+		return s.plain
+	}
+	return s.fancy
+}
+
+// The symbols decor prints. Emoji with a variation selector carry a trailing space because most terminals
+// draw them two columns wide but only advance the cursor by one.
+var (
+	OK        = Symbol{"✅", "[ok]"}
+	Failed    = Symbol{"❌", "[failed]"}
+	Warning   = Symbol{"⚠️ ", "[warning]"}
+	Skipped   = Symbol{"⊘", "[skipped]"}
+	Info      = Symbol{"ℹ️ ", "[info]"}
+	Update    = Symbol{"⬆️ ", "[update]"}
+	Pending   = Symbol{"⏳", "[...]"}
+	TimedOut  = Symbol{"⏱️ ", "[timeout]"}
+	Arrow     = Symbol{"→", "->"}
+	Bullet    = Symbol{"•", "*"}
+	Dash      = Symbol{"—", "-"}
+	Separator = Symbol{"›", ">"}
+	Rule      = Symbol{"─", "-"}
+	SortUp    = Symbol{"▲", "^"}
+	SortDown  = Symbol{"▼", "v"}
+	Expanded  = Symbol{"▾", "-"}
+	Collapsed = Symbol{"▸", "+"}
+)
+
+// spinner and plainSpinner are the frames of the animation shown while waiting
+var (
+	spinner      = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	plainSpinner = []string{"|", "/", "-", "\\"}
+)
+
+// Spinner returns frame n of the animation shown while waiting
+func Spinner(n int) string {
+	frames := spinner
+	if ascii.Load() {
+		frames = plainSpinner
+	}
+	return frames[n%len(frames)]
+}
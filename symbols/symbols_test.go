@@ -0,0 +1,32 @@
+package symbols
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestASCII(t *testing.T) {
+	defer SetASCII(false)
+	tests := []struct {
+		ascii bool
+		want  string
+	}{
+		{false, "✅ go → 1.23"},
+		{true, "[ok] go -> 1.23"},
+	}
+	for _, tt := range tests {
+		SetASCII(tt.ascii)
+		if got := fmt.Sprintf("%s go %s 1.23", OK, Arrow); got != tt.want {
+			t.Errorf("with ASCII %t got %q, want %q", tt.ascii, got, tt.want)
+		}
+	}
+
+	SetASCII(true)
+	for n := range 10 {
+		for _, r := range Spinner(n) {
+			if r > 127 {
+				t.Errorf("spinner frame %d is %q", n, Spinner(n))
+			}
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Package table lays rows of text out under column headings and sorts them by any column, the part of
+// bubbles/table decor needs, for the CLI and the TUI to share
+package table
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"decor/symbols"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Table is rows of cells under headings, sorted by one of the columns
+type Table struct {
+	Columns []string
+	Rows    [][]string
+	SortBy  int  // the column the rows are sorted by
+	Reverse bool // sorted largest first
+}
+
+// New creates a table with columns, sorted by the first
+func New(columns ...string) *Table {
+	return &Table{Columns: columns}
+}
+
+// Add appends a row, which should have a cell per column
+func (t *Table) Add(cells ...string) {
+	t.Rows = append(t.Rows, cells)
+}
+
+// SortOn sorts by column, or reverses the order if the table is already sorted by it
+func (t *Table) SortOn(column int) {
+	if column == t.SortBy {
+		t.Reverse = !t.Reverse
+	} else {
+		t.SortBy, t.Reverse = column, false
+	}
+	t.Sort()
+}
+
+// Sort orders the rows by the sort column. Empty cells go last either way, and rows that tie keep
+// their order.
+func (t *Table) Sort() {
+	slices.SortStableFunc(t.Rows, func(a, b []string) int {
+		x, y := cell(a, t.SortBy), cell(b, t.SortBy)
+		switch {
+		case x == y:
+			return 0
+		case x == "":
+			return 1
+		case y == "":
+			return -1
+		}
+		if t.Reverse {
+			return Compare(y, x)
+		}
+		return Compare(x, y)
+	})
+}
+
+// cell returns a row's cell in column, or "" if the row is short
+func cell(row []string, column int) string {
+	if column < len(row) {
+		return row[column]
+	}
+	return ""
+}
+
+// Compare orders strings the way people read them: runs of digits by their value, so 1.9 comes before
+// 1.10, and everything else ignoring case
+func Compare(a, b string) int {
+	for a != "" && b != "" {
+		x, y := chunk(a), chunk(b)
+		a, b = a[len(x):], b[len(y):]
+		if isDigit(x) && isDigit(y) {
+			m, _ := strconv.ParseUint(x, 10, 64)
+			n, _ := strconv.ParseUint(y, 10, 64)
+			if m != n {
+				if m < n {
+					return -1
+				}
+				return 1
+			}
+			continue
+		}
+		if c := strings.Compare(strings.ToLower(x), strings.ToLower(y)); c != 0 {
+			return c
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+// chunk returns the leading run of digits, or of anything but digits, of s
+func chunk(s string) string {
+	digits := isDigit(s[:1])
+	for i, r := range s {
+		if unicode.IsDigit(r) != digits {
+			return s[:i]
+		}
+	}
+	return s
+}
+
+// isDigit reports whether s starts with a digit
+func isDigit(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsDigit(r)
+}
+
+// Widths returns how many terminal columns each column takes: its widest cell or its heading
+func (t *Table) Widths() []int {
+	widths := make([]int, len(t.Columns))
+	for i := range t.Columns {
+		widths[i] = lipgloss.Width(t.heading(i))
+		for _, row := range t.Rows {
+			widths[i] = max(widths[i], lipgloss.Width(cell(row, i)))
+		}
+	}
+	return widths
+}
+
+// Line pads cells to the columns' widths, two spaces apart
+func (t *Table) Line(cells []string) string {
+	widths := t.Widths()
+	parts := make([]string, len(widths))
+	for i, width := range widths {
+		c := cell(cells, i)
+		parts[i] = c + strings.Repeat(" ", width-lipgloss.Width(c))
+	}
+	return strings.TrimRight(strings.Join(parts, "  "), " ")
+}
+
+// heading returns a column's heading, with an arrow on the sort column pointing the way it's sorted
+func (t *Table) heading(column int) string {
+	if column != t.SortBy {
+		return t.Columns[column]
+	}
+	if t.Reverse {
+		return t.Columns[column] + " " + symbols.SortDown.String()
+	}
+	return t.Columns[column] + " " + symbols.SortUp.String()
+}
+
+// Header returns the headings lined up with the rows
+func (t *Table) Header() string {
+	headings := make([]string, len(t.Columns))
+	for i := range t.Columns {
+		headings[i] = t.heading(i)
+	}
+	return t.Line(headings)
+}
+
+// String renders the heading and then every row
+func (t *Table) String() string {
+	lines := []string{t.Header()}
+	for _, row := range t.Rows {
+		lines = append(lines, t.Line(row))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
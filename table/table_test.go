@@ -0,0 +1,63 @@
+package table
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.9", "1.10", -1},
+		{"1.25.5", "1.25.5", 0},
+		{"go", "Java", -1},
+		{"v2", "v10", -1},
+		{"1.2", "1.2.1", -1},
+		{"apt", "brew", -1},
+	}
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSortOn(t *testing.T) {
+	table := New("Item", "Version")
+	table.Add("Go", "1.25.5")
+	table.Add("jq", "")
+	table.Add("Rust", "1.9.0")
+	table.Add("uv", "1.10.2")
+
+	names := func() []string {
+		var names []string
+		for _, row := range table.Rows {
+			names = append(names, row[0])
+		}
+		return names
+	}
+	table.SortOn(1)
+	if got := names(); !slices.Equal(got, []string{"Rust", "uv", "Go", "jq"}) {
+		t.Errorf("sorted by version: %q", got)
+	}
+	table.SortOn(1)
+	if got := names(); !slices.Equal(got, []string{"Go", "uv", "Rust", "jq"}) {
+		t.Errorf("sorted by version, largest first: %q", got)
+	}
+	table.SortOn(0)
+	if got := names(); !slices.Equal(got, []string{"Go", "jq", "Rust", "uv"}) {
+		t.Errorf("sorted by name: %q", got)
+	}
+}
+
+func TestString(t *testing.T) {
+	table := New("Item", "Version")
+	table.Add("GitHub CLI", "2.62.0")
+	table.Add("Go", "")
+	want := "Item ▲      Version\nGitHub CLI  2.62.0\nGo\n"
+	if got := table.String(); got != want {
+		t.Errorf("table renders\n%q, want\n%q", got, want)
+	}
+}
@@ -0,0 +1,148 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"decor/doctor"
+	"decor/runner"
+)
+
+// probeTimeout bounds each compile or run; a cold Rust or Java compile takes a few seconds
+const probeTimeout = 2 * time.Minute
+
+// probe is a tiny program that uses a language's compiler and standard library, catching broken
+// installs a version check misses, like a C++ compiler without SDK headers or a Python without ssl
+type probe struct {
+	language string
+	requires string            // program that must be on PATH, otherwise the language is skipped
+	files    map[string]string // written to a temporary directory the steps run in
+	steps    [][]string        // compile and run; the last step's output is the result's detail
+	fix      string            // shown when the probe fails
+}
+
+var probes = []probe{
+	{
+		language: "Go",
+		requires: "go",
+		files: map[string]string{
+			"main.go": "package main\n\nimport (\n\t\"fmt\"\n\t\"runtime\"\n\t\"strings\"\n)\n\nfunc main() {\n\tfmt.Println(strings.TrimPrefix(runtime.Version(), \"go\"), \"on\", runtime.GOOS+\"/\"+runtime.GOARCH)\n}\n",
+		},
+		steps: [][]string{{"go", "run", "main.go"}},
+		fix:   "Reinstall Go with decor, and check GOROOT isn't set to an old install",
+	},
+	{
+		language: "Python",
+		requires: "python3",
+		files: map[string]string{
+			"hello.py": "import json, sqlite3, ssl, sys\n\nprint(sys.version.split()[0], \"with\", ssl.OPENSSL_VERSION)\n",
+		},
+		steps: [][]string{{"python3", "hello.py"}},
+		fix:   "A module is missing, usually ssl or sqlite3 in a Python built without their libraries; reinstall Python with decor",
+	},
+	{
+		language: "Rust",
+		requires: "rustc",
+		files: map[string]string{
+			"hello.rs": "use std::collections::HashMap;\n\nfn main() {\n    let mut counts = HashMap::new();\n    counts.insert(\"ok\", 1);\n    println!(\"std works ({} entry)\", counts.len());\n}\n",
+		},
+		steps: [][]string{{"rustc", "-o", "hello", "hello.rs"}, {"./hello"}},
+		fix:   "Run rustup default stable, and on Linux make sure a C linker (cc) is installed",
+	},
+	{
+		language: "C",
+		requires: "cc",
+		files: map[string]string{
+			"hello.c": "#include <math.h>\n#include <stdio.h>\n\nint main(void) {\n    printf(\"C %ld from %s, sqrt(2) = %.3f\\n\", (long)__STDC_VERSION__, __VERSION__, sqrt(2.0));\n    return 0;\n}\n",
+		},
+		steps: [][]string{{"cc", "-o", "hello", "hello.c", "-lm"}, {"./hello"}},
+		fix:   "The C headers or libraries are missing: run xcode-select --install on macOS, or install build-essential on Linux",
+	},
+	{
+		language: "C++",
+		requires: "c++",
+		files: map[string]string{
+			"hello.cpp": "#include <iostream>\n#include <string>\n#include <vector>\n\nint main() {\n    std::vector<std::string> words{\"C++\", std::to_string(__cplusplus)};\n    std::cout << words[0] << \" \" << words[1] << std::endl;\n}\n",
+		},
+		steps: [][]string{{"c++", "-o", "hello", "hello.cpp"}, {"./hello"}},
+		fix:   "The C++ standard library headers are missing: run xcode-select --install on macOS, or install build-essential (or libstdc++-dev) on Linux",
+	},
+	{
+		language: "Java",
+		requires: "javac",
+		files: map[string]string{
+			"Hello.java": "import java.util.List;\n\npublic class Hello {\n    public static void main(String[] args) {\n        System.out.println(String.join(\" \", List.of(System.getProperty(\"java.version\"), System.getProperty(\"java.vendor\"))));\n    }\n}\n",
+		},
+		steps: [][]string{{"javac", "Hello.java"}, {"java", "Hello"}},
+		fix:   "javac and java come from different JDKs, or JAVA_HOME points at an old one; reinstall Java with decor",
+	},
+}
+
+// lookPath finds programs on PATH, replaced in tests
+var lookPath = exec.LookPath
+
+// Run compiles and runs a tiny program for each installed language, skipping languages that aren't
+// installed. The programs are built in a temporary directory, so this runs on a dry run too.
+func Run(ctx context.Context, r *runner.Runner) []doctor.Result {
+	var results []doctor.Result
+	for _, p := range probes {
+		if _, err := lookPath(p.requires); err != nil {
+			continue
+		}
+		results = append(results, p.run(ctx, r))
+	}
+	return results
+}
+
+// run builds and runs the probe in a fresh temporary directory
+func (p probe) run(ctx context.Context, r *runner.Runner) doctor.Result {
+	result := doctor.Result{Name: p.language}
+	dir, err := os.MkdirTemp("", "decor-verify-")
+	if err != nil {
+		result.Status, result.Detail = doctor.StatusWarn, fmt.Sprintf("couldn't create a directory to build in: %v", err)
+		return result
+	}
+	defer os.RemoveAll(dir)
+
+	for name, content := range p.files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			result.Status, result.Detail = doctor.StatusWarn, err.Error()
+			return result
+		}
+	}
+
+	var output []byte
+	for _, step := range p.steps {
+		output, err = r.Run(ctx, runner.Spec{
+			Op:       "verifying " + p.language,
+			Name:     step[0],
+			Args:     step[1:],
+			Dir:      dir,
+			Timeout:  probeTimeout,
+			ReadOnly: true,
+		})
+		if err != nil {
+			result.Status, result.Fix = doctor.StatusFail, p.fix
+			result.Detail = fmt.Sprintf("%s failed: %s", strings.Join(step, " "), lastLine(output, err))
+			return result
+		}
+	}
+	result.Status = doctor.StatusOK
+	result.Detail = "compiled and ran: " + lastLine(output, nil)
+	return result
+}
+
+// lastLine returns the last non-empty line of output, which names the problem when a step fails,
+// or err if there's no output
+func lastLine(output []byte, err error) string {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if last := strings.TrimSpace(lines[len(lines)-1]); last != "" || err == nil {
+		return last
+	}
+	return err.Error()
+}
@@ -0,0 +1,70 @@
+package verify
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"decor/doctor"
+	"decor/runner"
+)
+
+func TestRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the probes below use sh")
+	}
+	originalProbes, originalLookPath := probes, lookPath
+	t.Cleanup(func() { probes, lookPath = originalProbes, originalLookPath })
+	lookPath = func(name string) (string, error) {
+		if name == "missing" {
+			return "", exec.ErrNotFound
+		}
+		return exec.LookPath(name)
+	}
+	probes = []probe{
+		{language: "Works", requires: "sh", files: map[string]string{"hello.txt": "hello\n"}, steps: [][]string{{"cat", "hello.txt"}}},
+		{language: "Broken", requires: "sh", steps: [][]string{{"sh", "-c", "echo 'fatal error: iostream: No such file'; exit 1"}, {"true"}}, fix: "install the headers"},
+		{language: "Silent", requires: "sh", steps: [][]string{{"false"}}},
+		{language: "Absent", requires: "missing", steps: [][]string{{"true"}}},
+	}
+
+	// The probes only touch a temporary directory, so they run on a dry run too
+	results := Run(context.Background(), &runner.Runner{DryRun: true})
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3 without the missing language: %+v", len(results), results)
+	}
+
+	tests := []struct {
+		status doctor.Status
+		detail string
+		fix    string
+	}{
+		{doctor.StatusOK, "compiled and ran: hello", ""},
+		{doctor.StatusFail, "sh -c echo 'fatal error: iostream: No such file'; exit 1 failed: fatal error: iostream: No such file", "install the headers"},
+		{doctor.StatusFail, "false failed: ", ""},
+	}
+	for i, tt := range tests {
+		got := results[i]
+		if got.Status != tt.status || !strings.HasPrefix(got.Detail, tt.detail) || got.Fix != tt.fix {
+			t.Errorf("%s: got %+v, want status %d, detail %q, fix %q", got.Name, got, tt.status, tt.detail, tt.fix)
+		}
+	}
+}
+
+func TestProbes(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles real programs")
+	}
+	for _, p := range probes {
+		if _, err := exec.LookPath(p.requires); err != nil {
+			continue
+		}
+		t.Run(p.language, func(t *testing.T) {
+			if result := p.run(context.Background(), &runner.Runner{}); result.Status != doctor.StatusOK {
+				t.Errorf("%s: %s", result.Detail, result.Fix)
+			}
+		})
+	}
+}
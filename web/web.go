@@ -0,0 +1,96 @@
+package web
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"decor/daemon"
+	"decor/installer"
+)
+
+// DefaultAddr only listens on loopback; reach it from elsewhere with SSH port forwarding
+const DefaultAddr = "127.0.0.1:7878"
+
+//go:embed index.html
+var indexPage []byte
+
+// Handler serves the dashboard page and a JSON API backed by engine
+func Handler(engine *daemon.Server) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(indexPage)
+	})
+	mux.HandleFunc("GET /api/languages", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, installer.Offered())
+	})
+	mux.HandleFunc("POST /api", func(w http.ResponseWriter, r *http.Request) {
+		// Requiring JSON means other sites can't post here without a CORS preflight, which we never grant
+		if r.Header.Get("Content-Type") != "application/json" {
+			writeJSON(w, http.StatusUnsupportedMediaType, daemon.Response{Error: "expected application/json"})
+			return
+		}
+		var req daemon.Request
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, daemon.Response{Error: fmt.Sprintf("invalid request: %v", err)})
+			return
+		}
+		resp := engine.Dispatch(req)
+		status := http.StatusOK
+		if resp.Error != "" {
+			status = http.StatusConflict
+		}
+		writeJSON(w, status, resp)
+	})
+	return localOnly(mux)
+}
+
+// localOnly rejects requests whose Host isn't a loopback address, so DNS rebinding can't reach the API
+func localOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			http.Error(w, "forbidden host", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeJSON encodes v as the response body
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// Serve hosts the dashboard on addr until ctx is done, cancelling any running install on the way out
+func Serve(ctx context.Context, addr string, engine *daemon.Server) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           Handler(engine),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		engine.Dispatch(daemon.Request{Method: daemon.MethodCancel})
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// Package wsl detects Windows Subsystem for Linux, where Windows' PATH is appended to Linux's and
+// Windows programs run alongside Linux ones
+package wsl
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// procVersion and interop are where the kernel tells WSL apart; tests replace them
+var (
+	procVersion = "/proc/version"
+	interop     = "/proc/sys/fs/binfmt_misc/WSLInterop"
+)
+
+// Detected reports whether decor runs inside WSL. The answer can't change while decor runs, so it's
+// worked out once.
+var Detected = sync.OnceValue(detect)
+
+func detect() bool {
+	if runtime.GOOS != "linux" {
+		return false
+	}
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		return true
+	}
+	if _, err := os.Stat(interop); err == nil {
+		return true
+	}
+	version, err := os.ReadFile(procVersion)
+	return err == nil && strings.Contains(strings.ToLower(string(version)), "microsoft")
+}
+
+// windowsMount matches the Windows drives WSL mounts, like /mnt/c
+var windowsMount = regexp.MustCompile(`^/mnt/[a-zA-Z](/|$)`)
+
+// IsWindowsPath reports whether path is on a Windows drive rather than the Linux filesystem
+func IsWindowsPath(path string) bool {
+	return windowsMount.MatchString(path)
+}
+
+// LookPath finds a program like exec.LookPath, skipping the Windows directories on PATH, whose
+// programs are built for Windows even when, like npm's shell wrapper, they don't end in .exe
+func LookPath(name string) (string, error) {
+	if strings.Contains(name, "/") {
+		return name, nil
+	}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" || IsWindowsPath(dir) {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() && info.Mode()&0o111 != 0 {
+			return path, nil
+		}
+	}
+	return "", errors.New(name + " isn't on the Linux side of PATH")
+}
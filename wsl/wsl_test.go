@@ -0,0 +1,54 @@
+package wsl
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("WSL is Linux")
+	}
+	originalVersion, originalInterop := procVersion, interop
+	t.Cleanup(func() { procVersion, interop = originalVersion, originalInterop })
+	t.Setenv("WSL_DISTRO_NAME", "")
+	dir := t.TempDir()
+	interop = filepath.Join(dir, "WSLInterop")
+
+	for version, want := range map[string]bool{
+		"Linux version 5.15.167.4-microsoft-standard-WSL2 (root@f9c826d3017f) (gcc (GCC) 11.2.0)": true,
+		"Linux version 6.8.0-45-generic (buildd@lcy02-amd64-075) (x86_64-linux-gnu-gcc-13)":       false,
+	} {
+		procVersion = filepath.Join(dir, "version")
+		if err := os.WriteFile(procVersion, []byte(version), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if got := detect(); got != want {
+			t.Errorf("detect() with %q = %t, want %t", version, got, want)
+		}
+	}
+}
+
+func TestLookPath(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("PATH holds Linux directories here")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "npm"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", "/mnt/c/Program Files/nodejs"+string(os.PathListSeparator)+dir)
+	if got, err := LookPath("npm"); err != nil || got != filepath.Join(dir, "npm") {
+		t.Errorf("LookPath(npm) = %s, %v, want the Linux copy", got, err)
+	}
+	if got, err := LookPath("node"); err == nil {
+		t.Errorf("LookPath(node) = %s, want none", got)
+	}
+	for path, want := range map[string]bool{"/mnt/c/Program Files/Go/bin": true, "/mnt/d": true, "/mnt/data/bin": false, "/usr/bin": false} {
+		if got := IsWindowsPath(path); got != want {
+			t.Errorf("IsWindowsPath(%s) = %t, want %t", path, got, want)
+		}
+	}
+}